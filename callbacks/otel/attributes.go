@@ -0,0 +1,472 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	operationChat        = "chat"
+	operationEmbeddings  = "embeddings"
+	operationExecuteTool = "execute_tool"
+	operationRetrieve    = "retrieve"
+	operationIndex       = "index"
+)
+
+// operationName maps the eino component to the gen_ai.operation.name attribute,
+// empty means the component is not a GenAI operation, e.g. graph, chain or lambda.
+func operationName(component components.Component) string {
+	switch component {
+	case components.ComponentOfChatModel:
+		return operationChat
+	case components.ComponentOfEmbedding:
+		return operationEmbeddings
+	case components.ComponentOfTool:
+		return operationExecuteTool
+	case components.ComponentOfRetriever:
+		return operationRetrieve
+	case components.ComponentOfIndexer:
+		return operationIndex
+	default:
+		return ""
+	}
+}
+
+func spanKind(component components.Component) trace.SpanKind {
+	switch component {
+	case components.ComponentOfChatModel, components.ComponentOfEmbedding:
+		return trace.SpanKindClient
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+// genAISystem returns the gen_ai.system attribute, which is the implementation type of the model, e.g. "openai".
+func genAISystem(info *callbacks.RunInfo) string {
+	switch info.Component {
+	case components.ComponentOfChatModel, components.ComponentOfEmbedding:
+		return strings.ToLower(info.Type)
+	default:
+		return ""
+	}
+}
+
+// outputInfo is the part of the component output which is also reported as metrics.
+type outputInfo struct {
+	responseModel string
+	finishReason  string
+	usage         *model.TokenUsage
+}
+
+// setInputAttributes sets the attributes of the component input on span, and returns the request model if any.
+// ins contains a single element for non-stream input, or all chunks for stream input.
+func (h *otelHandler) setInputAttributes(span trace.Span, info *callbacks.RunInfo, ins []callbacks.CallbackInput) (requestModel string) {
+	var handled bool
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		requestModel, handled = h.setModelInputAttributes(span, ins)
+	case components.ComponentOfEmbedding:
+		requestModel, handled = h.setEmbeddingInputAttributes(span, ins)
+	case components.ComponentOfRetriever:
+		handled = h.setRetrieverInputAttributes(span, ins)
+	case components.ComponentOfIndexer:
+		handled = h.setIndexerInputAttributes(span, ins)
+	case components.ComponentOfTool:
+		handled = h.setToolInputAttributes(span, ins)
+	}
+
+	if !handled && !h.disableContent {
+		var in any = ins
+		if len(ins) == 1 {
+			in = ins[0]
+		}
+		s, err := sonic.MarshalString(in)
+		if err != nil {
+			log.Printf("marshal input error: %v, runinfo: %+v", err, info)
+		} else {
+			span.SetAttributes(attribute.String("eino.input", s))
+		}
+	}
+
+	return requestModel
+}
+
+func (h *otelHandler) setModelInputAttributes(span trace.Span, ins []callbacks.CallbackInput) (string, bool) {
+	config, messages, _, err := extractModelInput(convModelCallbackInput(ins))
+	if err != nil {
+		log.Printf("extract model input error: %v", err)
+		return "", false
+	}
+
+	requestModel := ""
+	if config != nil {
+		requestModel = config.Model
+		span.SetAttributes(
+			attribute.String("gen_ai.request.model", config.Model),
+			attribute.Int("gen_ai.request.max_tokens", config.MaxTokens),
+			attribute.Float64("gen_ai.request.temperature", float64(config.Temperature)),
+			attribute.Float64("gen_ai.request.top_p", float64(config.TopP)),
+			attribute.StringSlice("gen_ai.request.stop_sequences", config.Stop),
+		)
+	}
+
+	var tools []string
+	for _, in := range convModelCallbackInput(ins) {
+		if in == nil {
+			continue
+		}
+		for _, t := range in.Tools {
+			if t != nil {
+				tools = append(tools, t.Name)
+			}
+		}
+	}
+	if len(tools) > 0 {
+		span.SetAttributes(attribute.StringSlice("eino.model.tools", tools))
+	}
+
+	if len(messages) == 0 {
+		return requestModel, false
+	}
+	if !h.disableContent {
+		for i, in := range messages {
+			if in == nil {
+				continue
+			}
+			span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.prompt.%d.role", i), string(in.Role)))
+			if len(in.Content) > 0 {
+				span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.prompt.%d.content", i), in.Content))
+			}
+			if len(in.ToolCallID) > 0 {
+				span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.prompt.%d.tool_call_id", i), in.ToolCallID))
+			}
+		}
+	}
+
+	return requestModel, true
+}
+
+func (h *otelHandler) setEmbeddingInputAttributes(span trace.Span, ins []callbacks.CallbackInput) (string, bool) {
+	var (
+		requestModel string
+		texts        []string
+		handled      bool
+	)
+	for _, in := range ins {
+		ecbi := embedding.ConvCallbackInput(in)
+		if ecbi == nil {
+			continue
+		}
+		handled = true
+		texts = append(texts, ecbi.Texts...)
+		if ecbi.Config != nil {
+			requestModel = ecbi.Config.Model
+		}
+	}
+	if !handled {
+		return "", false
+	}
+
+	span.SetAttributes(
+		attribute.String("gen_ai.request.model", requestModel),
+		attribute.Int("eino.embedding.input_count", len(texts)),
+	)
+	if !h.disableContent {
+		span.SetAttributes(attribute.StringSlice("eino.embedding.input", texts))
+	}
+
+	return requestModel, true
+}
+
+func (h *otelHandler) setRetrieverInputAttributes(span trace.Span, ins []callbacks.CallbackInput) bool {
+	var handled bool
+	for _, in := range ins {
+		rcbi := retriever.ConvCallbackInput(in)
+		if rcbi == nil {
+			continue
+		}
+		handled = true
+		if !h.disableContent && len(rcbi.Query) > 0 {
+			span.SetAttributes(attribute.String("eino.retriever.query", rcbi.Query))
+		}
+		if rcbi.TopK > 0 {
+			span.SetAttributes(attribute.Int("eino.retriever.top_k", rcbi.TopK))
+		}
+		if len(rcbi.Filter) > 0 {
+			span.SetAttributes(attribute.String("eino.retriever.filter", rcbi.Filter))
+		}
+		if rcbi.ScoreThreshold != nil {
+			span.SetAttributes(attribute.Float64("eino.retriever.score_threshold", *rcbi.ScoreThreshold))
+		}
+	}
+	return handled
+}
+
+func (h *otelHandler) setIndexerInputAttributes(span trace.Span, ins []callbacks.CallbackInput) bool {
+	var (
+		count   int
+		handled bool
+	)
+	for _, in := range ins {
+		icbi := indexer.ConvCallbackInput(in)
+		if icbi == nil {
+			continue
+		}
+		handled = true
+		count += len(icbi.Docs)
+	}
+	if handled {
+		span.SetAttributes(attribute.Int("eino.indexer.document_count", count))
+	}
+	return handled
+}
+
+func (h *otelHandler) setToolInputAttributes(span trace.Span, ins []callbacks.CallbackInput) bool {
+	var (
+		sb      strings.Builder
+		handled bool
+	)
+	for _, in := range ins {
+		tcbi := tool.ConvCallbackInput(in)
+		if tcbi == nil {
+			continue
+		}
+		handled = true
+		sb.WriteString(tcbi.ArgumentsInJSON)
+	}
+	if handled && !h.disableContent {
+		span.SetAttributes(attribute.String("gen_ai.tool.call.arguments", sb.String()))
+	}
+	return handled
+}
+
+// setOutputAttributes sets the attributes of the component output on span.
+// outs contains a single element for non-stream output, or all chunks for stream output.
+func (h *otelHandler) setOutputAttributes(span trace.Span, info *callbacks.RunInfo, outs []callbacks.CallbackOutput) *outputInfo {
+	var (
+		oi      = &outputInfo{}
+		handled bool
+	)
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		handled = h.setModelOutputAttributes(span, outs, oi)
+	case components.ComponentOfEmbedding:
+		handled = h.setEmbeddingOutputAttributes(span, outs, oi)
+	case components.ComponentOfRetriever:
+		handled = h.setRetrieverOutputAttributes(span, outs)
+	case components.ComponentOfIndexer:
+		handled = h.setIndexerOutputAttributes(span, outs)
+	case components.ComponentOfTool:
+		handled = h.setToolOutputAttributes(span, outs)
+	}
+
+	if !handled && !h.disableContent {
+		var out any = outs
+		if len(outs) == 1 {
+			out = outs[0]
+		}
+		s, err := sonic.MarshalString(out)
+		if err != nil {
+			log.Printf("marshal output error: %v, runinfo: %+v", err, info)
+		} else {
+			span.SetAttributes(attribute.String("eino.output", s))
+		}
+	}
+
+	return oi
+}
+
+func (h *otelHandler) setModelOutputAttributes(span trace.Span, outs []callbacks.CallbackOutput, oi *outputInfo) bool {
+	usage, messages, _, config, err := extractModelOutput(convModelCallbackOutput(outs))
+	if err != nil {
+		log.Printf("extract model output error: %v", err)
+		return false
+	}
+
+	if config != nil {
+		oi.responseModel = config.Model
+		span.SetAttributes(attribute.String("gen_ai.response.model", config.Model))
+	}
+	if usage != nil {
+		oi.usage = usage
+		span.SetAttributes(
+			attribute.Int("gen_ai.usage.input_tokens", usage.PromptTokens),
+			attribute.Int("gen_ai.usage.output_tokens", usage.CompletionTokens),
+			attribute.Int("gen_ai.usage.total_tokens", usage.TotalTokens),
+		)
+	}
+
+	if len(messages) == 0 {
+		return false
+	}
+	for i, out := range messages {
+		if out == nil {
+			continue
+		}
+		if out.ResponseMeta != nil && len(out.ResponseMeta.FinishReason) > 0 {
+			oi.finishReason = out.ResponseMeta.FinishReason
+			span.SetAttributes(attribute.StringSlice("gen_ai.response.finish_reasons", []string{oi.finishReason}))
+		}
+		if h.disableContent {
+			continue
+		}
+		span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.completion.%d.role", i), string(out.Role)))
+		if len(out.Content) > 0 {
+			span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.completion.%d.content", i), out.Content))
+		}
+		if len(out.ToolCalls) > 0 {
+			toolCalls, err := sonic.MarshalString(out.ToolCalls)
+			if err == nil {
+				span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.completion.%d.tool_calls", i), toolCalls))
+			}
+		}
+	}
+
+	return true
+}
+
+func (h *otelHandler) setEmbeddingOutputAttributes(span trace.Span, outs []callbacks.CallbackOutput, oi *outputInfo) bool {
+	var (
+		count   int
+		dim     int
+		handled bool
+	)
+	for _, out := range outs {
+		ecbo := embedding.ConvCallbackOutput(out)
+		if ecbo == nil {
+			continue
+		}
+		handled = true
+		count += len(ecbo.Embeddings)
+		if len(ecbo.Embeddings) > 0 {
+			dim = len(ecbo.Embeddings[0])
+		}
+		if ecbo.Config != nil {
+			oi.responseModel = ecbo.Config.Model
+		}
+		if ecbo.TokenUsage != nil {
+			oi.usage = &model.TokenUsage{
+				PromptTokens:     ecbo.TokenUsage.PromptTokens,
+				CompletionTokens: ecbo.TokenUsage.CompletionTokens,
+				TotalTokens:      ecbo.TokenUsage.TotalTokens,
+			}
+		}
+	}
+	if !handled {
+		return false
+	}
+
+	span.SetAttributes(
+		attribute.String("gen_ai.response.model", oi.responseModel),
+		attribute.Int("eino.embedding.output_count", count),
+	)
+	if dim > 0 {
+		span.SetAttributes(attribute.Int("gen_ai.embeddings.dimension.count", dim))
+	}
+	if oi.usage != nil {
+		span.SetAttributes(
+			attribute.Int("gen_ai.usage.input_tokens", oi.usage.PromptTokens),
+			attribute.Int("gen_ai.usage.total_tokens", oi.usage.TotalTokens),
+		)
+	}
+
+	return true
+}
+
+func (h *otelHandler) setRetrieverOutputAttributes(span trace.Span, outs []callbacks.CallbackOutput) bool {
+	var (
+		ids     []string
+		handled bool
+	)
+	for _, out := range outs {
+		rcbo := retriever.ConvCallbackOutput(out)
+		if rcbo == nil {
+			continue
+		}
+		handled = true
+		for _, doc := range rcbo.Docs {
+			if doc != nil {
+				ids = append(ids, doc.ID)
+			}
+		}
+		if !h.disableContent {
+			docs, err := sonic.MarshalString(rcbo.Docs)
+			if err == nil {
+				span.SetAttributes(attribute.String("eino.retriever.documents", docs))
+			}
+		}
+	}
+	if handled {
+		span.SetAttributes(
+			attribute.Int("eino.retriever.document_count", len(ids)),
+			attribute.StringSlice("eino.retriever.document_ids", ids),
+		)
+	}
+	return handled
+}
+
+func (h *otelHandler) setIndexerOutputAttributes(span trace.Span, outs []callbacks.CallbackOutput) bool {
+	var (
+		ids     []string
+		handled bool
+	)
+	for _, out := range outs {
+		icbo := indexer.ConvCallbackOutput(out)
+		if icbo == nil {
+			continue
+		}
+		handled = true
+		ids = append(ids, icbo.IDs...)
+	}
+	if handled {
+		span.SetAttributes(attribute.StringSlice("eino.indexer.ids", ids))
+	}
+	return handled
+}
+
+func (h *otelHandler) setToolOutputAttributes(span trace.Span, outs []callbacks.CallbackOutput) bool {
+	var (
+		sb      strings.Builder
+		handled bool
+	)
+	for _, out := range outs {
+		tcbo := tool.ConvCallbackOutput(out)
+		if tcbo == nil {
+			continue
+		}
+		handled = true
+		sb.WriteString(tcbo.Response)
+	}
+	if handled && !h.disableContent {
+		span.SetAttributes(attribute.String("gen_ai.tool.call.result", sb.String()))
+	}
+	return handled
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type otelStateKey struct{}
+type otelState struct {
+	startTime time.Time
+	span      trace.Span
+	operation string
+	system    string
+	// requestModel is filled asynchronously for stream input, read it only after streamInputAsyncVal is closed.
+	requestModel string
+}
+
+type traceStreamInputAsyncKey struct{}
+type streamInputAsyncVal chan struct{}
+
+func (h *otelHandler) startSpan(ctx context.Context, info *callbacks.RunInfo) (context.Context, *otelState) {
+	spanName := getName(info)
+	if len(spanName) == 0 {
+		spanName = "unset"
+	}
+
+	state := &otelState{
+		startTime: time.Now(),
+		operation: operationName(info.Component),
+		system:    genAISystem(info),
+	}
+	ctx, state.span = h.tracer.Start(ctx, spanName, trace.WithSpanKind(spanKind(info.Component)), trace.WithTimestamp(state.startTime))
+
+	state.span.SetAttributes(
+		attribute.String("runinfo.name", info.Name),
+		attribute.String("runinfo.type", info.Type),
+		attribute.String("runinfo.component", string(info.Component)),
+	)
+	if len(state.operation) > 0 {
+		state.span.SetAttributes(attribute.String("gen_ai.operation.name", state.operation))
+	}
+	if len(state.system) > 0 {
+		state.span.SetAttributes(attribute.String("gen_ai.system", state.system))
+	}
+	if info.Component == components.ComponentOfTool {
+		state.span.SetAttributes(attribute.String("gen_ai.tool.name", info.Name))
+		if id := compose.GetToolCallID(ctx); len(id) > 0 {
+			state.span.SetAttributes(attribute.String("gen_ai.tool.call.id", id))
+		}
+	}
+
+	return context.WithValue(ctx, otelStateKey{}, state), state
+}
+
+func (h *otelHandler) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info == nil {
+		return ctx
+	}
+
+	ctx, state := h.startSpan(ctx, info)
+	state.requestModel = h.setInputAttributes(state.span, info, []callbacks.CallbackInput{input})
+
+	if info.Component == components.ComponentOfChatModel {
+		h.chatCount.Add(ctx, 1, metric.WithAttributes(state.metricAttributes(state.requestModel, false)...))
+	}
+
+	return ctx
+}
+
+func (h *otelHandler) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if info == nil {
+		return ctx
+	}
+
+	state, ok := ctx.Value(otelStateKey{}).(*otelState)
+	if !ok {
+		log.Printf("no state in context, runinfo: %+v", info)
+		return ctx
+	}
+	endTime := time.Now()
+	waitStreamInput(ctx)
+	defer state.span.End(trace.WithTimestamp(time.Now()))
+
+	oi := h.setOutputAttributes(state.span, info, []callbacks.CallbackOutput{output})
+	state.span.SetAttributes(attribute.Bool("gen_ai.is_streaming", false))
+
+	h.recordOutputMetrics(ctx, info, state, oi, false)
+	if len(state.operation) > 0 {
+		h.operationDuration.Record(ctx, endTime.Sub(state.startTime).Seconds(),
+			metric.WithAttributes(state.metricAttributes(state.responseModel(oi), false)...))
+	}
+
+	return ctx
+}
+
+func (h *otelHandler) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	if info == nil {
+		return ctx
+	}
+
+	state, ok := ctx.Value(otelStateKey{}).(*otelState)
+	if !ok {
+		log.Printf("no state in context, runinfo: %+v", info)
+		return ctx
+	}
+	endTime := time.Now()
+	waitStreamInput(ctx)
+	defer state.span.End(trace.WithTimestamp(time.Now()))
+
+	errorType := fmt.Sprintf("%T", err)
+	state.span.SetStatus(codes.Error, err.Error())
+	state.span.RecordError(err)
+	state.span.SetAttributes(attribute.String("error.type", errorType))
+
+	if info.Component == components.ComponentOfChatModel {
+		h.chatExceptionCounter.Add(ctx, 1, metric.WithAttributes(state.metricAttributes(state.requestModel, false)...))
+	}
+	if len(state.operation) > 0 {
+		attrs := append(state.metricAttributes(state.requestModel, false), attribute.String("error.type", errorType))
+		h.operationDuration.Record(ctx, endTime.Sub(state.startTime).Seconds(), metric.WithAttributes(attrs...))
+	}
+
+	return ctx
+}
+
+func (h *otelHandler) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	if info == nil {
+		input.Close()
+		return ctx
+	}
+
+	ctx, state := h.startSpan(ctx, info)
+
+	stopCh := make(streamInputAsyncVal)
+	ctx = context.WithValue(ctx, traceStreamInputAsyncKey{}, stopCh)
+
+	go func() {
+		defer func() {
+			e := recover()
+			if e != nil {
+				log.Printf("recover update span panic: %v, runinfo: %+v, stack: %s", e, info, string(debug.Stack()))
+			}
+			input.Close()
+			close(stopCh)
+		}()
+		var ins []callbacks.CallbackInput
+		for {
+			chunk, err := input.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("read stream input error: %v, runinfo: %+v", err, info)
+				return
+			}
+			ins = append(ins, chunk)
+		}
+
+		state.requestModel = h.setInputAttributes(state.span, info, ins)
+		if info.Component == components.ComponentOfChatModel {
+			h.chatCount.Add(ctx, 1, metric.WithAttributes(state.metricAttributes(state.requestModel, true)...))
+		}
+	}()
+
+	return ctx
+}
+
+func (h *otelHandler) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if info == nil {
+		output.Close()
+		return ctx
+	}
+
+	state, ok := ctx.Value(otelStateKey{}).(*otelState)
+	if !ok {
+		log.Printf("no state in context, runinfo: %+v", info)
+		output.Close()
+		return ctx
+	}
+
+	go func() {
+		defer func() {
+			e := recover()
+			if e != nil {
+				log.Printf("recover update span panic: %v, runinfo: %+v, stack: %s", e, info, string(debug.Stack()))
+			}
+			output.Close()
+			waitStreamInput(ctx)
+			state.span.End(trace.WithTimestamp(time.Now()))
+		}()
+
+		var (
+			outs             []callbacks.CallbackOutput
+			timeOfFirstToken time.Time
+		)
+		for {
+			chunk, err := output.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("read stream output error: %v, runinfo: %+v", err, info)
+				state.span.SetStatus(codes.Error, err.Error())
+				state.span.RecordError(err)
+				break
+			}
+			if timeOfFirstToken.IsZero() {
+				timeOfFirstToken = time.Now()
+			}
+			outs = append(outs, chunk)
+		}
+		endTime := time.Now()
+		if timeOfFirstToken.IsZero() {
+			timeOfFirstToken = endTime
+		}
+		waitStreamInput(ctx)
+
+		oi := h.setOutputAttributes(state.span, info, outs)
+		state.span.SetAttributes(attribute.Bool("gen_ai.is_streaming", true))
+
+		h.recordOutputMetrics(ctx, info, state, oi, true)
+		if len(state.operation) == 0 {
+			return
+		}
+		attrs := metric.WithAttributes(state.metricAttributes(state.responseModel(oi), true)...)
+		h.operationDuration.Record(ctx, endTime.Sub(state.startTime).Seconds(), attrs)
+
+		if info.Component != components.ComponentOfChatModel {
+			return
+		}
+
+		ttft := timeOfFirstToken.Sub(state.startTime).Seconds()
+		h.streamingTimeToFirstToken.Record(ctx, ttft, attrs)
+		state.span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_time_to_first_token", ttft))
+
+		h.streamingTimeToGenerate.Record(ctx, endTime.Sub(timeOfFirstToken).Seconds(), attrs)
+
+		if oi.usage != nil && oi.usage.CompletionTokens > 0 {
+			tpot := endTime.Sub(timeOfFirstToken).Seconds() / float64(oi.usage.CompletionTokens)
+			h.streamingTimePerOutputToken.Record(ctx, tpot, attrs)
+			state.span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_time_per_output_token", tpot))
+		}
+	}()
+
+	return ctx
+}
+
+// recordOutputMetrics records token usage and choices of GenAI operations.
+func (h *otelHandler) recordOutputMetrics(ctx context.Context, info *callbacks.RunInfo, state *otelState, oi *outputInfo, isStream bool) {
+	responseModel := state.responseModel(oi)
+
+	if info.Component == components.ComponentOfChatModel && len(oi.finishReason) > 0 {
+		attrs := append(state.metricAttributes(responseModel, isStream), attribute.String("gen_ai.response.finish_reason", oi.finishReason))
+		h.chatChoiceCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+
+	if oi.usage == nil {
+		return
+	}
+	for _, u := range []struct {
+		tokenType string
+		count     int
+	}{
+		{"total", oi.usage.TotalTokens},
+		{"output", oi.usage.CompletionTokens},
+		{"input", oi.usage.PromptTokens},
+	} {
+		attrs := append(state.metricAttributes(responseModel, isStream), attribute.String("gen_ai.token.type", u.tokenType))
+		h.tokenUsage.Record(ctx, int64(u.count), metric.WithAttributes(attrs...))
+	}
+}
+
+func (s *otelState) responseModel(oi *outputInfo) string {
+	if oi != nil && len(oi.responseModel) > 0 {
+		return oi.responseModel
+	}
+	return s.requestModel
+}
+
+func (s *otelState) metricAttributes(model string, isStream bool) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 5)
+	if len(s.operation) > 0 {
+		attrs = append(attrs, attribute.String("gen_ai.operation.name", s.operation))
+	}
+	if len(s.system) > 0 {
+		attrs = append(attrs, attribute.String("gen_ai.system", s.system))
+	}
+	attrs = append(attrs,
+		attribute.String("gen_ai.response.model", model),
+		attribute.Bool("stream", isStream),
+	)
+	return attrs
+}
+
+// waitStreamInput blocks until the stream input of the current span, if any, has been fully consumed.
+func waitStreamInput(ctx context.Context) {
+	if stopCh, ok := ctx.Value(traceStreamInputAsyncKey{}).(streamInputAsyncVal); ok {
+		<-stopCh
+	}
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/eino-ext/libs/acl/opentelemetry"
+	"github.com/cloudwego/eino/callbacks"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/cloudwego/eino-ext/callbacks/otel"
+
+type Config struct {
+	// Endpoint is the OTLP gRPC endpoint of the collector (Optional)
+	// Default: the OTEL_EXPORTER_OTLP_ENDPOINT environment variable, or "localhost:4317"
+	// Example: "localhost:4317", "tempo.example.com:4317"
+	Endpoint string
+
+	// Headers are attached to every export request, e.g. for authentication (Optional)
+	// Default: nil
+	// Example: map[string]string{"authorization": "Bearer xxx"}
+	Headers map[string]string
+
+	// Insecure disables TLS on the exporter connection (Optional)
+	// Default: false
+	Insecure bool
+
+	// ServiceName is the name of service (Required)
+	// Example: "my-app"
+	ServiceName string
+
+	// Release is the version or release identifier, reported as service.version (Optional)
+	// Default: ""
+	// Example: "v1.2.3"
+	Release string
+
+	// DisableContent stops prompts, completions, tool arguments and other payloads
+	// from being recorded as span attributes (Optional)
+	// Default: false
+	DisableContent bool
+
+	// Options are appended to the options used to build the OtelProvider (Optional),
+	// e.g. opentelemetry.WithSdkTracerProvider to reuse an existing tracer provider
+	// Default: nil
+	Options []opentelemetry.Option
+}
+
+// NewOtelHandler creates a callback handler that reports spans and metrics of every eino component
+// to an OTLP endpoint, following the OpenTelemetry GenAI semantic conventions.
+func NewOtelHandler(cfg *Config) (handler callbacks.Handler, shutdown func(ctx context.Context) error, err error) {
+	if cfg == nil {
+		return nil, nil, errors.New("config is nil")
+	}
+
+	opts := []opentelemetry.Option{
+		opentelemetry.WithServiceName(cfg.ServiceName),
+	}
+	if len(cfg.Endpoint) > 0 {
+		opts = append(opts, opentelemetry.WithExportEndpoint(cfg.Endpoint))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, opentelemetry.WithHeaders(cfg.Headers))
+	}
+	if cfg.Insecure {
+		opts = append(opts, opentelemetry.WithInsecure())
+	}
+	if len(cfg.Release) > 0 {
+		opts = append(opts, opentelemetry.WithResourceAttribute(attribute.String("service.version", cfg.Release)))
+	}
+	opts = append(opts, cfg.Options...)
+
+	p, err := opentelemetry.NewOpenTelemetryProvider(opts...)
+	if p == nil || err != nil {
+		return nil, nil, errors.New("init opentelemetry provider failed")
+	}
+
+	if p.TracerProvider == nil || p.MeterProvider == nil {
+		return nil, p.Shutdown, errors.New("tracer provider or meter provider is nil")
+	}
+
+	meter := p.MeterProvider.Meter(scopeName)
+	ins, err := newInstruments(meter)
+	if err != nil {
+		return nil, p.Shutdown, err
+	}
+
+	return &otelHandler{
+		tracer:         p.TracerProvider.Tracer(scopeName),
+		disableContent: cfg.DisableContent,
+		instruments:    ins,
+	}, p.Shutdown, nil
+}
+
+type instruments struct {
+	tokenUsage                  metric.Int64Histogram
+	chatCount                   metric.Int64Counter
+	chatChoiceCounter           metric.Int64Counter
+	operationDuration           metric.Float64Histogram
+	chatExceptionCounter        metric.Int64Counter
+	streamingTimeToFirstToken   metric.Float64Histogram
+	streamingTimeToGenerate     metric.Float64Histogram
+	streamingTimePerOutputToken metric.Float64Histogram
+}
+
+func newInstruments(meter metric.Meter) (*instruments, error) {
+	var (
+		ins = &instruments{}
+		err error
+	)
+
+	ins.tokenUsage, err = meter.Int64Histogram(
+		"gen_ai.client.token.usage",
+		metric.WithDescription("Number of tokens used in prompt and completions"),
+		metric.WithUnit("token"),
+		metric.WithExplicitBucketBoundaries(1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.chatCount, err = meter.Int64Counter(
+		"gen_ai.chat.count",
+		metric.WithDescription("Number of chat"),
+		metric.WithUnit("time"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.chatChoiceCounter, err = meter.Int64Counter(
+		"gen_ai.client.generation.choices",
+		metric.WithDescription("Number of choices returned by chat completions call"),
+		metric.WithUnit("choice"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.operationDuration, err = meter.Float64Histogram(
+		"gen_ai.client.operation.duration",
+		metric.WithDescription("GenAI operation duration"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.chatExceptionCounter, err = meter.Int64Counter(
+		"gen_ai.chat_completions.exceptions",
+		metric.WithDescription("Number of exceptions occurred during chat completions"),
+		metric.WithUnit("time"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.streamingTimeToFirstToken, err = meter.Float64Histogram(
+		"gen_ai.chat_completions.streaming_time_to_first_token",
+		metric.WithDescription("Time to first token in streaming chat completions"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.02, 0.04, 0.06, 0.08, 0.1, 0.25, 0.5, 0.75, 1.0, 2.5, 5.0, 7.5, 10.0),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.streamingTimeToGenerate, err = meter.Float64Histogram(
+		"gen_ai.chat_completions.streaming_time_to_generate",
+		metric.WithDescription("Time between first token and completion in streaming chat completions"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ins.streamingTimePerOutputToken, err = meter.Float64Histogram(
+		"gen_ai.chat_completions.streaming_time_per_output_token",
+		metric.WithDescription("Time per output token in streaming chat completions"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.025, 0.05, 0.075, 0.1, 0.15, 0.2, 0.3, 0.4, 0.5, 0.75, 1.0, 2.5),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ins, nil
+}
+
+type otelHandler struct {
+	tracer         trace.Tracer
+	disableContent bool
+	*instruments
+}
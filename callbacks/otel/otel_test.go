@@ -0,0 +1,324 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-ext/libs/acl/opentelemetry"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testEnv struct {
+	handler  callbacks.Handler
+	exporter *tracetest.InMemoryExporter
+	reader   *sdkmetric.ManualReader
+}
+
+func newTestEnv(t *testing.T, disableContent bool) *testEnv {
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	handler, shutdown, err := NewOtelHandler(&Config{
+		ServiceName:    "test",
+		DisableContent: disableContent,
+		Options: []opentelemetry.Option{
+			opentelemetry.WithSdkTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+			opentelemetry.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		},
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+	return &testEnv{handler: handler, exporter: exporter, reader: reader}
+}
+
+func (e *testEnv) spans() tracetest.SpanStubs {
+	return e.exporter.GetSpans()
+}
+
+func (e *testEnv) metrics(t *testing.T) map[string]metricdata.Metrics {
+	rm := metricdata.ResourceMetrics{}
+	assert.NoError(t, e.reader.Collect(context.Background(), &rm))
+	ret := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			ret[m.Name] = m
+		}
+	}
+	return ret
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	ret := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		ret[kv.Key] = kv.Value
+	}
+	return ret
+}
+
+func TestNewOtelHandler(t *testing.T) {
+	_, _, err := NewOtelHandler(nil)
+	assert.Error(t, err)
+}
+
+func TestChatModel(t *testing.T) {
+	env := newTestEnv(t, false)
+	ctx := context.Background()
+	info := &callbacks.RunInfo{Name: "chat", Type: "OpenAI", Component: components.ComponentOfChatModel}
+
+	ctx1 := env.handler.OnStart(ctx, info, &model.CallbackInput{
+		Messages: []*schema.Message{schema.SystemMessage("system message"), schema.UserMessage("user message")},
+		Tools:    []*schema.ToolInfo{{Name: "get_weather"}},
+		Config:   &model.Config{Model: "gpt-4o", MaxTokens: 1, Temperature: 2, TopP: 3, Stop: []string{"stop"}},
+	})
+	env.handler.OnEnd(ctx1, info, &model.CallbackOutput{
+		Message: &schema.Message{
+			Role:         schema.Assistant,
+			Content:      "assistant message",
+			ResponseMeta: &schema.ResponseMeta{FinishReason: "stop"},
+		},
+		Config:     &model.Config{Model: "gpt-4o-2024-08-06"},
+		TokenUsage: &model.TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+	})
+
+	spans := env.spans()
+	assert.Len(t, spans, 1)
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "chat", attrs["gen_ai.operation.name"].AsString())
+	assert.Equal(t, "openai", attrs["gen_ai.system"].AsString())
+	assert.Equal(t, "gpt-4o", attrs["gen_ai.request.model"].AsString())
+	assert.Equal(t, []string{"stop"}, attrs["gen_ai.request.stop_sequences"].AsStringSlice())
+	assert.Equal(t, []string{"get_weather"}, attrs["eino.model.tools"].AsStringSlice())
+	assert.Equal(t, "user message", attrs["gen_ai.prompt.1.content"].AsString())
+	assert.Equal(t, "gpt-4o-2024-08-06", attrs["gen_ai.response.model"].AsString())
+	assert.Equal(t, []string{"stop"}, attrs["gen_ai.response.finish_reasons"].AsStringSlice())
+	assert.Equal(t, "assistant message", attrs["gen_ai.completion.0.content"].AsString())
+	assert.Equal(t, int64(1), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	assert.Equal(t, int64(2), attrs["gen_ai.usage.output_tokens"].AsInt64())
+	assert.False(t, attrs["gen_ai.is_streaming"].AsBool())
+
+	metrics := env.metrics(t)
+	for _, name := range []string{
+		"gen_ai.chat.count",
+		"gen_ai.client.generation.choices",
+		"gen_ai.client.token.usage",
+		"gen_ai.client.operation.duration",
+	} {
+		assert.Contains(t, metrics, name)
+	}
+	usage := metrics["gen_ai.client.token.usage"].Data.(metricdata.Histogram[int64])
+	assert.Len(t, usage.DataPoints, 3)
+}
+
+func TestChatModelStream(t *testing.T) {
+	env := newTestEnv(t, false)
+	ctx := context.Background()
+	info := &callbacks.RunInfo{Name: "chat", Type: "OpenAI", Component: components.ComponentOfChatModel}
+
+	insr, insw := schema.Pipe[callbacks.CallbackInput](2)
+	insw.Send(&model.CallbackInput{
+		Messages: []*schema.Message{{Role: schema.User, Content: "user "}},
+	}, nil)
+	insw.Send(&model.CallbackInput{
+		Messages: []*schema.Message{{Role: schema.User, Content: "message"}},
+		Config:   &model.Config{Model: "gpt-4o"},
+	}, nil)
+	insw.Close()
+
+	outsr, outsw := schema.Pipe[callbacks.CallbackOutput](3)
+	outsw.Send(&model.CallbackOutput{Message: &schema.Message{Role: schema.Assistant, Content: "assistant "}}, nil)
+	outsw.Send(&model.CallbackOutput{Message: &schema.Message{Role: schema.Assistant, Content: "message"}}, nil)
+	outsw.Send(&model.CallbackOutput{
+		Message:    &schema.Message{Role: schema.Assistant, ResponseMeta: &schema.ResponseMeta{FinishReason: "stop"}},
+		TokenUsage: &model.TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+	}, nil)
+	outsw.Close()
+
+	ctx1 := env.handler.OnStartWithStreamInput(ctx, info, insr)
+	env.handler.OnEndWithStreamOutput(ctx1, info, outsr)
+
+	assert.Eventually(t, func() bool { return len(env.spans()) == 1 }, time.Second, 10*time.Millisecond)
+	attrs := spanAttributes(env.spans()[0])
+	assert.Equal(t, "gpt-4o", attrs["gen_ai.request.model"].AsString())
+	assert.Equal(t, "user message", attrs["gen_ai.prompt.0.content"].AsString())
+	assert.Equal(t, "assistant message", attrs["gen_ai.completion.0.content"].AsString())
+	assert.Equal(t, int64(2), attrs["gen_ai.usage.output_tokens"].AsInt64())
+	assert.True(t, attrs["gen_ai.is_streaming"].AsBool())
+	assert.Contains(t, attrs, attribute.Key("gen_ai.chat_completions.streaming_time_to_first_token"))
+
+	metrics := env.metrics(t)
+	for _, name := range []string{
+		"gen_ai.chat_completions.streaming_time_to_first_token",
+		"gen_ai.chat_completions.streaming_time_to_generate",
+		"gen_ai.chat_completions.streaming_time_per_output_token",
+	} {
+		assert.Contains(t, metrics, name)
+	}
+}
+
+func TestOnError(t *testing.T) {
+	env := newTestEnv(t, false)
+	info := &callbacks.RunInfo{Name: "chat", Type: "OpenAI", Component: components.ComponentOfChatModel}
+
+	ctx := env.handler.OnStart(context.Background(), info, &model.CallbackInput{
+		Messages: []*schema.Message{schema.UserMessage("hi")},
+		Config:   &model.Config{Model: "gpt-4o"},
+	})
+	env.handler.OnError(ctx, info, errors.New("mock error"))
+
+	spans := env.spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "mock error", spans[0].Status.Description)
+	assert.Contains(t, env.metrics(t), "gen_ai.chat_completions.exceptions")
+}
+
+func TestComponents(t *testing.T) {
+	threshold := 0.5
+	tests := []struct {
+		name   string
+		info   *callbacks.RunInfo
+		input  callbacks.CallbackInput
+		output callbacks.CallbackOutput
+		expect map[attribute.Key]any
+	}{
+		{
+			name:   "embedding",
+			info:   &callbacks.RunInfo{Name: "embedder", Type: "Ollama", Component: components.ComponentOfEmbedding},
+			input:  &embedding.CallbackInput{Texts: []string{"a", "b"}, Config: &embedding.Config{Model: "bge-m3"}},
+			output: &embedding.CallbackOutput{Embeddings: [][]float64{{1, 2, 3}, {4, 5, 6}}, Config: &embedding.Config{Model: "bge-m3"}},
+			expect: map[attribute.Key]any{
+				"gen_ai.operation.name":             "embeddings",
+				"gen_ai.system":                     "ollama",
+				"gen_ai.request.model":              "bge-m3",
+				"gen_ai.embeddings.dimension.count": int64(3),
+				"eino.embedding.output_count":       int64(2),
+			},
+		},
+		{
+			name:   "retriever",
+			info:   &callbacks.RunInfo{Name: "retriever", Type: "ES8", Component: components.ComponentOfRetriever},
+			input:  &retriever.CallbackInput{Query: "query", TopK: 3, ScoreThreshold: &threshold},
+			output: &retriever.CallbackOutput{Docs: []*schema.Document{{ID: "1"}, {ID: "2"}}},
+			expect: map[attribute.Key]any{
+				"gen_ai.operation.name":          "retrieve",
+				"eino.retriever.query":           "query",
+				"eino.retriever.top_k":           int64(3),
+				"eino.retriever.score_threshold": 0.5,
+				"eino.retriever.document_ids":    []string{"1", "2"},
+			},
+		},
+		{
+			name:   "tool",
+			info:   &callbacks.RunInfo{Name: "get_weather", Type: "InvokableTool", Component: components.ComponentOfTool},
+			input:  &tool.CallbackInput{ArgumentsInJSON: `{"city":"beijing"}`},
+			output: &tool.CallbackOutput{Response: "sunny"},
+			expect: map[attribute.Key]any{
+				"gen_ai.operation.name":      "execute_tool",
+				"gen_ai.tool.name":           "get_weather",
+				"gen_ai.tool.call.arguments": `{"city":"beijing"}`,
+				"gen_ai.tool.call.result":    "sunny",
+			},
+		},
+		{
+			name:   "lambda",
+			info:   &callbacks.RunInfo{Name: "node", Type: "Lambda", Component: compose.ComponentOfLambda},
+			input:  "input",
+			output: "output",
+			expect: map[attribute.Key]any{
+				"eino.input":  `"input"`,
+				"eino.output": `"output"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, false)
+			ctx := env.handler.OnStart(context.Background(), tt.info, tt.input)
+			env.handler.OnEnd(ctx, tt.info, tt.output)
+
+			spans := env.spans()
+			assert.Len(t, spans, 1)
+			attrs := spanAttributes(spans[0])
+			for k, v := range tt.expect {
+				assert.Contains(t, attrs, k)
+				assert.Equal(t, v, attrs[k].AsInterface(), k)
+			}
+		})
+	}
+}
+
+func TestDisableContent(t *testing.T) {
+	env := newTestEnv(t, true)
+	info := &callbacks.RunInfo{Name: "chat", Type: "OpenAI", Component: components.ComponentOfChatModel}
+
+	ctx := env.handler.OnStart(context.Background(), info, &model.CallbackInput{
+		Messages: []*schema.Message{schema.UserMessage("secret")},
+		Config:   &model.Config{Model: "gpt-4o"},
+	})
+	env.handler.OnEnd(ctx, info, &model.CallbackOutput{
+		Message: &schema.Message{Role: schema.Assistant, Content: "secret"},
+	})
+
+	spans := env.spans()
+	assert.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes {
+		assert.NotEqual(t, "secret", kv.Value.AsString(), kv.Key)
+	}
+	assert.Equal(t, "gpt-4o", spanAttributes(spans[0])["gen_ai.request.model"].AsString())
+}
+
+func TestGraph(t *testing.T) {
+	env := newTestEnv(t, false)
+	ctx := context.Background()
+
+	g := compose.NewGraph[string, string]()
+	err := g.AddLambdaNode("node1", compose.InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return strings.Repeat(input, 2), nil
+	}), compose.WithNodeName("node1"))
+	assert.NoError(t, err)
+	assert.NoError(t, g.AddEdge(compose.START, "node1"))
+	assert.NoError(t, g.AddEdge("node1", compose.END))
+	runner, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	result, err := runner.Invoke(ctx, "input", compose.WithCallbacks(env.handler))
+	assert.NoError(t, err)
+	assert.Equal(t, "inputinput", result)
+	assert.Len(t, env.spans(), 2)
+
+	sr, err := runner.Stream(ctx, "input", compose.WithCallbacks(env.handler))
+	assert.NoError(t, err)
+	sr.Close()
+	assert.Eventually(t, func() bool { return len(env.spans()) == 4 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package csv
+
+import (
+	"bytes"
+	"context"
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// MetaKeyRowIndex is the 0-based index of the record in the source, not counting the header.
+	// The skipped empty records are counted, so it stays the same when the empty rows are filled.
+	MetaKeyRowIndex = "_row_index"
+	// MetaKeyLine is the 1-based line number where the record starts in the source,
+	// which differs from the row index with the comment lines, the blank lines and the quoted line breaks.
+	MetaKeyLine = "_line"
+	// MetaKeyRow is the map from the column names to the cell values of the row, only set with the header.
+	MetaKeyRow = "_row"
+)
+
+var _ parser.Parser = (*Parser)(nil)
+
+// Config is the configuration for CSV parser.
+type Config struct {
+	// Comma is the field delimiter
+	// Optional. Default: ',', or '\t' when the URI ends with ".tsv"
+	Comma rune
+	// Comment is the leading character of the comment lines, which are ignored
+	// Optional. Default: no comment lines
+	Comment rune
+	// NoHeader treats the first row as data rather than the column names
+	// Optional. Default: false
+	NoHeader bool
+	// ContentColumns are the columns that make up the content of a row, the others are only kept in MetaKeyRow
+	// Optional. Default: all the columns
+	ContentColumns []string
+}
+
+// Parser implements parser.Parser. It parses CSV and TSV content into a document per row.
+// With the header, the content of a row is a "column: value" line per non-empty cell,
+// otherwise the cells are joined by tabs.
+type Parser struct {
+	conf *Config
+}
+
+// NewParser returns a new CSV parser.
+func NewParser(ctx context.Context, config *Config) (*Parser, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if len(config.ContentColumns) > 0 && config.NoHeader {
+		return nil, errors.New("content columns require the header")
+	}
+	return &Parser{conf: config}, nil
+}
+
+// Parse parses the CSV content from the reader.
+func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	commonOpts := parser.GetCommonOptions(nil, opts...)
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("csv parser read all from reader failed: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := stdcsv.NewReader(bytes.NewReader(data))
+	r.Comma = p.conf.Comma
+	if r.Comma == 0 {
+		r.Comma = ','
+		if strings.EqualFold(filepath.Ext(commonOpts.URI), ".tsv") {
+			r.Comma = '\t'
+		}
+	}
+	r.Comment = p.conf.Comment
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var (
+		header   []string
+		docs     []*schema.Document
+		rowIndex = -1
+	)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv parser read record failed: %w", err)
+		}
+		if header == nil && !p.conf.NoHeader {
+			if isEmpty(record) {
+				continue
+			}
+			header = normalizeHeader(record)
+			if err = p.checkHeader(header); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rowIndex++
+		if isEmpty(record) {
+			continue
+		}
+		line, _ := r.FieldPos(0)
+
+		meta := make(map[string]any, len(commonOpts.ExtraMeta)+3)
+		for k, v := range commonOpts.ExtraMeta {
+			meta[k] = v
+		}
+		meta[MetaKeyRowIndex] = rowIndex
+		meta[MetaKeyLine] = line
+
+		var content string
+		if p.conf.NoHeader {
+			content = strings.Join(trimAll(record), "\t")
+		} else {
+			row := make(map[string]string, len(record))
+			for i, cell := range record {
+				row[columnName(header, i)] = strings.TrimSpace(cell)
+			}
+			meta[MetaKeyRow] = row
+			content = p.content(header, record, row)
+		}
+
+		docs = append(docs, &schema.Document{
+			Content:  content,
+			MetaData: meta,
+		})
+	}
+
+	return docs, nil
+}
+
+// checkHeader returns an error if any of the content columns is not in the header.
+func (p *Parser) checkHeader(header []string) error {
+	for _, col := range p.conf.ContentColumns {
+		if !slices.Contains(header, col) {
+			return fmt.Errorf("csv parser content column %q not found in the header %q", col, header)
+		}
+	}
+	return nil
+}
+
+func (p *Parser) content(header, record []string, row map[string]string) string {
+	columns := p.conf.ContentColumns
+	if len(columns) == 0 {
+		columns = make([]string, len(record))
+		for i := range record {
+			columns[i] = columnName(header, i)
+		}
+	}
+
+	var sb strings.Builder
+	for _, col := range columns {
+		v := row[col]
+		if v == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(col)
+		sb.WriteString(": ")
+		sb.WriteString(v)
+	}
+	return sb.String()
+}
+
+// normalizeHeader trims the column names, and names the empty and duplicate columns by their positions.
+func normalizeHeader(record []string) []string {
+	header := make([]string, len(record))
+	seen := make(map[string]bool, len(record))
+	for i, name := range record {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			name = "column_" + strconv.Itoa(i+1)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+	return header
+}
+
+// columnName returns the name of the i-th column, the cells beyond the header are named by their positions.
+func columnName(header []string, i int) string {
+	if i < len(header) {
+		return header[i]
+	}
+	return "column_" + strconv.Itoa(i+1)
+}
+
+func trimAll(record []string) []string {
+	ret := make([]string, len(record))
+	for i, cell := range record {
+		ret[i] = strings.TrimSpace(cell)
+	}
+	return ret
+}
+
+func isEmpty(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package csv
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/document/parser"
+)
+
+func TestParser(t *testing.T) {
+	ctx := context.Background()
+	input := "\xef\xbb\xbfname,age,,name\n" +
+		"lihua,21,x,dup\n" +
+		"\n" +
+		"\"wang, wei\",\"30\",,,extra\n"
+
+	t.Run("header", func(t *testing.T) {
+		p, err := NewParser(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, strings.NewReader(input), parser.WithExtraMeta(map[string]any{"k": "v"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+		if want := "name: lihua\nage: 21\ncolumn_3: x\ncolumn_4: dup"; docs[0].Content != want {
+			t.Fatalf("got content %q", docs[0].Content)
+		}
+		if want := "name: wang, wei\nage: 30\ncolumn_5: extra"; docs[1].Content != want {
+			t.Fatalf("got content %q", docs[1].Content)
+		}
+		wantRow := map[string]string{"name": "wang, wei", "age": "30", "column_3": "", "column_4": "", "column_5": "extra"}
+		if !reflect.DeepEqual(docs[1].MetaData[MetaKeyRow], wantRow) {
+			t.Fatalf("got row %v", docs[1].MetaData[MetaKeyRow])
+		}
+		if docs[1].MetaData[MetaKeyRowIndex] != 1 || docs[1].MetaData[MetaKeyLine] != 4 || docs[1].MetaData["k"] != "v" {
+			t.Fatalf("got meta %v", docs[1].MetaData)
+		}
+	})
+
+	t.Run("source position", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{Comment: '#'})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, strings.NewReader("# comment\nname,bio\n\"a\",\"line 1\nline 2\"\n,\nb,x\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+		if docs[1].Content != "name: b\nbio: x" || docs[1].MetaData[MetaKeyRowIndex] != 2 || docs[1].MetaData[MetaKeyLine] != 6 {
+			t.Fatalf("got doc %v", docs[1])
+		}
+	})
+
+	t.Run("content columns", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{ContentColumns: []string{"age", "name"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "age: 21\nname: lihua"; docs[0].Content != want {
+			t.Fatalf("got content %q", docs[0].Content)
+		}
+
+		if _, err = NewParser(ctx, &Config{ContentColumns: []string{"age"}, NoHeader: true}); err == nil {
+			t.Fatal("expected error")
+		}
+
+		p, err = NewParser(ctx, &Config{ContentColumns: []string{"age", "title"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = p.Parse(ctx, strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), `content column "title" not found`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{NoHeader: true, Comment: '#'})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, strings.NewReader("# comment\na, b\nc,d\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 2 || docs[0].Content != "a\tb" || docs[1].Content != "c\td" {
+			t.Fatalf("got docs %v", docs)
+		}
+		if _, ok := docs[0].MetaData[MetaKeyRow]; ok {
+			t.Fatal("unexpected row meta")
+		}
+	})
+
+	t.Run("tsv by ext parser", func(t *testing.T) {
+		p, err := NewParser(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ep, err := parser.NewExtParser(ctx, &parser.ExtParserConfig{
+			Parsers: map[string]parser.Parser{".csv": p, ".tsv": p},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := ep.Parse(ctx, strings.NewReader("a\tb\n1,2\t3\n"), parser.WithURI("data.tsv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 1 || docs[0].Content != "a: 1,2\nb: 3" {
+			t.Fatalf("got docs %v", docs)
+		}
+	})
+}
@@ -41,6 +41,8 @@ type Config struct {
 	IncludeHeaders  bool // whether to include headers in the parsed content
 	IncludeFooters  bool // whether to include footers in the parsed content
 	IncludeTables   bool // whether to include table content
+	Markdown        bool // whether to render the main content as Markdown, with tables inlined in document order
+	EmbedImages     bool // whether to embed images as base64 data URLs in Markdown output instead of referencing their path
 }
 
 // DocxParser reads from io.Reader and parse Docx document content as plain text.
@@ -50,6 +52,8 @@ type DocxParser struct {
 	includeHeaders  bool
 	includeFooters  bool
 	includeTables   bool
+	markdown        bool
+	embedImages     bool
 }
 
 // NewDocxParser creates a new Docx parser.
@@ -63,6 +67,8 @@ func NewDocxParser(ctx context.Context, config *Config) (*DocxParser, error) {
 		includeHeaders:  config.IncludeHeaders,
 		includeFooters:  config.IncludeFooters,
 		includeTables:   config.IncludeTables,
+		markdown:        config.Markdown,
+		embedImages:     config.EmbedImages,
 	}, nil
 }
 
@@ -83,9 +89,16 @@ func (wp *DocxParser) Parse(ctx context.Context, reader io.Reader, opts ...parse
 	}
 
 	// Extract content based on configuration
-	sections := wp.extractContent(doc)
+	sections, err := wp.extractContent(doc, data)
+	if err != nil {
+		return nil, err
+	}
 	if wp.toSections {
-		for key, section := range sections {
+		for _, key := range sectionOrder {
+			section, ok := sections[key]
+			if !ok {
+				continue
+			}
 			content := strings.TrimSpace(section)
 			metadata := make(map[string]interface{})
 			for k, v := range commonOpts.ExtraMeta {
@@ -102,7 +115,8 @@ func (wp *DocxParser) Parse(ctx context.Context, reader io.Reader, opts ...parse
 		}
 	} else {
 		var contentBuilder strings.Builder
-		for _, section := range sections {
+		for _, key := range sectionOrder {
+			section := sections[key]
 			if trimmed := strings.TrimSpace(section); trimmed != "" {
 				contentBuilder.WriteString(trimmed)
 				contentBuilder.WriteString("\n")
@@ -134,17 +148,30 @@ func GetSectionType(doc *schema.Document) (string, bool) {
 	return sectionType, ok
 }
 
+// sectionOrder is the order in which sections are emitted.
+var sectionOrder = []string{"main", "comments", "headers", "tables", "footers"}
+
 // extractContent extracts all content from the Docx document based on configuration.
-func (wp *DocxParser) extractContent(doc *document.Document) map[string]string {
+func (wp *DocxParser) extractContent(doc *document.Document, data []byte) (map[string]string, error) {
 	sections := make(map[string]string)
 
 	// Extract main document content
-	var mainContentBuf bytes.Buffer
-	mainContentBuf.WriteString("=== MAIN CONTENT ===\n")
-	mainContent := wp.extractMainContent(doc)
-	mainContentBuf.WriteString(mainContent)
-	mainContentBuf.WriteString("\n")
-	sections["main"] = mainContentBuf.String()
+	if wp.markdown {
+		// Markdown is emitted without a section header line so that it can be fed
+		// directly into a markdown splitter.
+		renderer, err := newMarkdownRenderer(doc, data, wp.embedImages)
+		if err != nil {
+			return nil, err
+		}
+		sections["main"] = renderer.render(doc)
+	} else {
+		var mainContentBuf bytes.Buffer
+		mainContentBuf.WriteString("=== MAIN CONTENT ===\n")
+		mainContent := wp.extractMainContent(doc)
+		mainContentBuf.WriteString(mainContent)
+		mainContentBuf.WriteString("\n")
+		sections["main"] = mainContentBuf.String()
+	}
 
 	// Extract comments if enabled
 	if wp.includeComments {
@@ -170,8 +197,8 @@ func (wp *DocxParser) extractContent(doc *document.Document) map[string]string {
 		}
 	}
 
-	// Extract table content if enabled
-	if wp.includeTables {
+	// Extract table content if enabled, tables are already part of the main content in Markdown mode
+	if wp.includeTables && !wp.markdown {
 		tables := wp.extractTables(doc)
 		if tables != "" {
 			var tableBuf bytes.Buffer
@@ -194,7 +221,7 @@ func (wp *DocxParser) extractContent(doc *document.Document) map[string]string {
 		}
 	}
 
-	return sections
+	return sections, nil
 }
 
 // extractComments extracts comments from the Docx document.
@@ -17,11 +17,20 @@
 package docx
 
 import (
+	"bytes"
 	"context"
-	"github.com/cloudwego/eino/components/document/parser"
-	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/png"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/carmel/gooxml/common"
+	"github.com/carmel/gooxml/document"
+	"github.com/carmel/gooxml/schema/soo/wml"
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDocxParser_Parse(t *testing.T) {
@@ -50,3 +59,174 @@ func TestDocxParser_Parse(t *testing.T) {
 
 	})
 }
+
+func TestDocxParser_Markdown(t *testing.T) {
+	ctx := context.Background()
+	data := newMarkdownTestDocx(t)
+
+	p, err := NewDocxParser(ctx, &Config{Markdown: true, IncludeTables: true})
+	assert.NoError(t, err)
+	docs, err := p.Parse(ctx, bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(docs))
+
+	expected := `# Report
+
+# Overview
+
+**Bold** text, *italic*, ~~gone~~ and [the **eino** repo](https://github.com/cloudwego/eino) with snake\_case.
+
+\#not a heading
+
+## Details
+
+- first
+    - nested
+- second
+
+1. one
+2. two
+    1. two.one
+3. three
+
+| Name | Value |
+| --- | --- |
+| a\|b | line1<br>line2 |
+|  | 2 |
+
+![a chart](media/image1.png)
+`
+	assert.Equal(t, expected, docs[0].Content)
+
+	t.Run("embed images", func(t *testing.T) {
+		p, err := NewDocxParser(ctx, &Config{Markdown: true, EmbedImages: true})
+		assert.NoError(t, err)
+		docs, err := p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.Contains(t, docs[0].Content, "![a chart](data:image/png;base64,")
+	})
+
+	t.Run("testdata", func(t *testing.T) {
+		f, err := os.Open("./examples/testdata/test_docx.docx")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewDocxParser(ctx, &Config{Markdown: true, ToSections: true, IncludeHeaders: true})
+		assert.NoError(t, err)
+		docs, err := p.Parse(ctx, f)
+		assert.NoError(t, err)
+		assert.True(t, len(docs) > 0)
+		typ, _ := GetSectionType(docs[0])
+		assert.Equal(t, "main", typ)
+		assert.False(t, strings.HasPrefix(docs[0].Content, "==="))
+	})
+}
+
+func newMarkdownTestDocx(t *testing.T) []byte {
+	doc := document.New()
+
+	heading1 := doc.Styles.AddStyle("Heading1", wml.ST_StyleTypeParagraph, false)
+	heading1.SetName("heading 1")
+	// a localized heading style is only recognized by its outline level.
+	heading2 := doc.Styles.AddStyle("Berschrift2", wml.ST_StyleTypeParagraph, false)
+	heading2.SetName("Überschrift 2")
+	heading2.X().PPr = wml.NewCT_PPrGeneral()
+	heading2.X().PPr.OutlineLvl = wml.NewCT_DecimalNumber()
+	heading2.X().PPr.OutlineLvl.ValAttr = 1
+	heading3 := doc.Styles.AddStyle("Heading3", wml.ST_StyleTypeParagraph, false)
+	heading3.SetName("heading 3")
+
+	para := func(style string, texts ...string) document.Paragraph {
+		p := doc.AddParagraph()
+		if style != "" {
+			p.SetStyle(style)
+		}
+		for _, text := range texts {
+			p.AddRun().AddText(text)
+		}
+		return p
+	}
+
+	para("Title", "Report")
+	para("Heading1", "Overview")
+
+	p := doc.AddParagraph()
+	r := p.AddRun()
+	r.Properties().SetBold(true)
+	r.AddText("Bold")
+	p.AddRun().AddText(" text, ")
+	r = p.AddRun()
+	r.Properties().SetItalic(true)
+	r.AddText("italic")
+	p.AddRun().AddText(", ")
+	r = p.AddRun()
+	r.Properties().SetStrikeThrough(true)
+	r.AddText("gone")
+	p.AddRun().AddText(" and ")
+	link := p.AddHyperLink()
+	link.SetTarget("https://github.com/cloudwego/eino")
+	link.AddRun().AddText("the ")
+	r = link.AddRun()
+	r.Properties().SetBold(true)
+	r.AddText("eino ")
+	link.AddRun().AddText("repo")
+	p.AddRun().AddText(" with snake_case.")
+
+	para("", "#not a heading")
+	para("Berschrift2", "Details")
+
+	listDef := func(format wml.ST_NumberFormat) document.NumberingDefinition {
+		def := doc.Numbering.AddDefinition()
+		for i := 0; i < 2; i++ {
+			lvl := def.AddLevel()
+			lvl.X().IlvlAttr = int64(i)
+			lvl.SetFormat(format)
+		}
+		return def
+	}
+	item := func(def document.NumberingDefinition, level int, text string) {
+		p := para("", text)
+		p.SetNumberingDefinition(def)
+		p.SetNumberingLevel(level)
+	}
+	bullets := listDef(wml.ST_NumberFormatBullet)
+	item(bullets, 0, "first")
+	item(bullets, 1, "nested")
+	item(bullets, 0, "second")
+	numbers := listDef(wml.ST_NumberFormatDecimal)
+	item(numbers, 0, "one")
+	item(numbers, 0, "two")
+	item(numbers, 1, "two.one")
+	item(numbers, 0, "three")
+
+	table := doc.AddTable()
+	row := table.AddRow()
+	row.AddCell().AddParagraph().AddRun().AddText("Name")
+	row.AddCell().AddParagraph().AddRun().AddText("Value")
+	row = table.AddRow()
+	row.AddCell().AddParagraph().AddRun().AddText("a|b")
+	cell := row.AddCell()
+	cell.AddParagraph().AddRun().AddText("line1")
+	cell.AddParagraph().AddRun().AddText("line2")
+	row = table.AddRow()
+	row.AddCell()
+	row.AddCell().AddParagraph().AddRun().AddText("2")
+
+	var imgBuf bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.Black)
+	assert.NoError(t, png.Encode(&imgBuf, img))
+	ci, err := common.ImageFromBytes(imgBuf.Bytes())
+	assert.NoError(t, err)
+	ref, err := doc.AddImage(ci)
+	assert.NoError(t, err)
+	inline, err := doc.AddParagraph().AddRun().AddDrawingInline(ref)
+	assert.NoError(t, err)
+	descr := "a chart"
+	inline.X().DocPr.DescrAttr = &descr
+
+	var buf bytes.Buffer
+	assert.NoError(t, doc.Save(&buf))
+	return buf.Bytes()
+}
@@ -0,0 +1,861 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/carmel/gooxml/document"
+	"github.com/carmel/gooxml/schema/soo/dml"
+	pic "github.com/carmel/gooxml/schema/soo/dml/picture"
+	"github.com/carmel/gooxml/schema/soo/ofc/sharedTypes"
+	"github.com/carmel/gooxml/schema/soo/wml"
+)
+
+const (
+	maxHeadingLevel = 6
+	maxStyleDepth   = 16
+	// outline level 9 is "body text" in WordprocessingML.
+	bodyTextOutlineLevel = 9
+)
+
+// markdownRenderer renders the main document body as Markdown, keeping headings,
+// lists, emphasis, hyperlinks, images and tables in document order.
+type markdownRenderer struct {
+	embedImages bool
+
+	styles     map[string]*wml.CT_Style
+	numFormats map[int64]map[int64]wml.ST_NumberFormat // numId -> ilvl -> format
+	rels       map[string]string                       // relationship id -> target
+	partDir    string                                  // directory of the main document part
+	files      map[string]*zip.File
+
+	// list item counters keyed by numId, one counter per level.
+	counters map[int64][]int
+}
+
+type relationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+type relationships struct {
+	Relationships []relationship `xml:"Relationship"`
+}
+
+func newMarkdownRenderer(doc *document.Document, data []byte, embedImages bool) (*markdownRenderer, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open Docx package failed: %w", err)
+	}
+
+	r := &markdownRenderer{
+		embedImages: embedImages,
+		styles:      make(map[string]*wml.CT_Style),
+		numFormats:  make(map[int64]map[int64]wml.ST_NumberFormat),
+		rels:        make(map[string]string),
+		partDir:     "word",
+		files:       make(map[string]*zip.File, len(zr.File)),
+		counters:    make(map[int64][]int),
+	}
+	for _, f := range zr.File {
+		r.files[f.Name] = f
+	}
+
+	// styles and numbering parts are optional.
+	if doc.Styles.X() != nil {
+		for _, s := range doc.Styles.Styles() {
+			if s.X().StyleIdAttr != nil {
+				r.styles[*s.X().StyleIdAttr] = s.X()
+			}
+		}
+	}
+	if doc.Numbering.X() != nil {
+		abstractFormats := make(map[int64]map[int64]wml.ST_NumberFormat)
+		for _, def := range doc.Numbering.Definitions() {
+			levels := make(map[int64]wml.ST_NumberFormat)
+			for _, lvl := range def.X().Lvl {
+				if lvl.NumFmt != nil {
+					levels[lvl.IlvlAttr] = lvl.NumFmt.ValAttr
+				}
+			}
+			abstractFormats[def.AbstractNumberID()] = levels
+		}
+		for _, num := range doc.Numbering.X().Num {
+			if num.AbstractNumId != nil {
+				r.numFormats[num.NumIdAttr] = abstractFormats[num.AbstractNumId.ValAttr]
+			}
+		}
+	}
+
+	if err = r.loadRelationships(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// loadRelationships resolves the relationships of the main document part, which gooxml
+// does not expose, so that hyperlink targets and image paths can be looked up by id.
+func (r *markdownRenderer) loadRelationships() error {
+	partName := "word/document.xml"
+	var root relationships
+	if ok, err := r.readXML("_rels/.rels", &root); err != nil {
+		return err
+	} else if ok {
+		for _, rel := range root.Relationships {
+			if strings.HasSuffix(rel.Type, "/officeDocument") {
+				partName = strings.TrimPrefix(rel.Target, "/")
+				break
+			}
+		}
+	}
+	r.partDir = path.Dir(partName)
+
+	var rels relationships
+	relsName := path.Join(r.partDir, "_rels", path.Base(partName)+".rels")
+	if _, err := r.readXML(relsName, &rels); err != nil {
+		return err
+	}
+	for _, rel := range rels.Relationships {
+		r.rels[rel.ID] = rel.Target
+	}
+	return nil
+}
+
+func (r *markdownRenderer) readXML(name string, v any) (bool, error) {
+	f, ok := r.files[name]
+	if !ok {
+		return false, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return false, fmt.Errorf("open %s failed: %w", name, err)
+	}
+	defer rc.Close()
+	if err = xml.NewDecoder(rc).Decode(v); err != nil {
+		return false, fmt.Errorf("decode %s failed: %w", name, err)
+	}
+	return true, nil
+}
+
+// render renders the document body as Markdown.
+func (r *markdownRenderer) render(doc *document.Document) string {
+	if doc.X().Body == nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	prevList := int64(0)
+	for _, block := range r.blocks(doc.X().Body.EG_BlockLevelElts) {
+		if block.text == "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			// items of the same list are kept together, anything else is a new block.
+			if block.list != 0 && block.list == prevList {
+				buf.WriteString("\n")
+			} else {
+				buf.WriteString("\n\n")
+			}
+		}
+		buf.WriteString(block.text)
+		prevList = block.list
+	}
+	if buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+type mdBlock struct {
+	text string
+	list int64 // numId of the list the block is an item of, 0 if it is not a list item
+}
+
+func (r *markdownRenderer) blocks(elts []*wml.EG_BlockLevelElts) []mdBlock {
+	var blocks []mdBlock
+	for _, elt := range elts {
+		for _, content := range elt.EG_ContentBlockContent {
+			blocks = append(blocks, r.contentBlocks(content.P, content.Tbl, content.Sdt)...)
+		}
+	}
+	return blocks
+}
+
+func (r *markdownRenderer) contentBlocks(paras []*wml.CT_P, tables []*wml.CT_Tbl, sdt *wml.CT_SdtBlock) []mdBlock {
+	var blocks []mdBlock
+	for _, p := range paras {
+		blocks = append(blocks, r.paragraph(p))
+	}
+	for _, tbl := range tables {
+		blocks = append(blocks, mdBlock{text: r.table(tbl)})
+	}
+	if sdt != nil && sdt.SdtContent != nil {
+		blocks = append(blocks, r.contentBlocks(sdt.SdtContent.P, sdt.SdtContent.Tbl, sdt.SdtContent.Sdt)...)
+	}
+	return blocks
+}
+
+// paragraph renders a paragraph as a heading, a list item or a plain paragraph.
+func (r *markdownRenderer) paragraph(p *wml.CT_P) mdBlock {
+	text := strings.TrimSpace(r.inline(p.EG_PContent))
+	if text == "" {
+		return mdBlock{}
+	}
+
+	if level := r.headingLevel(p.PPr); level > 0 {
+		text = strings.ReplaceAll(text, "\n", " ")
+		return mdBlock{text: strings.Repeat("#", level) + " " + text}
+	}
+
+	if numID, ilvl, ok := r.numbering(p.PPr); ok {
+		format, ok := r.numFormats[numID][ilvl]
+		if ok && format != wml.ST_NumberFormatNone {
+			indent := strings.Repeat("    ", int(ilvl))
+			marker := "- "
+			if format != wml.ST_NumberFormatBullet {
+				marker = strconv.Itoa(r.nextNumber(numID, ilvl)) + ". "
+			}
+			text = strings.ReplaceAll(escapeLineStarts(text), "\n", "\n"+indent+strings.Repeat(" ", len(marker)))
+			return mdBlock{text: indent + marker + text, list: numID}
+		}
+	}
+
+	return mdBlock{text: escapeLineStarts(text)}
+}
+
+// headingLevel returns the Markdown heading level of the paragraph, or 0 if it is not
+// a heading. Built-in "heading N" and "Title" styles are recognized by name, anything
+// else by its outline level, following the basedOn chain of the paragraph style.
+func (r *markdownRenderer) headingLevel(pPr *wml.CT_PPr) int {
+	if pPr == nil {
+		return 0
+	}
+	if pPr.OutlineLvl != nil {
+		return outlineToHeading(pPr.OutlineLvl.ValAttr)
+	}
+	if pPr.PStyle == nil {
+		return 0
+	}
+	styleID := pPr.PStyle.ValAttr
+	for i := 0; i < maxStyleDepth && styleID != ""; i++ {
+		style, ok := r.styles[styleID]
+		if !ok {
+			return 0
+		}
+		if style.Name != nil {
+			name := strings.ToLower(strings.TrimSpace(style.Name.ValAttr))
+			if name == "title" {
+				return 1
+			}
+			if level, ok := strings.CutPrefix(name, "heading "); ok {
+				if n, err := strconv.Atoi(level); err == nil && n > 0 {
+					return min(n, maxHeadingLevel)
+				}
+			}
+		}
+		if style.PPr != nil && style.PPr.OutlineLvl != nil {
+			return outlineToHeading(style.PPr.OutlineLvl.ValAttr)
+		}
+		styleID = ""
+		if style.BasedOn != nil {
+			styleID = style.BasedOn.ValAttr
+		}
+	}
+	return 0
+}
+
+func outlineToHeading(lvl int64) int {
+	if lvl < 0 || lvl >= bodyTextOutlineLevel {
+		return 0
+	}
+	return min(int(lvl)+1, maxHeadingLevel)
+}
+
+// numbering resolves the numbering instance and level of the paragraph from its own
+// properties, falling back to the ones inherited from its style.
+func (r *markdownRenderer) numbering(pPr *wml.CT_PPr) (numID, ilvl int64, ok bool) {
+	if pPr == nil {
+		return 0, 0, false
+	}
+	var numPrs []*wml.CT_NumPr
+	if pPr.NumPr != nil {
+		numPrs = append(numPrs, pPr.NumPr)
+	}
+	if pPr.PStyle != nil {
+		styleID := pPr.PStyle.ValAttr
+		for i := 0; i < maxStyleDepth && styleID != ""; i++ {
+			style, ok := r.styles[styleID]
+			if !ok {
+				break
+			}
+			if style.PPr != nil && style.PPr.NumPr != nil {
+				numPrs = append(numPrs, style.PPr.NumPr)
+			}
+			styleID = ""
+			if style.BasedOn != nil {
+				styleID = style.BasedOn.ValAttr
+			}
+		}
+	}
+
+	numID, ilvl = -1, -1
+	for _, numPr := range numPrs {
+		if numID < 0 && numPr.NumId != nil {
+			numID = numPr.NumId.ValAttr
+		}
+		if ilvl < 0 && numPr.Ilvl != nil {
+			ilvl = numPr.Ilvl.ValAttr
+		}
+	}
+	// numId 0 explicitly removes numbering.
+	if numID <= 0 {
+		return 0, 0, false
+	}
+	return numID, max(ilvl, 0), true
+}
+
+// nextNumber returns the number of the next item of an ordered list at the given level,
+// restarting the numbering of all deeper levels.
+func (r *markdownRenderer) nextNumber(numID, ilvl int64) int {
+	counters := r.counters[numID]
+	for int64(len(counters)) <= ilvl {
+		counters = append(counters, 0)
+	}
+	counters[ilvl]++
+	counters = counters[:ilvl+1]
+	r.counters[numID] = counters
+	return counters[ilvl]
+}
+
+// table renders a table as a pipe table whose first row is the header row. Horizontally
+// merged cells are padded with empty cells so that every row has the same width.
+func (r *markdownRenderer) table(tbl *wml.CT_Tbl) string {
+	var rows [][]string
+	width := 0
+	for _, rowContent := range tbl.EG_ContentRowContent {
+		for _, tr := range rowContent.Tr {
+			var cells []string
+			for _, cellContent := range tr.EG_ContentCellContent {
+				for _, tc := range cellContent.Tc {
+					text := ""
+					if tc.TcPr == nil || tc.TcPr.VMerge == nil || tc.TcPr.VMerge.ValAttr == wml.ST_MergeRestart {
+						text = r.cell(tc.EG_BlockLevelElts)
+					}
+					cells = append(cells, text)
+					if tc.TcPr != nil && tc.TcPr.GridSpan != nil {
+						for i := int64(1); i < tc.TcPr.GridSpan.ValAttr; i++ {
+							cells = append(cells, "")
+						}
+					}
+				}
+			}
+			if len(cells) == 0 {
+				continue
+			}
+			rows = append(rows, cells)
+			width = max(width, len(cells))
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	for i, row := range rows {
+		buf.WriteString("|")
+		for j := 0; j < width; j++ {
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			buf.WriteString(" " + cell + " |")
+		}
+		buf.WriteString("\n")
+		if i == 0 {
+			buf.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// cell renders the content of a table cell on a single line, nested tables included.
+func (r *markdownRenderer) cell(elts []*wml.EG_BlockLevelElts) string {
+	var parts []string
+	var collect func(paras []*wml.CT_P, tables []*wml.CT_Tbl, sdt *wml.CT_SdtBlock)
+	collect = func(paras []*wml.CT_P, tables []*wml.CT_Tbl, sdt *wml.CT_SdtBlock) {
+		for _, p := range paras {
+			if text := strings.TrimSpace(r.inline(p.EG_PContent)); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		for _, tbl := range tables {
+			for _, rowContent := range tbl.EG_ContentRowContent {
+				for _, tr := range rowContent.Tr {
+					for _, cellContent := range tr.EG_ContentCellContent {
+						for _, tc := range cellContent.Tc {
+							for _, elt := range tc.EG_BlockLevelElts {
+								for _, content := range elt.EG_ContentBlockContent {
+									collect(content.P, content.Tbl, content.Sdt)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+		if sdt != nil && sdt.SdtContent != nil {
+			collect(sdt.SdtContent.P, sdt.SdtContent.Tbl, sdt.SdtContent.Sdt)
+		}
+	}
+	for _, elt := range elts {
+		for _, content := range elt.EG_ContentBlockContent {
+			collect(content.P, content.Tbl, content.Sdt)
+		}
+	}
+
+	text := strings.Join(parts, "<br>")
+	text = strings.ReplaceAll(text, "\n", "<br>")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// inlineSpan is a piece of paragraph text sharing the same formatting and link target.
+type inlineSpan struct {
+	text   string
+	raw    bool // text is already Markdown, e.g. an image
+	bold   bool
+	italic bool
+	strike bool
+	link   string
+}
+
+// inlineBuilder collects the spans of a paragraph. Hyperlinks may be expressed either as
+// hyperlink elements or as HYPERLINK fields, so the link target is tracked as a stack.
+type inlineBuilder struct {
+	spans  []inlineSpan
+	links  []string
+	fields []*fieldState
+}
+
+type fieldState struct {
+	instr    strings.Builder
+	inResult bool
+	link     bool
+}
+
+func (b *inlineBuilder) link() string {
+	if len(b.links) == 0 {
+		return ""
+	}
+	return b.links[len(b.links)-1]
+}
+
+func (r *markdownRenderer) inline(contents []*wml.EG_PContent) string {
+	b := &inlineBuilder{}
+	r.pContents(b, contents)
+	return b.render()
+}
+
+func (r *markdownRenderer) pContents(b *inlineBuilder, contents []*wml.EG_PContent) {
+	for _, c := range contents {
+		r.runContainer(b, c.FldSimple, c.Hyperlink, c.EG_ContentRunContent)
+	}
+}
+
+// runContainer renders the content shared by paragraph content and its run-level
+// wrappers such as content controls and bidi embeddings.
+func (r *markdownRenderer) runContainer(b *inlineBuilder, flds []*wml.CT_SimpleField, h *wml.CT_Hyperlink, contents []*wml.EG_ContentRunContent) {
+	for _, fld := range flds {
+		r.simpleField(b, fld)
+	}
+	if h != nil {
+		r.hyperlink(b, h)
+	}
+	r.runContents(b, contents)
+}
+
+func (r *markdownRenderer) hyperlink(b *inlineBuilder, h *wml.CT_Hyperlink) {
+	target := ""
+	if h.IdAttr != nil {
+		target = r.rels[*h.IdAttr]
+	}
+	if h.AnchorAttr != nil && *h.AnchorAttr != "" {
+		target += "#" + *h.AnchorAttr
+	}
+	b.links = append(b.links, target)
+	r.runContainer(b, h.FldSimple, h.Hyperlink, h.EG_ContentRunContent)
+	b.links = b.links[:len(b.links)-1]
+}
+
+func (r *markdownRenderer) simpleField(b *inlineBuilder, fld *wml.CT_SimpleField) {
+	target, isLink := hyperlinkField(fld.InstrAttr)
+	if isLink {
+		b.links = append(b.links, target)
+	}
+	r.pContents(b, fld.EG_PContent)
+	if isLink {
+		b.links = b.links[:len(b.links)-1]
+	}
+}
+
+func (r *markdownRenderer) runContents(b *inlineBuilder, contents []*wml.EG_ContentRunContent) {
+	for _, c := range contents {
+		if c.R != nil {
+			r.run(b, c.R)
+		}
+		if c.SmartTag != nil {
+			r.pContents(b, c.SmartTag.EG_PContent)
+		}
+		if c.CustomXml != nil {
+			r.pContents(b, c.CustomXml.EG_PContent)
+		}
+		if c.Sdt != nil && c.Sdt.SdtContent != nil {
+			sc := c.Sdt.SdtContent
+			r.runContainer(b, sc.FldSimple, sc.Hyperlink, sc.EG_ContentRunContent)
+		}
+		if c.Dir != nil {
+			r.runContainer(b, c.Dir.FldSimple, c.Dir.Hyperlink, c.Dir.EG_ContentRunContent)
+		}
+		if c.Bdo != nil {
+			r.runContainer(b, c.Bdo.FldSimple, c.Bdo.Hyperlink, c.Bdo.EG_ContentRunContent)
+		}
+	}
+}
+
+func (r *markdownRenderer) run(b *inlineBuilder, run *wml.CT_R) {
+	var bold, italic, strike bool
+	if rPr := run.RPr; rPr != nil {
+		bold = onOff(rPr.B)
+		italic = onOff(rPr.I)
+		strike = onOff(rPr.Strike) || onOff(rPr.Dstrike)
+		if onOff(rPr.Vanish) {
+			return
+		}
+	}
+
+	write := func(text string, raw bool) {
+		// text between a field's begin and separate characters is its instruction.
+		if n := len(b.fields); n > 0 && !b.fields[n-1].inResult {
+			return
+		}
+		b.spans = append(b.spans, inlineSpan{
+			text:   text,
+			raw:    raw,
+			bold:   bold,
+			italic: italic,
+			strike: strike,
+			link:   b.link(),
+		})
+	}
+
+	for _, c := range run.EG_RunInnerContent {
+		switch {
+		case c.T != nil:
+			write(c.T.Content, false)
+		case c.Tab != nil, c.Ptab != nil:
+			write(" ", false)
+		case c.Br != nil:
+			if c.Br.TypeAttr != wml.ST_BrTypePage && c.Br.TypeAttr != wml.ST_BrTypeColumn {
+				write("\n", false)
+			}
+		case c.Cr != nil:
+			write("\n", false)
+		case c.NoBreakHyphen != nil:
+			write("-", false)
+		case c.Drawing != nil:
+			for _, inline := range c.Drawing.Inline {
+				if img := r.image(inline.DocPr, inline.Graphic); img != "" {
+					write(img, true)
+				}
+			}
+			for _, anchor := range c.Drawing.Anchor {
+				if img := r.image(anchor.DocPr, anchor.Graphic); img != "" {
+					write(img, true)
+				}
+			}
+		case c.InstrText != nil:
+			if n := len(b.fields); n > 0 {
+				b.fields[n-1].instr.WriteString(c.InstrText.Content)
+			}
+		case c.FldChar != nil:
+			r.fieldChar(b, c.FldChar.FldCharTypeAttr)
+		}
+	}
+}
+
+// fieldChar tracks complex fields so that only their results are rendered, and that the
+// result of a HYPERLINK field becomes a link.
+func (r *markdownRenderer) fieldChar(b *inlineBuilder, typ wml.ST_FldCharType) {
+	n := len(b.fields)
+	switch typ {
+	case wml.ST_FldCharTypeBegin:
+		b.fields = append(b.fields, &fieldState{})
+	case wml.ST_FldCharTypeSeparate:
+		if n == 0 {
+			return
+		}
+		f := b.fields[n-1]
+		f.inResult = true
+		if target, ok := hyperlinkField(f.instr.String()); ok {
+			f.link = true
+			b.links = append(b.links, target)
+		}
+	case wml.ST_FldCharTypeEnd:
+		if n == 0 {
+			return
+		}
+		if b.fields[n-1].link {
+			b.links = b.links[:len(b.links)-1]
+		}
+		b.fields = b.fields[:n-1]
+	}
+}
+
+// hyperlinkField parses a field instruction such as `HYPERLINK "https://example.com"` or
+// `HYPERLINK \l "bookmark"`.
+func hyperlinkField(instr string) (string, bool) {
+	fields := strings.Fields(instr)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "HYPERLINK") {
+		return "", false
+	}
+	var target, anchor string
+	for i := 1; i < len(fields); i++ {
+		arg := strings.Trim(fields[i], `"`)
+		switch {
+		case fields[i] == `\l` && i+1 < len(fields):
+			i++
+			anchor = strings.Trim(fields[i], `"`)
+		case strings.HasPrefix(arg, `\`):
+			// other switches like \o "tooltip" take an argument.
+			if i+1 < len(fields) && !strings.HasPrefix(fields[i+1], `\`) {
+				i++
+			}
+		case target == "":
+			target = arg
+		}
+	}
+	if anchor != "" {
+		target += "#" + anchor
+	}
+	return target, true
+}
+
+// image renders a drawing as a Markdown image, referencing the image by its path inside
+// the package or, if embedImages is set, by a base64 data URL.
+func (r *markdownRenderer) image(docPr *dml.CT_NonVisualDrawingProps, graphic *dml.Graphic) string {
+	if graphic == nil || graphic.GraphicData == nil {
+		return ""
+	}
+	var embed string
+	for _, a := range graphic.GraphicData.Any {
+		p, ok := a.(*pic.Pic)
+		if !ok || p.BlipFill == nil || p.BlipFill.Blip == nil {
+			continue
+		}
+		if p.BlipFill.Blip.EmbedAttr != nil {
+			embed = *p.BlipFill.Blip.EmbedAttr
+		} else if p.BlipFill.Blip.LinkAttr != nil {
+			embed = *p.BlipFill.Blip.LinkAttr
+		}
+		break
+	}
+	target, ok := r.rels[embed]
+	if !ok {
+		return ""
+	}
+
+	alt := ""
+	if docPr != nil {
+		switch {
+		case docPr.DescrAttr != nil && *docPr.DescrAttr != "":
+			alt = *docPr.DescrAttr
+		case docPr.TitleAttr != nil && *docPr.TitleAttr != "":
+			alt = *docPr.TitleAttr
+		default:
+			alt = docPr.NameAttr
+		}
+	}
+	alt = escapeMarkdown(strings.Join(strings.Fields(alt), " "))
+
+	url := target
+	if r.embedImages {
+		if dataURL, ok := r.dataURL(target); ok {
+			url = dataURL
+		}
+	}
+	return fmt.Sprintf("![%s](%s)", alt, strings.ReplaceAll(url, " ", "%20"))
+}
+
+func (r *markdownRenderer) dataURL(target string) (string, bool) {
+	name := strings.TrimPrefix(target, "/")
+	if !strings.HasPrefix(target, "/") {
+		name = path.Join(r.partDir, target)
+	}
+	f, ok := r.files[name]
+	if !ok {
+		return "", false
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", false
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", false
+	}
+
+	mimeType := mime.TypeByExtension(path.Ext(name))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), true
+}
+
+// render merges adjacent spans with the same formatting, so that runs split by Word
+// for bookkeeping reasons don't produce broken emphasis like **foo****bar**.
+func (b *inlineBuilder) render() string {
+	var buf strings.Builder
+	for i := 0; i < len(b.spans); {
+		link := b.spans[i].link
+		j := i
+		for j < len(b.spans) && b.spans[j].link == link {
+			j++
+		}
+		text := renderSpans(b.spans[i:j])
+		if link != "" && strings.TrimSpace(text) != "" {
+			lead, body, trail := splitSpace(text)
+			text = lead + "[" + body + "](" + strings.ReplaceAll(link, " ", "%20") + ")" + trail
+		}
+		buf.WriteString(text)
+		i = j
+	}
+	return buf.String()
+}
+
+func renderSpans(spans []inlineSpan) string {
+	var buf strings.Builder
+	for i := 0; i < len(spans); {
+		s := spans[i]
+		var text strings.Builder
+		j := i
+		for ; j < len(spans); j++ {
+			n := spans[j]
+			if n.raw != s.raw || n.bold != s.bold || n.italic != s.italic || n.strike != s.strike {
+				break
+			}
+			if n.raw {
+				text.WriteString(n.text)
+			} else {
+				text.WriteString(escapeMarkdown(n.text))
+			}
+		}
+		buf.WriteString(emphasize(text.String(), s.bold, s.italic, s.strike))
+		i = j
+	}
+	return buf.String()
+}
+
+// emphasize wraps text in emphasis markers, keeping surrounding whitespace outside of
+// them since CommonMark does not recognize emphasis like "** foo **". Line breaks end
+// emphasis, so each line is wrapped on its own.
+func emphasize(text string, bold, italic, strike bool) string {
+	if !bold && !italic && !strike {
+		return text
+	}
+	marker := ""
+	if bold {
+		marker += "**"
+	}
+	if italic {
+		marker += "*"
+	}
+	open, closing := marker, marker
+	if strike {
+		open, closing = "~~"+marker, marker+"~~"
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lead, body, trail := splitSpace(line)
+		if body != "" {
+			lines[i] = lead + open + body + closing + trail
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func splitSpace(s string) (lead, body, trail string) {
+	body = strings.TrimLeft(s, " \t")
+	lead = s[:len(s)-len(body)]
+	trimmed := strings.TrimRight(body, " \t")
+	trail = body[len(trimmed):]
+	return lead, trimmed, trail
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// escapeLineStarts escapes leading "#" so that plain paragraphs are not mistaken for
+// headings, e.g. by the markdown header splitter.
+func escapeLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimLeft(line, " "), "#") {
+			lines[i] = strings.Replace(line, "#", `\#`, 1)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// onOff reports whether a toggle property is on. An element without a value is on.
+func onOff(v *wml.CT_OnOff) bool {
+	if v == nil {
+		return false
+	}
+	if v.ValAttr == nil {
+		return true
+	}
+	if v.ValAttr.Bool != nil {
+		return *v.ValAttr.Bool
+	}
+	return v.ValAttr.ST_OnOff1 != sharedTypes.ST_OnOff1Off
+}
@@ -0,0 +1,350 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eml
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// MetaKeyIndex is the 0-based index of the message in the mbox, 0 for the eml.
+	MetaKeyIndex = "_index"
+	// MetaKeySubject, MetaKeyMessageID and MetaKeyInReplyTo are the decoded headers, set if not empty.
+	MetaKeySubject   = "_subject"
+	MetaKeyMessageID = "_message_id"
+	MetaKeyInReplyTo = "_in_reply_to"
+	// MetaKeyFrom is the sender as "name <address>", set if not empty.
+	MetaKeyFrom = "_from"
+	// MetaKeyTo and MetaKeyCc are the []string of the recipients as "name <address>", set if not empty.
+	MetaKeyTo = "_to"
+	MetaKeyCc = "_cc"
+	// MetaKeyDate is the date of the message in RFC 3339, set if valid.
+	MetaKeyDate = "_date"
+	// MetaKeyAttachments is the []*Attachment of the message, set if not empty.
+	MetaKeyAttachments = "_attachments"
+)
+
+var _ parser.Parser = (*Parser)(nil)
+
+// Attachment is an attachment of the message, the content is not kept.
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	// Size is the decoded size in bytes.
+	Size      int    `json:"size"`
+	ContentID string `json:"content_id,omitempty"`
+}
+
+// Config is the configuration for EML parser.
+type Config struct {
+	// PreferHTML uses the HTML body rather than the plain text body when a message has both
+	// Optional. Default: false
+	PreferHTML bool
+}
+
+// Parser implements parser.Parser. It parses RFC 5322 messages (eml) and mbox archives into a document per message.
+// The content is the text body of the message, and the HTML body is converted to text if there is no text body.
+// The headers and the attachments are kept in the metadata, the messages without body are skipped.
+type Parser struct {
+	conf *Config
+	dec  *mime.WordDecoder
+}
+
+// NewParser returns a new EML parser.
+func NewParser(ctx context.Context, config *Config) (*Parser, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Parser{
+		conf: config,
+		dec:  &mime.WordDecoder{CharsetReader: charset.NewReaderLabel},
+	}, nil
+}
+
+// Parse parses the eml or mbox content from the reader, mbox is detected by the leading "From " line.
+func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	commonOpts := parser.GetCommonOptions(nil, opts...)
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("eml parser read all from reader failed: %w", err)
+	}
+
+	messages := [][]byte{data}
+	if bytes.HasPrefix(data, []byte("From ")) {
+		messages = splitMbox(data)
+	}
+
+	var docs []*schema.Document
+	for i, raw := range messages {
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("eml parser read message %d failed: %w", i, err)
+		}
+
+		b := &body{}
+		if err = p.walk(textproto.MIMEHeader(msg.Header), msg.Body, b); err != nil {
+			return nil, fmt.Errorf("eml parser read body of message %d failed: %w", i, err)
+		}
+		content := b.content(p.conf.PreferHTML)
+		if content == "" {
+			continue
+		}
+
+		meta := make(map[string]any, len(commonOpts.ExtraMeta)+9)
+		for k, v := range commonOpts.ExtraMeta {
+			meta[k] = v
+		}
+		meta[MetaKeyIndex] = i
+		p.headerMeta(msg.Header, meta)
+		if len(b.attachments) > 0 {
+			meta[MetaKeyAttachments] = b.attachments
+		}
+
+		docs = append(docs, &schema.Document{
+			Content:  content,
+			MetaData: meta,
+		})
+	}
+
+	return docs, nil
+}
+
+func (p *Parser) headerMeta(h mail.Header, meta map[string]any) {
+	for key, header := range map[string]string{
+		MetaKeySubject:   "Subject",
+		MetaKeyMessageID: "Message-Id",
+		MetaKeyInReplyTo: "In-Reply-To",
+	} {
+		if v := p.decodeHeader(h.Get(header)); v != "" {
+			meta[key] = v
+		}
+	}
+
+	for key, header := range map[string]string{
+		MetaKeyFrom: "From",
+		MetaKeyTo:   "To",
+		MetaKeyCc:   "Cc",
+	} {
+		addrs := p.addresses(h.Get(header))
+		if len(addrs) == 0 {
+			continue
+		}
+		if key == MetaKeyFrom {
+			meta[key] = addrs[0]
+		} else {
+			meta[key] = addrs
+		}
+	}
+
+	if date, err := h.Date(); err == nil {
+		meta[MetaKeyDate] = date.Format(time.RFC3339)
+	}
+}
+
+func (p *Parser) decodeHeader(v string) string {
+	decoded, err := p.dec.DecodeHeader(v)
+	if err != nil {
+		decoded = v
+	}
+	return strings.TrimSpace(decoded)
+}
+
+// addresses returns the addresses in the header as "name <address>", or as the decoded header if it is malformed.
+func (p *Parser) addresses(v string) []string {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+	list, err := (&mail.AddressParser{WordDecoder: p.dec}).ParseList(v)
+	if err != nil {
+		return []string{p.decodeHeader(v)}
+	}
+	ret := make([]string, 0, len(list))
+	for _, addr := range list {
+		if addr.Name == "" {
+			ret = append(ret, addr.Address)
+		} else {
+			ret = append(ret, addr.Name+" <"+addr.Address+">")
+		}
+	}
+	return ret
+}
+
+type body struct {
+	plain       []string
+	html        []string
+	attachments []*Attachment
+}
+
+func (b *body) content(preferHTML bool) string {
+	parts := b.plain
+	if len(parts) == 0 || (preferHTML && len(b.html) > 0) {
+		parts = b.html
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n\n"))
+}
+
+// walk collects the text bodies and the attachments of the part recursively.
+func (p *Parser) walk(header textproto.MIMEHeader, r io.Reader, b *body) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err = p.walk(part.Header, part, b); err != nil {
+				return err
+			}
+		}
+	}
+
+	decoded := decodeTransfer(header.Get("Content-Transfer-Encoding"), r)
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if disposition == "attachment" || filename != "" || !isText {
+		size, err := io.Copy(io.Discard, decoded)
+		if err != nil {
+			return err
+		}
+		b.attachments = append(b.attachments, &Attachment{
+			Filename:    p.decodeHeader(filename),
+			ContentType: mediaType,
+			Size:        int(size),
+			ContentID:   strings.Trim(header.Get("Content-Id"), "<>"),
+		})
+		return nil
+	}
+
+	if cs := params["charset"]; cs != "" && !strings.EqualFold(cs, "utf-8") && !strings.EqualFold(cs, "us-ascii") {
+		if decoded, err = charset.NewReaderLabel(cs, decoded); err != nil {
+			return err
+		}
+	}
+	if mediaType == "text/html" {
+		doc, err := html.Parse(decoded)
+		if err != nil {
+			return err
+		}
+		if t := htmlText(doc); t != "" {
+			b.html = append(b.html, t)
+		}
+		return nil
+	}
+
+	data, err := io.ReadAll(decoded)
+	if err != nil {
+		return err
+	}
+	if t := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")); t != "" {
+		b.plain = append(b.plain, t)
+	}
+	return nil
+}
+
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// base64Cleaner drops the characters out of the base64 alphabet, e.g. the spaces that some clients append to the lines.
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (c *base64Cleaner) Read(p []byte) (int, error) {
+	for {
+		n, err := c.r.Read(p)
+		j := 0
+		for _, ch := range p[:n] {
+			if ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '+' || ch == '/' || ch == '=' {
+				p[j] = ch
+				j++
+			}
+		}
+		if j > 0 || err != nil {
+			return j, err
+		}
+	}
+}
+
+// splitMbox splits the mbox into the messages by the "From " lines, and unescapes the ">From " lines.
+func splitMbox(data []byte) [][]byte {
+	var (
+		messages [][]byte
+		cur      *bytes.Buffer
+		blank    = true
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if blank && bytes.HasPrefix(line, []byte("From ")) {
+			if cur != nil {
+				messages = append(messages, cur.Bytes())
+			}
+			cur = &bytes.Buffer{}
+			blank = false
+			continue
+		}
+		blank = len(bytes.TrimRight(line, "\r")) == 0
+		if trimmed := bytes.TrimLeft(line, ">"); len(trimmed) < len(line) && bytes.HasPrefix(trimmed, []byte("From ")) {
+			line = line[1:]
+		}
+		cur.Write(line)
+		cur.WriteByte('\n')
+	}
+	if cur != nil {
+		messages = append(messages, cur.Bytes())
+	}
+	return messages
+}
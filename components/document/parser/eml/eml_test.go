@@ -0,0 +1,120 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eml
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/document/parser"
+)
+
+func TestParser_EML(t *testing.T) {
+	ctx := context.Background()
+	f, err := os.Open("./testdata/report.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	p, err := NewParser(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := p.Parse(ctx, f, parser.WithExtraMeta(map[string]any{"k": "v"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("got %d docs", len(docs))
+	}
+
+	if want := "Hi Wang,\n\nPlease find the draft attached. Revenue grew – see page 2. \nThanks"; docs[0].Content != want {
+		t.Fatalf("got content %q", docs[0].Content)
+	}
+	wantMeta := map[string]any{
+		"k":              "v",
+		MetaKeyIndex:     0,
+		MetaKeySubject:   "Q3 report – draft",
+		MetaKeyFrom:      "李华 <lihua@example.com>",
+		MetaKeyTo:        []string{"Wang Wei <wangwei@example.com>", "bob@example.com"},
+		MetaKeyCc:        []string{"carol@example.com"},
+		MetaKeyDate:      "2025-07-01T10:30:00+08:00",
+		MetaKeyMessageID: "<abc123@example.com>",
+		MetaKeyInReplyTo: "<xyz789@example.com>",
+		MetaKeyAttachments: []*Attachment{
+			{Filename: "report.pdf", ContentType: "application/pdf", Size: 25},
+			{Filename: "图.png", ContentType: "image/png", Size: 6, ContentID: "img1@example.com"},
+		},
+	}
+	if !reflect.DeepEqual(docs[0].MetaData, wantMeta) {
+		t.Fatalf("got meta %v", docs[0].MetaData)
+	}
+
+	p, err = NewParser(ctx, &Config{PreferHTML: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	docs, err = p.Parse(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hi Wang,\nPlease find the draft attached."; docs[0].Content != want {
+		t.Fatalf("got content %q", docs[0].Content)
+	}
+}
+
+func TestParser_MBox(t *testing.T) {
+	ctx := context.Background()
+	data, err := os.ReadFile("./testdata/archive.mbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewParser(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ep, err := parser.NewExtParser(ctx, &parser.ExtParserConfig{
+		Parsers: map[string]parser.Parser{".eml": p, ".mbox": p},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := ep.Parse(ctx, strings.NewReader(string(data)), parser.WithURI("archive.mbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("got %d docs", len(docs))
+	}
+	if docs[0].Content != "你好\n世界" || docs[0].MetaData[MetaKeySubject] != "first" || docs[0].MetaData[MetaKeyIndex] != 0 {
+		t.Fatalf("got doc %v", docs[0])
+	}
+	if docs[1].Content != "Quoted line:\nFrom the archive\nbye" || docs[1].MetaData[MetaKeyIndex] != 1 {
+		t.Fatalf("got doc %v", docs[1])
+	}
+	if _, ok := docs[1].MetaData[MetaKeyAttachments]; ok {
+		t.Fatal("unexpected attachments")
+	}
+}
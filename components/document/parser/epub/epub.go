@@ -0,0 +1,368 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+	"golang.org/x/net/html"
+)
+
+const (
+	// MetaKeyChapter is the 1-based position of the chapter in the reading order of the book.
+	MetaKeyChapter = "_chapter"
+	// MetaKeyChapterTitle is the title of the chapter in the table of contents, or the first heading of the chapter, set if not empty.
+	MetaKeyChapterTitle = "_chapter_title"
+	// MetaKeyHref is the path of the chapter file in the book.
+	MetaKeyHref = "_href"
+	// MetaKeyTitle, MetaKeyAuthor and MetaKeyLanguage are from the metadata of the book, set if not empty.
+	MetaKeyTitle    = "_title"
+	MetaKeyAuthor   = "_author"
+	MetaKeyLanguage = "_language"
+)
+
+var _ parser.Parser = (*Parser)(nil)
+
+// Config is the configuration for EPUB parser.
+type Config struct {
+	// IncludeNonLinear includes the auxiliary content out of the reading order, e.g. the footnotes and answer keys
+	// Optional. Default: false
+	IncludeNonLinear bool
+}
+
+// Parser implements parser.Parser. It parses EPUB 2 and EPUB 3 e-books into a document per chapter file
+// in the reading order, the chapter titles are from the table of contents, and the files without text are skipped.
+type Parser struct {
+	conf *Config
+}
+
+// NewParser returns a new EPUB parser.
+func NewParser(ctx context.Context, config *Config) (*Parser, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Parser{conf: config}, nil
+}
+
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type packageDocument struct {
+	Metadata struct {
+		Titles    []string `xml:"title"`
+		Creators  []string `xml:"creator"`
+		Languages []string `xml:"language"`
+	} `xml:"metadata"`
+	Manifest []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+	Spine struct {
+		Toc      string `xml:"toc,attr"`
+		ItemRefs []struct {
+			IDRef  string `xml:"idref,attr"`
+			Linear string `xml:"linear,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+type manifestItem struct {
+	path      string
+	mediaType string
+}
+
+// Parse parses the EPUB content from the reader.
+func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	commonOpts := parser.GetCommonOptions(nil, opts...)
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("epub parser read all from reader failed: %w", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("epub parser open zip failed: %w", err)
+	}
+	book := &book{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		book.files[f.Name] = f
+	}
+
+	var c container
+	if err = book.decode("META-INF/container.xml", &c); err != nil {
+		return nil, fmt.Errorf("epub parser read container failed: %w", err)
+	}
+	if len(c.Rootfiles) == 0 {
+		return nil, fmt.Errorf("epub parser read container failed: no rootfile")
+	}
+	opfPath := c.Rootfiles[0].FullPath
+	var pkg packageDocument
+	if err = book.decode(opfPath, &pkg); err != nil {
+		return nil, fmt.Errorf("epub parser read package document failed: %w", err)
+	}
+
+	items := make(map[string]*manifestItem, len(pkg.Manifest))
+	var navPath string
+	for _, item := range pkg.Manifest {
+		items[item.ID] = &manifestItem{path: resolve(opfPath, item.Href), mediaType: item.MediaType}
+		if navPath == "" && hasProperty(item.Properties, "nav") {
+			navPath = items[item.ID].path
+		}
+	}
+
+	// prefer the EPUB 3 navigation document, then the EPUB 2 NCX
+	var titles map[string]string
+	if navPath != "" {
+		titles, err = book.navTitles(navPath)
+	} else if ncx, ok := items[pkg.Spine.Toc]; ok {
+		titles, err = book.ncxTitles(ncx.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("epub parser read table of contents failed: %w", err)
+	}
+
+	bookMeta := map[string]string{
+		MetaKeyTitle:    first(pkg.Metadata.Titles),
+		MetaKeyAuthor:   strings.Join(trimAll(pkg.Metadata.Creators), ", "),
+		MetaKeyLanguage: first(pkg.Metadata.Languages),
+	}
+
+	var docs []*schema.Document
+	for i, ref := range pkg.Spine.ItemRefs {
+		if ref.Linear == "no" && !p.conf.IncludeNonLinear {
+			continue
+		}
+		item, ok := items[ref.IDRef]
+		if !ok || (item.mediaType != "application/xhtml+xml" && item.mediaType != "text/html") {
+			continue
+		}
+
+		content, heading, err := book.chapterText(item.path)
+		if err != nil {
+			return nil, fmt.Errorf("epub parser read chapter %s failed: %w", item.path, err)
+		}
+		if content == "" {
+			continue
+		}
+
+		meta := make(map[string]any, len(commonOpts.ExtraMeta)+6)
+		for k, v := range commonOpts.ExtraMeta {
+			meta[k] = v
+		}
+		for k, v := range bookMeta {
+			if v != "" {
+				meta[k] = v
+			}
+		}
+		meta[MetaKeyChapter] = i + 1
+		meta[MetaKeyHref] = item.path
+		title := titles[item.path]
+		if title == "" {
+			title = heading
+		}
+		if title != "" {
+			meta[MetaKeyChapterTitle] = title
+		}
+
+		docs = append(docs, &schema.Document{
+			Content:  content,
+			MetaData: meta,
+		})
+	}
+
+	return docs, nil
+}
+
+type book struct {
+	files map[string]*zip.File
+}
+
+func (b *book) open(name string) (io.ReadCloser, error) {
+	f, ok := b.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return f.Open()
+}
+
+func (b *book) decode(name string, v any) error {
+	rc, err := b.open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+func (b *book) parseHTML(name string) (*html.Node, error) {
+	rc, err := b.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return html.Parse(rc)
+}
+
+// chapterText returns the text of the chapter file, and its first heading.
+func (b *book) chapterText(name string) (string, string, error) {
+	doc, err := b.parseHTML(name)
+	if err != nil {
+		return "", "", err
+	}
+	body := findElement(doc, func(n *html.Node) bool { return n.Data == "body" })
+	if body == nil {
+		return "", "", nil
+	}
+
+	var heading string
+	if h := findElement(body, isHeading); h != nil {
+		heading = text(h)
+	}
+	return text(body), heading, nil
+}
+
+// navTitles reads the titles of the files from the toc nav of the EPUB 3 navigation document.
+func (b *book) navTitles(name string) (map[string]string, error) {
+	doc, err := b.parseHTML(name)
+	if err != nil {
+		return nil, err
+	}
+
+	nav := findElement(doc, func(n *html.Node) bool {
+		return n.Data == "nav" && hasProperty(attr(n, "epub:type"), "toc")
+	})
+	if nav == nil {
+		nav = findElement(doc, func(n *html.Node) bool { return n.Data == "nav" })
+	}
+	if nav == nil {
+		return nil, nil
+	}
+
+	titles := map[string]string{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			addTitle(titles, resolve(name, attr(n, "href")), text(n))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(nav)
+	return titles, nil
+}
+
+type navPoint struct {
+	Label   string `xml:"navLabel>text"`
+	Content struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+	Children []navPoint `xml:"navPoint"`
+}
+
+// ncxTitles reads the titles of the files from the EPUB 2 NCX.
+func (b *book) ncxTitles(name string) (map[string]string, error) {
+	var ncx struct {
+		NavPoints []navPoint `xml:"navMap>navPoint"`
+	}
+	if err := b.decode(name, &ncx); err != nil {
+		return nil, err
+	}
+
+	titles := map[string]string{}
+	var walk func(points []navPoint)
+	walk = func(points []navPoint) {
+		for _, np := range points {
+			addTitle(titles, resolve(name, np.Content.Src), np.Label)
+			walk(np.Children)
+		}
+	}
+	walk(ncx.NavPoints)
+	return titles, nil
+}
+
+// addTitle keeps the first title of a file, the following ones are usually the sections in the file.
+func addTitle(titles map[string]string, file, title string) {
+	title = strings.Join(strings.Fields(title), " ")
+	if file == "" || title == "" {
+		return
+	}
+	if _, ok := titles[file]; !ok {
+		titles[file] = title
+	}
+}
+
+// resolve resolves the href relative to the file into the path in the book, without the fragment.
+func resolve(base, href string) string {
+	if i := strings.IndexByte(href, '#'); i >= 0 {
+		href = href[:i]
+	}
+	if href == "" {
+		return ""
+	}
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	if strings.HasPrefix(href, "/") {
+		return strings.TrimPrefix(path.Clean(href), "/")
+	}
+	return path.Join(path.Dir(base), href)
+}
+
+func hasProperty(properties, property string) bool {
+	for _, p := range strings.Fields(properties) {
+		if p == property {
+			return true
+		}
+	}
+	return false
+}
+
+func first(values []string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func trimAll(values []string) []string {
+	ret := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
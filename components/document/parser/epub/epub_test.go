@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/document/parser"
+)
+
+func xhtml(body string) string {
+	return `<?xml version="1.0" encoding="utf-8"?><html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">` +
+		`<head><title>Ignored</title><style>p { color: red; }</style></head><body>` + body + `</body></html>`
+}
+
+func buildEPUB(t *testing.T, epub3 bool) []byte {
+	manifest := `<item id="cover" href="Text/cover.xhtml" media-type="application/xhtml+xml"/>` +
+		`<item id="c1" href="Text/chapter%201.xhtml" media-type="application/xhtml+xml"/>` +
+		`<item id="c2" href="Text/chapter2.xhtml" media-type="application/xhtml+xml"/>` +
+		`<item id="notes" href="Text/notes.xhtml" media-type="application/xhtml+xml"/>` +
+		`<item id="img" href="Images/cover.jpg" media-type="image/jpeg"/>`
+	spine := `<spine toc="ncx"><itemref idref="cover"/><itemref idref="c1"/><itemref idref="c2"/><itemref idref="notes" linear="no"/></spine>`
+
+	files := map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">` +
+			`<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles></container>`,
+		"OEBPS/Text/cover.xhtml":     xhtml(`<div><img src="../Images/cover.jpg"/></div>`),
+		"OEBPS/Text/chapter 1.xhtml": xhtml(`<h1>One</h1><p>It was a   bright <em>cold</em> day.</p><script>var x;</script><ul><li>a</li><li>b</li></ul>`),
+		"OEBPS/Text/chapter2.xhtml":  xhtml(`<section><h2>The Second</h2><p>Line one<br/>line two</p></section>`),
+		"OEBPS/Text/notes.xhtml":     xhtml(`<p>Footnote.</p>`),
+		"OEBPS/Images/cover.jpg":     "jpeg",
+	}
+	if epub3 {
+		manifest += `<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>`
+		files["OEBPS/nav.xhtml"] = xhtml(`<nav epub:type="landmarks"><ol><li><a href="Text/chapter2.xhtml">Wrong</a></li></ol></nav>` +
+			`<nav epub:type="toc"><ol><li><a href="Text/chapter%201.xhtml#start">Chapter  One</a>` +
+			`<ol><li><a href="Text/chapter%201.xhtml#s2">Section</a></li></ol></li></ol></nav>`)
+	} else {
+		manifest += `<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>`
+		files["OEBPS/toc.ncx"] = `<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1"><navMap>` +
+			`<navPoint id="n1"><navLabel><text>Chapter One</text></navLabel><content src="Text/chapter%201.xhtml"/>` +
+			`<navPoint id="n2"><navLabel><text>Section</text></navLabel><content src="Text/chapter%201.xhtml#s2"/></navPoint></navPoint>` +
+			`</navMap></ncx>`
+	}
+	files["OEBPS/content.opf"] = `<package xmlns="http://www.idpf.org/2007/opf" version="3.0">` +
+		`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>The Book</dc:title>` +
+		`<dc:creator>Li Hua</dc:creator><dc:creator>Wang Wei</dc:creator><dc:language>en</dc:language></metadata>` +
+		`<manifest>` + manifest + `</manifest>` + spine + `</package>`
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParser(t *testing.T) {
+	ctx := context.Background()
+
+	for _, epub3 := range []bool{true, false} {
+		p, err := NewParser(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, bytes.NewReader(buildEPUB(t, epub3)), parser.WithExtraMeta(map[string]any{"k": "v"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+
+		if want := "One\nIt was a bright cold day.\na\nb"; docs[0].Content != want {
+			t.Fatalf("got content %q", docs[0].Content)
+		}
+		meta := docs[0].MetaData
+		if meta[MetaKeyChapter] != 2 || meta[MetaKeyChapterTitle] != "Chapter One" || meta[MetaKeyHref] != "OEBPS/Text/chapter 1.xhtml" {
+			t.Fatalf("got meta %v", meta)
+		}
+		if meta[MetaKeyTitle] != "The Book" || meta[MetaKeyAuthor] != "Li Hua, Wang Wei" || meta[MetaKeyLanguage] != "en" || meta["k"] != "v" {
+			t.Fatalf("got meta %v", meta)
+		}
+
+		if docs[1].Content != "The Second\nLine one\nline two" || docs[1].MetaData[MetaKeyChapterTitle] != "The Second" {
+			t.Fatalf("got doc %v", docs[1])
+		}
+	}
+
+	p, err := NewParser(ctx, &Config{IncludeNonLinear: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := p.Parse(ctx, bytes.NewReader(buildEPUB(t, true)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 || docs[2].Content != "Footnote." || docs[2].MetaData[MetaKeyChapter] != 4 {
+		t.Fatalf("got docs %v", docs)
+	}
+
+	if _, err = p.Parse(ctx, strings.NewReader("not a zip")); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,415 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// MetaKeyPath is the JSON path of the record, e.g. "$.items[3]", relative to the line for JSONL.
+	MetaKeyPath = "_json_path"
+	// MetaKeyLine is the 1-based line number of the record, only set for JSONL.
+	MetaKeyLine = "_line"
+)
+
+// Format is the format of the content.
+type Format string
+
+const (
+	// FormatAuto detects the format by the extension of the URI, then by the content.
+	FormatAuto Format = ""
+	// FormatJSON is a single JSON value.
+	FormatJSON Format = "json"
+	// FormatJSONL is a JSON value per line, aka. NDJSON.
+	FormatJSONL Format = "jsonl"
+)
+
+var _ parser.Parser = (*Parser)(nil)
+
+// Config is the configuration for JSON parser.
+type Config struct {
+	// Format is the format of the content
+	// Optional. Default: FormatAuto, ".jsonl" and ".ndjson" are JSONL, ".json" is JSON,
+	// otherwise JSONL if the content has more than one value
+	Format Format
+	// RecordPath is the path of the records, e.g. "$.data.items", each element is a record if it is an array
+	// Optional. Default: "$", i.e. the elements of the top-level array, or the top-level value
+	RecordPath string
+	// ContentKeys are the paths relative to the record whose values make up the content, e.g. "title", "body.text",
+	// the string values are kept as is, and the others are flattened
+	// Optional. Default: the whole record, flattened into a "path: value" line per scalar value
+	ContentKeys []string
+	// MetaKeys are the paths relative to the record whose values are copied into the metadata by the paths
+	// Optional. Default: none
+	MetaKeys []string
+}
+
+// Parser implements parser.Parser. It parses JSON and JSONL content into a document per record.
+type Parser struct {
+	conf        *Config
+	recordPath  []any
+	contentKeys [][]any
+	metaKeys    [][]any
+}
+
+// NewParser returns a new JSON parser.
+func NewParser(ctx context.Context, config *Config) (*Parser, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	switch config.Format {
+	case FormatAuto, FormatJSON, FormatJSONL:
+	default:
+		return nil, fmt.Errorf("unknown format: %s", config.Format)
+	}
+
+	p := &Parser{conf: config}
+	var err error
+	if p.recordPath, err = parsePath(config.RecordPath); err != nil {
+		return nil, err
+	}
+	if p.contentKeys, err = parsePaths(config.ContentKeys); err != nil {
+		return nil, err
+	}
+	if p.metaKeys, err = parsePaths(config.MetaKeys); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type record struct {
+	path string
+	line int
+	raw  json.RawMessage
+}
+
+// Parse parses the JSON or JSONL content from the reader.
+func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	commonOpts := parser.GetCommonOptions(nil, opts...)
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("json parser read all from reader failed: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var records []*record
+	if p.format(commonOpts.URI, data) == FormatJSONL {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data)+1)
+		for line := 1; scanner.Scan(); line++ {
+			b := bytes.TrimSpace(scanner.Bytes())
+			if len(b) == 0 {
+				continue
+			}
+			rs, err := p.records(b)
+			if err != nil {
+				return nil, fmt.Errorf("json parser parse line %d failed: %w", line, err)
+			}
+			for _, r := range rs {
+				r.line = line
+			}
+			records = append(records, rs...)
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, fmt.Errorf("json parser scan lines failed: %w", err)
+		}
+	} else {
+		records, err = p.records(bytes.TrimSpace(data))
+		if err != nil {
+			return nil, fmt.Errorf("json parser parse failed: %w", err)
+		}
+	}
+
+	docs := make([]*schema.Document, 0, len(records))
+	for _, r := range records {
+		content, err := p.content(r.raw)
+		if err != nil {
+			return nil, fmt.Errorf("json parser render %s failed: %w", r.path, err)
+		}
+		if content == "" {
+			continue
+		}
+
+		meta := make(map[string]any, len(commonOpts.ExtraMeta)+len(p.metaKeys)+2)
+		for k, v := range commonOpts.ExtraMeta {
+			meta[k] = v
+		}
+		for i, key := range p.metaKeys {
+			raw, ok := lookup(r.raw, key)
+			if !ok {
+				continue
+			}
+			var v any
+			if err = json.Unmarshal(raw, &v); err != nil {
+				return nil, err
+			}
+			meta[p.conf.MetaKeys[i]] = v
+		}
+		meta[MetaKeyPath] = r.path
+		if r.line > 0 {
+			meta[MetaKeyLine] = r.line
+		}
+
+		docs = append(docs, &schema.Document{
+			Content:  content,
+			MetaData: meta,
+		})
+	}
+
+	return docs, nil
+}
+
+func (p *Parser) format(uri string, data []byte) Format {
+	if p.conf.Format != FormatAuto {
+		return p.conf.Format
+	}
+	switch strings.ToLower(filepath.Ext(uri)) {
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".json":
+		return FormatJSON
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var first json.RawMessage
+	if err := dec.Decode(&first); err != nil {
+		return FormatJSON
+	}
+	if _, err := dec.Token(); errors.Is(err, io.EOF) {
+		return FormatJSON
+	}
+	return FormatJSONL
+}
+
+// records returns the records at the record path of the value.
+func (p *Parser) records(data []byte) ([]*record, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("invalid json")
+	}
+	raw, ok := lookup(data, p.recordPath)
+	if !ok {
+		return nil, fmt.Errorf("record path %s not found", formatPath(p.recordPath))
+	}
+	path := formatPath(p.recordPath)
+
+	if raw[0] != '[' {
+		return []*record{{path: path, raw: raw}}, nil
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, err
+	}
+	records := make([]*record, 0, len(elems))
+	for i, elem := range elems {
+		records = append(records, &record{path: fmt.Sprintf("%s[%d]", path, i), raw: elem})
+	}
+	return records, nil
+}
+
+func (p *Parser) content(raw json.RawMessage) (string, error) {
+	if len(p.contentKeys) == 0 {
+		lines, err := flatten(raw, "")
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	var parts []string
+	for i, key := range p.contentKeys {
+		v, ok := lookup(raw, key)
+		if !ok {
+			continue
+		}
+		var s string
+		if v[0] == '"' {
+			if err := json.Unmarshal(v, &s); err != nil {
+				return "", err
+			}
+		} else {
+			lines, err := flatten(v, p.conf.ContentKeys[i])
+			if err != nil {
+				return "", err
+			}
+			s = strings.Join(lines, "\n")
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// flatten renders the value into a "path: value" line per scalar value in order, the scalar value itself for the empty prefix.
+func flatten(raw json.RawMessage, prefix string) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var lines []string
+	err := flattenValue(dec, prefix, &lines)
+	return lines, err
+}
+
+func flattenValue(dec *json.Decoder, path string, lines *[]string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				sub := key.(string)
+				if path != "" {
+					sub = path + "." + sub
+				}
+				if err = flattenValue(dec, sub, lines); err != nil {
+					return err
+				}
+			}
+		} else {
+			for i := 0; dec.More(); i++ {
+				if err = flattenValue(dec, fmt.Sprintf("%s[%d]", path, i), lines); err != nil {
+					return err
+				}
+			}
+		}
+		// consume the closing delimiter
+		_, err = dec.Token()
+		return err
+	case string:
+		if strings.TrimSpace(t) == "" {
+			return nil
+		}
+		appendLine(lines, path, t)
+	case nil:
+		appendLine(lines, path, "null")
+	default:
+		appendLine(lines, path, fmt.Sprint(t))
+	}
+	return nil
+}
+
+func appendLine(lines *[]string, path, value string) {
+	if path == "" {
+		*lines = append(*lines, value)
+		return
+	}
+	*lines = append(*lines, path+": "+value)
+}
+
+// lookup returns the value at the path, the path is a list of the object keys and the array indexes.
+func lookup(raw json.RawMessage, path []any) (json.RawMessage, bool) {
+	for _, seg := range path {
+		switch s := seg.(type) {
+		case string:
+			var obj map[string]json.RawMessage
+			if json.Unmarshal(raw, &obj) != nil {
+				return nil, false
+			}
+			v, ok := obj[s]
+			if !ok {
+				return nil, false
+			}
+			raw = v
+		case int:
+			var arr []json.RawMessage
+			if json.Unmarshal(raw, &arr) != nil || s >= len(arr) {
+				return nil, false
+			}
+			raw = arr[s]
+		}
+	}
+	raw = bytes.TrimSpace(raw)
+	return raw, len(raw) > 0
+}
+
+func parsePaths(paths []string) ([][]any, error) {
+	ret := make([][]any, 0, len(paths))
+	for _, path := range paths {
+		p, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// parsePath parses the path like "$.data.items[0].title", the leading "$" and "." are optional.
+func parsePath(path string) ([]any, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var segs []any
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", path)
+			}
+			idx, err := strconv.Atoi(s[1:end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, s[1:end])
+			}
+			segs = append(segs, idx)
+			s = s[end+1:]
+		default:
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			segs = append(segs, s[:end])
+			s = s[end:]
+		}
+	}
+	return segs, nil
+}
+
+func formatPath(path []any) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, seg := range path {
+		switch s := seg.(type) {
+		case string:
+			sb.WriteString(".")
+			sb.WriteString(s)
+		case int:
+			fmt.Fprintf(&sb, "[%d]", s)
+		}
+	}
+	return sb.String()
+}
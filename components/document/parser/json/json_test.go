@@ -0,0 +1,131 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/document/parser"
+)
+
+func TestParser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("top-level array", func(t *testing.T) {
+		p, err := NewParser(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := `[{"title": "a", "tags": ["x", "y"], "n": 1.50, "meta": {"ok": true, "none": null, "empty": ""}}, "plain", {}]`
+		docs, err := p.Parse(ctx, strings.NewReader(input), parser.WithExtraMeta(map[string]any{"k": "v"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+		if want := "title: a\ntags[0]: x\ntags[1]: y\nn: 1.50\nmeta.ok: true\nmeta.none: null"; docs[0].Content != want {
+			t.Fatalf("got content %q", docs[0].Content)
+		}
+		if docs[1].Content != "plain" || docs[1].MetaData[MetaKeyPath] != "$[1]" || docs[1].MetaData["k"] != "v" {
+			t.Fatalf("got doc %v", docs[1])
+		}
+		if _, ok := docs[1].MetaData[MetaKeyLine]; ok {
+			t.Fatal("unexpected line meta")
+		}
+	})
+
+	t.Run("record path and keys", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{
+			RecordPath:  "$.data.items",
+			ContentKeys: []string{"title", "body", "missing"},
+			MetaKeys:    []string{"id", "author.name"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := `{"data": {"items": [{"id": 7, "title": "Hello", "body": {"text": "World"}, "author": {"name": "li"}}]}}`
+		docs, err := p.Parse(ctx, strings.NewReader(input), parser.WithURI("dump.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 1 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+		if want := "Hello\nbody.text: World"; docs[0].Content != want {
+			t.Fatalf("got content %q", docs[0].Content)
+		}
+		wantMeta := map[string]any{MetaKeyPath: "$.data.items[0]", "id": float64(7), "author.name": "li"}
+		if !reflect.DeepEqual(docs[0].MetaData, wantMeta) {
+			t.Fatalf("got meta %v", docs[0].MetaData)
+		}
+
+		if _, err = p.Parse(ctx, strings.NewReader(`{"data": {}}`)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{ContentKeys: []string{"text"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := "{\"text\": \"first\"}\n\n{\"text\": \"second\"}\n"
+		for _, uri := range []string{"", "a.jsonl"} {
+			docs, err := p.Parse(ctx, strings.NewReader(input), parser.WithURI(uri))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(docs) != 2 || docs[1].Content != "second" || docs[1].MetaData[MetaKeyLine] != 3 || docs[1].MetaData[MetaKeyPath] != "$" {
+				t.Fatalf("got docs %v", docs)
+			}
+		}
+
+		_, err = p.Parse(ctx, strings.NewReader("{\"text\": \"ok\"}\n{bad\n"), parser.WithURI("a.ndjson"))
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Fatalf("got err %v", err)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		if _, err := NewParser(ctx, &Config{Format: "xml"}); err == nil {
+			t.Fatal("expected error")
+		}
+		if _, err := NewParser(ctx, &Config{RecordPath: "$.items[x]"}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestParsePath(t *testing.T) {
+	path, err := parsePath("$.data.items[2].title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []any{"data", "items", 2, "title"}) {
+		t.Fatalf("got %v", path)
+	}
+	if got := formatPath(path); got != "$.data.items[2].title" {
+		t.Fatalf("got %s", got)
+	}
+	if path, _ = parsePath("items"); formatPath(path) != "$.items" {
+		t.Fatalf("got %v", path)
+	}
+}
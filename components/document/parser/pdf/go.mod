@@ -7,6 +7,7 @@ require (
 	github.com/cloudwego/eino v0.3.27
 	github.com/dslipak/pdf v0.0.2
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/image v0.22.0
 )
 
 require (
@@ -0,0 +1,362 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dslipak/pdf"
+	"golang.org/x/image/ccitt"
+)
+
+// Image is an image drawn on a page, extracted in layout mode with ExtractImages.
+type Image struct {
+	// Page is the 1-based page number
+	Page int `json:"page"`
+	// DataURL is the image encoded as a data URL, e.g. data:image/jpeg;base64,..., which can be passed to vision models
+	DataURL string `json:"data_url"`
+	// Width and Height are the size of the image in pixels
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// BBox is where the image is drawn on the page
+	BBox BBox `json:"bbox"`
+}
+
+// maxFormDepth limits the nesting of the form XObjects to walk.
+const maxFormDepth = 5
+
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m x n, that is applying m and then n.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// bbox returns the bounding box of the unit square transformed by m, which is where an image is drawn.
+func (m matrix) bbox() BBox {
+	b := BBox{X0: m[4], Y0: m[5], X1: m[4], Y1: m[5]}
+	for _, p := range [][2]float64{{1, 0}, {0, 1}, {1, 1}} {
+		x := p[0]*m[0] + p[1]*m[2] + m[4]
+		y := p[0]*m[1] + p[1]*m[3] + m[5]
+		b = b.union(BBox{X0: x, Y0: y, X1: x, Y1: y})
+	}
+	return b
+}
+
+// imageExtractor extracts the images drawn on the pages.
+type imageExtractor struct {
+	// data is the whole PDF file, the images which the pdf library cannot decode, e.g. JPEG, are copied from it
+	data []byte
+	// raw reports whether the streams can be copied from data, which is false for the encrypted files
+	raw bool
+}
+
+// extract returns the images drawn by the content streams of the page, the ones in unsupported formats are skipped,
+// and whether the page paints anything which is not extracted, i.e. the vector graphics or the skipped images.
+func (e *imageExtractor) extract(page int, p pdf.Page) (images []*Image, unextracted bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	resources := inherited(p, "Resources")
+	contents := p.V.Key("Contents")
+	streams := []pdf.Value{contents}
+	if contents.Kind() == pdf.Array {
+		streams = streams[:0]
+		for i := 0; i < contents.Len(); i++ {
+			streams = append(streams, contents.Index(i))
+		}
+	}
+	for _, strm := range streams {
+		imgs, u := e.walk(page, strm, resources, identity, 0)
+		images, unextracted = append(images, imgs...), unextracted || u
+	}
+	return images, unextracted, nil
+}
+
+func (e *imageExtractor) walk(page int, strm pdf.Value, resources pdf.Value, ctm matrix, depth int) ([]*Image, bool) {
+	var (
+		images      []*Image
+		stack       []matrix
+		unextracted bool
+	)
+	pdf.Interpret(strm, func(stk *pdf.Stack, op string) {
+		n := stk.Len()
+		args := make([]pdf.Value, n)
+		for i := n - 1; i >= 0; i-- {
+			args[i] = stk.Pop()
+		}
+		switch op {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if len(stack) > 0 {
+				ctm, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+		case "cm":
+			if len(args) != 6 {
+				return
+			}
+			var m matrix
+			for i := range m {
+				m[i] = args[i].Float64()
+			}
+			ctm = m.mul(ctm)
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "sh":
+			// the paths and shadings are painted
+			unextracted = true
+		case "Do":
+			if len(args) != 1 {
+				return
+			}
+			xobj := resources.Key("XObject").Key(args[0].Name())
+			switch xobj.Key("Subtype").Name() {
+			case "Image":
+				if img, ok := e.image(xobj); ok {
+					img.Page = page
+					img.BBox = ctm.bbox()
+					images = append(images, img)
+				} else {
+					unextracted = true
+				}
+			case "Form":
+				if depth >= maxFormDepth {
+					return
+				}
+				formCTM := ctm
+				if fm := xobj.Key("Matrix"); fm.Len() == 6 {
+					var m matrix
+					for i := range m {
+						m[i] = fm.Index(i).Float64()
+					}
+					formCTM = m.mul(ctm)
+				}
+				formResources := xobj.Key("Resources")
+				if formResources.IsNull() {
+					formResources = resources
+				}
+				imgs, u := e.walk(page, xobj, formResources, formCTM, depth+1)
+				images, unextracted = append(images, imgs...), unextracted || u
+			}
+		}
+	})
+	return images, unextracted
+}
+
+// image encodes the image XObject as a data URL. JPEG and JPEG 2000 images are copied as they are,
+// and the 8-bit Gray, RGB and CMYK images and the bilevel CCITT images are encoded as PNG.
+func (e *imageExtractor) image(xobj pdf.Value) (*Image, bool) {
+	width, height := int(xobj.Key("Width").Int64()), int(xobj.Key("Height").Int64())
+	if width <= 0 || height <= 0 {
+		return nil, false
+	}
+
+	var filters []string
+	switch f := xobj.Key("Filter"); f.Kind() {
+	case pdf.Name:
+		filters = append(filters, f.Name())
+	case pdf.Array:
+		for i := 0; i < f.Len(); i++ {
+			filters = append(filters, f.Index(i).Name())
+		}
+	}
+	// the scanned text is often drawn as a CCITT stencil mask painted in black
+	isCCITT := len(filters) == 1 && filters[0] == "CCITTFaxDecode"
+	if xobj.Key("ImageMask").Bool() && !isCCITT {
+		return nil, false
+	}
+
+	var (
+		mimeType string
+		data     []byte
+		ok       bool
+	)
+	switch {
+	case len(filters) == 1 && filters[0] == "DCTDecode":
+		mimeType = "image/jpeg"
+		data, ok = e.rawStream(xobj)
+	case len(filters) == 1 && filters[0] == "JPXDecode":
+		mimeType = "image/jp2"
+		data, ok = e.rawStream(xobj)
+	case isCCITT:
+		mimeType = "image/png"
+		data, ok = e.decodeCCITT(xobj, width, height)
+	case len(filters) == 0 || len(filters) == 1 && filters[0] == "FlateDecode":
+		mimeType = "image/png"
+		data, ok = encodePNG(xobj, width, height)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return &Image{
+		DataURL: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data),
+		Width:   width,
+		Height:  height,
+	}, true
+}
+
+// rawStream returns the undecoded data of the stream, whose offset is only exposed by the string form of the stream,
+// e.g. <</Filter /DCTDecode /Length 1024>>@2048.
+func (e *imageExtractor) rawStream(strm pdf.Value) ([]byte, bool) {
+	if !e.raw {
+		return nil, false
+	}
+	s := strm.String()
+	i := strings.LastIndexByte(s, '@')
+	if i < 0 {
+		return nil, false
+	}
+	offset, err := strconv.ParseInt(s[i+1:], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	length := strm.Key("Length").Int64()
+	if offset < 0 || length <= 0 || offset+length > int64(len(e.data)) {
+		return nil, false
+	}
+	return e.data[offset : offset+length], true
+}
+
+// decodeCCITT decodes the Group 3 (1-D) and Group 4 fax images, which are the bilevel scans of most office scanners.
+// The black pixels are the 0 bits unless BlackIs1 is set or the Decode array is inverted, for both the images
+// and the stencil masks.
+func (e *imageExtractor) decodeCCITT(xobj pdf.Value, width, height int) ([]byte, bool) {
+	raw, ok := e.rawStream(xobj)
+	if !ok {
+		return nil, false
+	}
+	parms := xobj.Key("DecodeParms")
+	if parms.Kind() == pdf.Array {
+		parms = parms.Index(0)
+	}
+	var sf ccitt.SubFormat
+	switch k := parms.Key("K").Int64(); {
+	case k < 0:
+		sf = ccitt.Group4
+	case k == 0:
+		sf = ccitt.Group3
+	default:
+		// the mixed 1-D and 2-D encoding is not supported by the decoder
+		return nil, false
+	}
+	if columns := parms.Key("Columns"); !columns.IsNull() && int(columns.Int64()) != width {
+		return nil, false
+	}
+	invert := parms.Key("BlackIs1").Bool()
+	if d := xobj.Key("Decode"); d.Len() == 2 && d.Index(0).Float64() == 1 {
+		invert = !invert
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	err := ccitt.DecodeIntoGray(img, bytes.NewReader(raw), ccitt.MSB, sf, &ccitt.Options{
+		Align:  parms.Key("EncodedByteAlign").Bool(),
+		Invert: invert,
+	})
+	if err != nil {
+		return nil, false
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+func encodePNG(xobj pdf.Value, width, height int) (data []byte, ok bool) {
+	defer func() {
+		// the pdf library panics on the unsupported filter parameters
+		if r := recover(); r != nil {
+			data, ok = nil, false
+		}
+	}()
+
+	if xobj.Key("BitsPerComponent").Int64() != 8 {
+		return nil, false
+	}
+	components := 0
+	switch cs := xobj.Key("ColorSpace"); cs.Kind() {
+	case pdf.Name:
+		switch cs.Name() {
+		case "DeviceGray":
+			components = 1
+		case "DeviceRGB":
+			components = 3
+		case "DeviceCMYK":
+			components = 4
+		}
+	case pdf.Array:
+		if cs.Index(0).Name() == "ICCBased" {
+			components = int(cs.Index(1).Key("N").Int64())
+		}
+	}
+	if components != 1 && components != 3 && components != 4 {
+		return nil, false
+	}
+
+	pixels, err := io.ReadAll(xobj.Reader())
+	if err != nil || len(pixels) < width*height*components {
+		return nil, false
+	}
+
+	var img image.Image
+	switch components {
+	case 1:
+		img = &image.Gray{Pix: pixels[:width*height], Stride: width, Rect: image.Rect(0, 0, width, height)}
+	case 3:
+		rgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for i := 0; i < width*height; i++ {
+			copy(rgba.Pix[i*4:i*4+3], pixels[i*3:i*3+3])
+			rgba.Pix[i*4+3] = 0xff
+		}
+		img = rgba
+	case 4:
+		cmyk := &image.CMYK{Pix: pixels[:width*height*4], Stride: width * 4, Rect: image.Rect(0, 0, width, height)}
+		rgba := image.NewNRGBA(cmyk.Rect)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				rgba.Set(x, y, color.NRGBAModel.Convert(cmyk.At(x, y)))
+			}
+		}
+		img = rgba
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
@@ -0,0 +1,482 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dslipak/pdf"
+)
+
+const (
+	BlockTypeText  = "text"
+	BlockTypeTable = "table"
+)
+
+// BBox is a bounding box in PDF points (1/72 inch), whose origin is the bottom-left corner of the page.
+type BBox struct {
+	X0 float64 `json:"x0"`
+	Y0 float64 `json:"y0"`
+	X1 float64 `json:"x1"`
+	Y1 float64 `json:"y1"`
+}
+
+func (b BBox) union(o BBox) BBox {
+	return BBox{
+		X0: math.Min(b.X0, o.X0),
+		Y0: math.Min(b.Y0, o.Y0),
+		X1: math.Max(b.X1, o.X1),
+		Y1: math.Max(b.Y1, o.Y1),
+	}
+}
+
+// Block is a paragraph or a table of a page in layout mode.
+type Block struct {
+	// Page is the 1-based page number
+	Page int `json:"page"`
+	// Type is BlockTypeText or BlockTypeTable
+	Type string `json:"type"`
+	// Content is the text of the block, the tables are rendered as Markdown
+	Content string `json:"content"`
+	BBox    BBox   `json:"bbox"`
+}
+
+// the thresholds relative to the font size
+const (
+	// sameLineRatio is the max baseline offset of the glyphs in a line
+	sameLineRatio = 0.5
+	// spaceRatio is the min gap between the glyphs to insert a space
+	spaceRatio = 0.15
+	// cellRatio is the min gap between the glyphs to split a line into table cells
+	cellRatio = 1.5
+	// estimatedGapRatio enlarges spaceRatio and cellRatio for the glyphs whose widths are estimated by estimateWidth,
+	// since the gaps computed by them are rough
+	estimatedGapRatio = 0.05
+	// paragraphRatio is the max baseline distance of the lines in a paragraph
+	paragraphRatio = 1.5
+	// fontSizeChangeRatio is the min change of the font size to start a new paragraph, e.g. after a heading
+	fontSizeChangeRatio = 0.2
+	// columnGapRatio is the min width of the gutter between the text columns
+	columnGapRatio = 1.0
+	// columnWidthRatio is the min width of most of the lines of a text column, to tell the columns from the tables,
+	// whose cells are narrower
+	columnWidthRatio = 10
+	// the approximate ascent and descent of the font
+	ascentRatio  = 0.75
+	descentRatio = 0.25
+)
+
+type textLine struct {
+	y        float64
+	fontSize float64
+	cells    []*textCell
+	bbox     BBox
+}
+
+type textCell struct {
+	text   string
+	x0, x1 float64
+}
+
+// minColumnLines is the min number of lines of a multi-column region
+const minColumnLines = 3
+
+// layoutPage reconstructs the lines, paragraphs and tables of the page in reading order,
+// that is top to bottom and left to right, whatever order they are drawn in.
+// The text columns are read one after another, rather than line by line across the columns.
+func layoutPage(page int, texts []pdf.Text) []*Block {
+	var blocks []*Block
+	for _, lines := range splitColumns(buildLines(texts)) {
+		start := 0
+		for i := 1; i <= len(lines); i++ {
+			if i < len(lines) && !startsParagraph(lines[i-1], lines[i]) {
+				continue
+			}
+			blocks = append(blocks, buildBlocks(page, lines[start:i])...)
+			start = i
+		}
+	}
+	return blocks
+}
+
+// splitColumns splits the lines into the flows in reading order. The lines of a multi-column region, which are
+// separated by a vertical gutter, i.e. an x-gap crossed by none of them, are split at the gutter, and the left column
+// is read before the right one. The lines above, below and between the regions, e.g. the titles spanning the columns,
+// are kept as they are.
+func splitColumns(lines []*textLine) [][]*textLine {
+	if len(lines) < minColumnLines {
+		return [][]*textLine{lines}
+	}
+	x0, x1 := lines[0].bbox.X0, lines[0].bbox.X1
+	for _, l := range lines[1:] {
+		x0, x1 = math.Min(x0, l.bbox.X0), math.Max(x1, l.bbox.X1)
+	}
+
+	var flows [][]*textLine
+	start := 0
+	for i := 0; i < len(lines); {
+		gutter, j, ok := findGutter(lines, i, x0, x1)
+		if !ok {
+			i++
+			continue
+		}
+		if start < i {
+			flows = append(flows, lines[start:i])
+		}
+		left, right := splitLines(lines[i:j], gutter)
+		flows = append(flows, splitColumns(left)...)
+		flows = append(flows, splitColumns(right)...)
+		i, start = j, j
+	}
+	if start < len(lines) {
+		flows = append(flows, lines[start:])
+	}
+	return flows
+}
+
+// findGutter finds the longest region of the lines from lines[i], which are not crossed by a common x-gap,
+// and returns the middle of the gap and the end of the region. The gaps are searched between x0 and x1, which are
+// the bounds of the text of the page, so that the lines with the text on one side of the gutter only,
+// e.g. the last line of a paragraph, or the columns whose baselines are not aligned, are in the region as well.
+func findGutter(lines []*textLine, i int, x0, x1 float64) (gutter float64, end int, ok bool) {
+	for _, gap := range lineGaps(lines[i], x0, x1) {
+		j := i + 1
+		for ; j < len(lines); j++ {
+			next, found := [2]float64{}, false
+			for _, g := range lineGaps(lines[j], x0, x1) {
+				lo, hi := math.Max(gap[0], g[0]), math.Min(gap[1], g[1])
+				if hi-lo >= columnGapRatio*lines[j].fontSize {
+					next, found = [2]float64{lo, hi}, true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+			gap = next
+		}
+		if j-i < minColumnLines || j <= end || !isColumns(lines[i:j], gap) {
+			continue
+		}
+		gutter, end, ok = (gap[0]+gap[1])/2, j, true
+	}
+	return gutter, end, ok
+}
+
+// lineGaps returns the x-gaps of the line between x0 and x1, including the ones before and after its text.
+func lineGaps(l *textLine, x0, x1 float64) [][2]float64 {
+	var gaps [][2]float64
+	prev := x0
+	for _, c := range l.cells {
+		if c.x0 > prev {
+			gaps = append(gaps, [2]float64{prev, c.x0})
+		}
+		prev = math.Max(prev, c.x1)
+	}
+	if x1 > prev {
+		gaps = append(gaps, [2]float64{prev, x1})
+	}
+	return gaps
+}
+
+// isColumns reports whether the gap separates the lines into text columns rather than the columns of a table,
+// that is both sides have text, and most of the text on each side is as wide as the lines of a paragraph.
+func isColumns(lines []*textLine, gap [2]float64) bool {
+	var left, right, wideLeft, wideRight int
+	for _, l := range lines {
+		for _, c := range l.cells {
+			wide := c.x1-c.x0 >= columnWidthRatio*l.fontSize
+			if c.x1 <= gap[0] {
+				left++
+				if wide {
+					wideLeft++
+				}
+			} else {
+				right++
+				if wide {
+					wideRight++
+				}
+			}
+		}
+	}
+	return left > 1 && right > 1 && 2*wideLeft >= left && 2*wideRight >= right
+}
+
+// splitLines splits the cells of the lines at the gutter into the lines of the left and the right columns.
+func splitLines(lines []*textLine, gutter float64) (left, right []*textLine) {
+	for _, l := range lines {
+		k := sort.Search(len(l.cells), func(k int) bool {
+			return l.cells[k].x0 >= gutter
+		})
+		if k > 0 {
+			left = append(left, subLine(l, l.cells[:k]))
+		}
+		if k < len(l.cells) {
+			right = append(right, subLine(l, l.cells[k:]))
+		}
+	}
+	return left, right
+}
+
+func subLine(l *textLine, cells []*textCell) *textLine {
+	sub := &textLine{y: l.y, fontSize: l.fontSize, cells: cells, bbox: l.bbox}
+	sub.bbox.X0, sub.bbox.X1 = cells[0].x0, cells[len(cells)-1].x1
+	return sub
+}
+
+func buildLines(texts []pdf.Text) []*textLine {
+	glyphs := make([]pdf.Text, 0, len(texts))
+	for _, t := range texts {
+		if t.S == "" {
+			continue
+		}
+		t.FontSize = math.Abs(t.FontSize)
+		if t.FontSize == 0 {
+			t.FontSize = 1
+		}
+		glyphs = append(glyphs, t)
+	}
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		return glyphs[i].Y > glyphs[j].Y
+	})
+
+	var groups [][]pdf.Text
+	for _, g := range glyphs {
+		if n := len(groups); n > 0 {
+			last := groups[n-1][0]
+			if math.Abs(last.Y-g.Y) <= sameLineRatio*math.Max(last.FontSize, g.FontSize) {
+				groups[n-1] = append(groups[n-1], g)
+				continue
+			}
+		}
+		groups = append(groups, []pdf.Text{g})
+	}
+
+	lines := make([]*textLine, 0, len(groups))
+	for _, group := range groups {
+		if l := buildLine(group); l != nil {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func buildLine(glyphs []pdf.Text) *textLine {
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		return glyphs[i].X < glyphs[j].X
+	})
+
+	l := &textLine{y: glyphs[0].Y}
+	var (
+		sb      strings.Builder
+		current *textCell
+		end     float64
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if current.text = strings.TrimSpace(sb.String()); current.text != "" {
+			l.cells = append(l.cells, current)
+		}
+		sb.Reset()
+		current = nil
+	}
+	for _, g := range glyphs {
+		width, gapRatio := g.W, 0.0
+		if width <= 0 {
+			width, gapRatio = estimateWidth(g.S, g.FontSize), estimatedGapRatio
+		}
+		gap := g.X - end
+		if current != nil && gap > (cellRatio+gapRatio)*g.FontSize {
+			flush()
+		}
+		if current == nil && strings.TrimSpace(g.S) == "" {
+			end = math.Max(end, g.X+width)
+			continue
+		}
+		if current != nil && gap > (spaceRatio+gapRatio)*g.FontSize && g.S != " " && !strings.HasSuffix(sb.String(), " ") {
+			sb.WriteString(" ")
+		}
+		if current == nil {
+			current = &textCell{x0: g.X}
+		}
+		sb.WriteString(g.S)
+		end = math.Max(end, g.X+width)
+		if g.S != " " {
+			current.x1 = end
+		}
+		l.fontSize = math.Max(l.fontSize, g.FontSize)
+	}
+	flush()
+	if len(l.cells) == 0 {
+		return nil
+	}
+
+	l.bbox = BBox{
+		X0: l.cells[0].x0,
+		Y0: l.y - descentRatio*l.fontSize,
+		X1: l.cells[len(l.cells)-1].x1,
+		Y1: l.y + ascentRatio*l.fontSize,
+	}
+	return l
+}
+
+// estimateWidth estimates the width of a glyph by the common proportions of the fonts,
+// for the fonts whose widths are unknown to the pdf library, e.g. the CID fonts.
+func estimateWidth(s string, fontSize float64) float64 {
+	r, _ := utf8.DecodeRuneInString(s)
+	ratio := 0.5
+	switch {
+	case r == ' ' || strings.ContainsRune("iljtfrI.,:;'!|", r):
+		ratio = 0.28
+	case r == 'm' || r == 'M' || r == 'W':
+		ratio = 0.85
+	case r == 'w' || unicode.IsUpper(r):
+		ratio = 0.7
+	case unicode.IsDigit(r):
+		ratio = 0.55
+	case r >= 0x1100 && isWide(r):
+		ratio = 1
+	}
+	return ratio * fontSize
+}
+
+// isWide reports whether the rune is a full-width East Asian character.
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		r >= 0x3000 && r <= 0x303f || r >= 0xff00 && r <= 0xff60
+}
+
+func startsParagraph(prev, cur *textLine) bool {
+	fontSize := math.Max(prev.fontSize, cur.fontSize)
+	if prev.y-cur.y > paragraphRatio*fontSize {
+		return true
+	}
+	return math.Abs(prev.fontSize-cur.fontSize) > fontSizeChangeRatio*fontSize
+}
+
+// buildBlocks splits the lines of a paragraph into text and table blocks.
+// A table is at least 2 consecutive lines of the same number (>1) of cells, whose columns overlap horizontally.
+func buildBlocks(page int, lines []*textLine) []*Block {
+	var blocks []*Block
+	start := 0
+	for i := 0; i < len(lines); {
+		j := i + 1
+		for len(lines[i].cells) > 1 && j < len(lines) && alignedColumns(lines[j-1], lines[j]) {
+			j++
+		}
+		if j-i < 2 {
+			i++
+			continue
+		}
+		if start < i {
+			blocks = append(blocks, textBlock(page, lines[start:i]))
+		}
+		blocks = append(blocks, tableBlock(page, lines[i:j]))
+		i, start = j, j
+	}
+	if start < len(lines) {
+		blocks = append(blocks, textBlock(page, lines[start:]))
+	}
+	return blocks
+}
+
+func alignedColumns(a, b *textLine) bool {
+	if len(a.cells) != len(b.cells) {
+		return false
+	}
+	for k := range a.cells {
+		if a.cells[k].x0 > b.cells[k].x1 || b.cells[k].x0 > a.cells[k].x1 {
+			return false
+		}
+	}
+	return true
+}
+
+func textBlock(page int, lines []*textLine) *Block {
+	b := &Block{Page: page, Type: BlockTypeText, BBox: lines[0].bbox}
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		cells := make([]string, len(l.cells))
+		for k, c := range l.cells {
+			cells[k] = c.text
+		}
+		texts[i] = strings.Join(cells, " ")
+		b.BBox = b.BBox.union(l.bbox)
+	}
+	b.Content = strings.Join(texts, "\n")
+	return b
+}
+
+// tableBlock renders the lines as a Markdown table, whose first line is the header.
+func tableBlock(page int, lines []*textLine) *Block {
+	b := &Block{Page: page, Type: BlockTypeTable, BBox: lines[0].bbox}
+	var sb strings.Builder
+	for i, l := range lines {
+		sb.WriteString("|")
+		for _, c := range l.cells {
+			sb.WriteString(" " + strings.ReplaceAll(c.text, "|", "\\|") + " |")
+		}
+		sb.WriteString("\n")
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", len(l.cells)) + "\n")
+		}
+		b.BBox = b.BBox.union(l.bbox)
+	}
+	b.Content = strings.TrimSuffix(sb.String(), "\n")
+	return b
+}
+
+// pageContent returns the text of the page, and recovers the panic of the pdf library on malformed content.
+func pageContent(p pdf.Page) (content pdf.Content, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return p.Content(), nil
+}
+
+// mediaBox returns the media box of the page.
+func mediaBox(p pdf.Page) (BBox, bool) {
+	box := inherited(p, "MediaBox")
+	if box.Len() != 4 {
+		return BBox{}, false
+	}
+	return BBox{
+		X0: box.Index(0).Float64(),
+		Y0: box.Index(1).Float64(),
+		X1: box.Index(2).Float64(),
+		Y1: box.Index(3).Float64(),
+	}, true
+}
+
+// inherited returns the attribute of the page, which may be inherited from the page tree.
+func inherited(p pdf.Page, key string) pdf.Value {
+	for v := p.V; !v.IsNull(); v = v.Key("Parent") {
+		if attr := v.Key(key); !attr.IsNull() {
+			return attr
+		}
+	}
+	return pdf.Value{}
+}
@@ -19,7 +19,9 @@ package pdf
 import "github.com/cloudwego/eino/components/document/parser"
 
 type options struct {
-	toPages *bool
+	toPages       *bool
+	layout        *bool
+	extractImages *bool
 }
 
 // WithToPages is a parser option that specifies whether to parse the PDF into pages.
@@ -28,3 +30,17 @@ func WithToPages(toPages bool) parser.Option {
 		opts.toPages = &toPages
 	})
 }
+
+// WithLayout is a parser option that specifies whether to parse the PDF in layout mode, see Config.Layout.
+func WithLayout(layout bool) parser.Option {
+	return parser.WrapImplSpecificOptFn(func(opts *options) {
+		opts.layout = &layout
+	})
+}
+
+// WithExtractImages is a parser option that specifies whether to extract the images in layout mode, see Config.ExtractImages.
+func WithExtractImages(extractImages bool) parser.Option {
+	return parser.WrapImplSpecificOptFn(func(opts *options) {
+		opts.extractImages = &extractImages
+	})
+}
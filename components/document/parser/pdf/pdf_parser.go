@@ -21,22 +21,54 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
 	"github.com/dslipak/pdf"
 )
 
+// The metadata keys of the documents in layout mode, and the page keys are also set by ToPages without layout.
+const (
+	// MetaKeyPage is the 1-based page number, only set with ToPages.
+	MetaKeyPage = "_page"
+	// MetaKeyTotalPages is the number of pages of the file.
+	MetaKeyTotalPages = "_total_pages"
+	// MetaKeyTitle, MetaKeyAuthor and MetaKeySubject are from the document information of the file, set if not empty.
+	MetaKeyTitle   = "_title"
+	MetaKeyAuthor  = "_author"
+	MetaKeySubject = "_subject"
+	// MetaKeyBBox is the BBox of the content of the page, only set with ToPages.
+	MetaKeyBBox = "_bbox"
+	// MetaKeyMediaBox is the BBox of the page, only set with ToPages.
+	MetaKeyMediaBox = "_media_box"
+	// MetaKeyBlocks is the []*Block of the paragraphs and tables in reading order.
+	MetaKeyBlocks = "_blocks"
+	// MetaKeyImages is the []*Image drawn on the pages, only set with ExtractImages.
+	MetaKeyImages = "_images"
+	// MetaKeyUnextractedGraphics is true if the page paints the vector graphics or the images in unsupported formats,
+	// e.g. JBIG2, which are only read by rendering the page, only set with ToPages and ExtractImages.
+	MetaKeyUnextractedGraphics = "_unextracted_graphics"
+)
+
 // Config is the configuration for PDF parser.
 type Config struct {
 	ToPages bool // whether to
+	// Layout reconstructs the reading order, line breaks, paragraphs and tables of the pages,
+	// the tables are rendered as Markdown, and the metadata is set, see MetaKeyPage etc.
+	Layout bool
+	// ExtractImages extracts the images drawn on the pages as data URLs in MetaKeyImages,
+	// only works with Layout.
+	ExtractImages bool
 }
 
 // PDFParser reads from io.Reader and parse its content as plain text.
 // Attention: This is in alpha stage, and may not support all PDF use cases well enough.
-// For example, it will not preserve whitespace and new line for now.
+// For example, it will not preserve whitespace and new line for now, unless Layout is set.
 type PDFParser struct {
-	ToPages bool
+	ToPages       bool
+	Layout        bool
+	ExtractImages bool
 }
 
 // NewPDFParser creates a new PDF parser.
@@ -44,7 +76,11 @@ func NewPDFParser(ctx context.Context, config *Config) (*PDFParser, error) {
 	if config == nil {
 		config = &Config{}
 	}
-	return &PDFParser{ToPages: config.ToPages}, nil
+	return &PDFParser{
+		ToPages:       config.ToPages,
+		Layout:        config.Layout,
+		ExtractImages: config.ExtractImages,
+	}, nil
 }
 
 // Parse parses the PDF content from io.Reader.
@@ -52,7 +88,9 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 	commonOpts := parser.GetCommonOptions(nil, opts...)
 
 	specificOpts := parser.GetImplSpecificOptions(&options{
-		toPages: &pp.ToPages,
+		toPages:       &pp.ToPages,
+		layout:        &pp.Layout,
+		extractImages: &pp.ExtractImages,
 	}, opts...)
 
 	data, err := io.ReadAll(reader)
@@ -67,6 +105,10 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 		return nil, fmt.Errorf("create new pdf reader failed: %w", err)
 	}
 
+	if specificOpts.layout != nil && *specificOpts.layout {
+		return parseLayout(f, data, specificOpts, commonOpts.ExtraMeta)
+	}
+
 	pages := f.NumPage()
 	var (
 		buf     bytes.Buffer
@@ -87,9 +129,15 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 		}
 
 		if toPages {
+			meta := make(map[string]any, len(commonOpts.ExtraMeta)+2)
+			for k, v := range commonOpts.ExtraMeta {
+				meta[k] = v
+			}
+			meta[MetaKeyPage] = i
+			meta[MetaKeyTotalPages] = pages
 			docs = append(docs, &schema.Document{
 				Content:  text,
-				MetaData: commonOpts.ExtraMeta,
+				MetaData: meta,
 			})
 		} else {
 			buf.WriteString(text + "\n")
@@ -105,3 +153,115 @@ func (pp *PDFParser) Parse(ctx context.Context, reader io.Reader, opts ...parser
 
 	return docs, nil
 }
+
+// parseLayout parses the PDF in layout mode, the documents are separated by "\n\n" between paragraphs and tables.
+func parseLayout(f *pdf.Reader, data []byte, opts *options, extraMeta map[string]any) ([]*schema.Document, error) {
+	var (
+		pages         = f.NumPage()
+		toPages       = opts.toPages != nil && *opts.toPages
+		extractImages = opts.extractImages != nil && *opts.extractImages
+		info          = f.Trailer().Key("Info")
+		extractor     = &imageExtractor{data: data, raw: f.Trailer().Key("Encrypt").IsNull()}
+	)
+
+	newMeta := func() map[string]any {
+		meta := make(map[string]any, len(extraMeta)+8)
+		for k, v := range extraMeta {
+			meta[k] = v
+		}
+		meta[MetaKeyTotalPages] = pages
+		for key, name := range map[string]string{MetaKeyTitle: "Title", MetaKeyAuthor: "Author", MetaKeySubject: "Subject"} {
+			if v := strings.TrimSpace(info.Key(name).Text()); v != "" {
+				meta[key] = v
+			}
+		}
+		return meta
+	}
+
+	var (
+		docs      []*schema.Document
+		allBlocks []*Block
+		allImages []*Image
+	)
+	for i := 1; i <= pages; i++ {
+		p := f.Page(i)
+		content, err := pageContent(p)
+		if err != nil {
+			return nil, fmt.Errorf("read pdf page failed: %w, page= %d", err, i)
+		}
+		blocks := layoutPage(i, content.Text)
+
+		var (
+			images      []*Image
+			unextracted bool
+		)
+		if extractImages {
+			if images, unextracted, err = extractor.extract(i, p); err != nil {
+				return nil, fmt.Errorf("extract pdf page images failed: %w, page= %d", err, i)
+			}
+		}
+
+		if !toPages {
+			allBlocks = append(allBlocks, blocks...)
+			allImages = append(allImages, images...)
+			continue
+		}
+
+		meta := newMeta()
+		meta[MetaKeyPage] = i
+		meta[MetaKeyBlocks] = blocks
+		if box, ok := mediaBox(p); ok {
+			meta[MetaKeyMediaBox] = box
+		}
+		if bbox, ok := contentBBox(blocks, images); ok {
+			meta[MetaKeyBBox] = bbox
+		}
+		if extractImages {
+			meta[MetaKeyImages] = images
+			meta[MetaKeyUnextractedGraphics] = unextracted
+		}
+		docs = append(docs, &schema.Document{
+			Content:  blocksContent(blocks),
+			MetaData: meta,
+		})
+	}
+
+	if !toPages {
+		meta := newMeta()
+		meta[MetaKeyBlocks] = allBlocks
+		if extractImages {
+			meta[MetaKeyImages] = allImages
+		}
+		docs = append(docs, &schema.Document{
+			Content:  blocksContent(allBlocks),
+			MetaData: meta,
+		})
+	}
+
+	return docs, nil
+}
+
+func blocksContent(blocks []*Block) string {
+	contents := make([]string, len(blocks))
+	for i, b := range blocks {
+		contents[i] = b.Content
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+func contentBBox(blocks []*Block, images []*Image) (bbox BBox, ok bool) {
+	for _, b := range blocks {
+		bbox, ok = unionBBox(bbox, ok, b.BBox), true
+	}
+	for _, img := range images {
+		bbox, ok = unionBBox(bbox, ok, img.BBox), true
+	}
+	return bbox, ok
+}
+
+func unionBBox(bbox BBox, ok bool, o BBox) BBox {
+	if !ok {
+		return o
+	}
+	return bbox.union(o)
+}
@@ -17,11 +17,17 @@
 package pdf
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/dslipak/pdf"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,8 +45,165 @@ func TestLoader_Load(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(docs))
 		assert.True(t, len(docs[0].Content) > 0)
-		assert.Equal(t, map[string]any{"test": "test"}, docs[0].MetaData)
-		assert.True(t, len(docs[0].Content) > 0)
-		assert.Equal(t, map[string]any{"test": "test"}, docs[1].MetaData)
+		assert.Equal(t, map[string]any{"test": "test", MetaKeyPage: 1, MetaKeyTotalPages: 2}, docs[0].MetaData)
+		assert.True(t, len(docs[1].Content) > 0)
+		assert.Equal(t, map[string]any{"test": "test", MetaKeyPage: 2, MetaKeyTotalPages: 2}, docs[1].MetaData)
+	})
+}
+
+func TestParser_Layout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("to pages", func(t *testing.T) {
+		f, err := os.Open("./testdata/layout.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, &Config{Layout: true, ToPages: true, ExtractImages: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f, parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(docs))
+
+		// the paragraph lines are drawn in reverse order, the table is detected by the aligned columns
+		assert.Equal(t, "Quarterly Report\n\n"+
+			"This is the first line and\nsecond line of the paragraph.\n\n"+
+			"| Region | Q1 | Q2 |\n| --- | --- | --- |\n| North | 100 | 120 |\n| South | 80 | 95 |\n\n"+
+			"Totals are in thousands.", docs[0].Content)
+		assert.Equal(t, "Second page", docs[1].Content)
+
+		meta := docs[0].MetaData
+		assert.Equal(t, "test", meta["test"])
+		assert.Equal(t, 1, meta[MetaKeyPage])
+		assert.Equal(t, 2, meta[MetaKeyTotalPages])
+		assert.Equal(t, "Layout Test", meta[MetaKeyTitle])
+		assert.Equal(t, "Eino", meta[MetaKeyAuthor])
+		assert.NotContains(t, meta, MetaKeySubject)
+		assert.Equal(t, BBox{X0: 0, Y0: 0, X1: 612, Y1: 792}, meta[MetaKeyMediaBox])
+		assert.Equal(t, BBox{X0: 72, Y0: 500, X1: 335, Y1: 762}, meta[MetaKeyBBox])
+		assert.Equal(t, 2, docs[1].MetaData[MetaKeyPage])
+		assert.Equal(t, BBox{X0: 0, Y0: 0, X1: 612, Y1: 792}, docs[1].MetaData[MetaKeyMediaBox])
+
+		blocks := meta[MetaKeyBlocks].([]*Block)
+		assert.Equal(t, 4, len(blocks))
+		assert.Equal(t, BlockTypeText, blocks[1].Type)
+		assert.Equal(t, BBox{X0: 72, Y0: 703.5, X1: 207, Y1: 725.5}, blocks[1].BBox)
+		assert.Equal(t, BlockTypeTable, blocks[2].Type)
+		assert.Equal(t, 1, blocks[2].Page)
+
+		images := meta[MetaKeyImages].([]*Image)
+		assert.Equal(t, 2, len(images))
+		assert.True(t, strings.HasPrefix(images[0].DataURL, "data:image/jpeg;base64,/9j/"))
+		assert.Equal(t, 8, images[0].Width)
+		assert.Equal(t, BBox{X0: 72, Y0: 500, X1: 122, Y1: 540}, images[0].BBox)
+		assert.True(t, strings.HasPrefix(images[1].DataURL, "data:image/png;base64,"))
+		assert.Equal(t, 2, images[1].Height)
+		assert.Equal(t, BBox{X0: 200, Y0: 500, X1: 220, Y1: 520}, images[1].BBox)
+		assert.Empty(t, docs[1].MetaData[MetaKeyImages])
+	})
+
+	t.Run("whole file", func(t *testing.T) {
+		f, err := os.Open("./testdata/layout.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, nil)
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f, WithLayout(true))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.True(t, strings.HasSuffix(docs[0].Content, "Totals are in thousands.\n\nSecond page"))
+		assert.Equal(t, 2, docs[0].MetaData[MetaKeyTotalPages])
+		assert.NotContains(t, docs[0].MetaData, MetaKeyPage)
+		assert.NotContains(t, docs[0].MetaData, MetaKeyImages)
+		blocks := docs[0].MetaData[MetaKeyBlocks].([]*Block)
+		assert.Equal(t, 5, len(blocks))
+		assert.Equal(t, 2, blocks[4].Page)
+	})
+
+	t.Run("two columns", func(t *testing.T) {
+		f, err := os.Open("./testdata/columns.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, &Config{Layout: true, ToPages: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		// the columns are read one after another rather than merged into a table line by line
+		assert.Equal(t, "Two Column Article\n\n"+
+			"The left column starts the article and\nkeeps going down the page for a few\n"+
+			"lines before the reader moves on to\nthe right column.\n\n"+
+			"The right column continues the story\nwith more text that must be read after\n"+
+			"the whole left column is finished by\nthe reader.\n\n"+
+			"This footer spans both columns of the page and ends the article.", docs[0].Content)
+		for _, b := range docs[0].MetaData[MetaKeyBlocks].([]*Block) {
+			assert.Equal(t, BlockTypeText, b.Type)
+		}
+	})
+
+	t.Run("unaligned columns", func(t *testing.T) {
+		// the baselines of the right column are 7pt lower, so each line has the text of one column only
+		var texts []pdf.Text
+		for k, s := range []string{"left one is a long line of text", "left two is a long line of text", "left three is a long line"} {
+			texts = append(texts, pdf.Text{S: s, X: 72, Y: 700 - 14*float64(k), W: 160, FontSize: 10})
+		}
+		for k, s := range []string{"right one is a long line of text", "right two is a long line of text", "right three"} {
+			texts = append(texts, pdf.Text{S: s, X: 320, Y: 693 - 14*float64(k), W: 160, FontSize: 10})
+		}
+		blocks := layoutPage(1, texts)
+		assert.Equal(t, 2, len(blocks))
+		assert.Equal(t, "left one is a long line of text\nleft two is a long line of text\nleft three is a long line", blocks[0].Content)
+		assert.Equal(t, "right one is a long line of text\nright two is a long line of text\nright three", blocks[1].Content)
+		assert.Equal(t, BBox{X0: 320, Y0: 662.5, X1: 480, Y1: 700.5}, blocks[1].BBox)
+	})
+
+	t.Run("bilevel scan", func(t *testing.T) {
+		f, err := os.Open("./testdata/bilevel.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, &Config{Layout: true, ToPages: true, ExtractImages: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(docs))
+		assert.Equal(t, "", docs[0].Content)
+		assert.Equal(t, false, docs[0].MetaData[MetaKeyUnextractedGraphics])
+
+		// the CCITT Group 4 image is decoded, whose columns 2 to 5 are black
+		images := docs[0].MetaData[MetaKeyImages].([]*Image)
+		assert.Equal(t, 1, len(images))
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(images[0].DataURL, "data:image/png;base64,"))
+		assert.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+		gray := img.(*image.Gray)
+		assert.Equal(t, []uint8{255, 255, 0, 0, 0, 0, 255, 255}, gray.Pix[7*gray.Stride:7*gray.Stride+8])
+	})
+
+	t.Run("existing file", func(t *testing.T) {
+		f, err := os.Open("./testdata/test_pdf.pdf")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		p, err := NewPDFParser(ctx, &Config{Layout: true, ToPages: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, f)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(docs))
+		// the widths of the CID fonts are estimated to insert the spaces
+		assert.Equal(t, "test a new pdf.\na new line with 中文。", docs[0].Content)
+		assert.Equal(t, "尝试一些样式。", docs[1].Content)
+		for i, doc := range docs {
+			assert.Equal(t, i+1, doc.MetaData[MetaKeyPage])
+			assert.Equal(t, 2, doc.MetaData[MetaKeyTotalPages])
+		}
 	})
 }
@@ -0,0 +1,440 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pptx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// MetaKeySlide is the 1-based number of the slide.
+	MetaKeySlide = "_slide"
+	// MetaKeyTotalSlides is the number of slides of the file, including the hidden ones.
+	MetaKeyTotalSlides = "_total_slides"
+	// MetaKeyTitle is the text of the title placeholder of the slide, set if not empty.
+	MetaKeyTitle = "_title"
+	// MetaKeyNotes is the text of the speaker notes of the slide, only set with IncludeNotes.
+	MetaKeyNotes = "_notes"
+)
+
+const (
+	relTypeOfficeDocument = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	relTypeNotesSlide     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide"
+)
+
+var _ parser.Parser = (*Parser)(nil)
+
+// Config is the configuration for PPTX parser.
+type Config struct {
+	// IncludeNotes appends the speaker notes to the content of the slides, and sets MetaKeyNotes
+	// Optional. Default: false
+	IncludeNotes bool
+	// IncludeHidden includes the hidden slides
+	// Optional. Default: false
+	IncludeHidden bool
+}
+
+// Parser implements parser.Parser. It parses PowerPoint (PPTX) content into a document per slide.
+// The text of the shapes is kept in the order of the slide, a block per shape, and the tables are rendered as Markdown.
+// The slides without any text are skipped.
+type Parser struct {
+	conf *Config
+}
+
+// NewParser returns a new PPTX parser.
+func NewParser(ctx context.Context, config *Config) (*Parser, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Parser{conf: config}, nil
+}
+
+// Parse parses the PPTX content from the reader.
+func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	commonOpts := parser.GetCommonOptions(nil, opts...)
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("pptx parser read all from reader failed: %w", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("pptx parser open zip failed: %w", err)
+	}
+	pkg := &packageFiles{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		pkg.files[f.Name] = f
+	}
+
+	slides, err := pkg.slides()
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []*schema.Document
+	for i, slidePath := range slides {
+		s, err := pkg.readSlide(slidePath)
+		if err != nil {
+			return nil, fmt.Errorf("pptx parser read slide %d failed: %w", i+1, err)
+		}
+		if s.hidden && !p.conf.IncludeHidden {
+			continue
+		}
+
+		var notes string
+		if p.conf.IncludeNotes {
+			if notes, err = pkg.readNotes(slidePath); err != nil {
+				return nil, fmt.Errorf("pptx parser read notes of slide %d failed: %w", i+1, err)
+			}
+		}
+
+		content := strings.Join(s.blocks, "\n\n")
+		if notes != "" {
+			if content != "" {
+				content += "\n\n"
+			}
+			content += "Notes:\n" + notes
+		}
+		if content == "" {
+			continue
+		}
+
+		meta := make(map[string]any, len(commonOpts.ExtraMeta)+4)
+		for k, v := range commonOpts.ExtraMeta {
+			meta[k] = v
+		}
+		meta[MetaKeySlide] = i + 1
+		meta[MetaKeyTotalSlides] = len(slides)
+		if s.title != "" {
+			meta[MetaKeyTitle] = s.title
+		}
+		if notes != "" {
+			meta[MetaKeyNotes] = notes
+		}
+
+		docs = append(docs, &schema.Document{
+			Content:  content,
+			MetaData: meta,
+		})
+	}
+
+	return docs, nil
+}
+
+type packageFiles struct {
+	files map[string]*zip.File
+}
+
+type relationships struct {
+	Relationships []struct {
+		ID         string `xml:"Id,attr"`
+		Type       string `xml:"Type,attr"`
+		Target     string `xml:"Target,attr"`
+		TargetMode string `xml:"TargetMode,attr"`
+	} `xml:"Relationship"`
+}
+
+type presentation struct {
+	SlideIDs []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sldIdLst>sldId"`
+}
+
+// slides returns the paths of the slides in the order of the presentation.
+func (pkg *packageFiles) slides() ([]string, error) {
+	presPath := "ppt/presentation.xml"
+	rels, err := pkg.rels("")
+	if err != nil {
+		return nil, err
+	}
+	if target, ok := rels.byType[relTypeOfficeDocument]; ok {
+		presPath = target
+	}
+
+	var pres presentation
+	if err = pkg.decode(presPath, &pres); err != nil {
+		return nil, fmt.Errorf("pptx parser read presentation failed: %w", err)
+	}
+	presRels, err := pkg.rels(presPath)
+	if err != nil {
+		return nil, err
+	}
+
+	slides := make([]string, 0, len(pres.SlideIDs))
+	for _, id := range pres.SlideIDs {
+		if target, ok := presRels.byID[id.RID]; ok {
+			slides = append(slides, target)
+		}
+	}
+	return slides, nil
+}
+
+// resolvedRels are the paths of the targets by the relationship IDs, and by the types for the first of each type.
+type resolvedRels struct {
+	byID   map[string]string
+	byType map[string]string
+}
+
+// rels reads the relationships of the part, and resolves the targets into the paths in the package.
+func (pkg *packageFiles) rels(part string) (*resolvedRels, error) {
+	dir, relsPath := "", "_rels/.rels"
+	if part != "" {
+		dir = path.Dir(part)
+		relsPath = path.Join(dir, "_rels", path.Base(part)+".rels")
+	}
+
+	ret := &resolvedRels{byID: map[string]string{}, byType: map[string]string{}}
+	if _, ok := pkg.files[relsPath]; !ok {
+		return ret, nil
+	}
+	var rels relationships
+	if err := pkg.decode(relsPath, &rels); err != nil {
+		return nil, fmt.Errorf("pptx parser read %s failed: %w", relsPath, err)
+	}
+	for _, rel := range rels.Relationships {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(dir, target)
+		}
+		ret.byID[rel.ID] = target
+		if _, ok := ret.byType[rel.Type]; !ok {
+			ret.byType[rel.Type] = target
+		}
+	}
+	return ret, nil
+}
+
+func (pkg *packageFiles) open(name string) (io.ReadCloser, error) {
+	f, ok := pkg.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return f.Open()
+}
+
+func (pkg *packageFiles) decode(name string, v any) error {
+	rc, err := pkg.open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+func (pkg *packageFiles) readSlide(name string) (*slideText, error) {
+	rc, err := pkg.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return extractText(rc)
+}
+
+func (pkg *packageFiles) readNotes(slidePath string) (string, error) {
+	rels, err := pkg.rels(slidePath)
+	if err != nil {
+		return "", err
+	}
+	notesPath, ok := rels.byType[relTypeNotesSlide]
+	if !ok {
+		return "", nil
+	}
+	s, err := pkg.readSlide(notesPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(s.blocks, "\n"), nil
+}
+
+type slideText struct {
+	blocks []string
+	title  string
+	hidden bool
+}
+
+// skippedPlaceholders are the placeholders whose text is not the content, e.g. the slide number and the footer.
+var skippedPlaceholders = map[string]bool{
+	"dt":     true,
+	"ftr":    true,
+	"hdr":    true,
+	"sldNum": true,
+	"sldImg": true,
+}
+
+// extractText extracts the text of a slide or a notes slide.
+func extractText(r io.Reader) (*slideText, error) {
+	var (
+		ret = &slideText{}
+		dec = xml.NewDecoder(r)
+
+		inShape, isTitle, skipShape bool
+		shapeParas                  []string
+
+		inTable bool
+		table   [][]string
+		row     []string
+		cell    []string
+
+		inText bool
+		para   strings.Builder
+	)
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "sld":
+				if attr(t, "show") == "0" {
+					ret.hidden = true
+				}
+			case "sp":
+				inShape, isTitle, skipShape, shapeParas = true, false, false, nil
+			case "ph":
+				typ := attr(t, "type")
+				isTitle = typ == "title" || typ == "ctrTitle"
+				skipShape = skippedPlaceholders[typ]
+			case "tbl":
+				inTable, table = true, nil
+			case "tr":
+				row = nil
+			case "tc":
+				cell = nil
+			case "p":
+				para.Reset()
+			case "t":
+				inText = true
+			case "br":
+				para.WriteByte('\n')
+			case "tab":
+				para.WriteByte('\t')
+			}
+		case xml.CharData:
+			if inText {
+				para.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text := strings.TrimSpace(para.String())
+				if text == "" {
+					continue
+				}
+				switch {
+				case inTable:
+					cell = append(cell, text)
+				case inShape:
+					shapeParas = append(shapeParas, text)
+				default:
+					ret.blocks = append(ret.blocks, text)
+				}
+			case "tc":
+				row = append(row, strings.Join(cell, " "))
+			case "tr":
+				table = append(table, row)
+			case "tbl":
+				inTable = false
+				if md := markdownTable(table); md != "" {
+					ret.blocks = append(ret.blocks, md)
+				}
+			case "sp":
+				inShape = false
+				if skipShape || len(shapeParas) == 0 {
+					continue
+				}
+				text := strings.Join(shapeParas, "\n")
+				if isTitle && ret.title == "" {
+					ret.title = text
+				}
+				ret.blocks = append(ret.blocks, text)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+func attr(e xml.StartElement, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == local && a.Name.Space == "" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// markdownTable renders the rows as a Markdown table, the first row is the header.
+func markdownTable(rows [][]string) string {
+	cols := 0
+	empty := true
+	for _, row := range rows {
+		cols = max(cols, len(row))
+		for _, c := range row {
+			if c != "" {
+				empty = false
+			}
+		}
+	}
+	if empty {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := 0; i < cols; i++ {
+			c := ""
+			if i < len(row) {
+				c = strings.ReplaceAll(row[i], "|", "\\|")
+				c = strings.ReplaceAll(c, "\n", " ")
+			}
+			sb.WriteString(" " + c + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
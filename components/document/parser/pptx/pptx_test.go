@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pptx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/document/parser"
+)
+
+const (
+	nsA = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"`
+	nsP = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"`
+	nsR = `xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+)
+
+func shape(ph string, paras ...string) string {
+	var sb strings.Builder
+	sb.WriteString(`<p:sp><p:nvSpPr><p:nvPr>`)
+	if ph != "" {
+		sb.WriteString(`<p:ph type="` + ph + `"/>`)
+	}
+	sb.WriteString(`</p:nvPr></p:nvSpPr><p:txBody>`)
+	for _, para := range paras {
+		sb.WriteString(`<a:p>`)
+		for i, run := range strings.Split(para, "\n") {
+			if i > 0 {
+				sb.WriteString(`<a:br/>`)
+			}
+			sb.WriteString(`<a:r><a:t>` + run + `</a:t></a:r>`)
+		}
+		sb.WriteString(`</a:p>`)
+	}
+	sb.WriteString(`</p:txBody></p:sp>`)
+	return sb.String()
+}
+
+func slide(attrs string, shapes ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><p:sld ` + nsA + ` ` + nsP + ` ` + nsR + attrs + `><p:cSld><p:spTree>` +
+		strings.Join(shapes, "") + `</p:spTree></p:cSld></p:sld>`
+}
+
+func buildPPTX(t *testing.T) []byte {
+	table := `<p:graphicFrame><a:graphic><a:graphicData><a:tbl>` +
+		`<a:tr><a:tc><a:txBody><a:p><a:r><a:t>Name</a:t></a:r></a:p></a:txBody></a:tc><a:tc><a:txBody><a:p><a:r><a:t>Score</a:t></a:r></a:p></a:txBody></a:tc></a:tr>` +
+		`<a:tr><a:tc><a:txBody><a:p><a:r><a:t>a|b</a:t></a:r></a:p></a:txBody></a:tc><a:tc><a:txBody><a:p><a:r><a:t>9</a:t></a:r></a:p></a:txBody></a:tc></a:tr>` +
+		`</a:tbl></a:graphicData></a:graphic></p:graphicFrame>`
+
+	files := map[string]string{
+		"_rels/.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeOfficeDocument + `" Target="ppt/presentation.xml"/></Relationships>`,
+		"ppt/presentation.xml": `<p:presentation ` + nsP + ` ` + nsR + `><p:sldIdLst>` +
+			`<p:sldId id="256" r:id="rId3"/><p:sldId id="257" r:id="rId2"/><p:sldId id="258" r:id="rId4"/><p:sldId id="259" r:id="rId5"/>` +
+			`</p:sldIdLst></p:presentation>`,
+		"ppt/_rels/presentation.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId2" Type="slide" Target="slides/slide2.xml"/>` +
+			`<Relationship Id="rId3" Type="slide" Target="/ppt/slides/slide1.xml"/>` +
+			`<Relationship Id="rId4" Type="slide" Target="slides/slide3.xml"/>` +
+			`<Relationship Id="rId5" Type="slide" Target="slides/slide4.xml"/></Relationships>`,
+		"ppt/slides/slide1.xml": slide("",
+			shape("ctrTitle", "Quarterly Review"),
+			shape("", "Revenue grew", "Costs\nfell"),
+			table,
+			shape("sldNum", "1")),
+		"ppt/slides/_rels/slide1.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeNotesSlide + `" Target="../notesSlides/notesSlide1.xml"/></Relationships>`,
+		"ppt/notesSlides/notesSlide1.xml": `<p:notes ` + nsA + ` ` + nsP + `><p:cSld><p:spTree>` +
+			shape("sldImg") + shape("body", "Mention the churn") + shape("sldNum", "1") + `</p:spTree></p:cSld></p:notes>`,
+		"ppt/slides/slide2.xml": slide("", shape("title", "Agenda")),
+		"ppt/slides/slide3.xml": slide(` show="0"`, shape("title", "Backup")),
+		"ppt/slides/slide4.xml": slide("", shape("ftr", "Confidential")),
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParser(t *testing.T) {
+	ctx := context.Background()
+	data := buildPPTX(t)
+
+	t.Run("default", func(t *testing.T) {
+		p, err := NewParser(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, bytes.NewReader(data), parser.WithExtraMeta(map[string]any{"k": "v"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+
+		want := "Quarterly Review\n\nRevenue grew\nCosts\nfell\n\n| Name | Score |\n| --- | --- |\n| a\\|b | 9 |"
+		if docs[0].Content != want {
+			t.Fatalf("got content %q", docs[0].Content)
+		}
+		meta := docs[0].MetaData
+		if meta[MetaKeySlide] != 1 || meta[MetaKeyTotalSlides] != 4 || meta[MetaKeyTitle] != "Quarterly Review" || meta["k"] != "v" {
+			t.Fatalf("got meta %v", meta)
+		}
+		if _, ok := meta[MetaKeyNotes]; ok {
+			t.Fatal("unexpected notes")
+		}
+		if docs[1].Content != "Agenda" || docs[1].MetaData[MetaKeySlide] != 2 {
+			t.Fatalf("got doc %v", docs[1])
+		}
+	})
+
+	t.Run("notes and hidden", func(t *testing.T) {
+		p, err := NewParser(ctx, &Config{IncludeNotes: true, IncludeHidden: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := p.Parse(ctx, bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(docs) != 3 {
+			t.Fatalf("got %d docs", len(docs))
+		}
+		if docs[0].MetaData[MetaKeyNotes] != "Mention the churn" || !strings.HasSuffix(docs[0].Content, "\n\nNotes:\nMention the churn") {
+			t.Fatalf("got doc %v", docs[0])
+		}
+		if docs[2].Content != "Backup" || docs[2].MetaData[MetaKeySlide] != 3 {
+			t.Fatalf("got doc %v", docs[2])
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		p, err := NewParser(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = p.Parse(ctx, strings.NewReader("not a zip")); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vision
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"net/http"
+	"strings"
+
+	"golang.org/x/image/tiff"
+)
+
+// imageDataURL encodes the image file as a data URL, the TIFF images are converted to PNG,
+// which is not supported by most models.
+func imageDataURL(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("empty file")
+	}
+	mime := http.DetectContentType(data)
+	switch {
+	case mime == "image/png", mime == "image/jpeg", mime == "image/gif", mime == "image/webp":
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		// only the first page of a multi-page TIFF is decoded
+		img, err := tiff.Decode(bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("decode tiff failed: %w", err)
+		}
+		var buf bytes.Buffer
+		if err = png.Encode(&buf, img); err != nil {
+			return "", fmt.Errorf("encode tiff as png failed: %w", err)
+		}
+		mime, data = "image/png", buf.Bytes()
+	default:
+		return "", fmt.Errorf("unsupported file type: %s, expected pdf, png, jpeg, gif, webp or tiff", mime)
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// mimeType returns the mime type of the data URL, or empty for the other URLs.
+func mimeType(url string) string {
+	if !strings.HasPrefix(url, "data:") {
+		return ""
+	}
+	mime, _, _ := strings.Cut(url[len("data:"):], ";")
+	return mime
+}
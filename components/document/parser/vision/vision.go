@@ -0,0 +1,339 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vision
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/components/document/parser/pdf"
+)
+
+const (
+	// MetaKeyPage is the 1-based page number, 1 for the image files.
+	MetaKeyPage = pdf.MetaKeyPage
+	// MetaKeyTotalPages is the number of pages of the file.
+	MetaKeyTotalPages = pdf.MetaKeyTotalPages
+	// MetaKeyExtractMethod is how the content of the page is extracted, ExtractMethodTextLayer, ExtractMethodVision
+	// or ExtractMethodNone.
+	MetaKeyExtractMethod = "_extract_method"
+)
+
+const (
+	// ExtractMethodTextLayer means the content is the text layer of the PDF page.
+	ExtractMethodTextLayer = "text_layer"
+	// ExtractMethodVision means the content is transcribed by the chat model from the images of the page.
+	ExtractMethodVision = "vision"
+	// ExtractMethodNone means the page is blank, which has neither text layer nor anything painted, whose content is empty.
+	ExtractMethodNone = "none"
+)
+
+const (
+	defaultPrompt = `You are an OCR engine. Transcribe all the text in the image(s) of the document page into Markdown.
+- Keep the reading order of the page, and the original language of the text.
+- Use Markdown headings, lists and tables for the titles, lists and tables of the page.
+- Do not summarize, translate, correct or explain the text, and do not describe the images.
+- Write [illegible] for the text which cannot be recognized.
+Reply with the Markdown only. Reply with nothing if the page has no text.`
+	defaultConcurrency       = 4
+	defaultMinTextLayerChars = 20
+)
+
+// Rasterizer returns the images of the given 1-based pages of the PDF as data URLs, e.g. data:image/png;base64,...,
+// which are sent to the chat model. The pages without images can be omitted.
+// It can be implemented by a PDF renderer, e.g. pdftoppm or MuPDF, to render the pages drawn by vector graphics,
+// and the images in the formats which cannot be extracted from the pages, e.g. the JBIG2 scans.
+type Rasterizer func(ctx context.Context, data []byte, pages []int) (map[int][]string, error)
+
+type Config struct {
+	// ChatModel transcribes the images, which must support image input, e.g. ark, gemini, openai, claude and ollama
+	// Required
+	ChatModel model.BaseChatModel
+	// Prompt is the instruction sent with the images of a page
+	// Optional. Default: transcribes the page into Markdown
+	Prompt string
+	// Detail is the quality of the images sent to the chat model, which is supported by some models only
+	// Optional. Default: schema.ImageURLDetailHigh
+	Detail schema.ImageURLDetail
+	// Concurrency limits the number of concurrent model calls
+	// Optional. Default: 4
+	Concurrency int
+	// MinTextLayerChars is the min number of non-space characters in the text layer of a PDF page
+	// to use the text layer rather than the chat model, negative always uses the chat model for the pages with images
+	// Optional. Default: 20
+	MinTextLayerChars int
+	// Rasterizer returns the images of the PDF pages, which is required for the pages drawn by vector graphics or JBIG2 images
+	// Optional. Default: the images embedded in the pages, which suits the scanned PDFs of JPEG, CCITT or 8-bit images
+	Rasterizer Rasterizer
+}
+
+// Parser transcribes the scanned PDFs and the images (PNG, JPEG, GIF, WebP and TIFF) by a multimodal chat model,
+// and returns a Markdown document per page. The PDF pages with enough text layer are not sent to the model,
+// the text layer is read in the layout mode of the pdf parser. The blank pages are returned as empty documents
+// with ExtractMethodNone, rather than skipped, and the pages which can only be read by rendering, e.g. drawn by
+// vector graphics, fail the parsing without a Rasterizer, rather than returned empty.
+type Parser struct {
+	cm                model.BaseChatModel
+	prompt            string
+	detail            schema.ImageURLDetail
+	concurrency       int
+	minTextLayerChars int
+	rasterizer        Rasterizer
+}
+
+var _ parser.Parser = (*Parser)(nil)
+
+func NewParser(ctx context.Context, config *Config) (*Parser, error) {
+	if config == nil || config.ChatModel == nil {
+		return nil, errors.New("chat model is required")
+	}
+	prompt := config.Prompt
+	if prompt == "" {
+		prompt = defaultPrompt
+	}
+	detail := config.Detail
+	if detail == "" {
+		detail = schema.ImageURLDetailHigh
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	minTextLayerChars := config.MinTextLayerChars
+	if minTextLayerChars == 0 {
+		minTextLayerChars = defaultMinTextLayerChars
+	}
+
+	return &Parser{
+		cm:                config.ChatModel,
+		prompt:            prompt,
+		detail:            detail,
+		concurrency:       concurrency,
+		minTextLayerChars: minTextLayerChars,
+		rasterizer:        config.Rasterizer,
+	}, nil
+}
+
+// page is a page to output, whose content is either the text layer or transcribed from the images.
+type page struct {
+	num     int
+	content string
+	method  string
+	images  []string
+}
+
+// Parse parses the PDF or image from the reader, the format is detected by the content.
+func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	commonOpts := parser.GetCommonOptions(nil, opts...)
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("vision parser read all from reader failed: %w", err)
+	}
+
+	var pages []*page
+	if bytes.HasPrefix(data, []byte("%PDF-")) {
+		pages, err = p.pdfPages(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dataURL, err := imageDataURL(data)
+		if err != nil {
+			return nil, err
+		}
+		pages = []*page{{num: 1, images: []string{dataURL}}}
+	}
+
+	if err = p.transcribe(ctx, pages); err != nil {
+		return nil, err
+	}
+
+	docs := make([]*schema.Document, 0, len(pages))
+	for _, pg := range pages {
+		meta := make(map[string]any, len(commonOpts.ExtraMeta)+3)
+		for k, v := range commonOpts.ExtraMeta {
+			meta[k] = v
+		}
+		meta[MetaKeyPage] = pg.num
+		meta[MetaKeyTotalPages] = len(pages)
+		meta[MetaKeyExtractMethod] = pg.method
+		docs = append(docs, &schema.Document{
+			Content:  pg.content,
+			MetaData: meta,
+		})
+	}
+	return docs, nil
+}
+
+// pdfPages reads the text layer of the pages, and the images of the pages whose text layer is not enough.
+func (p *Parser) pdfPages(ctx context.Context, data []byte) ([]*page, error) {
+	pp, err := pdf.NewPDFParser(ctx, &pdf.Config{
+		ToPages:       true,
+		Layout:        true,
+		ExtractImages: p.rasterizer == nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	docs, err := pp.Parse(ctx, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]*page, len(docs))
+	var toRasterize, unextracted []int
+	for i, doc := range docs {
+		pg := &page{num: i + 1, content: doc.Content, method: ExtractMethodTextLayer}
+		pages[i] = pg
+		if p.minTextLayerChars > 0 && countChars(doc.Content) >= p.minTextLayerChars {
+			continue
+		}
+		if p.rasterizer != nil {
+			toRasterize = append(toRasterize, pg.num)
+			continue
+		}
+		images, _ := doc.MetaData[pdf.MetaKeyImages].([]*pdf.Image)
+		for _, img := range images {
+			pg.images = append(pg.images, img.DataURL)
+		}
+		if u, _ := doc.MetaData[pdf.MetaKeyUnextractedGraphics].(bool); u && len(pg.images) == 0 && countChars(pg.content) == 0 {
+			unextracted = append(unextracted, pg.num)
+		}
+	}
+	if len(unextracted) > 0 {
+		return nil, fmt.Errorf("pdf pages %v have no text layer and are drawn by vector graphics or unsupported images, e.g. JBIG2, "+
+			"set Config.Rasterizer to render them", unextracted)
+	}
+
+	if len(toRasterize) > 0 {
+		images, err := p.rasterizer(ctx, data, toRasterize)
+		if err != nil {
+			return nil, fmt.Errorf("rasterize pdf pages failed: %w", err)
+		}
+		for _, num := range toRasterize {
+			pages[num-1].images = images[num]
+		}
+	}
+
+	for _, pg := range pages {
+		if len(pg.images) == 0 && countChars(pg.content) == 0 {
+			pg.content, pg.method = "", ExtractMethodNone
+		}
+	}
+	return pages, nil
+}
+
+// transcribe replaces the content of the pages with images by the reply of the chat model.
+func (p *Parser) transcribe(ctx context.Context, pages []*page) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, p.concurrency)
+	)
+	for _, pg := range pages {
+		if len(pg.images) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			defer func() {
+				if pe := recover(); pe != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("panic error: %v, \nstack: %s", pe, string(debug.Stack())))
+					mu.Unlock()
+				}
+			}()
+
+			content, err := p.generate(ctx, pg.images)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("transcribe page %d failed: %w", pg.num, err))
+				mu.Unlock()
+				return
+			}
+			pg.content, pg.method = content, ExtractMethodVision
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (p *Parser) generate(ctx context.Context, images []string) (string, error) {
+	parts := make([]schema.ChatMessagePart, 0, len(images)+1)
+	parts = append(parts, schema.ChatMessagePart{
+		Type: schema.ChatMessagePartTypeText,
+		Text: p.prompt,
+	})
+	for _, img := range images {
+		parts = append(parts, schema.ChatMessagePart{
+			Type: schema.ChatMessagePartTypeImageURL,
+			ImageURL: &schema.ChatMessageImageURL{
+				URL:      img,
+				Detail:   p.detail,
+				MIMEType: mimeType(img),
+			},
+		})
+	}
+
+	msg, err := p.cm.Generate(ctx, []*schema.Message{{
+		Role:         schema.User,
+		MultiContent: parts,
+	}})
+	if err != nil {
+		return "", err
+	}
+	return trimFence(msg.Content), nil
+}
+
+// trimFence removes the code fence which the models often wrap the whole reply in, e.g. ```markdown ... ```.
+func trimFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") {
+		return s
+	}
+	i := strings.IndexByte(s, '\n')
+	if i < 0 {
+		return s
+	}
+	return strings.TrimSpace(strings.TrimSuffix(s[i+1:], "```"))
+}
+
+func countChars(s string) int {
+	n := 0
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			n++
+		}
+	}
+	return n
+}
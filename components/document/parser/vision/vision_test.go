@@ -0,0 +1,299 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vision
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"golang.org/x/image/tiff"
+)
+
+type fakeChatModel struct {
+	mu       sync.Mutex
+	inputs   [][]*schema.Message
+	reply    string
+	err      error
+	delay    time.Duration
+	running  int32
+	maxCalls int32
+}
+
+func (f *fakeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	n := atomic.AddInt32(&f.running, 1)
+	defer atomic.AddInt32(&f.running, -1)
+	for {
+		m := atomic.LoadInt32(&f.maxCalls)
+		if n <= m || atomic.CompareAndSwapInt32(&f.maxCalls, m, n) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	f.inputs = append(f.inputs, input)
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return schema.AssistantMessage(f.reply, nil), nil
+}
+
+func (f *fakeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func pngData(t *testing.T) []byte {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.White)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseScannedPDF(t *testing.T) {
+	ctx := context.Background()
+	cm := &fakeChatModel{reply: "```markdown\n# Contract\n\n| Party | Name |\n| --- | --- |\n| A | Eino |\n```"}
+	p, err := NewParser(ctx, &Config{ChatModel: cm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open("./testdata/scanned.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	docs, err := p.Parse(ctx, f, parser.WithExtraMeta(map[string]any{"test": "test"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("unexpected docs: %d", len(docs))
+	}
+
+	// page 1 is a scanned image
+	if docs[0].Content != "# Contract\n\n| Party | Name |\n| --- | --- |\n| A | Eino |" {
+		t.Fatalf("unexpected content: %q", docs[0].Content)
+	}
+	if docs[0].MetaData[MetaKeyPage] != 1 || docs[0].MetaData[MetaKeyTotalPages] != 2 ||
+		docs[0].MetaData[MetaKeyExtractMethod] != ExtractMethodVision || docs[0].MetaData["test"] != "test" {
+		t.Fatalf("unexpected metadata: %v", docs[0].MetaData)
+	}
+	// page 2 has text layer
+	if docs[1].Content != "Signed by both parties on the date written above." ||
+		docs[1].MetaData[MetaKeyPage] != 2 || docs[1].MetaData[MetaKeyExtractMethod] != ExtractMethodTextLayer {
+		t.Fatalf("unexpected page 2: %q, %v", docs[1].Content, docs[1].MetaData)
+	}
+
+	if len(cm.inputs) != 1 {
+		t.Fatalf("unexpected model calls: %d", len(cm.inputs))
+	}
+	parts := cm.inputs[0][0].MultiContent
+	if len(parts) != 2 || parts[0].Text != defaultPrompt || parts[1].ImageURL.MIMEType != "image/jpeg" ||
+		!strings.HasPrefix(parts[1].ImageURL.URL, "data:image/jpeg;base64,") || parts[1].ImageURL.Detail != schema.ImageURLDetailHigh {
+		t.Fatalf("unexpected input: %+v", parts)
+	}
+}
+
+func TestParseWithRasterizer(t *testing.T) {
+	ctx := context.Background()
+	cm := &fakeChatModel{reply: "transcribed", delay: 20 * time.Millisecond}
+	var requested []int
+	p, err := NewParser(ctx, &Config{
+		ChatModel:         cm,
+		Prompt:            "transcribe",
+		Concurrency:       1,
+		MinTextLayerChars: -1,
+		Rasterizer: func(ctx context.Context, data []byte, pages []int) (map[int][]string, error) {
+			requested = pages
+			return map[int][]string{1: {"data:image/png;base64,AAAA"}, 2: {"data:image/png;base64,BBBB"}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open("./testdata/scanned.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	docs, err := p.Parse(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requested) != 2 || len(docs) != 2 || docs[1].Content != "transcribed" ||
+		docs[1].MetaData[MetaKeyExtractMethod] != ExtractMethodVision {
+		t.Fatalf("unexpected result: %v, %v", requested, docs)
+	}
+	if atomic.LoadInt32(&cm.maxCalls) != 1 {
+		t.Fatalf("concurrency is not limited: %d", cm.maxCalls)
+	}
+	if cm.inputs[0][0].MultiContent[0].Text != "transcribe" {
+		t.Fatalf("prompt is not used: %+v", cm.inputs[0][0].MultiContent)
+	}
+}
+
+func TestParseBilevelPDF(t *testing.T) {
+	ctx := context.Background()
+	data, err := os.ReadFile("./testdata/bilevel.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the CCITT image is decoded and sent as PNG
+	cm := &fakeChatModel{reply: "transcribed"}
+	p, err := NewParser(ctx, &Config{ChatModel: cm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := p.Parse(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].Content != "transcribed" || docs[0].MetaData[MetaKeyPage] != 1 ||
+		docs[0].MetaData[MetaKeyExtractMethod] != ExtractMethodVision {
+		t.Fatalf("unexpected docs: %v", docs)
+	}
+	if len(cm.inputs) != 1 || !strings.HasPrefix(cm.inputs[0][0].MultiContent[1].ImageURL.URL, "data:image/png;base64,") {
+		t.Fatalf("unexpected model calls: %v", cm.inputs)
+	}
+}
+
+func TestParseVectorPDF(t *testing.T) {
+	ctx := context.Background()
+	data, err := os.ReadFile("./testdata/vector.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// page 1 is drawn by vector graphics only, which cannot be read without a rasterizer
+	cm := &fakeChatModel{reply: "transcribed"}
+	p, err := NewParser(ctx, &Config{ChatModel: cm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Parse(ctx, bytes.NewReader(data))
+	if err == nil || !strings.Contains(err.Error(), "pdf pages [1] have no text layer") ||
+		!strings.Contains(err.Error(), "Config.Rasterizer") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cm.inputs) != 0 {
+		t.Fatalf("unexpected model calls: %d", len(cm.inputs))
+	}
+
+	// the page is transcribed with a rasterizer, and the blank page 2 is empty
+	p, err = NewParser(ctx, &Config{
+		ChatModel: cm,
+		Rasterizer: func(ctx context.Context, data []byte, pages []int) (map[int][]string, error) {
+			return map[int][]string{1: {"data:image/png;base64,AAAA"}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := p.Parse(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 || docs[0].Content != "transcribed" || docs[0].MetaData[MetaKeyExtractMethod] != ExtractMethodVision ||
+		docs[1].Content != "" || docs[1].MetaData[MetaKeyExtractMethod] != ExtractMethodNone {
+		t.Fatalf("unexpected docs: %v", docs)
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	ctx := context.Background()
+	cm := &fakeChatModel{reply: "hello"}
+	p, err := NewParser(ctx, &Config{ChatModel: cm})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := p.Parse(ctx, bytes.NewReader(pngData(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].Content != "hello" || docs[0].MetaData[MetaKeyPage] != 1 || docs[0].MetaData[MetaKeyTotalPages] != 1 {
+		t.Fatalf("unexpected docs: %v", docs)
+	}
+
+	img, err := png.Decode(bytes.NewReader(pngData(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = tiff.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.Parse(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	url := cm.inputs[1][0].MultiContent[1].ImageURL.URL
+	if !strings.HasPrefix(url, "data:image/png;base64,") {
+		t.Fatalf("tiff is not converted to png: %s", url[:30])
+	}
+
+	if _, err = p.Parse(ctx, strings.NewReader("plain text")); err == nil || !strings.Contains(err.Error(), "unsupported file type") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseModelError(t *testing.T) {
+	ctx := context.Background()
+	p, err := NewParser(ctx, &Config{ChatModel: &fakeChatModel{err: errors.New("rate limited")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Parse(ctx, bytes.NewReader(pngData(t)))
+	if err == nil || err.Error() != "transcribe page 1 failed: rate limited" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = NewParser(ctx, &Config{}); err == nil {
+		t.Fatal("expect error without chat model")
+	}
+}
+
+func TestTrimFence(t *testing.T) {
+	for in, want := range map[string]string{
+		"# Title":                    "# Title",
+		"```markdown\n# Title\n```":  "# Title",
+		"```\n| a |\n| --- |\n```\n": "| a |\n| --- |",
+		"```go\ncode\n``` and more":  "```go\ncode\n``` and more",
+	} {
+		if got := trimFence(in); got != want {
+			t.Fatalf("trim %q, got: %q, want: %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xlsx
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// rawNumber matches the numbers as stored in the sheet XML
+var rawNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// cellValue returns the j-th cell of the row, typed by the raw value if raw is not nil,
+// date converts the number of the j-th cell to a time if the cell is formatted as a date
+func cellValue(row, raw []string, j int, date func(j int, v float64) (time.Time, bool)) any {
+	if raw == nil {
+		return row[j]
+	}
+	rawValue := ""
+	if j < len(raw) {
+		rawValue = raw[j]
+	}
+	return typedValue(row[j], rawValue, func(v float64) (time.Time, bool) {
+		return date(j, v)
+	})
+}
+
+// typedValue infers the type of a cell by its formatted and raw values, as the types of the cells are not exposed
+// by the rows iterator: the booleans are formatted as TRUE and FALSE from 1 and 0, the dates are the numbers with
+// a date number format, and the other numbers are float64.
+func typedValue(formatted, raw string, date func(v float64) (time.Time, bool)) any {
+	if (raw == "1" && formatted == "TRUE") || (raw == "0" && formatted == "FALSE") {
+		return formatted == "TRUE"
+	}
+	if !rawNumber.MatchString(raw) {
+		return formatted
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return formatted
+	}
+	// the numbers with the General format are formatted as they are stored, so only the others are looked up
+	if formatted != raw {
+		if t, ok := date(v); ok {
+			return t
+		}
+	}
+	return v
+}
+
+// numFmts looks up the number formats of the cells by their styles, the results are cached by the style index
+type numFmts struct {
+	xlFile   *excelize.File
+	date1904 bool
+	dates    map[int]bool
+}
+
+func newNumFmts(xlFile *excelize.File, date1904 bool) *numFmts {
+	return &numFmts{xlFile: xlFile, date1904: date1904, dates: make(map[int]bool)}
+}
+
+// date converts v to a time if the cell of the i-th row and the j-th column is formatted as a date
+func (nf *numFmts) date(sheet string, i, j int, v float64) (time.Time, bool) {
+	axis, err := excelize.CoordinatesToCellName(j+1, i+1)
+	if err != nil {
+		return time.Time{}, false
+	}
+	idx, err := nf.xlFile.GetCellStyle(sheet, axis)
+	if err != nil {
+		return time.Time{}, false
+	}
+	isDate, ok := nf.dates[idx]
+	if !ok {
+		style, err := nf.xlFile.GetStyle(idx)
+		if err == nil {
+			isDate = isDateStyle(style)
+		}
+		nf.dates[idx] = isDate
+	}
+	if !isDate {
+		return time.Time{}, false
+	}
+	t, err := excelize.ExcelDateToTime(v, nf.date1904)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// isDateStyle reports whether the number format of the style is a date or a time
+func isDateStyle(style *excelize.Style) bool {
+	if style.CustomNumFmt != nil {
+		return isDateFormat(*style.CustomNumFmt)
+	}
+	// the built-in date and time formats, and the ones of the CJK languages
+	switch n := style.NumFmt; {
+	case n >= 14 && n <= 22, n >= 27 && n <= 36, n >= 45 && n <= 47, n >= 50 && n <= 58:
+		return true
+	}
+	return false
+}
+
+// isDateFormat reports whether the format code has the date or time tokens in its first section, skipping the
+// literal texts, the escaped and padding characters and the bracketed colors, conditions and locales,
+// e.g. yyyy-mm-dd and [h]:mm are dates, while 000-00-0000 and 0.00E+00 are not
+func isDateFormat(code string) bool {
+	code = strings.ToLower(code)
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case ';':
+			return false
+		case '"':
+			if end := strings.IndexByte(code[i+1:], '"'); end >= 0 {
+				i += end + 1
+			} else {
+				return false
+			}
+		case '\\', '_', '*':
+			i++
+		case '[':
+			end := strings.IndexByte(code[i+1:], ']')
+			if end < 0 {
+				return false
+			}
+			// the elapsed time, e.g. [h]:mm:ss
+			if section := code[i+1 : i+1+end]; strings.Trim(section, "hms") == "" && section != "" {
+				return true
+			}
+			i += end + 1
+		case 'y', 'm', 'd', 'h', 's':
+			return true
+		}
+	}
+	return false
+}
+
+// computeFormulas computes the formulas without the cached values, e.g. the files written by libraries.
+// GetRows only trims the trailing cells without both the value and the formula, so the trailing formula cells
+// are kept as empty strings and computed here
+func computeFormulas(xlFile *excelize.File, sheetName string, rows, raws [][]string) error {
+	for i, row := range rows {
+		for j, cell := range row {
+			if cell != "" {
+				continue
+			}
+			axis, err := excelize.CoordinatesToCellName(j+1, i+1)
+			if err != nil {
+				return err
+			}
+			formula, err := xlFile.GetCellFormula(sheetName, axis)
+			if err != nil {
+				return err
+			}
+			if formula == "" {
+				continue
+			}
+			// the unsupported functions are left empty
+			if v, err := xlFile.CalcCellValue(sheetName, axis); err == nil {
+				row[j] = v
+			}
+			if raws != nil && i < len(raws) {
+				if v, err := xlFile.CalcCellValue(sheetName, axis, excelize.Options{RawCellValue: true}); err == nil {
+					raws[i] = setCell(raws[i], j, v)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fillMergedCells fills the value of the top-left cell of the merged ranges into the other cells of the ranges
+func fillMergedCells(xlFile *excelize.File, sheetName string, rows, raws [][]string) error {
+	mergeCells, err := xlFile.GetMergeCells(sheetName)
+	if err != nil {
+		return err
+	}
+	for _, mc := range mergeCells {
+		startCol, startRow, err := excelize.CellNameToCoordinates(mc.GetStartAxis())
+		if err != nil {
+			return err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(mc.GetEndAxis())
+		if err != nil {
+			return err
+		}
+
+		value := mc.GetCellValue()
+		if startRow <= len(rows) && startCol <= len(rows[startRow-1]) {
+			value = rows[startRow-1][startCol-1]
+		}
+		var rawValue string
+		if startRow <= len(raws) && startCol <= len(raws[startRow-1]) {
+			rawValue = raws[startRow-1][startCol-1]
+		}
+
+		for r := startRow; r <= endRow && r <= len(rows); r++ {
+			for c := startCol; c <= endCol; c++ {
+				rows[r-1] = setCell(rows[r-1], c-1, value)
+				if raws != nil && r <= len(raws) {
+					raws[r-1] = setCell(raws[r-1], c-1, rawValue)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func setCell(row []string, j int, value string) []string {
+	for len(row) <= j {
+		row = append(row, "")
+	}
+	row[j] = value
+	return row
+}
+
+// columnName returns the name of the j-th column, e.g. A, B, ..., AA
+func columnName(j int) string {
+	name, _ := excelize.ColumnNumberToName(j + 1)
+	return name
+}
+
+// markdownTable renders the rows as a Markdown table, the column names are used for the missing headers
+func markdownTable(headers []string, rows [][]string) string {
+	cols := len(headers)
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string, header bool) {
+		sb.WriteString("|")
+		for j := 0; j < cols; j++ {
+			cell := ""
+			if j < len(row) {
+				cell = strings.TrimSpace(row[j])
+			}
+			if cell == "" && header {
+				cell = columnName(j)
+			}
+			cell = strings.ReplaceAll(cell, "|", "\\|")
+			cell = strings.ReplaceAll(cell, "\r\n", "<br>")
+			cell = strings.ReplaceAll(cell, "\n", "<br>")
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(headers, true)
+	sb.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+	for _, row := range rows {
+		writeRow(row, false)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
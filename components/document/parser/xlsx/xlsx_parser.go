@@ -18,9 +18,11 @@ package xlsx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
@@ -30,6 +32,25 @@ import (
 const (
 	MetaDataRow = "_row"
 	MetaDataExt = "_ext"
+	// MetaDataSheet is the name of the sheet of the document.
+	MetaDataSheet = "_sheet"
+	// MetaDataRowNumber is the 1-based number of the row in the sheet, only set in OutputModeRow.
+	MetaDataRowNumber = "_row_number"
+	// MetaDataStartRow and MetaDataEndRow are the 1-based numbers of the first and the last rows of the chunk in the sheet,
+	// only set in OutputModeMarkdown.
+	MetaDataStartRow = "_start_row"
+	MetaDataEndRow   = "_end_row"
+)
+
+// OutputMode is how the rows are output as documents.
+type OutputMode string
+
+const (
+	// OutputModeRow outputs a document per row, the cells are joined by tabs.
+	OutputModeRow OutputMode = "row"
+	// OutputModeMarkdown outputs the rows as Markdown tables, a document per sheet or per MarkdownChunkRows rows,
+	// the header is repeated in every chunk.
+	OutputModeMarkdown OutputMode = "markdown"
 )
 
 // XlsxParser Custom parser for parsing Xlsx file content
@@ -47,7 +68,28 @@ type Config struct {
 	// NoHeader is set to false by default, which means that the first row is used as the table header
 	NoHeader bool
 	// IDPrefix is set to customize the prefix of document ID, default 1,2,3, ...
+	// When more than one sheet is processed, the sheet name is added to the ID, e.g. Sheet2_1
 	IDPrefix string
+	// Sheets is the list of the sheets to process in order, which overrides SheetName
+	Sheets []string
+	// AllSheets is set to process all the sheets in order, which overrides SheetName and Sheets
+	AllSheets bool
+	// TypedValues is set to keep the typed cell values in the row metadata rather than the strings,
+	// i.e. float64 for numbers, bool for booleans, time.Time for dates and string for the others,
+	// the formulas are resolved to their computed values, which are also used in the content.
+	// Without the header, the column names A, B, C... are used.
+	// Without TypedValues, the formulas without the cached values are left empty
+	TypedValues bool
+	// MergedCells is set to fill the value of a merged cell into every cell of the merged range,
+	// e.g. a category that spans several rows, it is not supported with Streaming
+	MergedCells bool
+	// Streaming is set to read the sheets row by row rather than loading them at once, for very large spreadsheets,
+	// the formulas without the cached values are not computed in this mode
+	Streaming bool
+	// OutputMode is set to OutputModeRow by default, which means a document per row
+	OutputMode OutputMode
+	// MarkdownChunkRows is the max number of rows of a Markdown table in OutputModeMarkdown, 0 means the whole sheet
+	MarkdownChunkRows int
 }
 
 // NewXlsxParser Create a new xlsxParser
@@ -56,32 +98,66 @@ func NewXlsxParser(ctx context.Context, config *Config) (xlp parser.Parser, err
 	if config == nil {
 		config = &Config{}
 	}
+	switch config.OutputMode {
+	case "", OutputModeRow, OutputModeMarkdown:
+	default:
+		return nil, fmt.Errorf("unknown output mode: %s", config.OutputMode)
+	}
+	if config.MergedCells && config.Streaming {
+		return nil, errors.New("merged cells is not supported in streaming mode")
+	}
 	// NoHeader is false by default, which means HasHeader is true by default
 	xlp = &XlsxParser{Config: config}
 	return xlp, nil
 }
 
 // generateID generates document ID based on configuration
-func (xlp *XlsxParser) generateID(i int) string {
+func (xlp *XlsxParser) generateID(sheet string, i int) string {
+	if sheet != "" {
+		return fmt.Sprintf("%s%s_%d", xlp.Config.IDPrefix, sheet, i)
+	}
 	if xlp.Config.IDPrefix == "" {
 		return fmt.Sprintf("%d", i)
 	}
 	return fmt.Sprintf("%s%d", xlp.Config.IDPrefix, i)
 }
 
-// buildRowMetaData builds row metadata from row data and headers
-func (xlp *XlsxParser) buildRowMetaData(row []string, headers []string) map[string]any {
+// buildRowMetaData builds row metadata from row data and headers, the values are typed if raw is not nil
+func (xlp *XlsxParser) buildRowMetaData(row, raw []string, headers []string, date func(j int, v float64) (time.Time, bool)) map[string]any {
 	metaData := make(map[string]any)
 	if !xlp.Config.NoHeader {
 		for j, header := range headers {
 			if j < len(row) {
-				metaData[header] = row[j]
+				metaData[header] = cellValue(row, raw, j, date)
 			}
 		}
+	} else if raw != nil {
+		for j := range row {
+			metaData[columnName(j)] = cellValue(row, raw, j, date)
+		}
 	}
 	return metaData
 }
 
+// sheetNames returns the sheets to process
+func (xlp *XlsxParser) sheetNames(xlFile *excelize.File) []string {
+	sheets := xlFile.GetSheetList()
+	if len(sheets) == 0 {
+		return nil
+	}
+	if xlp.Config.AllSheets {
+		return sheets
+	}
+	if len(xlp.Config.Sheets) > 0 {
+		return xlp.Config.Sheets
+	}
+	// Default
+	if xlp.Config.SheetName != "" {
+		return []string{xlp.Config.SheetName}
+	}
+	return sheets[:1]
+}
+
 // Parse parses the XLSX content from io.Reader.
 func (xlp *XlsxParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
 	option := parser.GetCommonOptions(&parser.Options{}, opts...)
@@ -91,70 +167,220 @@ func (xlp *XlsxParser) Parse(ctx context.Context, reader io.Reader, opts ...pars
 	}
 	defer xlFile.Close()
 
-	// Get all worksheets
-	sheets := xlFile.GetSheetList()
+	// Get the worksheets to process
+	sheets := xlp.sheetNames(xlFile)
 	if len(sheets) == 0 {
 		return nil, nil
 	}
 
-	// Default
-	sheetName := sheets[0]
-	if xlp.Config.SheetName != "" {
-		sheetName = xlp.Config.SheetName
+	var nf *numFmts
+	if xlp.Config.TypedValues {
+		props, err := xlFile.GetWorkbookProps()
+		if err != nil {
+			return nil, err
+		}
+		nf = newNumFmts(xlFile, props.Date1904 != nil && *props.Date1904)
+	}
+
+	var ret []*schema.Document
+	for _, sheetName := range sheets {
+		sp := &sheetParser{
+			xlp:     xlp,
+			sheet:   sheetName,
+			numFmts: nf,
+			extMeta: option.ExtraMeta,
+		}
+		if len(sheets) > 1 {
+			sp.idSheet = sheetName
+		}
+		if err = xlp.eachRow(xlFile, sheetName, sp.handleRow); err != nil {
+			return nil, err
+		}
+		sp.flushChunk()
+		ret = append(ret, sp.docs...)
+	}
+
+	return ret, nil
+}
+
+// eachRow calls fn with the formatted values of the rows of the sheet in order,
+// and the raw values if TypedValues is set
+func (xlp *XlsxParser) eachRow(xlFile *excelize.File, sheetName string, fn func(i int, row, raw []string) error) error {
+	if xlp.Config.Streaming {
+		return xlp.streamRows(xlFile, sheetName, fn)
 	}
 
 	// Get all rows, header + data rows
 	rows, err := xlFile.GetRows(sheetName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if len(rows) == 0 {
-		return nil, nil
+	var raws [][]string
+	if xlp.Config.TypedValues {
+		if raws, err = xlFile.GetRows(sheetName, excelize.Options{RawCellValue: true}); err != nil {
+			return err
+		}
+		if err = computeFormulas(xlFile, sheetName, rows, raws); err != nil {
+			return err
+		}
+	}
+	if xlp.Config.MergedCells {
+		if err = fillMergedCells(xlFile, sheetName, rows, raws); err != nil {
+			return err
+		}
 	}
 
-	var ret []*schema.Document
+	for i, row := range rows {
+		var raw []string
+		if raws != nil {
+			raw = []string{}
+			if i < len(raws) {
+				raw = raws[i]
+			}
+		}
+		if err = fn(i, row, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// Process the header
-	startIdx := 0
-	var headers []string
-	if !xlp.Config.NoHeader && len(rows) > 0 {
-		headers = rows[0]
-		startIdx = 1
+// streamRows reads the rows by the rows iterator, the raw values are read by another iterator in step
+func (xlp *XlsxParser) streamRows(xlFile *excelize.File, sheetName string, fn func(i int, row, raw []string) error) (err error) {
+	rows, err := xlFile.Rows(sheetName)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
 
-	// Process rows of data
-	for i := startIdx; i < len(rows); i++ {
-		row := rows[i]
-		if len(row) == 0 {
-			continue
+	var rawRows *excelize.Rows
+	if xlp.Config.TypedValues {
+		if rawRows, err = xlFile.Rows(sheetName); err != nil {
+			return err
 		}
-		// Convert row data to strings
-		contentParts := make([]string, len(row))
-		for j, cell := range row {
-			contentParts[j] = strings.TrimSpace(cell)
+		defer func() {
+			if closeErr := rawRows.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+	}
+
+	for i := 0; rows.Next(); i++ {
+		row, err := rows.Columns()
+		if err != nil {
+			return err
 		}
-		content := strings.Join(contentParts, "\t")
+		var raw []string
+		if rawRows != nil {
+			if !rawRows.Next() {
+				return errors.New("raw rows iterator ended early")
+			}
+			if raw, err = rawRows.Columns(excelize.Options{RawCellValue: true}); err != nil {
+				return err
+			}
+			if raw == nil {
+				raw = []string{}
+			}
+		}
+		if err = fn(i, row, raw); err != nil {
+			return err
+		}
+	}
+	return rows.Error()
+}
 
-		meta := make(map[string]any)
+// sheetParser converts the rows of a sheet into documents
+type sheetParser struct {
+	xlp     *XlsxParser
+	sheet   string
+	idSheet string
+	numFmts *numFmts
+	extMeta map[string]any
 
-		// Build the row's Meta
-		rowMeta := xlp.buildRowMetaData(row, headers)
-		meta[MetaDataRow] = rowMeta
+	headers    []string
+	chunk      [][]string
+	chunkStart int
+	chunkEnd   int
+	docs       []*schema.Document
+}
 
-		// Get the Common ExtraMeta
-		if option.ExtraMeta != nil {
-			meta[MetaDataExt] = option.ExtraMeta
-		}
+func (sp *sheetParser) handleRow(i int, row, raw []string) error {
+	config := sp.xlp.Config
+	// Process the header
+	if i == 0 && !config.NoHeader {
+		sp.headers = row
+		return nil
+	}
+	if len(row) == 0 {
+		return nil
+	}
 
-		// Create New Document
-		nDoc := &schema.Document{
-			ID:       xlp.generateID(i),
-			Content:  content,
-			MetaData: meta,
+	if config.OutputMode == OutputModeMarkdown {
+		if len(sp.chunk) == 0 {
+			sp.chunkStart = i
 		}
+		sp.chunk = append(sp.chunk, row)
+		sp.chunkEnd = i
+		if config.MarkdownChunkRows > 0 && len(sp.chunk) >= config.MarkdownChunkRows {
+			sp.flushChunk()
+		}
+		return nil
+	}
 
-		ret = append(ret, nDoc)
+	// Convert row data to strings
+	contentParts := make([]string, len(row))
+	for j, cell := range row {
+		contentParts[j] = strings.TrimSpace(cell)
 	}
+	content := strings.Join(contentParts, "\t")
 
-	return ret, nil
+	meta := sp.newMeta()
+
+	// Build the row's Meta
+	rowMeta := sp.xlp.buildRowMetaData(row, raw, sp.headers, func(j int, v float64) (time.Time, bool) {
+		return sp.numFmts.date(sp.sheet, i, j, v)
+	})
+	meta[MetaDataRow] = rowMeta
+	meta[MetaDataRowNumber] = i + 1
+
+	// Create New Document
+	sp.docs = append(sp.docs, &schema.Document{
+		ID:       sp.xlp.generateID(sp.idSheet, i),
+		Content:  content,
+		MetaData: meta,
+	})
+	return nil
+}
+
+// flushChunk outputs the pending rows as a Markdown table
+func (sp *sheetParser) flushChunk() {
+	if len(sp.chunk) == 0 {
+		return
+	}
+
+	meta := sp.newMeta()
+	meta[MetaDataStartRow] = sp.chunkStart + 1
+	meta[MetaDataEndRow] = sp.chunkEnd + 1
+
+	sp.docs = append(sp.docs, &schema.Document{
+		ID:       sp.xlp.generateID(sp.idSheet, sp.chunkStart),
+		Content:  markdownTable(sp.headers, sp.chunk),
+		MetaData: meta,
+	})
+	sp.chunk = nil
+}
+
+func (sp *sheetParser) newMeta() map[string]any {
+	meta := make(map[string]any)
+	meta[MetaDataSheet] = sp.sheet
+
+	// Get the Common ExtraMeta
+	if sp.extMeta != nil {
+		meta[MetaDataExt] = sp.extMeta
+	}
+	return meta
 }
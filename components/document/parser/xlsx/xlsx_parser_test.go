@@ -17,12 +17,15 @@
 package xlsx
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestXlsxParser_Parse(t *testing.T) {
@@ -103,3 +106,211 @@ func TestXlsxParser_Parse(t *testing.T) {
 		assert.Equal(t, map[string]any{"test": "test"}, docs[0].MetaData[MetaDataExt])
 	})
 }
+
+// buildTypedXlsx builds a workbook with typed cells, a formula and a merged cell in Sheet1,
+// and a second sheet of two rows
+func buildTypedXlsx(t *testing.T) []byte {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	rows := [][]any{
+		{"category", "name", "price", "double", "on sale", "since", "code"},
+		{"fruit", "apple", 3.5, nil, true, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "007"},
+		{nil, "pear", 2, nil, false, time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), "008"},
+		{"veg", "carrot", 1.25, nil, false, nil, "a|b"},
+	}
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	for _, r := range []string{"2", "3", "4"} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "D"+r, "C"+r+"*2"))
+	}
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+
+	_, err := f.NewSheet("Other")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Other", "A1", &[]any{"k", "v"}))
+	assert.NoError(t, f.SetSheetRow("Other", "A2", &[]any{"x", 1}))
+
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestXlsxParser_Options(t *testing.T) {
+	ctx := context.Background()
+	data := buildTypedXlsx(t)
+
+	t.Run("TestXlsxParser_WithTypedValuesAndMergedCells", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, &Config{TypedValues: true, MergedCells: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 3)
+
+		assert.Equal(t, "1", docs[0].ID)
+		assert.Equal(t, "Sheet1", docs[0].MetaData[MetaDataSheet])
+		assert.Equal(t, 2, docs[0].MetaData[MetaDataRowNumber])
+		assert.Equal(t, map[string]any{
+			"category": "fruit",
+			"name":     "apple",
+			"price":    3.5,
+			"double":   float64(7),
+			"on sale":  true,
+			"since":    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			"code":     "007",
+		}, docs[0].MetaData[MetaDataRow])
+
+		// the merged category is filled, and the formula is computed
+		row := docs[1].MetaData[MetaDataRow].(map[string]any)
+		assert.Equal(t, "fruit", row["category"])
+		assert.Equal(t, float64(4), row["double"])
+		assert.Equal(t, false, row["on sale"])
+		assert.Equal(t, "fruit\tpear", docs[1].Content[:len("fruit\tpear")])
+	})
+
+	t.Run("TestXlsxParser_WithTrailingFormulas", func(t *testing.T) {
+		f := excelize.NewFile()
+		defer f.Close()
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"a", "b", "sum"}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, 2}))
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C2", "A2+B2"))
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "A2*10"))
+		buf, err := f.WriteToBuffer()
+		assert.NoError(t, err)
+
+		p, err := NewXlsxParser(ctx, &Config{TypedValues: true})
+		assert.NoError(t, err)
+		docs, err := p.Parse(ctx, bytes.NewReader(buf.Bytes()))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		// the trailing formula cells are kept by GetRows, so they are computed as well
+		assert.Equal(t, float64(3), docs[0].MetaData[MetaDataRow].(map[string]any)["sum"])
+		assert.Equal(t, float64(10), docs[1].MetaData[MetaDataRow].(map[string]any)["a"])
+	})
+
+	t.Run("TestXlsxParser_WithNumberFormats", func(t *testing.T) {
+		f := excelize.NewFile()
+		defer f.Close()
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"ssn", "speed", "day", "elapsed", "sci", "text"}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{123456789, 12, 45352, 1.5, 0.000012, 45352}))
+		for cell, code := range map[string]string{
+			"A2": "000-00-0000",
+			"B2": `0" m/s"`,
+			"C2": `dd"/"mm"/"yyyy`,
+			"D2": "[h]:mm",
+			"E2": "0.00E+00",
+			"F2": `"day "0`,
+		} {
+			code := code
+			style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &code})
+			assert.NoError(t, err)
+			assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, style))
+		}
+		buf, err := f.WriteToBuffer()
+		assert.NoError(t, err)
+
+		for _, streaming := range []bool{false, true} {
+			p, err := NewXlsxParser(ctx, &Config{TypedValues: true, Streaming: streaming})
+			assert.NoError(t, err)
+			docs, err := p.Parse(ctx, bytes.NewReader(buf.Bytes()))
+			assert.NoError(t, err)
+			assert.Len(t, docs, 1)
+			// the formatted texts with the date separators are still numbers without a date format
+			assert.Equal(t, "123456789\t12 m/s\t01/03/2024", docs[0].Content[:len("123456789\t12 m/s\t01/03/2024")])
+			assert.Equal(t, map[string]any{
+				"ssn":     float64(123456789),
+				"speed":   float64(12),
+				"day":     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				"elapsed": time.Date(1899, 12, 31, 12, 0, 0, 0, time.UTC),
+				"sci":     0.000012,
+				"text":    float64(45352),
+			}, docs[0].MetaData[MetaDataRow])
+		}
+	})
+
+	t.Run("TestXlsxParser_WithStringValues", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, nil)
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 3)
+		row := docs[1].MetaData[MetaDataRow].(map[string]any)
+		assert.Equal(t, "", row["category"])
+		// the formulas without the cached values are only computed with TypedValues
+		assert.Equal(t, "", row["double"])
+		assert.Equal(t, "FALSE", row["on sale"])
+	})
+
+	t.Run("TestXlsxParser_WithAllSheets", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, &Config{AllSheets: true, IDPrefix: "x_"})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, bytes.NewReader(data), parser.WithExtraMeta(map[string]any{"test": "test"}))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 4)
+		assert.Equal(t, "x_Sheet1_1", docs[0].ID)
+		assert.Equal(t, "x_Other_1", docs[3].ID)
+		assert.Equal(t, "Other", docs[3].MetaData[MetaDataSheet])
+		assert.Equal(t, map[string]any{"k": "x", "v": "1"}, docs[3].MetaData[MetaDataRow])
+		assert.Equal(t, map[string]any{"test": "test"}, docs[3].MetaData[MetaDataExt])
+
+		p, err = NewXlsxParser(ctx, &Config{Sheets: []string{"Other"}})
+		assert.NoError(t, err)
+		docs, err = p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "1", docs[0].ID)
+	})
+
+	t.Run("TestXlsxParser_WithStreaming", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, &Config{TypedValues: true, Streaming: true, AllSheets: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 4)
+		row := docs[0].MetaData[MetaDataRow].(map[string]any)
+		assert.Equal(t, 3.5, row["price"])
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), row["since"])
+		// the formulas without the cached values are not computed in streaming mode
+		assert.Equal(t, "", row["double"])
+		assert.Equal(t, map[string]any{"k": "x", "v": float64(1)}, docs[3].MetaData[MetaDataRow])
+
+		_, err = NewXlsxParser(ctx, &Config{Streaming: true, MergedCells: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("TestXlsxParser_WithMarkdown", func(t *testing.T) {
+		p, err := NewXlsxParser(ctx, &Config{OutputMode: OutputModeMarkdown, MarkdownChunkRows: 2, MergedCells: true, TypedValues: true})
+		assert.NoError(t, err)
+
+		docs, err := p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "| category | name | price | double | on sale | since | code |\n"+
+			"| --- | --- | --- | --- | --- | --- | --- |\n"+
+			"| fruit | apple | 3.5 | 7 | TRUE | Mar-24 | 007 |\n"+
+			"| fruit | pear | 2 | 4 | FALSE | 05-20-24 | 008 |", docs[0].Content)
+		assert.Equal(t, 2, docs[0].MetaData[MetaDataStartRow])
+		assert.Equal(t, 3, docs[0].MetaData[MetaDataEndRow])
+		assert.Equal(t, "| category | name | price | double | on sale | since | code |\n"+
+			"| --- | --- | --- | --- | --- | --- | --- |\n"+
+			"| veg | carrot | 1.25 | 2.5 | FALSE |  | a\\|b |", docs[1].Content)
+		assert.Equal(t, "3", docs[1].ID)
+
+		p, err = NewXlsxParser(ctx, &Config{OutputMode: OutputModeMarkdown, NoHeader: true, Sheets: []string{"Other"}})
+		assert.NoError(t, err)
+		docs, err = p.Parse(ctx, bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "| A | B |\n| --- | --- |\n| k | v |\n| x | 1 |", docs[0].Content)
+
+		_, err = NewXlsxParser(ctx, &Config{OutputMode: "html"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package llm
+
+import (
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// the variables of the prompts
+	varQuery     = "query"
+	varDocument  = "document"
+	varDocuments = "documents"
+)
+
+const pointwiseSystemPrompt = `You are a relevance judge for a search system. Judge how relevant the document is to the query.
+Give a score from 0 to 10, where 0 means the document is unrelated and 10 means it fully answers the query.
+Reply with a JSON object only, e.g. {{"score": 7}}.`
+
+const compressSystemPrompt = `You are a relevance judge for a search system. Judge how relevant the document is to the query.
+Give a score from 0 to 10, where 0 means the document is unrelated and 10 means it fully answers the query,
+and extract the sentences of the document which are relevant to the query, copied verbatim without any change.
+Reply with a JSON object only, e.g. {{"score": 7, "content": "the relevant sentences"}}.
+Use an empty content if no sentence is relevant.`
+
+const pointwiseUserPrompt = `Query: {query}
+
+Document:
+{document}`
+
+const listwiseSystemPrompt = `You are a relevance judge for a search system. Judge how relevant each of the numbered documents is to the query.
+Give each document a score from 0 to 10, where 0 means the document is unrelated and 10 means it fully answers the query.
+Reply with a JSON array covering every document only, e.g. [{{"id": 0, "score": 7}}, {{"id": 1, "score": 2}}].`
+
+const listwiseUserPrompt = `Query: {query}
+
+Documents:
+{documents}`
+
+func defaultPrompt(mode Mode, compress bool) prompt.ChatTemplate {
+	if mode == ModeListwise {
+		return prompt.FromMessages(schema.FString,
+			schema.SystemMessage(listwiseSystemPrompt),
+			schema.UserMessage(listwiseUserPrompt))
+	}
+	if compress {
+		return prompt.FromMessages(schema.FString,
+			schema.SystemMessage(compressSystemPrompt),
+			schema.UserMessage(pointwiseUserPrompt))
+	}
+	return prompt.FromMessages(schema.FString,
+		schema.SystemMessage(pointwiseSystemPrompt),
+		schema.UserMessage(pointwiseUserPrompt))
+}
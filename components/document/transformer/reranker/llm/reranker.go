@@ -0,0 +1,364 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+type Mode string
+
+const (
+	// ModePointwise judges the documents one by one, a model call per document.
+	ModePointwise Mode = "pointwise"
+	// ModeListwise judges a batch of documents in a single model call.
+	ModeListwise Mode = "listwise"
+)
+
+// maxScore is the max score given by the model, the scores are normalized to [0, 1].
+const maxScore = 10
+
+type Config struct {
+	// ChatModel judges the relevance of the documents
+	// Required
+	ChatModel model.BaseChatModel
+	// Mode specifies how the documents are judged
+	// Optional. Default: ModePointwise
+	Mode Mode
+	// Prompt overrides the default prompt.
+	// The variables are {query} and {document} in ModePointwise, {query} and {documents} in ModeListwise,
+	// where documents are numbered from 0 in the form of "[0] content".
+	// The model must reply {"score": 0-10} for ModePointwise, with an extra "content" field if Compress is set,
+	// or [{"id": 0, "score": 0-10}, ...] for ModeListwise.
+	// Optional.
+	Prompt prompt.ChatTemplate
+	// ScoreThreshold drops the documents whose normalized score in [0, 1] is lower than it
+	// Optional. Default: 0, keeps all
+	ScoreThreshold float64
+	// TopN keeps the N most relevant documents, 0 keeps all
+	// Optional. Default: 0
+	TopN int
+	// Compress replaces the content of each document with its sentences relevant to the query,
+	// the documents without any relevant sentence are dropped. Only supported in ModePointwise.
+	// Optional. Default: false
+	Compress bool
+	// BatchSize is the number of documents judged in a single model call in ModeListwise
+	// Optional. Default: 10
+	BatchSize int
+	// Concurrency limits the number of concurrent model calls
+	// Optional. Default: 4
+	Concurrency int
+	// ScoreFieldKey specifies the key in metadata to store the score, in addition to WithScore
+	// Optional. Default: only WithScore
+	ScoreFieldKey *string
+}
+
+type options struct {
+	query string
+}
+
+// WithQuery sets the query which the relevance is judged against, it is required by Transform.
+func WithQuery(query string) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.query = query
+	})
+}
+
+// NewReranker creates a document transformer which scores the documents by a chat model,
+// drops the irrelevant ones and returns the rest in the descending order of the score.
+// The scores are written by WithScore, so that the result can be reordered by the score reranker.
+func NewReranker(ctx context.Context, config *Config) (document.Transformer, error) {
+	if config == nil || config.ChatModel == nil {
+		return nil, errors.New("chat model is required")
+	}
+	mode := config.Mode
+	if mode == "" {
+		mode = ModePointwise
+	}
+	if mode != ModePointwise && mode != ModeListwise {
+		return nil, fmt.Errorf("unknown mode: %s", mode)
+	}
+	if config.Compress && mode != ModePointwise {
+		return nil, errors.New("compress is only supported in pointwise mode")
+	}
+	if config.TopN < 0 {
+		return nil, fmt.Errorf("top n must not be negative, given=%d", config.TopN)
+	}
+	tpl := config.Prompt
+	if tpl == nil {
+		tpl = defaultPrompt(mode, config.Compress)
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	return &reranker{
+		cm:             config.ChatModel,
+		mode:           mode,
+		tpl:            tpl,
+		scoreThreshold: config.ScoreThreshold,
+		topN:           config.TopN,
+		compress:       config.Compress,
+		batchSize:      batchSize,
+		concurrency:    concurrency,
+		scoreFieldKey:  config.ScoreFieldKey,
+	}, nil
+}
+
+type reranker struct {
+	cm             model.BaseChatModel
+	mode           Mode
+	tpl            prompt.ChatTemplate
+	scoreThreshold float64
+	topN           int
+	compress       bool
+	batchSize      int
+	concurrency    int
+	scoreFieldKey  *string
+}
+
+// judgement is the result of a document.
+type judgement struct {
+	score   float64
+	content string
+}
+
+func (r *reranker) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	option := document.GetTransformerImplSpecificOptions(&options{}, opts...)
+	if option.query == "" {
+		return nil, errors.New("query is required, set it by WithQuery")
+	}
+	if len(src) == 0 {
+		return []*schema.Document{}, nil
+	}
+
+	judgements := make([]*judgement, len(src))
+	var tasks []func(ctx context.Context) error
+	if r.mode == ModeListwise {
+		for start := 0; start < len(src); start += r.batchSize {
+			end := min(start+r.batchSize, len(src))
+			tasks = append(tasks, func(ctx context.Context) error {
+				return r.judgeList(ctx, option.query, src[start:end], judgements[start:end])
+			})
+		}
+	} else {
+		for i := range src {
+			tasks = append(tasks, func(ctx context.Context) (err error) {
+				judgements[i], err = r.judgePoint(ctx, option.query, src[i])
+				return err
+			})
+		}
+	}
+	if err := runConcurrently(ctx, r.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*schema.Document, 0, len(src))
+	for i, doc := range src {
+		j := judgements[i]
+		if j.score < r.scoreThreshold {
+			continue
+		}
+		content := doc.Content
+		if r.compress {
+			if strings.TrimSpace(j.content) == "" {
+				continue
+			}
+			content = j.content
+		}
+		ret = append(ret, r.withScore(doc, content, j.score))
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Score() > ret[j].Score()
+	})
+	if r.topN > 0 && len(ret) > r.topN {
+		ret = ret[:r.topN]
+	}
+	return ret, nil
+}
+
+func (r *reranker) judgePoint(ctx context.Context, query string, doc *schema.Document) (*judgement, error) {
+	reply, err := r.generate(ctx, map[string]any{
+		varQuery:    query,
+		varDocument: doc.Content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("judge document[%s] fail: %w", doc.ID, err)
+	}
+
+	var result struct {
+		Score   *float64 `json:"score"`
+		Content string   `json:"content"`
+	}
+	if err = unmarshalReply(reply, "{", "}", &result); err != nil || result.Score == nil {
+		return nil, fmt.Errorf("judge document[%s] fail, unexpected reply: %s", doc.ID, reply)
+	}
+	return &judgement{score: normalize(*result.Score), content: result.Content}, nil
+}
+
+func (r *reranker) judgeList(ctx context.Context, query string, docs []*schema.Document, judgements []*judgement) error {
+	var sb strings.Builder
+	for i, doc := range docs {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("[%d] %s", i, doc.Content))
+	}
+	reply, err := r.generate(ctx, map[string]any{
+		varQuery:     query,
+		varDocuments: sb.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("judge documents[%s...] fail: %w", docs[0].ID, err)
+	}
+
+	var results []struct {
+		ID    int     `json:"id"`
+		Score float64 `json:"score"`
+	}
+	if err = unmarshalReply(reply, "[", "]", &results); err != nil {
+		return fmt.Errorf("judge documents[%s...] fail, unexpected reply: %s", docs[0].ID, reply)
+	}
+	for _, res := range results {
+		if res.ID < 0 || res.ID >= len(docs) {
+			continue
+		}
+		judgements[res.ID] = &judgement{score: normalize(res.Score)}
+	}
+	for i, j := range judgements {
+		if j == nil {
+			return fmt.Errorf("judge documents[%s...] fail, document %d is missing in reply: %s", docs[0].ID, i, reply)
+		}
+	}
+	return nil
+}
+
+func (r *reranker) generate(ctx context.Context, vs map[string]any) (string, error) {
+	msgs, err := r.tpl.Format(ctx, vs)
+	if err != nil {
+		return "", fmt.Errorf("format prompt fail: %w", err)
+	}
+	msg, err := r.cm.Generate(ctx, msgs)
+	if err != nil {
+		return "", err
+	}
+	return msg.Content, nil
+}
+
+// withScore returns a copy of the document with the score, the input documents are not modified.
+func (r *reranker) withScore(doc *schema.Document, content string, score float64) *schema.Document {
+	nd := &schema.Document{
+		ID:       doc.ID,
+		Content:  content,
+		MetaData: make(map[string]any, len(doc.MetaData)+2),
+	}
+	for k, v := range doc.MetaData {
+		nd.MetaData[k] = v
+	}
+	nd.WithScore(score)
+	if r.scoreFieldKey != nil {
+		nd.MetaData[*r.scoreFieldKey] = score
+	}
+	return nd
+}
+
+func (r *reranker) GetType() string {
+	return "LLMReranker"
+}
+
+func normalize(score float64) float64 {
+	return max(0, min(score, maxScore)) / maxScore
+}
+
+// unmarshalReply unmarshals the JSON in the reply of the model, which may be wrapped by a code block or other text,
+// open and closing are the delimiters of the expected JSON value.
+func unmarshalReply(reply string, open, closing string, v any) error {
+	reply = strings.TrimSpace(reply)
+	if err := json.Unmarshal([]byte(reply), v); err == nil {
+		return nil
+	}
+	start, end := strings.Index(reply, open), strings.LastIndex(reply, closing)
+	if start < 0 || end < start {
+		return errors.New("no json found")
+	}
+	return json.Unmarshal([]byte(reply[start:end+1]), v)
+}
+
+// runConcurrently runs the tasks with at most n of them at the same time, and returns the first error.
+func runConcurrently(ctx context.Context, n int, tasks []func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, n)
+	)
+	for _, task := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(task func(ctx context.Context) error) {
+			defer func() {
+				if pe := recover(); pe != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("panic error: %v", pe)
+						cancel()
+					})
+				}
+				<-sem
+				wg.Done()
+			}()
+			if err := task(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(task)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeJudge scores a document by the number of times the query appears in it.
+type fakeJudge struct {
+	calls   atomic.Int32
+	running atomic.Int32
+	maxRun  atomic.Int32
+	reply   func(query string, docs []string) string
+	err     error
+}
+
+var (
+	queryRe    = regexp.MustCompile(`Query: (.*)`)
+	documentRe = regexp.MustCompile(`(?s)Document:\n(.*)`)
+	listRe     = regexp.MustCompile(`\[(\d+)\] ([^\n]*)`)
+)
+
+func (f *fakeJudge) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	f.calls.Add(1)
+	n := f.running.Add(1)
+	defer f.running.Add(-1)
+	for {
+		m := f.maxRun.Load()
+		if n <= m || f.maxRun.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	user := input[len(input)-1].Content
+	query := queryRe.FindStringSubmatch(user)[1]
+	var docs []string
+	if m := listRe.FindAllStringSubmatch(user, -1); len(m) > 0 {
+		for _, d := range m {
+			docs = append(docs, d[2])
+		}
+	} else {
+		docs = []string{documentRe.FindStringSubmatch(user)[1]}
+	}
+	return schema.AssistantMessage(f.reply(query, docs), nil), nil
+}
+
+func (f *fakeJudge) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func countScore(query, doc string) int {
+	return min(strings.Count(doc, query)*3, 10)
+}
+
+func pointwiseReply(query string, docs []string) string {
+	return fmt.Sprintf(`{"score": %d}`, countScore(query, docs[0]))
+}
+
+var testDocs = []*schema.Document{
+	{ID: "1", Content: "eino eino eino eino", MetaData: map[string]any{"source": "a"}},
+	{ID: "2", Content: "golang"},
+	{ID: "3", Content: "eino is a framework. it is written in go"},
+	{ID: "4", Content: "eino and eino"},
+	{ID: "5", Content: "python"},
+}
+
+func ids(docs []*schema.Document) string {
+	var s []string
+	for _, d := range docs {
+		s = append(s, d.ID)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestPointwise(t *testing.T) {
+	ctx := context.Background()
+	judge := &fakeJudge{reply: pointwiseReply}
+	scoreKey := "llm_score"
+	r, err := NewReranker(ctx, &Config{
+		ChatModel:      judge,
+		ScoreThreshold: 0.3,
+		Concurrency:    2,
+		ScoreFieldKey:  &scoreKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := r.Transform(ctx, testDocs, WithQuery("eino"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(result) != "1,4,3" {
+		t.Fatalf("unexpected result: %s", ids(result))
+	}
+	if result[0].Score() != 1 || result[1].Score() != 0.6 || result[2].MetaData[scoreKey] != 0.3 {
+		t.Fatalf("unexpected scores: %v, %v, %v", result[0].MetaData, result[1].MetaData, result[2].MetaData)
+	}
+	if result[0].MetaData["source"] != "a" || testDocs[0].Score() != 0 {
+		t.Fatal("metadata should be copied without modifying the input")
+	}
+	if judge.calls.Load() != 5 || judge.maxRun.Load() > 2 {
+		t.Fatalf("unexpected calls: %d, max concurrency: %d", judge.calls.Load(), judge.maxRun.Load())
+	}
+
+	r, err = NewReranker(ctx, &Config{ChatModel: judge, TopN: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, err = r.Transform(ctx, testDocs, WithQuery("eino")); err != nil || ids(result) != "1" {
+		t.Fatalf("unexpected result: %v, %v", result, err)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	ctx := context.Background()
+	judge := &fakeJudge{reply: func(query string, docs []string) string {
+		var relevant []string
+		for _, s := range strings.Split(docs[0], ". ") {
+			if strings.Contains(s, query) {
+				relevant = append(relevant, s)
+			}
+		}
+		return fmt.Sprintf("```json\n{\"score\": %d, \"content\": %q}\n```", countScore(query, docs[0]), strings.Join(relevant, ". "))
+	}}
+	r, err := NewReranker(ctx, &Config{ChatModel: judge, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.Transform(ctx, testDocs, WithQuery("framework"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(result) != "3" || result[0].Content != "eino is a framework" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestListwise(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var batches []int
+	judge := &fakeJudge{reply: func(query string, docs []string) string {
+		mu.Lock()
+		batches = append(batches, len(docs))
+		mu.Unlock()
+		var items []string
+		// the order of the reply does not matter
+		for i := len(docs) - 1; i >= 0; i-- {
+			items = append(items, fmt.Sprintf(`{"id": %d, "score": %d}`, i, countScore(query, docs[i])))
+		}
+		return "Here are the scores:\n[" + strings.Join(items, ", ") + "]"
+	}}
+	r, err := NewReranker(ctx, &Config{ChatModel: judge, Mode: ModeListwise, BatchSize: 2, ScoreThreshold: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.Transform(ctx, testDocs, WithQuery("eino"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(result) != "1,4,3" || judge.calls.Load() != 3 || len(batches) != 3 {
+		t.Fatalf("unexpected result: %s, calls=%d, batches=%v", ids(result), judge.calls.Load(), batches)
+	}
+
+	judge.reply = func(string, []string) string { return `[{"id": 0, "score": 5}]` }
+	if _, err = r.Transform(ctx, testDocs[:2], WithQuery("eino")); err == nil || !strings.Contains(err.Error(), "document 1 is missing") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewReranker(ctx, &Config{}); err == nil {
+		t.Fatal("chat model should be required")
+	}
+	if _, err := NewReranker(ctx, &Config{ChatModel: &fakeJudge{}, Mode: ModeListwise, Compress: true}); err == nil {
+		t.Fatal("compress should not be supported in listwise mode")
+	}
+
+	judge := &fakeJudge{reply: func(string, []string) string { return "relevant" }}
+	r, err := NewReranker(ctx, &Config{ChatModel: judge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Transform(ctx, testDocs); err == nil || !strings.Contains(err.Error(), "query is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = r.Transform(ctx, testDocs, WithQuery("eino")); err == nil || !strings.Contains(err.Error(), "unexpected reply") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result, err := r.Transform(ctx, nil, WithQuery("eino")); err != nil || len(result) != 0 {
+		t.Fatalf("unexpected result: %v, %v", result, err)
+	}
+
+	modelErr := errors.New("rate limited")
+	r, err = NewReranker(ctx, &Config{ChatModel: &fakeJudge{err: modelErr}, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Transform(ctx, testDocs, WithQuery("eino")); !errors.Is(err, modelErr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
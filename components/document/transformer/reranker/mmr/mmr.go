@@ -0,0 +1,237 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mmr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/components/document/transformer/splitter/semantic"
+)
+
+const defaultLambda = 0.5
+
+type Config struct {
+	// Embedding embeds the query and the documents without DenseVector.
+	// Required unless the query vector is given by WithQueryVector and every document has DenseVector.
+	Embedding embedding.Embedder
+	// TopK is the number of documents to select, 0 selects all, which only reorders the documents.
+	// Optional. Default: 0
+	TopK int
+	// Lambda trades off between the relevance to the query (1) and the diversity of the result (0).
+	// Optional. Default: 0.5
+	Lambda *float64
+}
+
+type options struct {
+	query       string
+	queryVector []float64
+	topK        *int
+	lambda      *float64
+}
+
+// WithQuery sets the query, which is embedded by Config.Embedding.
+func WithQuery(query string) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.query = query
+	})
+}
+
+// WithQueryVector sets the vector of the query, which takes precedence over WithQuery,
+// e.g. reuse the query vector of the retriever to avoid embedding it again.
+func WithQueryVector(vector []float64) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.queryVector = vector
+	})
+}
+
+// WithTopK overrides Config.TopK for a single Transform.
+func WithTopK(topK int) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.topK = &topK
+	})
+}
+
+// WithLambda overrides Config.Lambda for a single Transform.
+func WithLambda(lambda float64) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.lambda = &lambda
+	})
+}
+
+// NewReranker creates a document transformer which selects the documents by Maximal Marginal Relevance,
+// that is the documents relevant to the query but dissimilar to the ones already selected,
+// so that near-duplicate documents do not crowd out the diverse ones (https://dl.acm.org/doi/10.1145/290941.291025).
+func NewReranker(ctx context.Context, config *Config) (document.Transformer, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	if config.TopK < 0 {
+		return nil, fmt.Errorf("top k must not be negative, given=%d", config.TopK)
+	}
+	lambda := defaultLambda
+	if config.Lambda != nil {
+		lambda = *config.Lambda
+	}
+	if err := checkLambda(lambda); err != nil {
+		return nil, err
+	}
+	return &reranker{
+		embedding: config.Embedding,
+		topK:      config.TopK,
+		lambda:    lambda,
+	}, nil
+}
+
+type reranker struct {
+	embedding embedding.Embedder
+	topK      int
+	lambda    float64
+}
+
+func (r *reranker) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	option := document.GetTransformerImplSpecificOptions(&options{topK: &r.topK, lambda: &r.lambda}, opts...)
+	if err := checkLambda(*option.lambda); err != nil {
+		return nil, err
+	}
+	if option.query == "" && len(option.queryVector) == 0 {
+		return nil, errors.New("query is required, set it by WithQuery or WithQueryVector")
+	}
+	if len(src) == 0 {
+		return []*schema.Document{}, nil
+	}
+
+	queryVector, docVectors, err := r.vectors(ctx, option, src)
+	if err != nil {
+		return nil, err
+	}
+
+	topK := *option.topK
+	if topK <= 0 || topK > len(src) {
+		topK = len(src)
+	}
+	selected := selectMMR(queryVector, docVectors, topK, *option.lambda)
+
+	ret := make([]*schema.Document, len(selected))
+	for i, idx := range selected {
+		ret[i] = src[idx]
+	}
+	return ret, nil
+}
+
+// vectors returns the vectors of the query and the documents, the missing ones are embedded in a single call.
+func (r *reranker) vectors(ctx context.Context, option *options, docs []*schema.Document) ([]float64, [][]float64, error) {
+	queryVector := option.queryVector
+	docVectors := make([][]float64, len(docs))
+
+	var texts []string
+	var missing []int
+	if len(queryVector) == 0 {
+		texts = append(texts, option.query)
+	}
+	for i, doc := range docs {
+		if v := doc.DenseVector(); len(v) > 0 {
+			docVectors[i] = v
+			continue
+		}
+		texts = append(texts, doc.Content)
+		missing = append(missing, i)
+	}
+
+	if len(texts) > 0 {
+		if r.embedding == nil {
+			return nil, nil, errors.New("embedding is required to embed the query or the documents without dense vector")
+		}
+		vectors, err := r.embedding.EmbedStrings(ctx, texts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("embed strings fail: %w", err)
+		}
+		if len(vectors) != len(texts) {
+			return nil, nil, fmt.Errorf("unexpected number of vectors, expected: %d, got: %d", len(texts), len(vectors))
+		}
+		if len(queryVector) == 0 {
+			queryVector, vectors = vectors[0], vectors[1:]
+		}
+		for i, idx := range missing {
+			docVectors[idx] = vectors[i]
+		}
+	}
+
+	for i, v := range docVectors {
+		if len(v) != len(queryVector) {
+			return nil, nil, fmt.Errorf("dimension of document[%s] vector is %d, expected: %d", docs[i].ID, len(v), len(queryVector))
+		}
+	}
+	return queryVector, docVectors, nil
+}
+
+// selectMMR returns the indexes of the selected documents in the order of selection.
+func selectMMR(queryVector []float64, docVectors [][]float64, topK int, lambda float64) []int {
+	relevance := make([]float64, len(docVectors))
+	for i, v := range docVectors {
+		relevance[i] = semantic.CosineSimilarity(queryVector, v)
+	}
+	// maxSimilarity[i] is the max similarity of document i to the selected documents
+	maxSimilarity := make([]float64, len(docVectors))
+	for i := range maxSimilarity {
+		maxSimilarity[i] = math.Inf(-1)
+	}
+	picked := make([]bool, len(docVectors))
+
+	selected := make([]int, 0, topK)
+	for len(selected) < topK {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range docVectors {
+			if picked[i] {
+				continue
+			}
+			score := lambda * relevance[i]
+			if len(selected) > 0 {
+				score -= (1 - lambda) * maxSimilarity[i]
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked[best] = true
+		selected = append(selected, best)
+		for i := range docVectors {
+			if !picked[i] {
+				maxSimilarity[i] = max(maxSimilarity[i], semantic.CosineSimilarity(docVectors[i], docVectors[best]))
+			}
+		}
+	}
+	return selected
+}
+
+func checkLambda(lambda float64) error {
+	if lambda < 0 || lambda > 1 {
+		return fmt.Errorf("lambda must be in [0, 1], given=%v", lambda)
+	}
+	return nil
+}
+
+func (r *reranker) GetType() string {
+	return "MMRReranker"
+}
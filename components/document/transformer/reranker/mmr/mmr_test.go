@@ -0,0 +1,173 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mmr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
+
+var testVectors = map[string][]float64{
+	"query":        {1, 0.5},
+	"eino":         {1, 0.2},
+	"eino again":   {0.98, 0.22},
+	"eino and go":  {0.6, 0.8},
+	"unrelated go": {0, 1},
+}
+
+type fakeEmbedding struct {
+	texts [][]string
+}
+
+func (f *fakeEmbedding) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	f.texts = append(f.texts, texts)
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		v, ok := testVectors[text]
+		if !ok {
+			return nil, fmt.Errorf("unknown text: %s", text)
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func newTestDocs() []*schema.Document {
+	return []*schema.Document{
+		{ID: "a", Content: "eino"},
+		{ID: "a2", Content: "eino again"},
+		{ID: "b", Content: "eino and go"},
+		{ID: "c", Content: "unrelated go"},
+	}
+}
+
+func ids(docs []*schema.Document) string {
+	var s []string
+	for _, d := range docs {
+		s = append(s, d.ID)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestMMR(t *testing.T) {
+	ctx := context.Background()
+	emb := &fakeEmbedding{}
+	r, err := NewReranker(ctx, &Config{Embedding: emb, TopK: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		lambda float64
+		want   string
+	}{
+		{name: "relevance only", lambda: 1, want: "a2,a,b"},
+		{name: "relevance first", lambda: 0.7, want: "a2,b,a"},
+		{name: "diversity first", lambda: 0.2, want: "a2,c,b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Transform(ctx, newTestDocs(), WithQuery("query"), WithLambda(tt.lambda))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids(got) != tt.want {
+				t.Errorf("Transform() got = %s, want %s", ids(got), tt.want)
+			}
+		})
+	}
+	// the query and the documents are embedded in a single call
+	if len(emb.texts) != 3 || len(emb.texts[0]) != 5 || emb.texts[0][0] != "query" {
+		t.Fatalf("unexpected embedding calls: %v", emb.texts)
+	}
+
+	got, err := r.Transform(ctx, newTestDocs(), WithQuery("query"), WithTopK(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(got) != "a2,c,b,a" {
+		t.Fatalf("unexpected result: %s", ids(got))
+	}
+}
+
+func TestMMRWithVectors(t *testing.T) {
+	ctx := context.Background()
+	docs := newTestDocs()
+	for _, doc := range docs[:3] {
+		doc.WithDenseVector(testVectors[doc.Content])
+	}
+
+	emb := &fakeEmbedding{}
+	r, err := NewReranker(ctx, &Config{Embedding: emb, TopK: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Transform(ctx, docs, WithQueryVector(testVectors["query"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids(got) != "a2,c" || len(emb.texts) != 1 || strings.Join(emb.texts[0], ",") != "unrelated go" {
+		t.Fatalf("unexpected result: %s, embedding calls: %v", ids(got), emb.texts)
+	}
+
+	// no embedding is required if all the vectors are given
+	docs[3].WithDenseVector(testVectors[docs[3].Content])
+	r, err = NewReranker(ctx, &Config{TopK: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = r.Transform(ctx, docs, WithQueryVector(testVectors["query"])); err != nil || ids(got) != "a2,c" {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+}
+
+func TestMMRErrors(t *testing.T) {
+	ctx := context.Background()
+	invalid := 1.5
+	if _, err := NewReranker(ctx, &Config{Lambda: &invalid}); err == nil {
+		t.Fatal("lambda should be checked")
+	}
+
+	r, err := NewReranker(ctx, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Transform(ctx, newTestDocs()); err == nil || !strings.Contains(err.Error(), "query is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = r.Transform(ctx, newTestDocs(), WithQuery("query")); err == nil || !strings.Contains(err.Error(), "embedding is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = r.Transform(ctx, newTestDocs(), WithQuery("query"), WithLambda(-1)); err == nil {
+		t.Fatal("lambda should be checked")
+	}
+
+	docs := newTestDocs()[:1]
+	docs[0].WithDenseVector([]float64{1, 0, 0})
+	if _, err = r.Transform(ctx, docs, WithQueryVector([]float64{1, 0})); err == nil || !strings.Contains(err.Error(), "dimension") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := r.Transform(ctx, nil, WithQuery("query")); err != nil || len(got) != 0 {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rerankapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Provider string
+
+const (
+	// ProviderCohere is the Cohere rerank API, also used by the compatible services such as Xinference and vLLM.
+	// Ref: https://docs.cohere.com/reference/rerank
+	ProviderCohere Provider = "cohere"
+	// ProviderJina is the Jina rerank API, which is compatible with Cohere.
+	// Ref: https://jina.ai/reranker
+	ProviderJina Provider = "jina"
+	// ProviderDashScope is the text rerank API of Alibaba Cloud DashScope, e.g. gte-rerank.
+	ProviderDashScope Provider = "dashscope"
+	// ProviderArk is the rerank API of the Volcengine knowledge base service.
+	ProviderArk Provider = "ark"
+	// ProviderQianfan is the rerank API of Baidu Qianfan v2.
+	ProviderQianfan Provider = "qianfan"
+)
+
+type provider struct {
+	defaultURL   string
+	defaultModel string
+	maxBatchSize int
+	buildRequest func(model, query string, texts []string) any
+	// parseResponse returns the scores by the indexes of the texts
+	parseResponse func(body []byte, n int) (map[int]float64, error)
+}
+
+var providers = map[Provider]*provider{
+	ProviderCohere: {
+		defaultURL:    "https://api.cohere.com/v2/rerank",
+		maxBatchSize:  1000,
+		buildRequest:  buildCohereRequest,
+		parseResponse: parseCohereResponse,
+	},
+	ProviderJina: {
+		defaultURL:    "https://api.jina.ai/v1/rerank",
+		defaultModel:  "jina-reranker-v2-base-multilingual",
+		maxBatchSize:  1000,
+		buildRequest:  buildCohereRequest,
+		parseResponse: parseCohereResponse,
+	},
+	ProviderQianfan: {
+		defaultURL:    "https://qianfan.baidubce.com/v2/rerankers",
+		defaultModel:  "bce-reranker-base",
+		maxBatchSize:  64,
+		buildRequest:  buildCohereRequest,
+		parseResponse: parseCohereResponse,
+	},
+	ProviderDashScope: {
+		defaultURL:    "https://dashscope.aliyuncs.com/api/v1/services/rerank/text-rerank/text-rerank",
+		defaultModel:  "gte-rerank",
+		maxBatchSize:  500,
+		buildRequest:  buildDashScopeRequest,
+		parseResponse: parseDashScopeResponse,
+	},
+	ProviderArk: {
+		defaultURL:    "https://api-knowledgebase.mlp.cn-beijing.volces.com/api/knowledge/service/rerank",
+		defaultModel:  "base-multilingual-rerank",
+		maxBatchSize:  50,
+		buildRequest:  buildArkRequest,
+		parseResponse: parseArkResponse,
+	},
+}
+
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+func toScores(results []rerankResult) map[int]float64 {
+	scores := make(map[int]float64, len(results))
+	for _, r := range results {
+		scores[r.Index] = r.RelevanceScore
+	}
+	return scores
+}
+
+type cohereRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type cohereResponse struct {
+	Results []rerankResult `json:"results"`
+}
+
+func buildCohereRequest(model, query string, texts []string) any {
+	return &cohereRequest{
+		Model:     model,
+		Query:     query,
+		Documents: texts,
+	}
+}
+
+func parseCohereResponse(body []byte, _ int) (map[int]float64, error) {
+	resp := &cohereResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response fail: %w", err)
+	}
+	return toScores(resp.Results), nil
+}
+
+type dashScopeRequest struct {
+	Model string `json:"model"`
+	Input struct {
+		Query     string   `json:"query"`
+		Documents []string `json:"documents"`
+	} `json:"input"`
+	Parameters struct {
+		ReturnDocuments bool `json:"return_documents"`
+	} `json:"parameters"`
+}
+
+type dashScopeResponse struct {
+	Output struct {
+		Results []rerankResult `json:"results"`
+	} `json:"output"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func buildDashScopeRequest(model, query string, texts []string) any {
+	req := &dashScopeRequest{Model: model}
+	req.Input.Query = query
+	req.Input.Documents = texts
+	return req
+}
+
+func parseDashScopeResponse(body []byte, _ int) (map[int]float64, error) {
+	resp := &dashScopeResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response fail: %w", err)
+	}
+	if resp.Code != "" {
+		return nil, fmt.Errorf("dashscope rerank api error, code: %s, message: %s", resp.Code, resp.Message)
+	}
+	return toScores(resp.Output.Results), nil
+}
+
+type arkData struct {
+	Query   string `json:"query"`
+	Content string `json:"content"`
+}
+
+type arkRequest struct {
+	RerankModel string     `json:"rerank_model"`
+	Datas       []*arkData `json:"datas"`
+}
+
+type arkResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Scores []float64 `json:"scores"`
+	} `json:"data"`
+}
+
+func buildArkRequest(model, query string, texts []string) any {
+	req := &arkRequest{
+		RerankModel: model,
+		Datas:       make([]*arkData, len(texts)),
+	}
+	for i, text := range texts {
+		req.Datas[i] = &arkData{Query: query, Content: text}
+	}
+	return req
+}
+
+// parseArkResponse parses the scores which are in the same order as the datas of the request.
+func parseArkResponse(body []byte, n int) (map[int]float64, error) {
+	resp := &arkResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response fail: %w", err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("ark rerank api error, code: %d, message: %s", resp.Code, resp.Message)
+	}
+	if len(resp.Data.Scores) != n {
+		return nil, fmt.Errorf("unexpected number of scores, expected: %d, got: %d", n, len(resp.Data.Scores))
+	}
+	scores := make(map[int]float64, n)
+	for i, s := range resp.Data.Scores {
+		scores[i] = s
+	}
+	return scores, nil
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rerankapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+type Config struct {
+	// Provider specifies the rerank API to call
+	// Required
+	Provider Provider
+	// APIKey is sent as the bearer token of the requests
+	// Required
+	APIKey string
+	// BaseURL is the URL of the rerank endpoint, which overrides the default one of the provider.
+	// It can point to a self-hosted service compatible with the provider, e.g. Xinference or vLLM for ProviderCohere.
+	// Optional. Default: the public endpoint of the provider
+	BaseURL string
+	// Model is the rerank model to use
+	// Required for ProviderCohere, optional for the others. Default: the recommended model of the provider
+	Model string
+	// TopN keeps the N most relevant documents after reranking, 0 keeps all
+	// Optional. Default: 0
+	TopN int
+	// ScoreThreshold drops the documents whose relevance score is lower than it
+	// Optional. Default: no threshold
+	ScoreThreshold *float64
+	// BatchSize is the max number of documents in a single request, larger inputs are split into batches.
+	// The scores of a cross-encoder are absolute, so the documents of different batches are ranked together.
+	// Optional. Default: the limit of the provider
+	BatchSize int
+	// ScoreFieldKey specifies the key in metadata to store the relevance score, in addition to WithScore.
+	// Optional. Default: only WithScore
+	ScoreFieldKey *string
+	// Timeout specifies the maximum duration to wait for API responses
+	// If HTTPClient is set, Timeout will not be used.
+	// Optional. Default: no timeout
+	Timeout time.Duration
+	// HTTPClient specifies the client to send HTTP requests.
+	// If HTTPClient is set, Timeout will not be used.
+	// Optional. Default &http.Client{Timeout: Timeout}
+	HTTPClient *http.Client
+}
+
+type options struct {
+	query string
+	topN  *int
+}
+
+// WithQuery sets the query which the documents are reranked against, it is required by Transform.
+func WithQuery(query string) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.query = query
+	})
+}
+
+// WithTopN overrides Config.TopN for a single Transform.
+func WithTopN(topN int) document.TransformerOption {
+	return document.WrapTransformerImplSpecificOptFn(func(o *options) {
+		o.topN = &topN
+	})
+}
+
+// NewReranker creates a document transformer which scores the documents against the query by a rerank API,
+// and returns them in the descending order of the relevance score.
+func NewReranker(ctx context.Context, config *Config) (document.Transformer, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	p, ok := providers[config.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s", config.Provider)
+	}
+	if config.APIKey == "" {
+		return nil, errors.New("api key is required")
+	}
+	model := config.Model
+	if model == "" {
+		model = p.defaultModel
+	}
+	if model == "" {
+		return nil, fmt.Errorf("model is required by provider %s", config.Provider)
+	}
+	if config.TopN < 0 {
+		return nil, fmt.Errorf("top n must not be negative, given=%d", config.TopN)
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = p.maxBatchSize
+	}
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = p.defaultURL
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &reranker{
+		provider:       config.Provider,
+		p:              p,
+		apiKey:         config.APIKey,
+		url:            baseURL,
+		model:          model,
+		topN:           config.TopN,
+		scoreThreshold: config.ScoreThreshold,
+		batchSize:      batchSize,
+		scoreFieldKey:  config.ScoreFieldKey,
+		cli:            httpClient,
+	}, nil
+}
+
+type reranker struct {
+	provider       Provider
+	p              *provider
+	apiKey         string
+	url            string
+	model          string
+	topN           int
+	scoreThreshold *float64
+	batchSize      int
+	scoreFieldKey  *string
+	cli            *http.Client
+}
+
+func (r *reranker) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	option := document.GetTransformerImplSpecificOptions(&options{topN: &r.topN}, opts...)
+	if option.query == "" {
+		return nil, errors.New("query is required, set it by WithQuery")
+	}
+	if len(src) == 0 {
+		return []*schema.Document{}, nil
+	}
+
+	scored := make([]*schema.Document, 0, len(src))
+	for start := 0; start < len(src); start += r.batchSize {
+		batch := src[start:min(start+r.batchSize, len(src))]
+		texts := make([]string, len(batch))
+		for i, doc := range batch {
+			texts[i] = doc.Content
+		}
+
+		scores, err := r.rerank(ctx, option.query, texts)
+		if err != nil {
+			return nil, fmt.Errorf("rerank documents[%d:%d] fail: %w", start, start+len(batch), err)
+		}
+		for i, doc := range batch {
+			score, ok := scores[i]
+			if !ok {
+				// not returned by the api, e.g. truncated by its own top n
+				continue
+			}
+			if r.scoreThreshold != nil && score < *r.scoreThreshold {
+				continue
+			}
+			scored = append(scored, r.withScore(doc, score))
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score() > scored[j].Score()
+	})
+	if topN := *option.topN; topN > 0 && len(scored) > topN {
+		scored = scored[:topN]
+	}
+	return scored, nil
+}
+
+// withScore returns a copy of the document with the score, the input documents are not modified.
+func (r *reranker) withScore(doc *schema.Document, score float64) *schema.Document {
+	nd := &schema.Document{
+		ID:       doc.ID,
+		Content:  doc.Content,
+		MetaData: make(map[string]any, len(doc.MetaData)+2),
+	}
+	for k, v := range doc.MetaData {
+		nd.MetaData[k] = v
+	}
+	nd.WithScore(score)
+	if r.scoreFieldKey != nil {
+		nd.MetaData[*r.scoreFieldKey] = score
+	}
+	return nd
+}
+
+// rerank returns the scores of the texts by their indexes.
+func (r *reranker) rerank(ctx context.Context, query string, texts []string) (map[int]float64, error) {
+	body, err := json.Marshal(r.p.buildRequest(r.model, query, texts))
+	if err != nil {
+		return nil, fmt.Errorf("marshal request fail: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request fail: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+
+	resp, err := r.cli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request fail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response fail: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Provider:   r.provider,
+			StatusCode: resp.StatusCode,
+			Message:    errorMessage(respBody),
+		}
+	}
+
+	scores, err := r.p.parseResponse(respBody, len(texts))
+	if err != nil {
+		return nil, err
+	}
+	for idx := range scores {
+		if idx < 0 || idx >= len(texts) {
+			return nil, fmt.Errorf("index %d of the result is out of range [0, %d)", idx, len(texts))
+		}
+	}
+	return scores, nil
+}
+
+func (r *reranker) GetType() string {
+	return "RerankAPIReranker"
+}
+
+// APIError is returned when the rerank API responds with a non-200 status code.
+type APIError struct {
+	Provider   Provider
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s rerank api error, status code: %d, message: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// errorMessage extracts the message from the error response of the providers, or returns the raw body.
+func errorMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+		Detail  any    `json:"detail"`
+		Error   struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil {
+		switch {
+		case resp.Message != "":
+			return resp.Message
+		case resp.Error.Message != "":
+			return resp.Error.Message
+		case resp.Detail != nil:
+			return fmt.Sprint(resp.Detail)
+		}
+	}
+	return string(body)
+}
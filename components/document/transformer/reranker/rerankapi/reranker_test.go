@@ -0,0 +1,221 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rerankapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// relevance of the stub server, the documents containing the query score higher
+func relevance(query, text string) float64 {
+	if strings.Contains(text, query) {
+		return 0.5 + float64(len(text))/100
+	}
+	return float64(len(text)) / 100
+}
+
+// newStubServer serves the rerank api of the provider and records the sizes of the batches.
+func newStubServer(t *testing.T, p Provider, batches *[]int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"invalid api key"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		var query string
+		var texts []string
+		var resp any
+		switch p {
+		case ProviderDashScope:
+			req := &dashScopeRequest{}
+			if err := json.Unmarshal(body, req); err != nil {
+				t.Error(err)
+			}
+			query, texts = req.Input.Query, req.Input.Documents
+			out := &dashScopeResponse{}
+			for i, text := range texts {
+				out.Output.Results = append(out.Output.Results, rerankResult{Index: i, RelevanceScore: relevance(query, text)})
+			}
+			resp = out
+		case ProviderArk:
+			req := &arkRequest{}
+			if err := json.Unmarshal(body, req); err != nil {
+				t.Error(err)
+			}
+			out := &arkResponse{}
+			for _, d := range req.Datas {
+				texts = append(texts, d.Content)
+				out.Data.Scores = append(out.Data.Scores, relevance(d.Query, d.Content))
+			}
+			resp = out
+		default:
+			req := &cohereRequest{}
+			if err := json.Unmarshal(body, req); err != nil {
+				t.Error(err)
+			}
+			query, texts = req.Query, req.Documents
+			out := &cohereResponse{}
+			// return in the order of relevance like the real apis
+			for i := len(texts) - 1; i >= 0; i-- {
+				out.Results = append(out.Results, rerankResult{Index: i, RelevanceScore: relevance(query, texts[i])})
+			}
+			resp = out
+		}
+		*batches = append(*batches, len(texts))
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestReranker(t *testing.T) {
+	ctx := context.Background()
+	docs := []*schema.Document{
+		{ID: "1", Content: "go", MetaData: map[string]any{"source": "a"}},
+		{ID: "2", Content: "eino is a framework"},
+		{ID: "3", Content: "python is a language"},
+		{ID: "4", Content: "eino"},
+		{ID: "5", Content: "rust is fast"},
+	}
+
+	for _, p := range []Provider{ProviderCohere, ProviderJina, ProviderQianfan, ProviderDashScope, ProviderArk} {
+		t.Run(string(p), func(t *testing.T) {
+			var batches []int
+			srv := newStubServer(t, p, &batches)
+			scoreKey := "rerank_score"
+			r, err := NewReranker(ctx, &Config{
+				Provider:      p,
+				APIKey:        "key",
+				BaseURL:       srv.URL,
+				Model:         "rerank-model",
+				BatchSize:     2,
+				ScoreFieldKey: &scoreKey,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := r.Transform(ctx, docs, WithQuery("eino"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(batches, []int{2, 2, 1}) {
+				t.Fatalf("unexpected batches: %v", batches)
+			}
+			var ids []string
+			for _, doc := range result {
+				ids = append(ids, doc.ID)
+			}
+			if strings.Join(ids, ",") != "2,4,3,5,1" {
+				t.Fatalf("unexpected order: %v", ids)
+			}
+			if result[0].Score() != relevance("eino", docs[1].Content) || result[0].MetaData[scoreKey] != result[0].Score() {
+				t.Fatalf("unexpected score: %v", result[0].MetaData)
+			}
+			if result[4].MetaData["source"] != "a" || docs[0].Score() != 0 {
+				t.Fatal("metadata should be copied without modifying the input")
+			}
+
+			result, err = r.Transform(ctx, docs, WithQuery("eino"), WithTopN(2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result) != 2 || result[1].ID != "4" {
+				t.Fatalf("unexpected top n: %v", result)
+			}
+		})
+	}
+}
+
+func TestRerankerOptions(t *testing.T) {
+	ctx := context.Background()
+	var batches []int
+	srv := newStubServer(t, ProviderCohere, &batches)
+
+	if _, err := NewReranker(ctx, &Config{Provider: "unknown", APIKey: "key"}); err == nil {
+		t.Fatal("provider should be checked")
+	}
+	if _, err := NewReranker(ctx, &Config{Provider: ProviderCohere, APIKey: "key"}); err == nil {
+		t.Fatal("model should be required by cohere")
+	}
+
+	threshold := 0.5
+	r, err := NewReranker(ctx, &Config{
+		Provider:       ProviderJina,
+		APIKey:         "key",
+		BaseURL:        srv.URL,
+		TopN:           3,
+		ScoreThreshold: &threshold,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := []*schema.Document{{ID: "1", Content: "eino"}, {ID: "2", Content: "go"}, {ID: "3", Content: "about eino"}}
+	if _, err = r.Transform(ctx, docs); err == nil || !strings.Contains(err.Error(), "query is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := r.Transform(ctx, docs, WithQuery("eino"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[0].ID != "3" || result[1].ID != "1" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if result, err = r.Transform(ctx, nil, WithQuery("eino")); err != nil || len(result) != 0 {
+		t.Fatalf("unexpected result: %v, %v", result, err)
+	}
+
+	r, err = NewReranker(ctx, &Config{Provider: ProviderJina, APIKey: "wrong", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Transform(ctx, docs, WithQuery("eino"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "invalid api key" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	if _, err := parseArkResponse([]byte(`{"code":1000,"message":"rate limited"}`), 1); err == nil {
+		t.Fatal("error code should be checked")
+	}
+	if _, err := parseArkResponse([]byte(`{"code":0,"data":{"scores":[0.1]}}`), 2); err == nil {
+		t.Fatal("number of scores should be checked")
+	}
+	if _, err := parseDashScopeResponse([]byte(`{"code":"InvalidParameter","message":"bad"}`), 1); err == nil {
+		t.Fatal("error code should be checked")
+	}
+	if msg := errorMessage([]byte(`{"error":{"message":"quota exceeded"}}`)); msg != "quota exceeded" {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+	if msg := errorMessage([]byte(`bad gateway`)); msg != "bad gateway" {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
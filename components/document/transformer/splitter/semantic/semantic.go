@@ -155,7 +155,7 @@ func (s *splitter) splitText(ctx context.Context, text string, separators []stri
 	// cosine distances
 	distances := make([]float64, len(texts))
 	for i := 1; i < len(texts); i++ {
-		distances[i] = 1 - cosine(vectors[i-1], vectors[i])
+		distances[i] = 1 - CosineSimilarity(vectors[i-1], vectors[i])
 	}
 
 	threshold := calThreshold(distances, s.percentile)
@@ -183,10 +183,15 @@ func (s *splitter) GetType() string {
 	return "SemanticSplitter"
 }
 
-func cosine(vec1, vec2 []float64) float64 {
+// CosineSimilarity returns the cosine similarity of two vectors of the same dimension,
+// or 0 if either of them is a zero vector.
+func CosineSimilarity(vec1, vec2 []float64) float64 {
 	dotProduct := dot(vec1, vec2)
 	normVec1 := math.Sqrt(dot(vec1, vec1))
 	normVec2 := math.Sqrt(dot(vec2, vec2))
+	if normVec1 == 0 || normVec2 == 0 {
+		return 0
+	}
 	return dotProduct / (normVec1 * normVec2)
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/schema"
+	"math"
 	"math/rand/v2"
 	"reflect"
 	"testing"
@@ -138,3 +139,23 @@ func TestSemanticSplitter(t *testing.T) {
 		})
 	}
 }
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name       string
+		vec1, vec2 []float64
+		want       float64
+	}{
+		{name: "same direction", vec1: []float64{1, 2}, vec2: []float64{2, 4}, want: 1},
+		{name: "orthogonal", vec1: []float64{1, 0}, vec2: []float64{0, 3}, want: 0},
+		{name: "opposite", vec1: []float64{1, 1}, vec2: []float64{-1, -1}, want: -1},
+		{name: "zero vector", vec1: []float64{0, 0}, vec2: []float64{1, 1}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CosineSimilarity(tt.vec1, tt.vec2); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CosineSimilarity() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bbolt
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/embedding/cache"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	defaultBucket = "eino_embedding_cache"
+	headerSize    = 8
+)
+
+// Cacher is a [cache.Cacher] backed by a bbolt database file,
+// so that embeddings survive process restarts without running a cache server.
+type Cacher struct {
+	db     *bbolt.DB
+	bucket []byte
+
+	now func() time.Time
+}
+
+type Option interface {
+	apply(*Cacher)
+}
+
+type optionFunc func(*Cacher)
+
+func (f optionFunc) apply(c *Cacher) {
+	f(c)
+}
+
+// WithBucket sets the bucket used to store embeddings, default is "eino_embedding_cache".
+func WithBucket(bucket string) Option {
+	return optionFunc(func(c *Cacher) {
+		c.bucket = []byte(bucket)
+	})
+}
+
+var _ cache.BatchCacher = (*Cacher)(nil)
+
+// NewCacher creates a [Cacher] on an opened bbolt database, the bucket is created if not exists.
+func NewCacher(db *bbolt.DB, opts ...Option) (*Cacher, error) {
+	if db == nil {
+		return nil, errors.New("bbolt db is nil")
+	}
+
+	cacher := &Cacher{
+		db:     db,
+		bucket: []byte(defaultBucket),
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt.apply(cacher)
+	}
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(cacher.bucket)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("create bucket failed: %w", err)
+	}
+
+	return cacher, nil
+}
+
+func (c *Cacher) Set(ctx context.Context, key string, value []float64, expire time.Duration) error {
+	return c.MSet(ctx, []string{key}, [][]float64{value}, expire)
+}
+
+func (c *Cacher) Get(ctx context.Context, key string) ([]float64, bool, error) {
+	values, found, err := c.MGet(ctx, []string{key})
+	if err != nil {
+		return nil, false, err
+	}
+	return values[0], found[0], nil
+}
+
+// MSet stores all values in a single transaction.
+func (c *Cacher) MSet(_ context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("keys and values length mismatch, keys: %d, values: %d", len(keys), len(values))
+	}
+
+	var expireAt int64
+	if expire > 0 {
+		expireAt = c.now().Add(expire).UnixNano()
+	}
+
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(c.bucket)
+		for i, key := range keys {
+			if err := b.Put([]byte(key), encode(values[i], expireAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MGet reads all values in a single transaction.
+func (c *Cacher) MGet(_ context.Context, keys []string) ([][]float64, []bool, error) {
+	var (
+		values  = make([][]float64, len(keys))
+		found   = make([]bool, len(keys))
+		expired []string
+		now     = c.now().UnixNano()
+	)
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(c.bucket)
+		for i, key := range keys {
+			data := b.Get([]byte(key))
+			if data == nil {
+				continue
+			}
+
+			// data is only valid during the transaction, decode copies it out
+			v, expireAt, err := decode(data)
+			if err != nil {
+				return err
+			}
+			if expireAt > 0 && now >= expireAt {
+				expired = append(expired, key)
+				continue
+			}
+			values[i], found[i] = v, true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(expired) > 0 {
+		if err := c.delete(expired); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return values, found, nil
+}
+
+// Purge removes all expired embeddings from the database.
+func (c *Cacher) Purge(_ context.Context) error {
+	now := c.now().UnixNano()
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		cur := tx.Bucket(c.bucket).Cursor()
+		for k, v := cur.First(); k != nil; {
+			if len(v) >= headerSize {
+				expireAt := int64(binary.BigEndian.Uint64(v[:headerSize]))
+				if expireAt > 0 && now >= expireAt {
+					if err := cur.Delete(); err != nil {
+						return err
+					}
+					// after Delete, the cursor points to the next item
+					k, v = cur.Seek(k)
+					continue
+				}
+			}
+			k, v = cur.Next()
+		}
+		return nil
+	})
+}
+
+// delete removes the expired keys.
+func (c *Cacher) delete(keys []string) error {
+	now := c.now().UnixNano()
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(c.bucket)
+		for _, key := range keys {
+			// the key may have been refreshed by another writer since it was read
+			data := b.Get([]byte(key))
+			if len(data) < headerSize {
+				continue
+			}
+			expireAt := int64(binary.BigEndian.Uint64(data[:headerSize]))
+			if expireAt > 0 && now >= expireAt {
+				if err := b.Delete([]byte(key)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// encode lays out the expiration time in unix nanoseconds (0 means never)
+// followed by the embedding as little-endian float64 values.
+func encode(value []float64, expireAt int64) []byte {
+	data := make([]byte, headerSize+len(value)*8)
+	binary.BigEndian.PutUint64(data[:headerSize], uint64(expireAt))
+	for i, f := range value {
+		binary.LittleEndian.PutUint64(data[headerSize+i*8:], math.Float64bits(f))
+	}
+	return data
+}
+
+func decode(data []byte) ([]float64, int64, error) {
+	if len(data) < headerSize || (len(data)-headerSize)%8 != 0 {
+		return nil, 0, fmt.Errorf("invalid cached embedding, length=%d", len(data))
+	}
+
+	expireAt := int64(binary.BigEndian.Uint64(data[:headerSize]))
+	value := make([]float64, (len(data)-headerSize)/8)
+	for i := range value {
+		value[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[headerSize+i*8:]))
+	}
+	return value, expireAt, nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bbolt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func openDB(t *testing.T) *bbolt.DB {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "cache.db"), 0600, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestCacher(t *testing.T) {
+	ctx := context.Background()
+	value := []float64{1.1, -2.2, 3.3}
+
+	t.Run("nil db", func(t *testing.T) {
+		_, err := NewCacher(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		c, err := NewCacher(openDB(t), WithBucket("test"))
+		require.NoError(t, err)
+
+		require.NoError(t, c.Set(ctx, "key", value, 0))
+		got, ok, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, value, got)
+
+		_, ok, err = c.Get(ctx, "missing")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("persist across reopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.db")
+		db, err := bbolt.Open(path, 0600, nil)
+		require.NoError(t, err)
+		c, err := NewCacher(db)
+		require.NoError(t, err)
+		require.NoError(t, c.Set(ctx, "key", value, time.Hour))
+		require.NoError(t, db.Close())
+
+		db, err = bbolt.Open(path, 0600, nil)
+		require.NoError(t, err)
+		defer db.Close()
+		c, err = NewCacher(db)
+		require.NoError(t, err)
+		got, ok, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, value, got)
+	})
+
+	t.Run("expire and purge", func(t *testing.T) {
+		now := time.Now()
+		c, err := NewCacher(openDB(t))
+		require.NoError(t, err)
+		c.now = func() time.Time { return now }
+
+		require.NoError(t, c.Set(ctx, "a", value, time.Minute))
+		require.NoError(t, c.Set(ctx, "b", value, time.Minute))
+		require.NoError(t, c.Set(ctx, "c", value, time.Hour))
+		require.NoError(t, c.Set(ctx, "d", value, 0))
+
+		now = now.Add(time.Minute)
+		_, ok, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, c.Purge(ctx))
+		var keys []string
+		require.NoError(t, c.db.View(func(tx *bbolt.Tx) error {
+			return tx.Bucket(c.bucket).ForEach(func(k, _ []byte) error {
+				keys = append(keys, string(k))
+				return nil
+			})
+		}))
+		assert.Equal(t, []string{"c", "d"}, keys)
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		c, err := NewCacher(openDB(t))
+		require.NoError(t, err)
+		require.NoError(t, c.db.Update(func(tx *bbolt.Tx) error {
+			return tx.Bucket(c.bucket).Put([]byte("key"), []byte{1, 2, 3})
+		}))
+
+		_, _, err = c.Get(ctx, "key")
+		assert.Error(t, err)
+	})
+	t.Run("mget and mset", func(t *testing.T) {
+		c, err := NewCacher(openDB(t))
+		require.NoError(t, err)
+
+		require.NoError(t, c.MSet(ctx, []string{"a", "b"}, [][]float64{{1}, {2, 3}}, time.Minute))
+		assert.Error(t, c.MSet(ctx, []string{"a"}, nil, 0))
+
+		values, found, err := c.MGet(ctx, []string{"a", "missing", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, found)
+		assert.Equal(t, [][]float64{{1}, nil, {2, 3}}, values)
+	})
+}
@@ -31,3 +31,17 @@ type Cacher interface {
 	// If the value is not of type []float64, it returns an error.
 	Get(ctx context.Context, key string) ([]float64, bool, error)
 }
+
+// BatchCacher is an optional interface of Cacher, which gets and sets multiple keys in a single round trip.
+// The Embedder uses MGet and MSet instead of calling Get and Set once per text if the Cacher implements it.
+type BatchCacher interface {
+	Cacher
+
+	// MGet retrieves the values from the cache with the given keys.
+	// values[i] and found[i] correspond to keys[i], found[i] is false if keys[i] does not exist.
+	MGet(ctx context.Context, keys []string) (values [][]float64, found []bool, err error)
+
+	// MSet stores values[i] in the cache with keys[i].
+	// If a key already exists, it will be overwritten.
+	MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error
+}
@@ -19,6 +19,9 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudwego/eino/components/embedding"
@@ -34,6 +37,29 @@ type Embedder struct {
 	cacher     Cacher
 	generator  Generator
 	expiration time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]*call
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Stats is the cache statistics of an [Embedder], counted per text.
+type Stats struct {
+	// Hits is the number of texts whose embeddings were found in the cache.
+	Hits uint64
+	// Misses is the number of texts which had to be embedded by the underlying embedder.
+	Misses uint64
+}
+
+// HitRate returns the fraction of texts served from the cache, or 0 if nothing has been embedded yet.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
 }
 
 type Option interface {
@@ -74,6 +100,7 @@ func NewEmbedder(embedder embedding.Embedder, opts ...Option) (*Embedder, error)
 	e := &Embedder{
 		embedder:   embedder,
 		expiration: time.Hour * 2,
+		inflight:   make(map[string]*call),
 	}
 	for _, opt := range opts {
 		opt.apply(e)
@@ -92,10 +119,10 @@ func NewEmbedder(embedder embedding.Embedder, opts ...Option) (*Embedder, error)
 
 func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
 	var (
-		embeddingsByKey = make(map[int][]float64)
-		embeddingOpts   = embedding.GetCommonOptions(nil, opts...)
-		uncached        []int
-		uncachedTexts   []string
+		embeddingOpts = embedding.GetCommonOptions(nil, opts...)
+		keys          = make([]string, len(texts))
+		textByKey     = make(map[string]string, len(texts))
+		uniqueKeys    = make([]string, 0, len(texts))
 	)
 
 	// generate options for the generator
@@ -104,47 +131,205 @@ func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...emb
 		generatorOpt.Model = *embeddingOpts.Model
 	}
 
-	// Get cached embeddings and find uncached texts
+	// Deduplicate identical texts, so that each of them is looked up and embedded only once
 	for idx, text := range texts {
 		key := e.generator.Generate(ctx, text, generatorOpt)
-		emb, ok, err := e.cacher.Get(ctx, key)
+		keys[idx] = key
+		if _, ok := textByKey[key]; !ok {
+			textByKey[key] = text
+			uniqueKeys = append(uniqueKeys, key)
+		}
+	}
+
+	// Get cached embeddings
+	embeddingsByKey, err := e.getCached(ctx, uniqueKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find uncached texts, the ones being embedded by another call are waited for instead of embedded again
+	var uncached []string
+	for _, key := range uniqueKeys {
+		if _, ok := embeddingsByKey[key]; !ok {
+			uncached = append(uncached, key)
+		}
+	}
+	owned, waiting := e.claim(uncached)
+
+	var hits uint64
+	for _, key := range keys {
+		if _, ok := embeddingsByKey[key]; ok {
+			hits++
+		}
+	}
+	e.hits.Add(hits)
+	e.misses.Add(uint64(len(keys)) - hits)
+
+	for {
+		// Embed the uncached texts
+		if len(owned) > 0 {
+			if err := e.embedUncached(ctx, owned, textByKey, embeddingsByKey, opts...); err != nil {
+				return nil, err
+			}
+		}
+
+		// The texts of the calls canceled or timed out are embedded again by the current call with its own context
+		var retry []string
+		for key, c := range waiting {
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if c.err != nil {
+				if isContextError(c.err) && ctx.Err() == nil {
+					retry = append(retry, key)
+					continue
+				}
+				return nil, c.err
+			}
+			embeddingsByKey[key] = c.embedding
+		}
+		if len(retry) == 0 {
+			break
+		}
+		owned, waiting = e.claim(retry)
+	}
+
+	// Convert the map to a slice
+	result := make([][]float64, len(texts))
+	for i, key := range keys {
+		result[i] = embeddingsByKey[key]
+	}
+
+	return result, nil
+}
+
+// call is an in-flight embedding of a single text, shared by concurrent EmbedStrings calls.
+type call struct {
+	done      chan struct{}
+	embedding []float64
+	err       error
+}
+
+// claim registers the keys which are not in flight as owned by the current call,
+// and returns the in-flight calls of the others to wait for.
+func (e *Embedder) claim(keys []string) (owned []string, waiting map[string]*call) {
+	waiting = make(map[string]*call)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, key := range keys {
+		if c, ok := e.inflight[key]; ok {
+			waiting[key] = c
+			continue
+		}
+		e.inflight[key] = &call{done: make(chan struct{})}
+		owned = append(owned, key)
+	}
+	return owned, waiting
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (e *Embedder) getCached(ctx context.Context, keys []string) (map[string][]float64, error) {
+	embeddingsByKey := make(map[string][]float64, len(keys))
+	if len(keys) == 0 {
+		return embeddingsByKey, nil
+	}
+
+	if bc, ok := e.cacher.(BatchCacher); ok {
+		values, found, err := bc.MGet(ctx, keys)
 		if err != nil {
 			return nil, err
-		} else if ok {
-			embeddingsByKey[idx] = emb
-		} else {
-			// If the key is not found, we consider it as uncached
-			uncached = append(uncached, idx)
-			uncachedTexts = append(uncachedTexts, text)
 		}
+		if len(values) != len(keys) || len(found) != len(keys) {
+			return nil, fmt.Errorf("embedding/cache: unexpected MGet result length, keys: %d, values: %d, found: %d",
+				len(keys), len(values), len(found))
+		}
+		for i, key := range keys {
+			if found[i] {
+				embeddingsByKey[key] = values[i]
+			}
+		}
+		return embeddingsByKey, nil
 	}
 
-	// Embed the uncached texts
-	if len(uncachedTexts) > 0 {
-		uncachedEmbeddings, err := e.embedder.EmbedStrings(ctx, uncachedTexts, opts...)
+	for _, key := range keys {
+		emb, ok, err := e.cacher.Get(ctx, key)
 		if err != nil {
 			return nil, err
+		} else if ok {
+			embeddingsByKey[key] = emb
 		}
+	}
+	return embeddingsByKey, nil
+}
 
-		// Cache the uncachedEmbeddings
-		for i, idx := range uncached {
-			key := e.generator.Generate(ctx, texts[idx], generatorOpt)
-			if err := e.cacher.Set(ctx, key, uncachedEmbeddings[i], e.expiration); err != nil {
-				_ = err // skip caching if there's an error
+// embedUncached embeds the texts of keys owned by the current call with a single upstream request,
+// caches the results and wakes up the calls waiting for them.
+func (e *Embedder) embedUncached(ctx context.Context, keys []string, textByKey map[string]string,
+	embeddingsByKey map[string][]float64, opts ...embedding.Option) (err error) {
+
+	var embeddings [][]float64
+	defer func() {
+		e.mu.Lock()
+		for i, key := range keys {
+			c := e.inflight[key]
+			delete(e.inflight, key)
+			switch {
+			case err != nil:
+				c.err = err
+			case i < len(embeddings):
+				c.embedding = embeddings[i]
+			default:
+				c.err = errors.New("embedding/cache: embedding aborted")
 			}
-			embeddingsByKey[idx] = uncachedEmbeddings[i]
+			close(c.done)
 		}
+		e.mu.Unlock()
+	}()
+
+	texts := make([]string, len(keys))
+	for i, key := range keys {
+		texts[i] = textByKey[key]
 	}
 
-	// Convert the map to a slice
-	result := make([][]float64, len(texts))
-	for i := range texts {
-		if emb, ok := embeddingsByKey[i]; ok {
-			result[i] = emb
-		} else {
-			result[i] = nil // it seems that such a case should not happen
+	embeddings, err = e.embedder.EmbedStrings(ctx, texts, opts...)
+	if err != nil {
+		return err
+	}
+	if len(embeddings) != len(texts) {
+		return fmt.Errorf("embedding/cache: unexpected number of embeddings, expected: %d, got: %d", len(texts), len(embeddings))
+	}
+
+	// Cache the embeddings, skip caching if there's an error
+	if bc, ok := e.cacher.(BatchCacher); ok {
+		_ = bc.MSet(ctx, keys, embeddings, e.expiration)
+	} else {
+		for i, key := range keys {
+			_ = e.cacher.Set(ctx, key, embeddings[i], e.expiration)
 		}
 	}
 
-	return result, nil
+	for i, key := range keys {
+		embeddingsByKey[key] = embeddings[i]
+	}
+	return nil
+}
+
+// Stats returns the cache hit and miss counters accumulated since the [Embedder] was created
+// or since the last call to [Embedder.ResetStats].
+func (e *Embedder) Stats() Stats {
+	return Stats{
+		Hits:   e.hits.Load(),
+		Misses: e.misses.Load(),
+	}
+}
+
+// ResetStats resets the cache hit and miss counters to zero.
+func (e *Embedder) ResetStats() {
+	e.hits.Store(0)
+	e.misses.Store(0)
 }
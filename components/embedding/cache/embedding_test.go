@@ -19,6 +19,8 @@ package cache
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -122,6 +124,12 @@ func TestEmbedder_EmbedStrings(t *testing.T) {
 		result, err := e.EmbedStrings(ctx, texts)
 		assert.NoError(t, err)
 		assert.Equal(t, embeddings, result)
+		assert.Equal(t, Stats{Hits: 1, Misses: 1}, e.Stats())
+		assert.Equal(t, 0.5, e.Stats().HitRate())
+
+		e.ResetStats()
+		assert.Equal(t, Stats{}, e.Stats())
+		assert.Equal(t, float64(0), e.Stats().HitRate())
 
 		mc.AssertExpectations(t)
 		me.AssertExpectations(t)
@@ -199,3 +207,224 @@ func TestEmbedder_EmbedStrings(t *testing.T) {
 		me.AssertExpectations(t)
 	})
 }
+
+type mockBatchCacher struct {
+	mockCacher
+}
+
+var _ BatchCacher = (*mockBatchCacher)(nil)
+
+func (m *mockBatchCacher) MGet(ctx context.Context, keys []string) ([][]float64, []bool, error) {
+	args := m.Called(ctx, keys)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([][]float64), args.Get(1).([]bool), args.Error(2)
+}
+
+func (m *mockBatchCacher) MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	args := m.Called(ctx, keys, values, expire)
+	return args.Error(0)
+}
+
+// blockingEmbedder blocks EmbedStrings until release is closed, and counts the texts it receives.
+type blockingEmbedder struct {
+	release chan struct{}
+	calls   atomic.Int32
+	texts   atomic.Int32
+}
+
+func (b *blockingEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	b.calls.Add(1)
+	b.texts.Add(int32(len(texts)))
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = []float64{float64(len(text))}
+	}
+	return ret, nil
+}
+
+func TestEmbedder_Batch(t *testing.T) {
+	ctx := context.Background()
+	expiration := time.Minute
+	generatorOpt := GeneratorOption{}
+
+	t.Run("duplicate texts in one batch", func(t *testing.T) {
+		mc := new(mockCacher)
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(mc), WithGenerator(NewSimpleGenerator()), WithExpiration(expiration))
+		require.NoError(t, err)
+
+		key0 := e.generator.Generate(ctx, "foo", generatorOpt)
+		key1 := e.generator.Generate(ctx, "bar", generatorOpt)
+
+		mc.On("Get", mock.Anything, key0).Return(nil, false, nil).Once()
+		mc.On("Get", mock.Anything, key1).Return(nil, false, nil).Once()
+		me.On("EmbedStrings", mock.Anything, []string{"foo", "bar"}, mock.Anything).Return([][]float64{{1}, {2}}, nil).Once()
+		mc.On("Set", mock.Anything, key0, []float64{1}, expiration).Return(nil).Once()
+		mc.On("Set", mock.Anything, key1, []float64{2}, expiration).Return(nil).Once()
+
+		result, err := e.EmbedStrings(ctx, []string{"foo", "bar", "foo"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}, {2}, {1}}, result)
+		assert.Equal(t, Stats{Misses: 3}, e.Stats())
+		mc.AssertExpectations(t)
+		me.AssertExpectations(t)
+	})
+
+	t.Run("batch cacher", func(t *testing.T) {
+		mc := new(mockBatchCacher)
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(mc), WithGenerator(NewSimpleGenerator()), WithExpiration(expiration))
+		require.NoError(t, err)
+
+		key0 := e.generator.Generate(ctx, "foo", generatorOpt)
+		key1 := e.generator.Generate(ctx, "bar", generatorOpt)
+
+		mc.On("MGet", mock.Anything, []string{key0, key1}).Return([][]float64{{1}, nil}, []bool{true, false}, nil)
+		me.On("EmbedStrings", mock.Anything, []string{"bar"}, mock.Anything).Return([][]float64{{2}}, nil)
+		mc.On("MSet", mock.Anything, []string{key1}, [][]float64{{2}}, expiration).Return(nil)
+
+		result, err := e.EmbedStrings(ctx, []string{"foo", "bar"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}, {2}}, result)
+		assert.Equal(t, Stats{Hits: 1, Misses: 1}, e.Stats())
+		mc.AssertExpectations(t)
+		me.AssertExpectations(t)
+	})
+
+	t.Run("batch cacher get error", func(t *testing.T) {
+		mc := new(mockBatchCacher)
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(mc), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+
+		mc.On("MGet", mock.Anything, mock.Anything).Return(nil, nil, errors.New("mget error"))
+
+		_, err = e.EmbedStrings(ctx, []string{"foo"})
+		assert.Error(t, err)
+		mc.AssertExpectations(t)
+	})
+
+	t.Run("unexpected number of embeddings", func(t *testing.T) {
+		mc := new(mockCacher)
+		me := new(mockEmbedder)
+		e, err := NewEmbedder(me, WithCacher(mc), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+
+		mc.On("Get", mock.Anything, mock.Anything).Return(nil, false, nil)
+		me.On("EmbedStrings", mock.Anything, mock.Anything, mock.Anything).Return([][]float64{{1}}, nil)
+
+		_, err = e.EmbedStrings(ctx, []string{"foo", "bar"})
+		assert.Error(t, err)
+		assert.Empty(t, e.inflight)
+	})
+
+	t.Run("single flight", func(t *testing.T) {
+		be := &blockingEmbedder{release: make(chan struct{})}
+		e, err := NewEmbedder(be, WithCacher(newMapCacher()), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+
+		const n = 5
+		var wg sync.WaitGroup
+		results := make([][][]float64, n)
+		errs := make([]error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = e.EmbedStrings(ctx, []string{"foo", "hello"})
+			}(i)
+		}
+
+		// wait until every call either owns or waits for the in-flight texts
+		assert.Eventually(t, func() bool {
+			return e.Stats().Misses == 2*n
+		}, time.Second, time.Millisecond)
+		close(be.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), be.calls.Load())
+		assert.Equal(t, int32(2), be.texts.Load())
+		for i := 0; i < n; i++ {
+			assert.NoError(t, errs[i])
+			assert.Equal(t, [][]float64{{3}, {5}}, results[i])
+		}
+		assert.Empty(t, e.inflight)
+
+		// served from the cache afterwards
+		result, err := e.EmbedStrings(ctx, []string{"hello"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{5}}, result)
+		assert.Equal(t, int32(1), be.calls.Load())
+	})
+
+	t.Run("single flight owner canceled", func(t *testing.T) {
+		be := &blockingEmbedder{release: make(chan struct{})}
+		e, err := NewEmbedder(be, WithCacher(newMapCacher()), WithGenerator(NewSimpleGenerator()))
+		require.NoError(t, err)
+
+		ownerCtx, cancel := context.WithCancel(ctx)
+		var (
+			wg                  sync.WaitGroup
+			ownerErr, waiterErr error
+			result              [][]float64
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, ownerErr = e.EmbedStrings(ownerCtx, []string{"foo"})
+		}()
+		assert.Eventually(t, func() bool {
+			return be.calls.Load() == 1
+		}, time.Second, time.Millisecond)
+		go func() {
+			defer wg.Done()
+			result, waiterErr = e.EmbedStrings(ctx, []string{"foo"})
+		}()
+		assert.Eventually(t, func() bool {
+			return e.Stats().Misses == 2
+		}, time.Second, time.Millisecond)
+
+		// the waiter embeds the text by itself rather than failing with the context error of the owner
+		cancel()
+		assert.Eventually(t, func() bool {
+			return be.calls.Load() == 2
+		}, time.Second, time.Millisecond)
+		close(be.release)
+		wg.Wait()
+
+		assert.ErrorIs(t, ownerErr, context.Canceled)
+		assert.NoError(t, waiterErr)
+		assert.Equal(t, [][]float64{{3}}, result)
+		assert.Empty(t, e.inflight)
+	})
+}
+
+type mapCacher struct {
+	mu sync.Mutex
+	m  map[string][]float64
+}
+
+func newMapCacher() *mapCacher {
+	return &mapCacher{m: make(map[string][]float64)}
+}
+
+func (c *mapCacher) Set(_ context.Context, key string, value []float64, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[key] = value
+	return nil
+}
+
+func (c *mapCacher) Get(_ context.Context, key string) ([]float64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.m[key]
+	return v, ok, nil
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lru
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/embedding/cache"
+)
+
+const defaultCapacity = 10000
+
+// Cacher is an in-process [cache.Cacher] which keeps at most capacity embeddings,
+// evicting the least recently used one when full.
+// Entries stored with a positive expire are dropped once expired.
+type Cacher struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+
+	now func() time.Time
+}
+
+type entry struct {
+	key      string
+	value    []float64
+	expireAt time.Time
+}
+
+type Option interface {
+	apply(*Cacher)
+}
+
+type optionFunc func(*Cacher)
+
+func (f optionFunc) apply(c *Cacher) {
+	f(c)
+}
+
+// WithCapacity sets the max number of embeddings kept by the [Cacher], default is 10000.
+// A non-positive capacity is ignored.
+func WithCapacity(capacity int) Option {
+	return optionFunc(func(c *Cacher) {
+		if capacity > 0 {
+			c.capacity = capacity
+		}
+	})
+}
+
+var _ cache.BatchCacher = (*Cacher)(nil)
+
+func NewCacher(opts ...Option) *Cacher {
+	cacher := &Cacher{
+		capacity: defaultCapacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt.apply(cacher)
+	}
+	return cacher
+}
+
+func (c *Cacher) Set(_ context.Context, key string, value []float64, expire time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, c.expireAt(expire))
+	return nil
+}
+
+func (c *Cacher) Get(_ context.Context, key string) ([]float64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.get(key, c.now())
+	return value, ok, nil
+}
+
+func (c *Cacher) MSet(_ context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("keys and values length mismatch, keys: %d, values: %d", len(keys), len(values))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expireAt := c.expireAt(expire)
+	for i, key := range keys {
+		c.set(key, values[i], expireAt)
+	}
+	return nil
+}
+
+func (c *Cacher) MGet(_ context.Context, keys []string) ([][]float64, []bool, error) {
+	values := make([][]float64, len(keys))
+	found := make([]bool, len(keys))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for i, key := range keys {
+		values[i], found[i] = c.get(key, now)
+	}
+	return values, found, nil
+}
+
+func (c *Cacher) expireAt(expire time.Duration) time.Time {
+	if expire > 0 {
+		return c.now().Add(expire)
+	}
+	return time.Time{}
+}
+
+func (c *Cacher) set(key string, value []float64, expireAt time.Time) {
+	value = append([]float64(nil), value...)
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry)
+		e.value, e.expireAt = value, expireAt
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expireAt: expireAt})
+	for c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *Cacher) get(key string, now time.Time) ([]float64, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if !e.expireAt.IsZero() && !now.Before(e.expireAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return append([]float64(nil), e.value...), true
+}
+
+// Len returns the number of embeddings currently held, including expired ones not yet evicted.
+func (c *Cacher) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *Cacher) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("set and get", func(t *testing.T) {
+		c := NewCacher()
+		value := []float64{1.1, 2.2, 3.3}
+		require.NoError(t, c.Set(ctx, "key", value, 0))
+
+		got, ok, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, value, got)
+
+		// the cached value must not be affected by the caller
+		got[0] = 0
+		value[1] = 0
+		got, _, _ = c.Get(ctx, "key")
+		assert.Equal(t, []float64{1.1, 2.2, 3.3}, got)
+
+		_, ok, err = c.Get(ctx, "missing")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("evict least recently used", func(t *testing.T) {
+		c := NewCacher(WithCapacity(2))
+		require.NoError(t, c.Set(ctx, "a", []float64{1}, 0))
+		require.NoError(t, c.Set(ctx, "b", []float64{2}, 0))
+
+		// touch a, so that b becomes the least recently used
+		_, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+
+		require.NoError(t, c.Set(ctx, "c", []float64{3}, 0))
+		assert.Equal(t, 2, c.Len())
+
+		_, ok, _ = c.Get(ctx, "b")
+		assert.False(t, ok)
+		_, ok, _ = c.Get(ctx, "a")
+		assert.True(t, ok)
+		_, ok, _ = c.Get(ctx, "c")
+		assert.True(t, ok)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		c := NewCacher(WithCapacity(1))
+		require.NoError(t, c.Set(ctx, "a", []float64{1}, 0))
+		require.NoError(t, c.Set(ctx, "a", []float64{2}, 0))
+		assert.Equal(t, 1, c.Len())
+
+		got, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []float64{2}, got)
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		now := time.Now()
+		c := NewCacher()
+		c.now = func() time.Time { return now }
+
+		require.NoError(t, c.Set(ctx, "a", []float64{1}, time.Minute))
+		_, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		_, ok, _ = c.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+	t.Run("mget and mset", func(t *testing.T) {
+		c := NewCacher(WithCapacity(2))
+		require.NoError(t, c.MSet(ctx, []string{"a", "b", "c"}, [][]float64{{1}, {2}, {3}}, time.Minute))
+		assert.Error(t, c.MSet(ctx, []string{"a"}, nil, 0))
+
+		values, found, err := c.MGet(ctx, []string{"a", "b", "c"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false, true, true}, found)
+		assert.Equal(t, [][]float64{nil, {2}, {3}}, values)
+	})
+}
@@ -19,6 +19,7 @@ package redis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -48,7 +49,7 @@ func WithPrefix(prefix string) Option {
 	})
 }
 
-var _ cache.Cacher = (*Cacher)(nil)
+var _ cache.BatchCacher = (*Cacher)(nil)
 
 func NewCacher(rdb redis.UniversalClient, opts ...Option) *Cacher {
 	cacher := &Cacher{
@@ -85,3 +86,52 @@ func (c *Cacher) Get(ctx context.Context, key string) ([]float64, bool, error) {
 	}
 	return value, true, nil
 }
+
+// MSet stores the values with pipelining, so that all keys are written in a single round trip.
+func (c *Cacher) MSet(ctx context.Context, keys []string, values [][]float64, expire time.Duration) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("keys and values length mismatch, keys: %d, values: %d", len(keys), len(values))
+	}
+
+	pipe := c.rdb.Pipeline()
+	for i, key := range keys {
+		data, err := c.codec.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, c.prefix+key, data, expire)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// MGet retrieves the values with pipelining, so that all keys are read in a single round trip.
+// Unlike MGET, pipelined GETs also work when keys are spread over several slots of a cluster.
+func (c *Cacher) MGet(ctx context.Context, keys []string) ([][]float64, []bool, error) {
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, c.prefix+key)
+	}
+	// Exec returns the first failed command error, which is redis.Nil for a missing key, check each command instead
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, nil, err
+	}
+
+	values := make([][]float64, len(keys))
+	found := make([]bool, len(keys))
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, nil, err
+		}
+		if err := c.codec.Unmarshal(data, &values[i]); err != nil {
+			return nil, nil, err
+		}
+		found[i] = true
+	}
+	return values, found, nil
+}
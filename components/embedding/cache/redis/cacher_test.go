@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -164,3 +165,49 @@ func TestWithPrefix(t *testing.T) {
 	assert.Equal(t, "custom:", NewCacher(nil, WithPrefix("custom:")).prefix)
 	assert.Equal(t, "custom:", NewCacher(nil, WithPrefix("custom")).prefix)
 }
+
+func TestCacherBatch(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	c := NewCacher(rdb, WithPrefix("test"))
+
+	t.Run("mset and mget", func(t *testing.T) {
+		err := c.MSet(ctx, []string{"a", "b"}, [][]float64{{1.1, 2.2}, {3.3}}, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, mr.Exists("test:a"))
+		assert.Equal(t, time.Minute, mr.TTL("test:b"))
+
+		values, found, err := c.MGet(ctx, []string{"a", "missing", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, found)
+		assert.Equal(t, [][]float64{{1.1, 2.2}, nil, {3.3}}, values)
+
+		assert.Error(t, c.MSet(ctx, []string{"a"}, nil, 0))
+	})
+
+	t.Run("all missing", func(t *testing.T) {
+		values, found, err := c.MGet(ctx, []string{"x", "y"})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false, false}, found)
+		assert.Equal(t, [][]float64{nil, nil}, values)
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		require.NoError(t, mr.Set("test:invalid", "invalid"))
+		_, _, err := c.MGet(ctx, []string{"invalid"})
+		assert.Error(t, err)
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		badRdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+		defer badRdb.Close()
+		bc := NewCacher(badRdb)
+
+		_, _, err := bc.MGet(ctx, []string{"a"})
+		assert.Error(t, err)
+		assert.Error(t, bc.MSet(ctx, []string{"a"}, [][]float64{{1}}, 0))
+	})
+}
@@ -2,10 +2,10 @@ module github.com/cloudwego/eino-ext/components/embedding/cache/redis
 
 go 1.23.0
 
-
 replace github.com/cloudwego/eino-ext/components/embedding/cache => ../
 
 require (
+	github.com/alicebob/miniredis/v2 v2.35.0
 	github.com/bytedance/sonic v1.13.2
 	github.com/cloudwego/eino-ext/components/embedding/cache v0.0.0-00010101000000-000000000000
 	github.com/redis/go-redis/v9 v9.8.0
@@ -42,6 +42,7 @@ require (
 	github.com/stretchr/objx v0.5.2 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	github.com/yargevad/filepathx v1.0.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 	golang.org/x/arch v0.11.0 // indirect
 	golang.org/x/exp v0.0.0-20230713183714-613f0c0eb8a1 // indirect
 	golang.org/x/sys v0.33.0 // indirect
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ollama
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ollama/ollama/api"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+const defaultBaseURL = "http://localhost:11434"
+
+type EmbeddingConfig struct {
+	// BaseURL is the address of the ollama server
+	// Optional. Default: "http://localhost:11434"
+	BaseURL string `json:"base_url"`
+
+	// Timeout specifies the maximum duration to wait for API responses
+	// If HTTPClient is set, Timeout will not be used.
+	// Optional. Default: no timeout
+	Timeout time.Duration `json:"timeout"`
+
+	// HTTPClient specifies the client to send HTTP requests.
+	// If HTTPClient is set, Timeout will not be used.
+	// Optional. Default &http.Client{Timeout: Timeout}
+	HTTPClient *http.Client `json:"http_client"`
+
+	// The following fields correspond to ollama's embed API parameters
+	// Ref: https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embeddings
+
+	// Model specifies the name of the embedding model, e.g. "nomic-embed-text", "bge-m3"
+	// It can be overridden by embedding.WithModel
+	// Required
+	Model string `json:"model"`
+
+	// Truncate truncates the end of each input to fit within the context length
+	// If false, an error is returned when the context length is exceeded
+	// Optional. Default: nil, which means true on the server side
+	Truncate *bool `json:"truncate,omitempty"`
+
+	// KeepAlive controls how long the model will stay loaded in memory following the request
+	// Optional. Default: nil, which means 5 minutes on the server side
+	KeepAlive *time.Duration `json:"keep_alive,omitempty"`
+
+	// Options lists model-specific options, e.g. "num_ctx"
+	// Optional.
+	Options map[string]any `json:"options,omitempty"`
+
+	// BatchSize limits the number of texts sent in a single request
+	// Texts exceeding BatchSize are split into several requests
+	// Optional. Default: 0, which sends all texts in one request
+	BatchSize int `json:"batch_size"`
+}
+
+var _ embedding.Embedder = (*Embedder)(nil)
+
+// Embedder is an ollama embedding client
+type Embedder struct {
+	cli  *api.Client
+	conf *EmbeddingConfig
+}
+
+// NewEmbedder creates a new ollama embedding client
+func NewEmbedder(ctx context.Context, config *EmbeddingConfig) (*Embedder, error) {
+	if config == nil {
+		return nil, errors.New("config must not be nil")
+	}
+	if config.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size: %d", config.BatchSize)
+	}
+
+	var httpClient *http.Client
+	if config.HTTPClient != nil {
+		httpClient = config.HTTPClient
+	} else {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	return &Embedder{
+		cli:  api.NewClient(u, httpClient),
+		conf: config,
+	}, nil
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) (
+	embeddings [][]float64, err error) {
+
+	options := embedding.GetCommonOptions(&embedding.Options{
+		Model: &e.conf.Model,
+	}, opts...)
+
+	conf := &embedding.Config{
+		Model: *options.Model,
+	}
+
+	ctx = callbacks.EnsureRunInfo(ctx, e.GetType(), components.ComponentOfEmbedding)
+	ctx = callbacks.OnStart(ctx, &embedding.CallbackInput{
+		Texts:  texts,
+		Config: conf,
+	})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	batchSize := e.conf.BatchSize
+	if batchSize == 0 {
+		batchSize = len(texts)
+	}
+
+	promptTokens := 0
+	embeddings = make([][]float64, 0, len(texts))
+	for l := 0; l < len(texts); l += batchSize {
+		r := min(l+batchSize, len(texts))
+
+		req := &api.EmbedRequest{
+			Model:    conf.Model,
+			Input:    texts[l:r],
+			Truncate: e.conf.Truncate,
+			Options:  e.conf.Options,
+		}
+		if e.conf.KeepAlive != nil {
+			req.KeepAlive = &api.Duration{Duration: *e.conf.KeepAlive}
+		}
+
+		resp, err := e.cli.Embed(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("ollama embed fail: %w", err)
+		}
+		if len(resp.Embeddings) != r-l {
+			return nil, fmt.Errorf("unexpected number of embeddings, expected: %d, got: %d", r-l, len(resp.Embeddings))
+		}
+
+		for _, d := range resp.Embeddings {
+			res := make([]float64, len(d))
+			for i, emb := range d {
+				res[i] = float64(emb)
+			}
+			embeddings = append(embeddings, res)
+		}
+
+		promptTokens += resp.PromptEvalCount
+	}
+
+	_ = callbacks.OnEnd(ctx, &embedding.CallbackOutput{
+		Embeddings: embeddings,
+		Config:     conf,
+		TokenUsage: &embedding.TokenUsage{
+			PromptTokens: promptTokens,
+			TotalTokens:  promptTokens,
+		},
+	})
+
+	return embeddings, nil
+}
+
+const typ = "Ollama"
+
+func (e *Embedder) GetType() string {
+	return typ
+}
+
+func (e *Embedder) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+func TestEmbedStrings(t *testing.T) {
+	var requests []api.EmbedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+
+		var req api.EmbedRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		inputs := req.Input.([]any)
+		resp := api.EmbedResponse{Model: req.Model, PromptEvalCount: len(inputs)}
+		for i := range inputs {
+			resp.Embeddings = append(resp.Embeddings, []float32{float32(len(requests)), float32(i)})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	truncate := false
+	keepAlive := time.Minute
+	emb, err := NewEmbedder(ctx, &EmbeddingConfig{
+		BaseURL:   server.URL,
+		Model:     "nomic-embed-text",
+		Truncate:  &truncate,
+		KeepAlive: &keepAlive,
+		BatchSize: 2,
+	})
+	assert.NoError(t, err)
+
+	var usage *embedding.TokenUsage
+	handler := callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			usage = embedding.ConvCallbackOutput(output).TokenUsage
+			return ctx
+		}).Build()
+	ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+	embeddings, err := emb.EmbedStrings(ctx, []string{"a", "b", "c"}, embedding.WithModel("bge-m3"))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{1, 0}, {1, 1}, {2, 0}}, embeddings)
+	assert.Equal(t, 3, usage.PromptTokens)
+
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "bge-m3", requests[0].Model)
+	assert.Equal(t, []any{"a", "b"}, requests[0].Input)
+	assert.Equal(t, []any{"c"}, requests[1].Input)
+	assert.False(t, *requests[0].Truncate)
+	assert.Equal(t, time.Minute, requests[0].KeepAlive.Duration)
+}
+
+func TestEmbedStringsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"model not found"}`))
+	}))
+	defer server.Close()
+
+	emb, err := NewEmbedder(context.Background(), &EmbeddingConfig{BaseURL: server.URL, Model: "unknown"})
+	assert.NoError(t, err)
+
+	_, err = emb.EmbedStrings(context.Background(), []string{"a"})
+	assert.ErrorContains(t, err, "model not found")
+
+	_, err = NewEmbedder(context.Background(), &EmbeddingConfig{BatchSize: -1})
+	assert.Error(t, err)
+}
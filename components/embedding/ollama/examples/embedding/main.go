@@ -0,0 +1,54 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/cloudwego/eino-ext/components/embedding/ollama"
+)
+
+func main() {
+	ctx := context.Background()
+
+	baseURL := os.Getenv("OLLAMA_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	model := os.Getenv("OLLAMA_EMBED_MODEL")
+	if model == "" {
+		model = "nomic-embed-text"
+	}
+
+	embedder, err := ollama.NewEmbedder(ctx, &ollama.EmbeddingConfig{
+		BaseURL:   baseURL,
+		Model:     model,
+		BatchSize: 16,
+	})
+	if err != nil {
+		log.Fatalf("NewEmbedder of ollama failed, err=%v", err)
+	}
+
+	embeddings, err := embedder.EmbedStrings(ctx, []string{"hello", "how are you"})
+	if err != nil {
+		log.Fatalf("EmbedStrings of ollama failed, err=%v", err)
+	}
+
+	log.Printf("embeddings: %d, dimensions: %d", len(embeddings), len(embeddings[0]))
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es8
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v8/esutil"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/deletebyquery"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// Indexer implements the delete methods of the indexers: Delete by ids, and DeleteByFilter by the native filter of the store
+var _ interface {
+	Delete(ctx context.Context, ids []string, opts ...indexer.Option) error
+	DeleteByFilter(ctx context.Context, filter types.Query, opts ...indexer.Option) error
+} = (*Indexer)(nil)
+
+// Delete removes documents by ids from index, ids not found are ignored.
+func (i *Indexer) Delete(ctx context.Context, ids []string, opts ...indexer.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
+		Index:  i.config.Index,
+		Client: i.client,
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
+	// Close does not report the failures of single items, collect them by OnFailure
+	onFailure := func(ctx context.Context, item esutil.BulkIndexerItem, res esutil.BulkIndexerResponseItem, err error) {
+		if err == nil && res.Status == http.StatusNotFound {
+			return
+		}
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = fmt.Sprintf("status=%d, type=%s, reason=%s", res.Status, res.Error.Type, res.Error.Reason)
+		}
+		mu.Lock()
+		failures = append(failures, fmt.Sprintf("id=%s, %s", item.DocumentID, reason))
+		mu.Unlock()
+	}
+
+	for _, id := range ids {
+		if err = bi.Add(ctx, esutil.BulkIndexerItem{
+			Index:      i.config.Index,
+			Action:     "delete",
+			DocumentID: id,
+			OnFailure:  onFailure,
+		}); err != nil {
+			return fmt.Errorf("[Delete] add bulk item failed, %w", err)
+		}
+	}
+
+	if err = bi.Close(ctx); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) > 0 {
+		return fmt.Errorf("[Delete] delete partially failed, failures=%d, first failure: %s", len(failures), failures[0])
+	}
+
+	return nil
+}
+
+// DeleteByFilter removes all documents matching filter from index.
+// see: https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-delete-by-query.html
+func (i *Indexer) DeleteByFilter(ctx context.Context, filter types.Query, opts ...indexer.Option) error {
+	resp, err := deletebyquery.NewDeleteByQueryFunc(i.client)(i.config.Index).
+		Query(&filter).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("[DeleteByFilter] delete by query failed, %w", err)
+	}
+
+	if len(resp.Failures) > 0 {
+		f := resp.Failures[0]
+		return fmt.Errorf("[DeleteByFilter] delete by query partially failed, failures=%d, first failure: id=%s, status=%d, type=%s",
+			len(resp.Failures), f.Id, f.Status, f.Cause.Type)
+	}
+
+	return nil
+}
+
+// deleteStaleChunks deletes the stored chunks of the source ids of docs, except the ones just stored.
+func (i *Indexer) deleteStaleChunks(ctx context.Context, docs []*schema.Document) error {
+	var (
+		sources []types.FieldValue
+		seen    = make(map[string]bool)
+		ids     []string
+	)
+	for _, doc := range docs {
+		val, ok := doc.MetaData[i.config.SourceIDField]
+		if !ok {
+			continue
+		}
+		sourceID, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("[deleteStaleChunks] source id must be string, id=%s, got type=%T", doc.ID, val)
+		}
+		ids = append(ids, doc.ID)
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, sourceID)
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil
+	}
+
+	return i.DeleteByFilter(ctx, types.Query{
+		Bool: &types.BoolQuery{
+			Filter:  []types.Query{{Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{i.config.SourceIDField: sources}}}},
+			MustNot: []types.Query{{Ids: &types.IdsQuery{Values: ids}}},
+		},
+	})
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es8
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esutil"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/deletebyquery"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestDelete(t *testing.T) {
+	PatchConvey("test Delete", t, func() {
+		ctx := context.Background()
+		i := &Indexer{client: &elasticsearch.Client{}, config: &IndexerConfig{Index: "mock_index"}}
+		bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{})
+		convey.So(err, convey.ShouldBeNil)
+
+		PatchConvey("test empty ids", func() {
+			convey.So(i.Delete(ctx, nil), convey.ShouldBeNil)
+		})
+
+		PatchConvey("test NewBulkIndexer error", func() {
+			mockErr := fmt.Errorf("test err")
+			Mock(esutil.NewBulkIndexer).Return(nil, mockErr).Build()
+			convey.So(i.Delete(ctx, []string{"1"}), convey.ShouldBeError, mockErr)
+		})
+
+		PatchConvey("test success", func() {
+			var items []esutil.BulkIndexerItem
+			Mock(esutil.NewBulkIndexer).Return(bi, nil).Build()
+			Mock(GetMethod(bi, "Add")).To(func(ctx context.Context, item esutil.BulkIndexerItem) error {
+				items = append(items, item)
+				return nil
+			}).Build()
+			Mock(GetMethod(bi, "Close")).Return(nil).Build()
+
+			convey.So(i.Delete(ctx, []string{"1", "2"}), convey.ShouldBeNil)
+			convey.So(len(items), convey.ShouldEqual, 2)
+			for j, id := range []string{"1", "2"} {
+				convey.So(items[j].Action, convey.ShouldEqual, "delete")
+				convey.So(items[j].Index, convey.ShouldEqual, "mock_index")
+				convey.So(items[j].DocumentID, convey.ShouldEqual, id)
+			}
+		})
+
+		PatchConvey("test item failed", func() {
+			Mock(esutil.NewBulkIndexer).Return(bi, nil).Build()
+			Mock(GetMethod(bi, "Add")).To(func(ctx context.Context, item esutil.BulkIndexerItem) error {
+				res := esutil.BulkIndexerResponseItem{Status: 404}
+				if item.DocumentID == "2" {
+					res.Status = 500
+					res.Error.Type = "shard_failure"
+				}
+				item.OnFailure(ctx, item, res, nil)
+				return nil
+			}).Build()
+			Mock(GetMethod(bi, "Close")).Return(nil).Build()
+
+			convey.So(i.Delete(ctx, []string{"1"}), convey.ShouldBeNil)
+			err := i.Delete(ctx, []string{"1", "2"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "failures=1")
+			convey.So(err.Error(), convey.ShouldContainSubstring, "id=2, status=500, type=shard_failure")
+		})
+	})
+}
+
+func TestDeleteByFilter(t *testing.T) {
+	PatchConvey("test DeleteByFilter", t, func() {
+		ctx := context.Background()
+		i := &Indexer{client: &elasticsearch.Client{}, config: &IndexerConfig{Index: "mock_index"}}
+		filter := types.Query{Term: map[string]types.TermQuery{"source_id": {Value: "doc_1"}}}
+		dbq := deletebyquery.NewDeleteByQueryFunc(i.client)(i.config.Index)
+
+		PatchConvey("test request failed", func() {
+			mockErr := fmt.Errorf("test err")
+			Mock(GetMethod(dbq, "Do")).Return(nil, mockErr).Build()
+			err := i.DeleteByFilter(ctx, filter)
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[DeleteByFilter] delete by query failed, %w", mockErr))
+		})
+
+		PatchConvey("test partially failed", func() {
+			Mock(GetMethod(dbq, "Do")).Return(&deletebyquery.Response{
+				Failures: []types.BulkIndexByScrollFailure{
+					{Id: "1", Status: 409, Cause: types.ErrorCause{Type: "version_conflict_engine_exception"}},
+				},
+			}, nil).Build()
+			err := i.DeleteByFilter(ctx, filter)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "version_conflict_engine_exception")
+		})
+
+		PatchConvey("test success", func() {
+			var query *types.Query
+			Mock(GetMethod(dbq, "Query")).To(func(q *types.Query) *deletebyquery.DeleteByQuery {
+				query = q
+				return dbq
+			}).Build()
+			Mock(GetMethod(dbq, "Do")).Return(&deletebyquery.Response{}, nil).Build()
+			convey.So(i.DeleteByFilter(ctx, filter), convey.ShouldBeNil)
+			convey.So(query, convey.ShouldResemble, &filter)
+		})
+	})
+}
+
+func TestDeleteStaleChunks(t *testing.T) {
+	PatchConvey("test deleteStaleChunks", t, func() {
+		ctx := context.Background()
+		i := &Indexer{client: &elasticsearch.Client{}, config: &IndexerConfig{Index: "mock_index", SourceIDField: "source_id"}}
+		var filters []types.Query
+		Mock(GetMethod(i, "DeleteByFilter")).To(func(ctx context.Context, filter types.Query, opts ...indexer.Option) error {
+			filters = append(filters, filter)
+			return nil
+		}).Build()
+
+		PatchConvey("test without source id", func() {
+			convey.So(i.deleteStaleChunks(ctx, []*schema.Document{{ID: "1"}}), convey.ShouldBeNil)
+			convey.So(filters, convey.ShouldBeEmpty)
+		})
+
+		PatchConvey("test invalid source id", func() {
+			err := i.deleteStaleChunks(ctx, []*schema.Document{{ID: "1", MetaData: map[string]any{"source_id": 1}}})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(filters, convey.ShouldBeEmpty)
+		})
+
+		PatchConvey("test success", func() {
+			docs := []*schema.Document{
+				{ID: "a_1", MetaData: map[string]any{"source_id": "a.md"}},
+				{ID: "a_2", MetaData: map[string]any{"source_id": "a.md"}},
+				{ID: "b_1", MetaData: map[string]any{"source_id": "b.md"}},
+				{ID: "c_1"},
+			}
+			convey.So(i.deleteStaleChunks(ctx, docs), convey.ShouldBeNil)
+			convey.So(filters, convey.ShouldResemble, []types.Query{{
+				Bool: &types.BoolQuery{
+					Filter: []types.Query{{Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{
+						"source_id": []types.FieldValue{"a.md", "b.md"},
+					}}}},
+					MustNot: []types.Query{{Ids: &types.IdsQuery{Values: []string{"a_1", "a_2", "b_1"}}}},
+				},
+			}})
+		})
+	})
+}
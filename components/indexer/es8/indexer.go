@@ -46,6 +46,12 @@ type IndexerConfig struct {
 	// 1. VectorFields contains fields except doc Content
 	// 2. VectorFields contains doc Content and vector not provided in doc extra (see Document.Vector method)
 	Embedding embedding.Embedder
+	// SourceIDField enables replacing the chunks of source documents, e.g. the chunks split from a file.
+	// It is both the metadata key of the source document id in the chunks and the keyword field storing it in index,
+	// which is added to the fields from DocumentToFields if absent.
+	// If set, Store deletes the stored chunks of the same source ids which are not in docs after storing them,
+	// so that re-indexing a shrunk source document leaves no stale chunks.
+	SourceIDField string `json:"source_id_field"`
 }
 
 type FieldValue struct {
@@ -83,6 +89,7 @@ func NewIndexer(_ context.Context, conf *IndexerConfig) (*Indexer, error) {
 	}, nil
 }
 
+// Store indexes docs with doc.ID as document id, existing documents with the same id are replaced.
 func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, i.GetType(), components.ComponentOfIndexer)
 	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
@@ -100,6 +107,12 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 		return nil, err
 	}
 
+	if i.config.SourceIDField != "" {
+		if err = i.deleteStaleChunks(ctx, docs); err != nil {
+			return nil, err
+		}
+	}
+
 	ids = iter(docs, func(t *schema.Document) string { return t.ID })
 
 	callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
@@ -183,6 +196,14 @@ func (i *Indexer) bulkAdd(ctx context.Context, docs []*schema.Document, options
 			}
 		}
 
+		if field := i.config.SourceIDField; field != "" {
+			if _, found := rawFields[field]; !found {
+				if sourceID, ok := doc.MetaData[field]; ok {
+					rawFields[field] = sourceID
+				}
+			}
+		}
+
 		if embSize > i.config.BatchSize {
 			return fmt.Errorf("[bulkAdd] needEmbeddingFields length over batch size, batch size=%d, got size=%d",
 				i.config.BatchSize, embSize)
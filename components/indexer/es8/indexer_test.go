@@ -212,6 +212,35 @@ func TestBulkAdd(t *testing.T) {
 				convey.So(mp["vk2"], convey.ShouldEqual, []any{2.1})
 			}
 		})
+
+		PatchConvey("test source id field", func() {
+			var mps []esutil.BulkIndexerItem
+			Mock(esutil.NewBulkIndexer).Return(bi, nil).Build()
+			Mock(GetMethod(bi, "Add")).To(func(ctx context.Context, item esutil.BulkIndexerItem) error {
+				mps = append(mps, item)
+				return nil
+			}).Build()
+			Mock(GetMethod(bi, "Close")).Return(nil).Build()
+
+			i := &Indexer{
+				config: &IndexerConfig{
+					Index:         "mock_index",
+					BatchSize:     2,
+					SourceIDField: "source_id",
+					DocumentToFields: func(ctx context.Context, doc *schema.Document) (field2Value map[string]FieldValue, err error) {
+						return map[string]FieldValue{"k0": {Value: doc.Content}}, nil
+					},
+				},
+			}
+			err := i.bulkAdd(ctx, []*schema.Document{
+				{ID: "1", Content: "asd", MetaData: map[string]any{"source_id": "a.md"}},
+			}, &indexer.Options{})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(mps), convey.ShouldEqual, 1)
+			b, err := io.ReadAll(mps[0].Body)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(string(b), convey.ShouldEqual, `{"k0":"asd","source_id":"a.md"}`)
+		})
 	})
 }
 
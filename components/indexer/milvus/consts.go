@@ -29,10 +29,19 @@ const (
 	defaultCollectionMetadata     = "metadata"
 	defaultCollectionMetadataDesc = "the metadata of the document"
 	
+	defaultCollectionSparseVectorDesc = "the sparse vector of the document"
+	defaultSparseDropRatio            = 0.2
+	
+	// docMetaDataKeySparseVector is the metadata key of schema.Document.WithSparseVector
+	docMetaDataKeySparseVector = "_sparse_vector"
+	
 	defaultDim = 81920
 	
 	defaultIndexField = "vector"
 	
 	defaultConsistencyLevel = ConsistencyLevelBounded
 	defaultMetricType       = HAMMING
+	
+	defaultVectorType      = BinaryVector
+	defaultFloatMetricType = CONSINE
 )
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// Indexer implements the delete methods of the indexers: Delete by ids, and DeleteByFilter by the native filter of the store
+var _ interface {
+	Delete(ctx context.Context, ids []string, opts ...indexer.Option) error
+	DeleteByFilter(ctx context.Context, filter string, opts ...indexer.Option) error
+} = (*Indexer)(nil)
+
+// Delete deletes the documents by ids, which are the primary keys of the collection.
+func (i *Indexer) Delete(ctx context.Context, ids []string, opts ...indexer.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	io := indexer.GetImplSpecificOptions(&ImplOptions{}, opts...)
+	if io.Partition == "" {
+		io.Partition = i.config.PartitionName
+	}
+
+	pk, err := i.config.getPrimaryKeyField()
+	if err != nil {
+		return fmt.Errorf("[Indexer.Delete] %w", err)
+	}
+
+	var column entity.Column
+	switch pk.DataType {
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		column = entity.NewColumnVarChar(pk.Name, ids)
+	default:
+		return fmt.Errorf("[Indexer.Delete] unsupported primary key type: %s", pk.DataType.String())
+	}
+
+	if err := i.config.Client.DeleteByPks(ctx, i.config.Collection, io.Partition, column); err != nil {
+		return fmt.Errorf("[Indexer.Delete] failed to delete by pks: %w", err)
+	}
+
+	if err := i.config.Client.Flush(ctx, i.config.Collection, false); err != nil {
+		return fmt.Errorf("[Indexer.Delete] failed to flush collection: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByFilter deletes the documents matching the boolean expression, e.g. `metadata["source"] == "a.md"`.
+// see: https://milvus.io/docs/boolean.md
+func (i *Indexer) DeleteByFilter(ctx context.Context, expr string, opts ...indexer.Option) error {
+	if expr == "" {
+		return fmt.Errorf("[Indexer.DeleteByFilter] expr not provided")
+	}
+
+	io := indexer.GetImplSpecificOptions(&ImplOptions{}, opts...)
+	if io.Partition == "" {
+		io.Partition = i.config.PartitionName
+	}
+
+	if err := i.config.Client.Delete(ctx, i.config.Collection, io.Partition, expr); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByFilter] failed to delete: %w", err)
+	}
+
+	if err := i.config.Client.Flush(ctx, i.config.Collection, false); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByFilter] failed to flush collection: %w", err)
+	}
+
+	return nil
+}
+
+// deleteStaleChunks deletes the stored chunks of the source ids of docs, except the ones just stored with ids.
+func (i *Indexer) deleteStaleChunks(ctx context.Context, partition string, docs []*schema.Document, ids []string) error {
+	if len(ids) != len(docs) {
+		return fmt.Errorf("ids length not match docs, need: %d, got: %d", len(docs), len(ids))
+	}
+
+	var (
+		sources []string
+		stored  []string
+		seen    = make(map[string]bool)
+	)
+	for idx, doc := range docs {
+		val, ok := doc.MetaData[i.config.SourceIDKey]
+		if !ok {
+			continue
+		}
+		sourceID, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("source id must be string, id: %s, got type: %T", ids[idx], val)
+		}
+		stored = append(stored, strconv.Quote(ids[idx]))
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, strconv.Quote(sourceID))
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	pk, err := i.config.getPrimaryKeyField()
+	if err != nil {
+		return err
+	}
+	expr := fmt.Sprintf("%s[%s] in [%s] && %s not in [%s]", defaultCollectionMetadata, strconv.Quote(i.config.SourceIDKey),
+		strings.Join(sources, ", "), pk.Name, strings.Join(stored, ", "))
+	return i.DeleteByFilter(ctx, expr, WithPartition(partition))
+}
+
+// getPrimaryKeyField returns the primary key field of the collection fields
+func (i *IndexerConfig) getPrimaryKeyField() (*entity.Field, error) {
+	for _, field := range i.Fields {
+		if field.PrimaryKey {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("primary key field not found")
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestIndexer_Delete(t *testing.T) {
+	PatchConvey("test Indexer.Delete", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+		i := &Indexer{config: IndexerConfig{
+			Client:        mockClient,
+			Collection:    defaultCollection,
+			PartitionName: "p1",
+			Fields:        getDefaultFields(),
+		}}
+
+		PatchConvey("test empty ids", func() {
+			convey.So(i.Delete(ctx, nil), convey.ShouldBeNil)
+		})
+
+		PatchConvey("test delete by pks error", func() {
+			Mock(GetMethod(mockClient, "DeleteByPks")).Return(fmt.Errorf("delete error")).Build()
+			err := i.Delete(ctx, []string{"doc1"})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.Delete] failed to delete by pks: delete error"))
+		})
+
+		PatchConvey("test unsupported primary key", func() {
+			i.config.Fields = []*entity.Field{
+				entity.NewField().WithName("id").WithIsPrimaryKey(true).WithDataType(entity.FieldTypeInt64),
+			}
+			err := i.Delete(ctx, []string{"doc1"})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		PatchConvey("test delete success", func() {
+			var (
+				partition string
+				pks       entity.Column
+			)
+			Mock(GetMethod(mockClient, "DeleteByPks")).To(func(ctx context.Context, collName string, partitionName string, ids entity.Column) error {
+				partition, pks = partitionName, ids
+				return nil
+			}).Build()
+			Mock(GetMethod(mockClient, "Flush")).Return(nil).Build()
+
+			err := i.Delete(ctx, []string{"doc1", "doc2"}, WithPartition("p2"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(partition, convey.ShouldEqual, "p2")
+			convey.So(pks.Name(), convey.ShouldEqual, defaultCollectionID)
+			convey.So(pks.Len(), convey.ShouldEqual, 2)
+		})
+	})
+}
+
+func TestIndexer_DeleteByFilter(t *testing.T) {
+	PatchConvey("test Indexer.DeleteByFilter", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+		i := &Indexer{config: IndexerConfig{
+			Client:     mockClient,
+			Collection: defaultCollection,
+			Fields:     getDefaultFields(),
+		}}
+
+		PatchConvey("test empty expr", func() {
+			convey.So(i.DeleteByFilter(ctx, ""), convey.ShouldNotBeNil)
+		})
+
+		PatchConvey("test delete error", func() {
+			Mock(GetMethod(mockClient, "Delete")).Return(fmt.Errorf("delete error")).Build()
+			err := i.DeleteByFilter(ctx, `metadata["source"] == "a.md"`)
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.DeleteByFilter] failed to delete: delete error"))
+		})
+
+		PatchConvey("test delete success", func() {
+			var expr string
+			Mock(GetMethod(mockClient, "Delete")).To(func(ctx context.Context, collName string, partitionName string, e string) error {
+				expr = e
+				return nil
+			}).Build()
+			Mock(GetMethod(mockClient, "Flush")).Return(nil).Build()
+
+			err := i.DeleteByFilter(ctx, `metadata["source"] == "a.md"`)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(expr, convey.ShouldEqual, `metadata["source"] == "a.md"`)
+		})
+	})
+}
+
+func TestIndexer_deleteStaleChunks(t *testing.T) {
+	PatchConvey("test Indexer.deleteStaleChunks", t, func() {
+		ctx := context.Background()
+		i := &Indexer{config: IndexerConfig{
+			Collection:  defaultCollection,
+			Fields:      getDefaultFields(),
+			SourceIDKey: "source_id",
+		}}
+		var (
+			exprs     []string
+			partition string
+		)
+		Mock(GetMethod(i, "DeleteByFilter")).To(func(ctx context.Context, expr string, opts ...indexer.Option) error {
+			exprs = append(exprs, expr)
+			partition = indexer.GetImplSpecificOptions(&ImplOptions{}, opts...).Partition
+			return nil
+		}).Build()
+
+		PatchConvey("test without source id", func() {
+			err := i.deleteStaleChunks(ctx, "", []*schema.Document{{ID: "1"}}, []string{"1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(exprs, convey.ShouldBeEmpty)
+		})
+
+		PatchConvey("test invalid source id", func() {
+			err := i.deleteStaleChunks(ctx, "", []*schema.Document{{ID: "1", MetaData: map[string]any{"source_id": 1}}}, []string{"1"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(exprs, convey.ShouldBeEmpty)
+		})
+
+		PatchConvey("test success", func() {
+			docs := []*schema.Document{
+				{ID: "a_1", MetaData: map[string]any{"source_id": "a.md"}},
+				{ID: "a_2", MetaData: map[string]any{"source_id": "a.md"}},
+				{ID: "b_1", MetaData: map[string]any{"source_id": "b.md"}},
+				{ID: "c_1"},
+			}
+			err := i.deleteStaleChunks(ctx, "p1", docs, []string{"a_1", "a_2", "b_1", "c_1"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(exprs, convey.ShouldResemble, []string{`metadata["source_id"] in ["a.md", "b.md"] && id not in ["a_1", "a_2", "b_1"]`})
+			convey.So(partition, convey.ShouldEqual, "p1")
+		})
+	})
+}
@@ -67,15 +67,39 @@ type IndexerConfig struct {
 	// Optional, and the default value is false
 	// Enable to dynamic schema it could affect milvus performance
 	EnableDynamicSchema bool
+	// Upsert makes Store replace the rows with the same primary key instead of inserting duplicates
+	// Optional, and the default value is false
+	Upsert bool
+	// SourceIDKey is the metadata key of the source document id in the chunks, e.g. the chunks split from a file,
+	// which enables replacing the chunks of source documents: Store deletes the stored chunks of the same source ids
+	// which are not in docs after storing them, so that re-indexing a shrunk source document leaves no stale chunks.
+	// The chunks are matched by the key in the metadata JSON field, which is stored by the default DocumentConverter
+	// Optional, and the default value is "" (disabled)
+	SourceIDKey string
 	
 	// DocumentConverter is the function to convert the schema.Document to the row data
 	// Optional, and the default value is defaultDocumentConverter
 	DocumentConverter func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]interface{}, error)
 	
+	// VectorType is the data type of the vector field, and the embedding is converted to it by the default DocumentConverter
+	// Optional, and the default value is the type of the vector field in Fields if Fields is set, otherwise BinaryVector
+	// FloatVector, Float16Vector and BFloat16Vector keep the precision of the embedding and require Dim
+	VectorType VectorType
+	// Dim is the dimension of the vector field in the default fields
+	// Optional for BinaryVector, and the default value is 81920
+	// Required for the float vector types, and it should be the output dimension of the embedding model
+	Dim int64
+	
 	// Index config to the vector column
 	// MetricType the metric type for vector
-	// Optional and default type is HAMMING
+	// Optional and default type is HAMMING for BinaryVector, COSINE for the float vector types
+	// The float vector types support L2, IP and COSINE, while BinaryVector supports HAMMING and JACCARD etc.
 	MetricType MetricType
+	// SparseVectorField is the sparse float vector field name for hybrid search
+	// Optional, and the default value is empty(disable)
+	// If set, the default fields contain a sparse vector field with this name, indexed by SPARSE_INVERTED_INDEX with IP metric,
+	// and the sparse vector of each document is read from schema.Document.SparseVector
+	SparseVectorField string
 	
 	// Embedding vectorization method for values needs to be embedded from schema.Document's content.
 	// Required
@@ -210,9 +234,17 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 	}
 	
 	// store documents into milvus
-	results, err := i.config.Client.InsertRows(ctx, i.config.Collection, io.Partition, rows)
-	if err != nil {
-		return nil, fmt.Errorf("[Indexer.Store] failed to insert rows: %w", err)
+	var results entity.Column
+	if i.config.Upsert {
+		results, err = i.upsertRows(ctx, io.Partition, rows)
+		if err != nil {
+			return nil, fmt.Errorf("[Indexer.Store] failed to upsert rows: %w", err)
+		}
+	} else {
+		results, err = i.config.Client.InsertRows(ctx, i.config.Collection, io.Partition, rows)
+		if err != nil {
+			return nil, fmt.Errorf("[Indexer.Store] failed to insert rows: %w", err)
+		}
 	}
 	
 	// flush collection to make sure the data is visible
@@ -229,12 +261,31 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 		}
 	}
 	
+	if i.config.SourceIDKey != "" {
+		if err = i.deleteStaleChunks(ctx, io.Partition, docs, ids); err != nil {
+			return nil, fmt.Errorf("[Indexer.Store] failed to delete stale chunks: %w", err)
+		}
+	}
+	
 	callbacks.OnEnd(ctx, &indexer.CallbackOutput{
 		IDs: ids,
 	})
 	return ids, nil
 }
 
+// upsertRows upserts the row based data, which milvus client only supports with columns
+func (i *Indexer) upsertRows(ctx context.Context, partition string, rows []interface{}) (entity.Column, error) {
+	collection, err := i.config.Client.DescribeCollection(ctx, i.config.Collection)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := entity.AnyToColumns(rows, collection.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return i.config.Client.Upsert(ctx, i.config.Collection, partition, columns...)
+}
+
 func (i *Indexer) GetType() string {
 	return typ
 }
@@ -260,6 +311,10 @@ func (i *IndexerConfig) getDefaultDocumentConvert() func(ctx context.Context, do
 		texts := make([]string, 0, len(docs))
 		rows := make([]interface{}, 0, len(docs))
 		
+		if i.SparseVectorField != "" || i.VectorType != BinaryVector {
+			return i.convertMapRows(docs, vectors)
+		}
+		
 		for _, doc := range docs {
 			metadata, err := sonic.Marshal(doc.MetaData)
 			if err != nil {
@@ -283,6 +338,59 @@ func (i *IndexerConfig) getDefaultDocumentConvert() func(ctx context.Context, do
 	}
 }
 
+// convertMapRows converts the documents to map rows, which contain the vector of VectorType and the optional sparse vector field
+func (i *IndexerConfig) convertMapRows(docs []*schema.Document, vectors [][]float64) ([]interface{}, error) {
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("vectors length not match need: %d, got: %d", len(docs), len(vectors))
+	}
+	rows := make([]interface{}, 0, len(docs))
+	for idx, doc := range docs {
+		row := map[string]interface{}{
+			defaultCollectionID:      doc.ID,
+			defaultCollectionContent: doc.Content,
+			defaultCollectionVector:  convertVector(i.VectorType, vectors[idx]),
+		}
+		if i.SparseVectorField != "" {
+			sparse := doc.SparseVector()
+			if sparse == nil {
+				return nil, fmt.Errorf("sparse vector not found in document: %s", doc.ID)
+			}
+			embedding, err := sparse2Embedding(sparse)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert sparse vector: %w", err)
+			}
+			row[i.SparseVectorField] = embedding
+		}
+		// the sparse vector has been stored in its own field
+		metadata := make(map[string]any, len(doc.MetaData))
+		for k, v := range doc.MetaData {
+			if k == docMetaDataKeySparseVector {
+				continue
+			}
+			metadata[k] = v
+		}
+		b, err := sonic.Marshal(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		row[defaultCollectionMetadata] = b
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// createdSparseIndex creates the sparse inverted index for the sparse vector field
+func (i *IndexerConfig) createdSparseIndex(ctx context.Context, async bool) error {
+	index, err := entity.NewIndexSparseInverted(entity.IP, defaultSparseDropRatio)
+	if err != nil {
+		return fmt.Errorf("[NewIndexer] failed to create sparse index: %w", err)
+	}
+	if err := i.Client.CreateIndex(ctx, i.Collection, i.SparseVectorField, index, async); err != nil {
+		return fmt.Errorf("[NewIndexer] failed to create sparse index: %w", err)
+	}
+	return nil
+}
+
 // createdDefaultIndex creates the default index
 func (i *IndexerConfig) createdDefaultIndex(ctx context.Context, async bool) error {
 	index, err := entity.NewIndexAUTOINDEX(i.MetricType.getMetricType())
@@ -333,6 +441,17 @@ func (i *IndexerConfig) loadCollection(ctx context.Context) error {
 				return err
 			}
 		}
+		if i.SparseVectorField != "" {
+			index, err = i.Client.DescribeIndex(ctx, i.Collection, i.SparseVectorField)
+			if errors.Is(err, client.ErrClientNotReady) {
+				return fmt.Errorf("[NewIndexer] milvus client not ready: %w", err)
+			}
+			if len(index) == 0 {
+				if err := i.createdSparseIndex(ctx, false); err != nil {
+					return err
+				}
+			}
+		}
 		if err := i.Client.LoadCollection(ctx, i.Collection, true); err != nil {
 			return err
 		}
@@ -382,14 +501,44 @@ func (i *IndexerConfig) check() error {
 	if i.ConsistencyLevel <= 0 || i.ConsistencyLevel > 5 {
 		i.ConsistencyLevel = defaultConsistencyLevel
 	}
+	// the vector type of custom fields is inferred from their vector field,
+	// and the metric type is only validated when the vector type is known
+	checkMetric := i.VectorType != 0 || i.Fields == nil
+	if i.VectorType == 0 && i.Fields != nil {
+		if vt, ok := getFieldsVectorType(i.Fields); ok {
+			i.VectorType = vt
+			checkMetric = true
+		}
+	}
+	if i.VectorType == 0 {
+		i.VectorType = defaultVectorType
+	}
+	if i.VectorType != BinaryVector && !i.VectorType.isFloat() {
+		return fmt.Errorf("[NewIndexer] vector type not supported: %s", i.VectorType.getFieldType().Name())
+	}
 	if i.MetricType == "" {
 		i.MetricType = defaultMetricType
+		if i.VectorType.isFloat() {
+			i.MetricType = defaultFloatMetricType
+		}
+	}
+	if checkMetric && i.VectorType.isFloat() != i.MetricType.isFloat() {
+		return fmt.Errorf("[NewIndexer] metric type %s not match vector type %s", i.MetricType, i.VectorType.getFieldType().Name())
 	}
 	if i.PartitionNum <= 1 {
 		i.PartitionNum = 0
 	}
 	if i.Fields == nil {
-		i.Fields = getDefaultFields()
+		if i.Dim <= 0 {
+			if i.VectorType.isFloat() {
+				return fmt.Errorf("[NewIndexer] dim is required for vector type %s", i.VectorType.getFieldType().Name())
+			}
+			i.Dim = defaultDim
+		}
+		i.Fields = getDefaultFieldsWithVector(i.VectorType, i.Dim)
+		if i.SparseVectorField != "" {
+			i.Fields = append(i.Fields, getDefaultSparseField(i.SparseVectorField))
+		}
 	}
 	if i.DocumentConverter == nil {
 		i.DocumentConverter = i.getDefaultDocumentConvert()
@@ -19,6 +19,7 @@ package milvus
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 	
 	. "github.com/bytedance/mockey"
@@ -383,6 +384,37 @@ func TestIndexer_Store(t *testing.T) {
 			convey.So(ids[1], convey.ShouldEqual, "doc2")
 		})
 		
+		PatchConvey("test store with upsert", func() {
+			// 模拟Upsert成功
+			var columns []entity.Column
+			mockIDs := entity.NewColumnVarChar("id", []string{"doc1", "doc2"})
+			Mock(GetMethod(mockClient, "Upsert")).To(func(ctx context.Context, collName string, partitionName string, cols ...entity.Column) (entity.Column, error) {
+				columns = cols
+				return mockIDs, nil
+			}).Build()
+			Mock(GetMethod(mockClient, "InsertRows")).Return(nil, fmt.Errorf("insert rows should not be called")).Build()
+			
+			// 模拟Flush成功
+			Mock(GetMethod(mockClient, "Flush")).Return(nil).Build()
+			
+			mockEmb := &mockEmbedding{}
+			indexer, err := NewIndexer(ctx, &IndexerConfig{
+				Client:     mockClient,
+				Collection: defaultCollection,
+				Embedding:  mockEmb,
+				Upsert:     true,
+			})
+			convey.So(err, convey.ShouldBeNil)
+			
+			ids, err := indexer.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc2"})
+			convey.So(len(columns), convey.ShouldEqual, 4)
+			for _, col := range columns {
+				convey.So(col.Len(), convey.ShouldEqual, 2)
+			}
+		})
+		
 		PatchConvey("test store with custom embedding", func() {
 			// 模拟InsertRows成功
 			mockIDs := entity.NewColumnVarChar("id", []string{"doc1", "doc2"})
@@ -409,3 +441,158 @@ func TestIndexer_Store(t *testing.T) {
 		})
 	})
 }
+
+func TestIndexer_StoreSparseVector(t *testing.T) {
+	PatchConvey("test Indexer.Store with sparse vector", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+		
+		conf := &IndexerConfig{
+			Client:            mockClient,
+			Embedding:         &mockEmbedding{},
+			SparseVectorField: "sparse_vector",
+		}
+		convey.So(conf.check(), convey.ShouldBeNil)
+		convey.So(len(conf.Fields), convey.ShouldEqual, 5)
+		convey.So(conf.Fields[4].Name, convey.ShouldEqual, "sparse_vector")
+		convey.So(conf.Fields[4].DataType, convey.ShouldEqual, entity.FieldTypeSparseVector)
+		
+		var rows []interface{}
+		Mock(GetMethod(mockClient, "InsertRows")).To(func(ctx context.Context, collName string, partitionName string, r []interface{}) (entity.Column, error) {
+			rows = r
+			return entity.NewColumnVarChar("id", []string{"doc1"}), nil
+		}).Build()
+		Mock(GetMethod(mockClient, "Flush")).Return(nil).Build()
+		i := &Indexer{config: *conf}
+		
+		PatchConvey("test sparse vector not found", func() {
+			_, err := i.Store(ctx, []*schema.Document{{ID: "doc1", Content: "asd"}})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		
+		PatchConvey("test success", func() {
+			doc := &schema.Document{ID: "doc1", Content: "asd", MetaData: map[string]any{"key": "value"}}
+			doc.WithSparseVector(map[int]float64{7: 0.5, 3: 1.5})
+			ids, err := i.Store(ctx, []*schema.Document{doc})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1"})
+			convey.So(len(rows), convey.ShouldEqual, 1)
+			
+			row := rows[0].(map[string]interface{})
+			convey.So(row["id"], convey.ShouldEqual, "doc1")
+			convey.So(string(row["metadata"].([]byte)), convey.ShouldEqual, `{"key":"value"}`)
+			sparse := row["sparse_vector"].(entity.SparseEmbedding)
+			convey.So(sparse.Len(), convey.ShouldEqual, 2)
+			pos, val, ok := sparse.Get(0)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(pos, convey.ShouldEqual, 3)
+			convey.So(val, convey.ShouldEqual, 1.5)
+		})
+	})
+}
+
+func TestIndexerConfig_VectorType(t *testing.T) {
+	PatchConvey("test IndexerConfig with vector type", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+		
+		PatchConvey("test dim required", func() {
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, VectorType: FloatVector}
+			convey.So(conf.check(), convey.ShouldNotBeNil)
+		})
+		
+		PatchConvey("test metric type not match", func() {
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, VectorType: FloatVector, Dim: 4, MetricType: HAMMING}
+			convey.So(conf.check(), convey.ShouldNotBeNil)
+			conf = &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, MetricType: L2}
+			convey.So(conf.check(), convey.ShouldNotBeNil)
+		})
+		
+		PatchConvey("test vector type not supported", func() {
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, VectorType: VectorType(entity.FieldTypeSparseVector)}
+			convey.So(conf.check(), convey.ShouldNotBeNil)
+		})
+		
+		PatchConvey("test default binary vector", func() {
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}}
+			convey.So(conf.check(), convey.ShouldBeNil)
+			convey.So(conf.MetricType, convey.ShouldEqual, HAMMING)
+			convey.So(conf.Fields[1].DataType, convey.ShouldEqual, entity.FieldTypeBinaryVector)
+			convey.So(conf.Fields[1].TypeParams[entity.TypeParamDim], convey.ShouldEqual, "81920")
+		})
+		
+		PatchConvey("test float vector", func() {
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, VectorType: FloatVector, Dim: 2}
+			convey.So(conf.check(), convey.ShouldBeNil)
+			convey.So(conf.MetricType, convey.ShouldEqual, CONSINE)
+			convey.So(conf.Fields[1].DataType, convey.ShouldEqual, entity.FieldTypeFloatVector)
+			convey.So(conf.Fields[1].TypeParams[entity.TypeParamDim], convey.ShouldEqual, "2")
+			
+			rows, err := conf.DocumentConverter(ctx, []*schema.Document{{ID: "1", Content: "a"}}, [][]float64{{0.5, -1}})
+			convey.So(err, convey.ShouldBeNil)
+			row := rows[0].(map[string]interface{})
+			convey.So(row["vector"], convey.ShouldResemble, []float32{0.5, -1})
+			
+			columns, err := entity.AnyToColumns(rows, conf.getSchema(conf.Collection, conf.Description, conf.Fields))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(columns), convey.ShouldEqual, 4)
+		})
+		
+		PatchConvey("test custom float fields", func() {
+			fields := getDefaultFieldsWithVector(FloatVector, 2)
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, Fields: fields, MetricType: IP}
+			convey.So(conf.check(), convey.ShouldBeNil)
+			convey.So(conf.VectorType, convey.ShouldEqual, FloatVector)
+			convey.So(conf.MetricType, convey.ShouldEqual, IP)
+			convey.So(conf.Fields, convey.ShouldResemble, fields)
+			
+			rows, err := conf.DocumentConverter(ctx, []*schema.Document{{ID: "1", Content: "a"}}, [][]float64{{0.5, -1}})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(rows[0].(map[string]interface{})["vector"], convey.ShouldResemble, []float32{0.5, -1})
+			
+			conf = &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, Fields: getDefaultFieldsWithVector(FloatVector, 2), MetricType: HAMMING}
+			convey.So(conf.check(), convey.ShouldNotBeNil)
+		})
+		
+		PatchConvey("test float16 and bfloat16 vector", func() {
+			conf := &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, VectorType: Float16Vector, Dim: 2, MetricType: IP}
+			convey.So(conf.check(), convey.ShouldBeNil)
+			rows, err := conf.DocumentConverter(ctx, []*schema.Document{{ID: "1", Content: "a"}}, [][]float64{{1, -2}})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(rows[0].(map[string]interface{})["vector"], convey.ShouldResemble, []byte{0x00, 0x3c, 0x00, 0xc0})
+			
+			conf = &IndexerConfig{Client: mockClient, Embedding: &mockEmbedding{}, VectorType: BFloat16Vector, Dim: 2, MetricType: L2}
+			convey.So(conf.check(), convey.ShouldBeNil)
+			rows, err = conf.DocumentConverter(ctx, []*schema.Document{{ID: "1", Content: "a"}}, [][]float64{{1, -2}})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(rows[0].(map[string]interface{})["vector"], convey.ShouldResemble, []byte{0x80, 0x3f, 0x00, 0xc0})
+		})
+	})
+}
+
+func TestFloat16Conversion(t *testing.T) {
+	convey.Convey("test float32 to float16 and bfloat16", t, func() {
+		cases := []struct {
+			in       float32
+			float16  uint16
+			bfloat16 uint16
+		}{
+			{0, 0x0000, 0x0000},
+			{1, 0x3c00, 0x3f80},
+			{-2, 0xc000, 0xc000},
+			{0.1, 0x2e66, 0x3dcd},
+			{65504, 0x7bff, 0x4780},
+			{1e6, 0x7c00, 0x4974},
+			{float32(math.Inf(-1)), 0xfc00, 0xff80},
+			{5.960464477539063e-08, 0x0001, 0x3380},
+		}
+		for _, c := range cases {
+			convey.So(float32ToFloat16(c.in), convey.ShouldEqual, c.float16)
+			convey.So(float32ToBFloat16(c.in), convey.ShouldEqual, c.bfloat16)
+		}
+		nan := float32ToFloat16(float32(math.NaN()))
+		convey.So(nan&0x7c00 == 0x7c00 && nan&0x3ff != 0, convey.ShouldBeTrue)
+	})
+}
@@ -46,6 +46,11 @@ type defaultSchema struct {
 }
 
 func getDefaultFields() []*entity.Field {
+	return getDefaultFieldsWithVector(BinaryVector, defaultDim)
+}
+
+// getDefaultFieldsWithVector returns the default fields with the given vector type and dimension
+func getDefaultFieldsWithVector(vectorType VectorType, dim int64) []*entity.Field {
 	return []*entity.Field{
 		entity.NewField().
 			WithName(defaultCollectionID).
@@ -57,8 +62,8 @@ func getDefaultFields() []*entity.Field {
 			WithName(defaultCollectionVector).
 			WithDescription(defaultCollectionVectorDesc).
 			WithIsPrimaryKey(false).
-			WithDataType(entity.FieldTypeBinaryVector).
-			WithDim(defaultDim),
+			WithDataType(vectorType.getFieldType()).
+			WithDim(dim),
 		entity.NewField().
 			WithName(defaultCollectionContent).
 			WithDescription(defaultCollectionContentDesc).
@@ -73,12 +78,57 @@ func getDefaultFields() []*entity.Field {
 	}
 }
 
+func getDefaultSparseField(name string) *entity.Field {
+	return entity.NewField().
+		WithName(name).
+		WithDescription(defaultCollectionSparseVectorDesc).
+		WithIsPrimaryKey(false).
+		WithDataType(entity.FieldTypeSparseVector)
+}
+
 type ConsistencyLevel entity.ConsistencyLevel
 
 func (c *ConsistencyLevel) getConsistencyLevel() entity.ConsistencyLevel {
 	return entity.ConsistencyLevel(*c - 1)
 }
 
+// VectorType is the data type of the default vector field by eino
+type VectorType entity.FieldType
+
+const (
+	// BinaryVector stores the float32 little endian bytes of the embedding, which is compatible with the former versions
+	BinaryVector = VectorType(entity.FieldTypeBinaryVector)
+	// FloatVector stores the embedding as float32
+	FloatVector = VectorType(entity.FieldTypeFloatVector)
+	// Float16Vector stores the embedding as IEEE 754 half precision
+	Float16Vector = VectorType(entity.FieldTypeFloat16Vector)
+	// BFloat16Vector stores the embedding as brain floating point
+	BFloat16Vector = VectorType(entity.FieldTypeBFloat16Vector)
+)
+
+// getFieldType returns the field type
+func (t VectorType) getFieldType() entity.FieldType {
+	return entity.FieldType(t)
+}
+
+// isFloat reports whether the vector type stores float values
+func (t VectorType) isFloat() bool {
+	return t == FloatVector || t == Float16Vector || t == BFloat16Vector
+}
+
+// getFieldsVectorType returns the vector type of the first binary or float vector field in fields
+func getFieldsVectorType(fields []*entity.Field) (VectorType, bool) {
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		if vt := VectorType(field.DataType); vt == BinaryVector || vt.isFloat() {
+			return vt, true
+		}
+	}
+	return 0, false
+}
+
 // MetricType is the metric type for vector by eino
 type MetricType entity.MetricType
 
@@ -86,3 +136,8 @@ type MetricType entity.MetricType
 func (t *MetricType) getMetricType() entity.MetricType {
 	return entity.MetricType(*t)
 }
+
+// isFloat reports whether the metric type is used by the float vectors
+func (t *MetricType) isFloat() bool {
+	return *t == L2 || *t == IP || *t == CONSINE
+}
@@ -20,10 +20,12 @@ import (
 	"context"
 	"encoding/binary"
 	"math"
+	"sort"
 
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/embedding"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 )
 
 // vector2Bytes converts vector to bytes
@@ -39,6 +41,96 @@ func vector2Bytes(vector []float64) []byte {
 	return bytes
 }
 
+// convertVector converts the embedding to the row value of the vector type
+func convertVector(vectorType VectorType, vector []float64) interface{} {
+	switch vectorType {
+	case FloatVector:
+		return vector2Float32(vector)
+	case Float16Vector:
+		return vector2Float16Bytes(vector)
+	case BFloat16Vector:
+		return vector2BFloat16Bytes(vector)
+	default:
+		return vector2Bytes(vector)
+	}
+}
+
+// vector2Float32 converts vector to float32 slice
+func vector2Float32(vector []float64) []float32 {
+	float32Arr := make([]float32, len(vector))
+	for i, v := range vector {
+		float32Arr[i] = float32(v)
+	}
+	return float32Arr
+}
+
+// vector2Float16Bytes converts vector to IEEE 754 half precision bytes in little endian
+func vector2Float16Bytes(vector []float64) []byte {
+	bytes := make([]byte, len(vector)*2)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint16(bytes[i*2:], float32ToFloat16(float32(v)))
+	}
+	return bytes
+}
+
+// vector2BFloat16Bytes converts vector to bfloat16 bytes in little endian
+func vector2BFloat16Bytes(vector []float64) []byte {
+	bytes := make([]byte, len(vector)*2)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint16(bytes[i*2:], float32ToBFloat16(float32(v)))
+	}
+	return bytes
+}
+
+// float32ToFloat16 converts float32 to half precision bits, rounding to nearest even
+func float32ToFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+	switch {
+	case bits&0x7fffffff == 0:
+		return sign
+	case bits&0x7f800000 == 0x7f800000 && mant != 0:
+		// NaN
+		return sign | 0x7e00
+	case exp >= 0x1f:
+		// overflow to Inf
+		return sign | 0x7c00
+	case exp <= 0:
+		// subnormal
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem, halfway := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		// the carry could overflow into exponent, which is still the right result
+		half++
+	}
+	return half
+}
+
+// float32ToBFloat16 converts float32 to bfloat16 bits, rounding to nearest even
+func float32ToBFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	if bits&0x7fffffff > 0x7f800000 {
+		// NaN, keep it quiet
+		return uint16(bits>>16) | 0x40
+	}
+	bits += 0x7fff + (bits>>16)&1
+	return uint16(bits >> 16)
+}
+
 // MakeEmbeddingCtx makes the embedding context.
 func makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
 	runInfo := &callbacks.RunInfo{
@@ -53,3 +145,18 @@ func makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Conte
 
 	return callbacks.ReuseHandlers(ctx, runInfo)
 }
+
+// sparse2Embedding converts the sparse vector of schema.Document to entity.SparseEmbedding
+func sparse2Embedding(sparse map[int]float64) (entity.SparseEmbedding, error) {
+	positions := make([]uint32, 0, len(sparse))
+	for pos := range sparse {
+		positions = append(positions, uint32(pos))
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	values := make([]float32, 0, len(positions))
+	for _, pos := range positions {
+		values = append(values, float32(sparse[int(pos)]))
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
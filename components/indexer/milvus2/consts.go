@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import "github.com/milvus-io/milvus/client/v2/entity"
+
+const (
+	typ                = "Milvus2"
+	defaultCollection  = "eino_collection"
+	defaultDescription = "the collection for eino"
+
+	defaultIDField       = "id"
+	defaultContentField  = "content"
+	defaultVectorField   = "vector"
+	defaultMetadataField = "metadata"
+
+	defaultIDMaxLength      = 255
+	defaultContentMaxLength = 65535
+
+	defaultBM25Function    = "bm25_fn"
+	defaultSparseDropRatio = 0.2
+
+	// docMetaDataKeySparseVector is the metadata key of schema.Document.WithSparseVector
+	docMetaDataKeySparseVector = "_sparse_vector"
+
+	defaultVectorType       = FloatVector
+	defaultMetricType       = entity.COSINE
+	defaultConsistencyLevel = ConsistencyLevelBounded
+)
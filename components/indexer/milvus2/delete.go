@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Delete deletes the documents by ids, which are the primary keys of the collection.
+func (i *Indexer) Delete(ctx context.Context, ids []string, opts ...indexer.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	option := milvusclient.NewDeleteOption(i.config.Collection).
+		WithStringIDs(defaultIDField, ids).
+		WithPartition(i.getPartition(opts...))
+	if _, err := i.config.Client.Delete(ctx, option); err != nil {
+		return fmt.Errorf("[Indexer.Delete] failed to delete by ids: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents matching the boolean expression, e.g. `metadata["source"] == "a.md"`.
+// see: https://milvus.io/docs/boolean.md
+func (i *Indexer) DeleteByFilter(ctx context.Context, expr string, opts ...indexer.Option) error {
+	if expr == "" {
+		return fmt.Errorf("[Indexer.DeleteByFilter] expr not provided")
+	}
+
+	option := milvusclient.NewDeleteOption(i.config.Collection).
+		WithExpr(expr).
+		WithPartition(i.getPartition(opts...))
+	if _, err := i.config.Client.Delete(ctx, option); err != nil {
+		return fmt.Errorf("[Indexer.DeleteByFilter] failed to delete: %w", err)
+	}
+	return nil
+}
+
+// getPartition returns the partition of WithPartition or the config
+func (i *Indexer) getPartition(opts ...indexer.Option) string {
+	io := indexer.GetImplSpecificOptions(&ImplOptions{}, opts...)
+	if io.Partition == "" {
+		return i.config.PartitionName
+	}
+	return io.Partition
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+func TestIndexer_Delete(t *testing.T) {
+	PatchConvey("test Indexer.Delete", t, func() {
+		ctx := context.Background()
+		i := &Indexer{config: IndexerConfig{
+			Client:        &milvusclient.Client{},
+			Collection:    defaultCollection,
+			PartitionName: "p1",
+		}}
+
+		var partition, expr string
+		Mock((*milvusclient.Client).Delete).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.DeleteOption, callOptions ...grpc.CallOption) (milvusclient.DeleteResult, error) {
+			req := option.Request()
+			partition, expr = req.GetPartitionName(), req.GetExpr()
+			return milvusclient.DeleteResult{DeleteCount: 1}, nil
+		}).Build()
+
+		PatchConvey("test empty ids", func() {
+			convey.So(i.Delete(ctx, nil), convey.ShouldBeNil)
+		})
+
+		PatchConvey("test delete by ids", func() {
+			convey.So(i.Delete(ctx, []string{"doc1", "doc2"}), convey.ShouldBeNil)
+			convey.So(partition, convey.ShouldEqual, "p1")
+			convey.So(expr, convey.ShouldEqual, `id in ["doc1","doc2"]`)
+		})
+
+		PatchConvey("test delete by filter", func() {
+			err := i.DeleteByFilter(ctx, "")
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.DeleteByFilter] expr not provided"))
+
+			convey.So(i.DeleteByFilter(ctx, `metadata["source"] == "a.md"`, WithPartition("p2")), convey.ShouldBeNil)
+			convey.So(partition, convey.ShouldEqual, "p2")
+			convey.So(expr, convey.ShouldEqual, `metadata["source"] == "a.md"`)
+		})
+
+		PatchConvey("test delete error", func() {
+			Mock((*milvusclient.Client).Delete).Return(milvusclient.DeleteResult{}, fmt.Errorf("delete error")).Build()
+			err := i.Delete(ctx, []string{"doc1"})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.Delete] failed to delete by ids: delete error"))
+		})
+	})
+}
@@ -0,0 +1,339 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+type IndexerConfig struct {
+	// Client is the milvus client to be called
+	// It requires the milvusclient of github.com/milvus-io/milvus/client/v2
+	// Required
+	Client *milvusclient.Client
+
+	// Default Collection config
+	// Collection is the collection name in milvus database
+	// Optional, and the default value is "eino_collection"
+	Collection string
+	// Description is the description for collection
+	// Optional, and the default value is "the collection for eino"
+	Description string
+	// PartitionName is the partition name in milvus database
+	// Optional, and the default value is "", give priority to using WithPartition
+	PartitionName string
+	// ShardNum is the milvus required param to create collection
+	// Optional, and the default value is 1
+	ShardNum int32
+	// ConsistencyLevel is the milvus collection consistency tactics
+	// Optional, and the default level is ConsistencyLevelBounded
+	ConsistencyLevel ConsistencyLevel
+	// EnableDynamicSchema is means the collection is enabled to dynamic schema
+	// Optional, and the default value is false
+	EnableDynamicSchema bool
+	// Upsert makes Store replace the rows with the same primary key instead of inserting duplicates
+	// Optional, and the default value is false
+	Upsert bool
+
+	// Vector config
+	// VectorType is the data type of the dense vector field
+	// Optional, and the default value is FloatVector
+	VectorType VectorType
+	// Dim is the dimension of the dense vector field, which should be the output dimension of the embedding model
+	// Required
+	Dim int64
+	// MetricType is the metric type of the dense vector field
+	// Optional, and the default value is COSINE, which should be one of COSINE, IP and L2
+	MetricType entity.MetricType
+	// Index is the index of the dense vector field
+	// Optional, and the default value is AUTOINDEX with MetricType
+	Index index.Index
+
+	// SparseVectorField is the sparse float vector field name for hybrid search
+	// Optional, and the default value is empty(disable)
+	// The sparse vector of each document is read from schema.Document.SparseVector unless EnableBM25 is set
+	SparseVectorField string
+	// EnableBM25 generates SparseVectorField from the content field by the milvus BM25 function, which enables full-text search
+	// Optional, and the default value is false, it requires SparseVectorField and milvus 2.5 or later
+	EnableBM25 bool
+
+	// DocumentConverter is the function to convert the schema.Document to the columns
+	// Optional, and the default value is defaultDocumentConverter
+	DocumentConverter func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error)
+
+	// Embedding vectorization method for values needs to be embedded from schema.Document's content.
+	// Required
+	Embedding embedding.Embedder
+}
+
+type Indexer struct {
+	config IndexerConfig
+}
+
+// NewIndexer creates a new indexer.
+func NewIndexer(ctx context.Context, conf *IndexerConfig) (*Indexer, error) {
+	if err := conf.check(); err != nil {
+		return nil, err
+	}
+
+	// check the collection whether to be created
+	ok, err := conf.Client.HasCollection(ctx, milvusclient.NewHasCollectionOption(conf.Collection))
+	if err != nil {
+		return nil, fmt.Errorf("[NewIndexer] failed to check collection: %w", err)
+	}
+	if !ok {
+		indexOptions := make([]milvusclient.CreateIndexOption, 0, 2)
+		for _, idx := range conf.getIndexes() {
+			indexOptions = append(indexOptions, milvusclient.NewCreateIndexOption(conf.Collection, idx.field, idx.index))
+		}
+		err = conf.Client.CreateCollection(ctx, milvusclient.NewCreateCollectionOption(conf.Collection, conf.getDefaultSchema()).
+			WithShardNum(conf.ShardNum).
+			WithConsistencyLevel(conf.ConsistencyLevel.getConsistencyLevel()).
+			WithIndexOptions(indexOptions...))
+		if err != nil {
+			return nil, fmt.Errorf("[NewIndexer] failed to create collection: %w", err)
+		}
+	}
+
+	// load collection info
+	collection, err := conf.Client.DescribeCollection(ctx, milvusclient.NewDescribeCollectionOption(conf.Collection))
+	if err != nil {
+		return nil, fmt.Errorf("[NewIndexer] failed to describe collection: %w", err)
+	}
+	if err := conf.checkCollectionSchema(collection.Schema); err != nil {
+		return nil, fmt.Errorf("[NewIndexer] collection schema not match: %w", err)
+	}
+	if !collection.Loaded {
+		if err := conf.loadCollection(ctx); err != nil {
+			return nil, fmt.Errorf("[NewIndexer] failed to load collection: %w", err)
+		}
+	}
+
+	if conf.PartitionName != "" {
+		ok, err = conf.Client.HasPartition(ctx, milvusclient.NewHasPartitionOption(conf.Collection, conf.PartitionName))
+		if err != nil {
+			return nil, fmt.Errorf("[NewIndexer] failed to check partition: %w", err)
+		}
+		if !ok {
+			if err := conf.Client.CreatePartition(ctx, milvusclient.NewCreatePartitionOption(conf.Collection, conf.PartitionName)); err != nil {
+				return nil, fmt.Errorf("[NewIndexer] failed to create partition: %w", err)
+			}
+		}
+		task, err := conf.Client.LoadPartitions(ctx, milvusclient.NewLoadPartitionsOption(conf.Collection, conf.PartitionName))
+		if err != nil {
+			return nil, fmt.Errorf("[NewIndexer] failed to load partition: %w", err)
+		}
+		if err := task.Await(ctx); err != nil {
+			return nil, fmt.Errorf("[NewIndexer] failed to load partition: %w", err)
+		}
+	}
+
+	return &Indexer{
+		config: *conf,
+	}, nil
+}
+
+// Store stores the documents into the indexer.
+func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	co := indexer.GetCommonOptions(&indexer.Options{
+		Embedding: i.config.Embedding,
+	}, opts...)
+	ctx = callbacks.EnsureRunInfo(ctx, i.GetType(), components.ComponentOfIndexer)
+	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{
+		Docs: docs,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	emb := co.Embedding
+	if emb == nil {
+		return nil, fmt.Errorf("[Indexer.Store] embedding not provided")
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.Content)
+	}
+	vectors, err := emb.EmbedStrings(makeEmbeddingCtx(ctx, emb), texts)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.Store] failed to embed documents: %w", err)
+	}
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("[Indexer.Store] embedding result length not match need: %d, got: %d", len(docs), len(vectors))
+	}
+
+	columns, err := i.config.DocumentConverter(ctx, docs, vectors)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.Store] failed to convert documents: %w", err)
+	}
+
+	option := milvusclient.NewColumnBasedInsertOption(i.config.Collection, columns...).WithPartition(i.getPartition(opts...))
+	var idColumn column.Column
+	if i.config.Upsert {
+		result, err := i.config.Client.Upsert(ctx, option)
+		if err != nil {
+			return nil, fmt.Errorf("[Indexer.Store] failed to upsert rows: %w", err)
+		}
+		idColumn = result.IDs
+	} else {
+		result, err := i.config.Client.Insert(ctx, option)
+		if err != nil {
+			return nil, fmt.Errorf("[Indexer.Store] failed to insert rows: %w", err)
+		}
+		idColumn = result.IDs
+	}
+
+	ids = make([]string, 0, len(docs))
+	if idColumn != nil {
+		for idx := 0; idx < idColumn.Len(); idx++ {
+			id, err := idColumn.GetAsString(idx)
+			if err != nil {
+				return nil, fmt.Errorf("[Indexer.Store] failed to get id: %w", err)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	callbacks.OnEnd(ctx, &indexer.CallbackOutput{
+		IDs: ids,
+	})
+	return ids, nil
+}
+
+func (i *Indexer) GetType() string {
+	return typ
+}
+
+func (i *Indexer) IsCallbacksEnabled() bool {
+	return true
+}
+
+// checkCollectionSchema checks the existing collection has the fields of the default schema
+func (i *IndexerConfig) checkCollectionSchema(s *entity.Schema) error {
+	expected := i.getDefaultSchema()
+	fields := make(map[string]*entity.Field, len(s.Fields))
+	for _, field := range s.Fields {
+		fields[field.Name] = field
+	}
+	for _, e := range expected.Fields {
+		field, ok := fields[e.Name]
+		if !ok {
+			return fmt.Errorf("field %s not found", e.Name)
+		}
+		if field.DataType != e.DataType {
+			return fmt.Errorf("field %s type not match, expected: %s, got: %s", e.Name, e.DataType.Name(), field.DataType.Name())
+		}
+		if dim, ok := e.TypeParams[entity.TypeParamDim]; ok && field.TypeParams[entity.TypeParamDim] != dim {
+			return fmt.Errorf("field %s dim not match, expected: %s, got: %s", e.Name, dim, field.TypeParams[entity.TypeParamDim])
+		}
+	}
+	return nil
+}
+
+// loadCollection creates the missing indexes and loads the collection
+func (i *IndexerConfig) loadCollection(ctx context.Context) error {
+	state, err := i.Client.GetLoadState(ctx, milvusclient.NewGetLoadStateOption(i.Collection))
+	if err != nil {
+		return fmt.Errorf("failed to get load state: %w", err)
+	}
+	switch state.State {
+	case entity.LoadStateLoaded:
+		return nil
+	case entity.LoadStateNotLoad:
+		for _, idx := range i.getIndexes() {
+			indexes, err := i.Client.ListIndexes(ctx, milvusclient.NewListIndexOption(i.Collection).WithFieldName(idx.field))
+			if err != nil {
+				return fmt.Errorf("failed to list indexes: %w", err)
+			}
+			if len(indexes) > 0 {
+				continue
+			}
+			task, err := i.Client.CreateIndex(ctx, milvusclient.NewCreateIndexOption(i.Collection, idx.field, idx.index))
+			if err != nil {
+				return fmt.Errorf("failed to create index: %w", err)
+			}
+			if err := task.Await(ctx); err != nil {
+				return fmt.Errorf("failed to create index: %w", err)
+			}
+		}
+	}
+	task, err := i.Client.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(i.Collection))
+	if err != nil {
+		return err
+	}
+	return task.Await(ctx)
+}
+
+// check the indexer config and set the default value
+func (i *IndexerConfig) check() error {
+	if i.Client == nil {
+		return fmt.Errorf("[NewIndexer] milvus client not provided")
+	}
+	if i.Embedding == nil {
+		return fmt.Errorf("[NewIndexer] embedding not provided")
+	}
+	if i.Dim <= 0 {
+		return fmt.Errorf("[NewIndexer] dim not provided")
+	}
+	if i.EnableBM25 && i.SparseVectorField == "" {
+		return fmt.Errorf("[NewIndexer] sparse vector field is required by bm25")
+	}
+	if i.Collection == "" {
+		i.Collection = defaultCollection
+	}
+	if i.Description == "" {
+		i.Description = defaultDescription
+	}
+	if i.ShardNum <= 0 {
+		i.ShardNum = 1
+	}
+	if i.ConsistencyLevel <= 0 || i.ConsistencyLevel > ConsistencyLevelCustomized {
+		i.ConsistencyLevel = defaultConsistencyLevel
+	}
+	if i.VectorType == 0 {
+		i.VectorType = defaultVectorType
+	}
+	if !i.VectorType.isFloat() {
+		return fmt.Errorf("[NewIndexer] vector type not supported: %s", i.VectorType.getFieldType().Name())
+	}
+	if i.MetricType == "" {
+		i.MetricType = defaultMetricType
+	}
+	if !isFloatMetric(i.MetricType) {
+		return fmt.Errorf("[NewIndexer] metric type %s not match vector type %s", i.MetricType, i.VectorType.getFieldType().Name())
+	}
+	if i.Index == nil {
+		i.Index = index.NewAutoIndex(i.MetricType)
+	}
+	if i.DocumentConverter == nil {
+		i.DocumentConverter = i.defaultDocumentConverter()
+	}
+	return nil
+}
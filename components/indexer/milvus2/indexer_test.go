@@ -0,0 +1,199 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+type mockEmbedding struct{}
+
+func (m *mockEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	result := make([][]float64, len(texts))
+	for i := range texts {
+		result[i] = []float64{0.1, 0.2, 0.3}
+	}
+	return result, nil
+}
+
+func TestNewIndexer(t *testing.T) {
+	PatchConvey("test NewIndexer", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		mockEmb := &mockEmbedding{}
+
+		PatchConvey("test indexer config check", func() {
+			_, err := NewIndexer(ctx, &IndexerConfig{Embedding: mockEmb, Dim: 3})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] milvus client not provided"))
+
+			_, err = NewIndexer(ctx, &IndexerConfig{Client: mockClient, Dim: 3})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] embedding not provided"))
+
+			_, err = NewIndexer(ctx, &IndexerConfig{Client: mockClient, Embedding: mockEmb})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] dim not provided"))
+
+			_, err = NewIndexer(ctx, &IndexerConfig{Client: mockClient, Embedding: mockEmb, Dim: 3, EnableBM25: true})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] sparse vector field is required by bm25"))
+
+			_, err = NewIndexer(ctx, &IndexerConfig{Client: mockClient, Embedding: mockEmb, Dim: 3, MetricType: entity.HAMMING})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] metric type HAMMING not match vector type FloatVector"))
+		})
+
+		PatchConvey("test create collection", func() {
+			var created *entity.Schema
+			Mock((*milvusclient.Client).HasCollection).Return(false, nil).Build()
+			Mock((*milvusclient.Client).CreateCollection).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.CreateCollectionOption, callOptions ...grpc.CallOption) error {
+				sch := &schemapb.CollectionSchema{}
+				convey.So(proto.Unmarshal(option.Request().GetSchema(), sch), convey.ShouldBeNil)
+				created = (&entity.Schema{}).ReadProto(sch)
+				convey.So(len(option.Indexes()), convey.ShouldEqual, 2)
+				return nil
+			}).Build()
+
+			conf := &IndexerConfig{
+				Client:            mockClient,
+				Embedding:         mockEmb,
+				Dim:               3,
+				SparseVectorField: "sparse",
+				EnableBM25:        true,
+			}
+
+			PatchConvey("test create collection error", func() {
+				Mock((*milvusclient.Client).CreateCollection).Return(fmt.Errorf("create error")).Build()
+				_, err := NewIndexer(ctx, conf)
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] failed to create collection: create error"))
+			})
+
+			PatchConvey("test schema not match", func() {
+				Mock((*milvusclient.Client).DescribeCollection).Return(&entity.Collection{
+					Schema: entity.NewSchema().WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar)),
+				}, nil).Build()
+				_, err := NewIndexer(ctx, conf)
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewIndexer] collection schema not match: field content not found"))
+			})
+
+			PatchConvey("test success", func() {
+				Mock((*milvusclient.Client).DescribeCollection).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.DescribeCollectionOption, callOptions ...grpc.CallOption) (*entity.Collection, error) {
+					return &entity.Collection{Schema: created, Loaded: true}, nil
+				}).Build()
+				i, err := NewIndexer(ctx, conf)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(i, convey.ShouldNotBeNil)
+				convey.So(i.config.MetricType, convey.ShouldEqual, entity.COSINE)
+				convey.So(created.Fields[2].DataType, convey.ShouldEqual, entity.FieldTypeFloatVector)
+				convey.So(created.Fields[2].TypeParams[entity.TypeParamDim], convey.ShouldEqual, "3")
+				convey.So(created.Fields[4].DataType, convey.ShouldEqual, entity.FieldTypeSparseVector)
+				convey.So(len(created.Functions), convey.ShouldEqual, 1)
+				convey.So(created.Functions[0].Type, convey.ShouldEqual, entity.FunctionTypeBM25)
+				convey.So(created.Functions[0].OutputFieldNames, convey.ShouldResemble, []string{"sparse"})
+			})
+		})
+	})
+}
+
+func TestIndexer_Store(t *testing.T) {
+	PatchConvey("test Indexer.Store", t, func() {
+		ctx := context.Background()
+		conf := &IndexerConfig{
+			Client:    &milvusclient.Client{},
+			Embedding: &mockEmbedding{},
+			Dim:       3,
+		}
+		convey.So(conf.check(), convey.ShouldBeNil)
+		i := &Indexer{config: *conf}
+		docs := []*schema.Document{
+			{ID: "1", Content: "a", MetaData: map[string]any{"k": "v"}},
+			{ID: "2", Content: "b"},
+		}
+		ids := column.NewColumnVarChar("id", []string{"1", "2"})
+
+		PatchConvey("test insert error", func() {
+			Mock((*milvusclient.Client).Insert).Return(milvusclient.InsertResult{}, fmt.Errorf("insert error")).Build()
+			_, err := i.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[Indexer.Store] failed to insert rows: insert error"))
+		})
+
+		PatchConvey("test insert success", func() {
+			Mock((*milvusclient.Client).Insert).Return(milvusclient.InsertResult{InsertCount: 2, IDs: ids}, nil).Build()
+			result, err := i.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(result, convey.ShouldResemble, []string{"1", "2"})
+		})
+
+		PatchConvey("test upsert success", func() {
+			i.config.Upsert = true
+			Mock((*milvusclient.Client).Upsert).Return(milvusclient.UpsertResult{UpsertCount: 2, IDs: ids}, nil).Build()
+			result, err := i.Store(ctx, docs, WithPartition("p1"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(result, convey.ShouldResemble, []string{"1", "2"})
+		})
+	})
+}
+
+func TestDefaultDocumentConverter(t *testing.T) {
+	convey.Convey("test defaultDocumentConverter", t, func() {
+		ctx := context.Background()
+		doc := &schema.Document{ID: "1", Content: "a", MetaData: map[string]any{"k": "v"}}
+		doc.WithSparseVector(map[int]float64{5: 0.5, 1: 0.1})
+		vectors := [][]float64{{1, -2}}
+
+		for _, vectorType := range []VectorType{FloatVector, Float16Vector, BFloat16Vector} {
+			conf := &IndexerConfig{Client: &milvusclient.Client{}, Embedding: &mockEmbedding{}, Dim: 2, VectorType: vectorType}
+			convey.So(conf.check(), convey.ShouldBeNil)
+			columns, err := conf.DocumentConverter(ctx, []*schema.Document{doc}, vectors)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(columns), convey.ShouldEqual, 4)
+			convey.So(columns[2].Type(), convey.ShouldEqual, vectorType.getFieldType())
+			metadata, _ := columns[3].Get(0)
+			convey.So(string(metadata.([]byte)), convey.ShouldEqual, `{"k":"v"}`)
+		}
+
+		conf := &IndexerConfig{Client: &milvusclient.Client{}, Embedding: &mockEmbedding{}, Dim: 2, VectorType: VectorType(entity.FieldTypeBinaryVector)}
+		convey.So(conf.check(), convey.ShouldBeError, fmt.Errorf("[NewIndexer] vector type not supported: BinaryVector"))
+
+		conf = &IndexerConfig{Client: &milvusclient.Client{}, Embedding: &mockEmbedding{}, Dim: 2, SparseVectorField: "sparse"}
+		convey.So(conf.check(), convey.ShouldBeNil)
+		convey.So(conf.MetricType, convey.ShouldEqual, entity.COSINE)
+		columns, err := conf.DocumentConverter(ctx, []*schema.Document{doc}, vectors)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(len(columns), convey.ShouldEqual, 5)
+		convey.So(columns[2].Type(), convey.ShouldEqual, entity.FieldTypeFloatVector)
+		convey.So(columns[4].Type(), convey.ShouldEqual, entity.FieldTypeSparseVector)
+
+		_, err = conf.DocumentConverter(ctx, []*schema.Document{{ID: "2"}}, vectors)
+		convey.So(err, convey.ShouldBeError, fmt.Errorf("sparse vector not found in document: 2"))
+
+		// the sparse vector is generated by milvus with bm25
+		conf = &IndexerConfig{Client: &milvusclient.Client{}, Embedding: &mockEmbedding{}, Dim: 2, SparseVectorField: "sparse", EnableBM25: true}
+		convey.So(conf.check(), convey.ShouldBeNil)
+		columns, err = conf.DocumentConverter(ctx, []*schema.Document{{ID: "2"}}, vectors)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(len(columns), convey.ShouldEqual, 4)
+	})
+}
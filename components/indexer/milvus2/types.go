@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+)
+
+// ConsistencyLevel is the milvus collection consistency tactics, and the zero value means the default level
+type ConsistencyLevel entity.ConsistencyLevel
+
+const (
+	ConsistencyLevelStrong     ConsistencyLevel = 1
+	ConsistencyLevelSession    ConsistencyLevel = 2
+	ConsistencyLevelBounded    ConsistencyLevel = 3
+	ConsistencyLevelEventually ConsistencyLevel = 4
+	ConsistencyLevelCustomized ConsistencyLevel = 5
+)
+
+// getConsistencyLevel returns the consistency level of milvus
+func (c ConsistencyLevel) getConsistencyLevel() entity.ConsistencyLevel {
+	return entity.ConsistencyLevel(c - 1)
+}
+
+// VectorType is the data type of the dense vector field
+type VectorType entity.FieldType
+
+const (
+	// FloatVector stores the embedding as float32
+	FloatVector = VectorType(entity.FieldTypeFloatVector)
+	// Float16Vector stores the embedding as IEEE 754 half precision
+	Float16Vector = VectorType(entity.FieldTypeFloat16Vector)
+	// BFloat16Vector stores the embedding as brain floating point
+	BFloat16Vector = VectorType(entity.FieldTypeBFloat16Vector)
+)
+
+// getFieldType returns the field type
+func (t VectorType) getFieldType() entity.FieldType {
+	return entity.FieldType(t)
+}
+
+// isFloat reports whether the vector type stores float values
+func (t VectorType) isFloat() bool {
+	return t == FloatVector || t == Float16Vector || t == BFloat16Vector
+}
+
+// isFloatMetric reports whether the metric type is used by the float vectors
+func isFloatMetric(metric entity.MetricType) bool {
+	return metric == entity.L2 || metric == entity.IP || metric == entity.COSINE
+}
+
+// getDefaultSchema returns the default collection schema
+func (i *IndexerConfig) getDefaultSchema() *entity.Schema {
+	content := entity.NewField().
+		WithName(defaultContentField).
+		WithDescription("the content of the document").
+		WithDataType(entity.FieldTypeVarChar).
+		WithMaxLength(defaultContentMaxLength)
+	if i.EnableBM25 {
+		content.WithEnableAnalyzer(true)
+	}
+
+	s := entity.NewSchema().
+		WithName(i.Collection).
+		WithDescription(i.Description).
+		WithDynamicFieldEnabled(i.EnableDynamicSchema).
+		WithField(entity.NewField().
+			WithName(defaultIDField).
+			WithDescription("the unique id of the document").
+			WithIsPrimaryKey(true).
+			WithDataType(entity.FieldTypeVarChar).
+			WithMaxLength(defaultIDMaxLength)).
+		WithField(content).
+		WithField(entity.NewField().
+			WithName(defaultVectorField).
+			WithDescription("the vector of the document").
+			WithDataType(i.VectorType.getFieldType()).
+			WithDim(i.Dim)).
+		WithField(entity.NewField().
+			WithName(defaultMetadataField).
+			WithDescription("the metadata of the document").
+			WithDataType(entity.FieldTypeJSON))
+
+	if i.SparseVectorField != "" {
+		s.WithField(entity.NewField().
+			WithName(i.SparseVectorField).
+			WithDescription("the sparse vector of the document").
+			WithDataType(entity.FieldTypeSparseVector))
+	}
+	if i.EnableBM25 {
+		s.WithFunction(entity.NewFunction().
+			WithName(defaultBM25Function).
+			WithType(entity.FunctionTypeBM25).
+			WithInputFields(defaultContentField).
+			WithOutputFields(i.SparseVectorField))
+	}
+	return s
+}
+
+// fieldIndex is the index of a vector field
+type fieldIndex struct {
+	field string
+	index index.Index
+}
+
+// getIndexes returns the indexes to be created for the vector fields
+func (i *IndexerConfig) getIndexes() []fieldIndex {
+	indexes := []fieldIndex{{field: defaultVectorField, index: i.Index}}
+	if i.SparseVectorField != "" {
+		metric := entity.IP
+		if i.EnableBM25 {
+			metric = entity.BM25
+		}
+		indexes = append(indexes, fieldIndex{
+			field: i.SparseVectorField,
+			index: index.NewSparseInvertedIndex(metric, defaultSparseDropRatio),
+		})
+	}
+	return indexes
+}
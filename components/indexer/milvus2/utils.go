@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// defaultDocumentConverter returns the default document converter, which builds the columns of the default schema
+func (i *IndexerConfig) defaultDocumentConverter() func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error) {
+	return func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error) {
+		if len(vectors) != len(docs) {
+			return nil, fmt.Errorf("vectors length not match need: %d, got: %d", len(docs), len(vectors))
+		}
+
+		ids := make([]string, 0, len(docs))
+		contents := make([]string, 0, len(docs))
+		metadatas := make([][]byte, 0, len(docs))
+		dense := make([][]float32, 0, len(docs))
+		var sparse []entity.SparseEmbedding
+		writeSparse := i.SparseVectorField != "" && !i.EnableBM25
+
+		for idx, doc := range docs {
+			// the sparse vector has been stored in its own field
+			metadata := make(map[string]any, len(doc.MetaData))
+			for k, v := range doc.MetaData {
+				if k == docMetaDataKeySparseVector {
+					continue
+				}
+				metadata[k] = v
+			}
+			b, err := sonic.Marshal(metadata)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+			}
+
+			ids = append(ids, doc.ID)
+			contents = append(contents, doc.Content)
+			metadatas = append(metadatas, b)
+			dense = append(dense, vector2Float32(vectors[idx]))
+
+			if writeSparse {
+				sv := doc.SparseVector()
+				if sv == nil {
+					return nil, fmt.Errorf("sparse vector not found in document: %s", doc.ID)
+				}
+				embedding, err := sparse2Embedding(sv)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert sparse vector: %w", err)
+				}
+				sparse = append(sparse, embedding)
+			}
+		}
+
+		columns := []column.Column{
+			column.NewColumnVarChar(defaultIDField, ids),
+			column.NewColumnVarChar(defaultContentField, contents),
+			i.vectorColumn(dense),
+			column.NewColumnJSONBytes(defaultMetadataField, metadatas),
+		}
+		if writeSparse {
+			columns = append(columns, column.NewColumnSparseVectors(i.SparseVectorField, sparse))
+		}
+		return columns, nil
+	}
+}
+
+// vectorColumn builds the dense vector column of VectorType
+func (i *IndexerConfig) vectorColumn(vectors [][]float32) column.Column {
+	dim := int(i.Dim)
+	switch i.VectorType {
+	case Float16Vector:
+		return column.NewColumnFloat16VectorFromFp32Vector(defaultVectorField, dim, vectors)
+	case BFloat16Vector:
+		return column.NewColumnBFloat16VectorFromFp32Vector(defaultVectorField, dim, vectors)
+	default:
+		return column.NewColumnFloatVector(defaultVectorField, dim, vectors)
+	}
+}
+
+// vector2Float32 converts vector to float32 slice
+func vector2Float32(vector []float64) []float32 {
+	float32Arr := make([]float32, len(vector))
+	for i, v := range vector {
+		float32Arr[i] = float32(v)
+	}
+	return float32Arr
+}
+
+// sparse2Embedding converts the sparse vector of schema.Document to entity.SparseEmbedding
+func sparse2Embedding(sparse map[int]float64) (entity.SparseEmbedding, error) {
+	positions := make([]uint32, 0, len(sparse))
+	for pos := range sparse {
+		positions = append(positions, uint32(pos))
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	values := make([]float32, 0, len(positions))
+	for _, pos := range positions {
+		values = append(values, float32(sparse[int(pos)]))
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
+
+// makeEmbeddingCtx makes the embedding context.
+func makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfEmbedding,
+	}
+
+	if embType, ok := components.GetType(emb); ok {
+		runInfo.Type = embType
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
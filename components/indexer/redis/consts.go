@@ -20,3 +20,5 @@ const (
 	defaultReturnFieldContent       = "content"
 	defaultReturnFieldVectorContent = "vector_content"
 )
+
+const defaultDeleteBatchSize = 1000
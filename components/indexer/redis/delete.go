@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+)
+
+// Indexer implements the delete methods of the indexers: Delete by ids, and DeleteByFilter by the native filter of the store
+var _ interface {
+	Delete(ctx context.Context, ids []string, opts ...indexer.Option) error
+	DeleteByFilter(ctx context.Context, filter string, opts ...indexer.Option) error
+} = (*Indexer)(nil)
+
+// Delete removes hashes by ids, hash key would be KeyPrefix+id.
+// If DocumentToHashes doesn't use document ID as hash key, pass the hash keys (without KeyPrefix) instead.
+func (i *Indexer) Delete(ctx context.Context, ids []string, opts ...indexer.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, i.config.KeyPrefix+id)
+	}
+
+	if err := i.config.Client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("[Delete] del keys failed, %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByFilter removes all hashes matching query from IndexerConfig.Index, e.g. `@source:{a\.md}`.
+// see: https://redis.io/docs/latest/develop/interact/search-and-query/query/
+func (i *Indexer) DeleteByFilter(ctx context.Context, query string, opts ...indexer.Option) error {
+	if i.config.Index == "" {
+		return fmt.Errorf("[DeleteByFilter] index not provided")
+	}
+
+	for {
+		result, err := i.config.Client.FTSearchWithArgs(ctx, i.config.Index, query, &redis.FTSearchOptions{
+			NoContent: true,
+			Limit:     defaultDeleteBatchSize,
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("[DeleteByFilter] search failed, %w", err)
+		}
+
+		if len(result.Docs) == 0 {
+			return nil
+		}
+
+		keys := make([]string, 0, len(result.Docs))
+		for _, doc := range result.Docs {
+			keys = append(keys, doc.ID)
+		}
+
+		deleted, err := i.config.Client.Del(ctx, keys...).Result()
+		if err != nil {
+			return fmt.Errorf("[DeleteByFilter] del keys failed, %w", err)
+		}
+
+		// stop if nothing deleted, in case of stale index entries
+		if deleted == 0 || len(result.Docs) < defaultDeleteBatchSize {
+			return nil
+		}
+	}
+}
+
+// deleteStaleChunks removes the hashes whose SourceIDField is one of the source ids of docs,
+// but not in keys, which are the hash keys just stored for docs.
+func (i *Indexer) deleteStaleChunks(ctx context.Context, docs []*schema.Document, keys []string) error {
+	field := i.config.SourceIDField
+
+	var sources []string
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		v, ok := doc.MetaData[field]
+		if !ok {
+			continue
+		}
+
+		sourceID, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("[deleteStaleChunks] source id should be string, got %T, doc id=%s", v, doc.ID)
+		}
+
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, escapeTag(sourceID))
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil
+	}
+
+	stored := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		stored[key] = true
+	}
+
+	query := fmt.Sprintf("@%s:{%s}", field, strings.Join(sources, " | "))
+
+	// collect stale keys before deleting, so that paging is not shifted by the deletion
+	var stale []string
+	for offset := 0; ; offset += defaultDeleteBatchSize {
+		result, err := i.config.Client.FTSearchWithArgs(ctx, i.config.Index, query, &redis.FTSearchOptions{
+			NoContent:   true,
+			LimitOffset: offset,
+			Limit:       defaultDeleteBatchSize,
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("[deleteStaleChunks] search failed, %w", err)
+		}
+
+		for _, doc := range result.Docs {
+			if !stored[doc.ID] {
+				stale = append(stale, doc.ID)
+			}
+		}
+
+		if len(result.Docs) < defaultDeleteBatchSize {
+			break
+		}
+	}
+
+	for start := 0; start < len(stale); start += defaultDeleteBatchSize {
+		end := min(start+defaultDeleteBatchSize, len(stale))
+		if err := i.config.Client.Del(ctx, stale[start:end]...).Err(); err != nil {
+			return fmt.Errorf("[deleteStaleChunks] del keys failed, %w", err)
+		}
+	}
+
+	return nil
+}
+
+// escapeTag escapes the punctuations and spaces of a tag value in query.
+// see: https://redis.io/docs/latest/develop/interact/search-and-query/advanced-concepts/tags/
+func escapeTag(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(",.<>{}[]\"'\\:;!@#$%^&*()-+=~|/ ", r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestDelete(t *testing.T) {
+	PatchConvey("test Delete", t, func() {
+		ctx := context.Background()
+		mockClient := redis.NewClient(&redis.Options{})
+		i := &Indexer{config: &IndexerConfig{Client: mockClient, KeyPrefix: "test_prefix:"}}
+
+		PatchConvey("test empty ids", func() {
+			convey.So(i.Delete(ctx, nil), convey.ShouldBeNil)
+		})
+
+		PatchConvey("test del failed", func() {
+			Mock(GetMethod(mockClient, "Del")).Return(redis.NewIntResult(0, fmt.Errorf("mock err"))).Build()
+			convey.So(i.Delete(ctx, []string{"1"}), convey.ShouldBeError, fmt.Errorf("[Delete] del keys failed, %w", fmt.Errorf("mock err")))
+		})
+
+		PatchConvey("test success", func() {
+			var keys []string
+			Mock(GetMethod(mockClient, "Del")).To(func(ctx context.Context, k ...string) *redis.IntCmd {
+				keys = k
+				return redis.NewIntResult(int64(len(k)), nil)
+			}).Build()
+			convey.So(i.Delete(ctx, []string{"1", "2"}), convey.ShouldBeNil)
+			convey.So(keys, convey.ShouldResemble, []string{"test_prefix:1", "test_prefix:2"})
+		})
+	})
+}
+
+func TestDeleteByFilter(t *testing.T) {
+	PatchConvey("test DeleteByFilter", t, func() {
+		ctx := context.Background()
+		mockClient := redis.NewClient(&redis.Options{})
+		i := &Indexer{config: &IndexerConfig{Client: mockClient, KeyPrefix: "test_prefix:", Index: "test_index"}}
+		query := `@source:{a\.md}`
+
+		PatchConvey("test index not provided", func() {
+			i.config.Index = ""
+			convey.So(i.DeleteByFilter(ctx, query), convey.ShouldBeError, fmt.Errorf("[DeleteByFilter] index not provided"))
+		})
+
+		PatchConvey("test search failed", func() {
+			cmd := &redis.FTSearchCmd{}
+			cmd.SetErr(fmt.Errorf("mock err"))
+			Mock(GetMethod(mockClient, "FTSearchWithArgs")).Return(cmd).Build()
+			convey.So(i.DeleteByFilter(ctx, query), convey.ShouldBeError, fmt.Errorf("[DeleteByFilter] search failed, %w", fmt.Errorf("mock err")))
+		})
+
+		PatchConvey("test success", func() {
+			var (
+				searched int
+				deleted  []string
+			)
+			Mock(GetMethod(mockClient, "FTSearchWithArgs")).To(func(ctx context.Context, index string, q string, options *redis.FTSearchOptions) *redis.FTSearchCmd {
+				convey.So(index, convey.ShouldEqual, "test_index")
+				convey.So(q, convey.ShouldEqual, query)
+				convey.So(options.NoContent, convey.ShouldBeTrue)
+
+				cmd := &redis.FTSearchCmd{}
+				if searched == 0 {
+					cmd.SetVal(redis.FTSearchResult{Total: 2, Docs: []redis.Document{{ID: "test_prefix:1"}, {ID: "test_prefix:2"}}})
+				}
+				searched++
+				return cmd
+			}).Build()
+			Mock(GetMethod(mockClient, "Del")).To(func(ctx context.Context, k ...string) *redis.IntCmd {
+				deleted = append(deleted, k...)
+				return redis.NewIntResult(int64(len(k)), nil)
+			}).Build()
+
+			convey.So(i.DeleteByFilter(ctx, query), convey.ShouldBeNil)
+			convey.So(searched, convey.ShouldEqual, 1)
+			convey.So(deleted, convey.ShouldResemble, []string{"test_prefix:1", "test_prefix:2"})
+		})
+	})
+}
+
+func TestDeleteStaleChunks(t *testing.T) {
+	PatchConvey("test deleteStaleChunks", t, func() {
+		ctx := context.Background()
+		mockClient := redis.NewClient(&redis.Options{})
+		i := &Indexer{config: &IndexerConfig{Client: mockClient, KeyPrefix: "test_prefix:", Index: "test_index", SourceIDField: "source_id"}}
+		docs := []*schema.Document{
+			{ID: "a_1", MetaData: map[string]any{"source_id": "a.md"}},
+			{ID: "b_1", MetaData: map[string]any{"source_id": "b c.md"}},
+			{ID: "c_1"},
+		}
+		keys := []string{"test_prefix:a_1", "test_prefix:b_1", "test_prefix:c_1"}
+
+		PatchConvey("test no source id", func() {
+			convey.So(i.deleteStaleChunks(ctx, docs[2:], keys[2:]), convey.ShouldBeNil)
+		})
+
+		PatchConvey("test source id not string", func() {
+			d := &schema.Document{ID: "d_1", MetaData: map[string]any{"source_id": 1}}
+			convey.So(i.deleteStaleChunks(ctx, []*schema.Document{d}, []string{"test_prefix:d_1"}), convey.ShouldBeError,
+				fmt.Errorf("[deleteStaleChunks] source id should be string, got int, doc id=d_1"))
+		})
+
+		PatchConvey("test search failed", func() {
+			cmd := &redis.FTSearchCmd{}
+			cmd.SetErr(fmt.Errorf("mock err"))
+			Mock(GetMethod(mockClient, "FTSearchWithArgs")).Return(cmd).Build()
+			convey.So(i.deleteStaleChunks(ctx, docs, keys), convey.ShouldBeError, fmt.Errorf("[deleteStaleChunks] search failed, %w", fmt.Errorf("mock err")))
+		})
+
+		PatchConvey("test success", func() {
+			var deleted []string
+			Mock(GetMethod(mockClient, "FTSearchWithArgs")).To(func(ctx context.Context, index string, q string, options *redis.FTSearchOptions) *redis.FTSearchCmd {
+				convey.So(index, convey.ShouldEqual, "test_index")
+				convey.So(q, convey.ShouldEqual, `@source_id:{a\.md | b\ c\.md}`)
+				convey.So(options.NoContent, convey.ShouldBeTrue)
+				convey.So(options.LimitOffset, convey.ShouldEqual, 0)
+
+				cmd := &redis.FTSearchCmd{}
+				cmd.SetVal(redis.FTSearchResult{Total: 3, Docs: []redis.Document{{ID: "test_prefix:a_1"}, {ID: "test_prefix:a_2"}, {ID: "test_prefix:b_1"}}})
+				return cmd
+			}).Build()
+			Mock(GetMethod(mockClient, "Del")).To(func(ctx context.Context, k ...string) *redis.IntCmd {
+				deleted = append(deleted, k...)
+				return redis.NewIntResult(int64(len(k)), nil)
+			}).Build()
+
+			convey.So(i.deleteStaleChunks(ctx, docs, keys), convey.ShouldBeNil)
+			convey.So(deleted, convey.ShouldResemble, []string{"test_prefix:a_2"})
+		})
+	})
+}
@@ -47,6 +47,18 @@ type IndexerConfig struct {
 	// BatchSize controls embedding texts size.
 	// Default 10.
 	BatchSize int `json:"batch_size"`
+	// Upsert if true, existing hash with the same key will be deleted before hset,
+	// so fields not present in the new document won't be left over.
+	// Default false.
+	Upsert bool `json:"upsert"`
+	// Index name of the redis search index created on KeyPrefix, required by DeleteByFilter and SourceIDField.
+	Index string `json:"index"`
+	// SourceIDField enables replacing the chunks of source documents, e.g. the chunks split from a file.
+	// It is both the metadata key of the source document id in the chunks and the hash field storing it,
+	// which is added to the fields from DocumentToHashes if absent, and must be a TAG field of Index.
+	// If set, Store deletes the stored hashes of the same source ids which are not stored by this call,
+	// so that re-indexing a shrunk source document leaves no stale chunks.
+	SourceIDField string `json:"source_id_field"`
 	// Embedding vectorization method for values need to be embedded from FieldValue.
 	Embedding embedding.Embedder
 }
@@ -82,6 +94,10 @@ func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
 		return nil, fmt.Errorf("[NewIndexer] redis client not provided")
 	}
 
+	if config.SourceIDField != "" && config.Index == "" {
+		return nil, fmt.Errorf("[NewIndexer] index not provided for source id field")
+	}
+
 	if config.DocumentToHashes == nil {
 		config.DocumentToHashes = defaultDocumentToFields
 	}
@@ -108,10 +124,17 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 		}
 	}()
 
-	if err = i.pipelineHSet(ctx, docs, options); err != nil {
+	keys, err := i.pipelineHSet(ctx, docs, options)
+	if err != nil {
 		return nil, err
 	}
 
+	if i.config.SourceIDField != "" {
+		if err = i.deleteStaleChunks(ctx, docs, keys); err != nil {
+			return nil, err
+		}
+	}
+
 	ids = make([]string, 0, len(docs))
 	for _, doc := range docs {
 		// If you need hash key returned by FieldMapping, set doc.ID with key manually in DocumentToHashes.
@@ -123,9 +146,11 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 	return ids, nil
 }
 
-func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, options *indexer.Options) (err error) {
+// pipelineHSet sets the hashes of docs, and returns the hash keys (with KeyPrefix) in the order of docs
+func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, options *indexer.Options) (keys []string, err error) {
 	emb := options.Embedding
 	pipeline := i.config.Client.Pipeline()
+	keys = make([]string, 0, len(docs))
 
 	var (
 		tuples []tuple
@@ -156,7 +181,12 @@ func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, opt
 				fields[k] = vector2Bytes(vectors[idx])
 			}
 
-			pipeline.HSet(ctx, i.config.KeyPrefix+t.key, flatten(fields)...)
+			key := i.config.KeyPrefix + t.key
+			if i.config.Upsert {
+				pipeline.Del(ctx, key)
+			}
+
+			pipeline.HSet(ctx, key, flatten(fields)...)
 		}
 
 		tuples = tuples[:0]
@@ -168,7 +198,7 @@ func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, opt
 	for _, doc := range docs {
 		hashes, err := i.config.DocumentToHashes(ctx, doc)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		key := hashes.Key
@@ -183,13 +213,13 @@ func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, opt
 		}
 
 		if embSize > i.config.BatchSize {
-			return fmt.Errorf("[pipelineHSet] embedding size over batch size, batch size=%d, got size=%d",
+			return nil, fmt.Errorf("[pipelineHSet] embedding size over batch size, batch size=%d, got size=%d",
 				i.config.BatchSize, embSize)
 		}
 
 		if len(texts)+embSize > i.config.BatchSize {
 			if err = embAndAdd(); err != nil {
-				return err
+				return nil, err
 			}
 		}
 
@@ -197,20 +227,20 @@ func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, opt
 		for k, v := range field2Value {
 			if v.EmbedKey != "" {
 				if _, found := fields[v.EmbedKey]; found {
-					return fmt.Errorf("[pipelineHSet] duplicate key for value and vector, field=%s", k)
+					return nil, fmt.Errorf("[pipelineHSet] duplicate key for value and vector, field=%s", k)
 				}
 
 				var text string
 				if v.Stringify != nil {
 					text, err = v.Stringify(v.Value)
 					if err != nil {
-						return err
+						return nil, err
 					}
 				} else {
 					var ok bool
 					text, ok = v.Value.(string)
 					if !ok {
-						return fmt.Errorf("[pipelineHSet] assert value as string failed, key=%s, emb_key=%s", k, v.EmbedKey)
+						return nil, fmt.Errorf("[pipelineHSet] assert value as string failed, key=%s, emb_key=%s", k, v.EmbedKey)
 					}
 				}
 
@@ -219,24 +249,33 @@ func (i *Indexer) pipelineHSet(ctx context.Context, docs []*schema.Document, opt
 			}
 		}
 
+		if field := i.config.SourceIDField; field != "" {
+			if _, found := fields[field]; !found {
+				if sourceID, ok := doc.MetaData[field]; ok {
+					fields[field] = sourceID
+				}
+			}
+		}
+
 		tuples = append(tuples, tuple{
 			key:     key,
 			fields:  fields,
 			key2Idx: key2Idx,
 		})
+		keys = append(keys, i.config.KeyPrefix+key)
 	}
 
 	if len(tuples) > 0 {
 		if err = embAndAdd(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if _, err = pipeline.Exec(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return keys, nil
 }
 
 func (i *Indexer) makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
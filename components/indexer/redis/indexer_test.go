@@ -53,9 +53,10 @@ func TestPipelineHSet(t *testing.T) {
 				},
 			}
 
-			convey.So(i.pipelineHSet(ctx, docs, &indexer.Options{
+			_, err := i.pipelineHSet(ctx, docs, &indexer.Options{
 				Embedding: nil,
-			}), convey.ShouldBeError, fmt.Errorf("mock err"))
+			})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("mock err"))
 		})
 
 		PatchConvey("test embSize > i.config.BatchSize", func() {
@@ -82,9 +83,10 @@ func TestPipelineHSet(t *testing.T) {
 				},
 			}
 
-			convey.So(i.pipelineHSet(ctx, docs, &indexer.Options{
+			_, err := i.pipelineHSet(ctx, docs, &indexer.Options{
 				Embedding: nil,
-			}), convey.ShouldBeError, fmt.Errorf("[pipelineHSet] embedding size over batch size, batch size=%d, got size=%d",
+			})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[pipelineHSet] embedding size over batch size, batch size=%d, got size=%d",
 				i.config.BatchSize, 2))
 		})
 
@@ -98,9 +100,10 @@ func TestPipelineHSet(t *testing.T) {
 				},
 			}
 
-			convey.So(i.pipelineHSet(ctx, docs, &indexer.Options{
+			_, err := i.pipelineHSet(ctx, docs, &indexer.Options{
 				Embedding: nil,
-			}), convey.ShouldBeError, fmt.Errorf("[pipelineHSet] embedding method not provided"))
+			})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[pipelineHSet] embedding method not provided"))
 		})
 
 		PatchConvey("test embedding failed", func() {
@@ -113,9 +116,10 @@ func TestPipelineHSet(t *testing.T) {
 				},
 			}
 
-			convey.So(i.pipelineHSet(ctx, docs, &indexer.Options{
+			_, err := i.pipelineHSet(ctx, docs, &indexer.Options{
 				Embedding: &mockEmbedding{err: exp},
-			}), convey.ShouldBeError, fmt.Errorf("[pipelineHSet] embedding failed, %w", exp))
+			})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[pipelineHSet] embedding failed, %w", exp))
 		})
 
 		PatchConvey("test len(vectors) != len(texts)", func() {
@@ -127,9 +131,10 @@ func TestPipelineHSet(t *testing.T) {
 				},
 			}
 
-			convey.So(i.pipelineHSet(ctx, docs, &indexer.Options{
+			_, err := i.pipelineHSet(ctx, docs, &indexer.Options{
 				Embedding: &mockEmbedding{sizeForCall: []int{2}, dims: 1024},
-			}), convey.ShouldBeError, fmt.Errorf("[pipelineHSet] invalid vector length, expected=1, got=2"))
+			})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[pipelineHSet] invalid vector length, expected=1, got=2"))
 		})
 
 		PatchConvey("test success", func() {
@@ -152,9 +157,10 @@ func TestPipelineHSet(t *testing.T) {
 				},
 			}
 
-			convey.So(i.pipelineHSet(ctx, docs, &indexer.Options{
+			_, err := i.pipelineHSet(ctx, docs, &indexer.Options{
 				Embedding: &mockEmbedding{sizeForCall: []int{1, 1}, dims: 1024},
-			}), convey.ShouldBeNil)
+			})
+			convey.So(err, convey.ShouldBeNil)
 
 			slice := make([]float64, 1024)
 			for i := range slice {
@@ -183,6 +189,77 @@ func TestPipelineHSet(t *testing.T) {
 			contains(d1)
 			contains(d2)
 		})
+
+		PatchConvey("test upsert", func() {
+			var cmds []string
+			pl := &redis.Pipeline{}
+			Mock(GetMethod(mockClient, "Pipeline")).Return(pl).Build()
+			Mock(GetMethod(pl, "Del")).To(func(ctx context.Context, keys ...string) *redis.IntCmd {
+				cmds = append(cmds, "del "+keys[0])
+				return nil
+			}).Build()
+			Mock(GetMethod(pl, "HSet")).To(func(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+				cmds = append(cmds, "hset "+key)
+				return nil
+			}).Build()
+			Mock(GetMethod(pl, "Exec")).Return(nil, nil).Build()
+
+			i := &Indexer{
+				config: &IndexerConfig{
+					Client:           mockClient,
+					DocumentToHashes: defaultDocumentToFields,
+					KeyPrefix:        "test_prefix:",
+					BatchSize:        10,
+					Upsert:           true,
+				},
+			}
+
+			keys, err := i.pipelineHSet(ctx, docs, &indexer.Options{
+				Embedding: &mockEmbedding{sizeForCall: []int{2}, dims: 8},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(keys, convey.ShouldResemble, []string{"test_prefix:1", "test_prefix:2"})
+			convey.So(cmds, convey.ShouldResemble, []string{
+				"del test_prefix:1", "hset test_prefix:1",
+				"del test_prefix:2", "hset test_prefix:2",
+			})
+		})
+
+		PatchConvey("test source id field", func() {
+			args := make(map[string][]any)
+			pl := &redis.Pipeline{}
+			Mock(GetMethod(mockClient, "Pipeline")).Return(pl).Build()
+			Mock(GetMethod(pl, "HSet")).To(func(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+				args[key] = values
+				return nil
+			}).Build()
+			Mock(GetMethod(pl, "Exec")).Return(nil, nil).Build()
+
+			i := &Indexer{
+				config: &IndexerConfig{
+					Client: mockClient,
+					DocumentToHashes: func(ctx context.Context, doc *schema.Document) (*Hashes, error) {
+						return &Hashes{Key: doc.ID, Field2Value: map[string]FieldValue{
+							defaultReturnFieldContent: {Value: doc.Content},
+						}}, nil
+					},
+					KeyPrefix:     "test_prefix:",
+					BatchSize:     10,
+					SourceIDField: "source_id",
+				},
+			}
+
+			d := &schema.Document{ID: "a_1", Content: "asd", MetaData: map[string]any{"source_id": "a.md"}}
+			keys, err := i.pipelineHSet(ctx, []*schema.Document{d}, &indexer.Options{})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(keys, convey.ShouldResemble, []string{"test_prefix:a_1"})
+			convey.So(args["test_prefix:a_1"], convey.ShouldHaveLength, 4)
+			f2v := map[string]any{}
+			for j := 0; j < len(args["test_prefix:a_1"]); j += 2 {
+				f2v[args["test_prefix:a_1"][j].(string)] = args["test_prefix:a_1"][j+1]
+			}
+			convey.So(f2v, convey.ShouldResemble, map[string]any{defaultReturnFieldContent: "asd", "source_id": "a.md"})
+		})
 	})
 }
 
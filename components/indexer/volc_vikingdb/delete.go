@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package volc_vikingdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/volcengine/volc-sdk-golang/service/vikingdb"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Indexer implements the delete methods of the indexers: Delete by ids, and DeleteByFilter by the native filter of the store
+var _ interface {
+	Delete(ctx context.Context, ids []string, opts ...indexer.Option) error
+	DeleteByFilter(ctx context.Context, filter map[string]interface{}, opts ...indexer.Option) error
+} = (*Indexer)(nil)
+
+// Delete removes data by primary keys from collection.
+func (i *Indexer) Delete(ctx context.Context, ids []string, opts ...indexer.Option) error {
+	for _, sub := range chunk(ids, defaultDeleteBatchSize) {
+		if err := i.collection.DeleteData(sub); err != nil {
+			return fmt.Errorf("DeleteData failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByFilter removes all data matching filter from collection, data is searched from IndexerConfig.Index.
+// e.g. map[string]interface{}{"op": "must", "field": "source", "conds": []string{"a.md"}}
+// see: https://www.volcengine.com/docs/84313/1254609
+func (i *Indexer) DeleteByFilter(ctx context.Context, filter map[string]interface{}, opts ...indexer.Option) error {
+	return i.deleteByFilter(ctx, filter, nil)
+}
+
+// deleteStaleChunks removes the data whose SourceIDField is one of the source ids of docs, but not in docs.
+func (i *Indexer) deleteStaleChunks(ctx context.Context, docs []*schema.Document) error {
+	field := i.config.SourceIDField
+
+	var (
+		sources []string
+		ids     []interface{}
+	)
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		v, ok := doc.MetaData[field]
+		if !ok {
+			continue
+		}
+
+		sourceID, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("[deleteStaleChunks] source id should be string, got %T, doc id=%s", v, doc.ID)
+		}
+
+		if !seen[sourceID] {
+			seen[sourceID] = true
+			sources = append(sources, sourceID)
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	if len(sources) == 0 {
+		return nil
+	}
+
+	return i.deleteByFilter(ctx, map[string]interface{}{"op": "must", "field": field, "conds": sources}, ids)
+}
+
+// deleteByFilter removes all data matching filter, except the primary keys in excluded.
+func (i *Indexer) deleteByFilter(ctx context.Context, filter map[string]interface{}, excluded []interface{}) error {
+	if i.config.Index == "" {
+		return fmt.Errorf("[DeleteByFilter] index not provided")
+	}
+
+	index, err := i.service.GetIndex(i.config.Collection, i.config.Index)
+	if err != nil {
+		return fmt.Errorf("[DeleteByFilter] GetIndex failed: %w", err)
+	}
+
+	// index is updated asynchronously, exclude deleted primary keys from following searches
+	deleted := excluded
+	for {
+		options := vikingdb.NewSearchOptions().
+			SetFilter(filter).
+			SetLimit(defaultDeleteBatchSize).
+			SetOutputFields([]string{defaultFieldID})
+		if len(deleted) > 0 {
+			options.SetPrimaryKeyNotIn(deleted)
+		}
+
+		data, err := index.Search(nil, options)
+		if err != nil {
+			return fmt.Errorf("[DeleteByFilter] Search failed: %w", err)
+		}
+
+		if len(data) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, 0, len(data))
+		for _, d := range data {
+			ids = append(ids, d.Id)
+		}
+
+		if err = i.collection.DeleteData(ids); err != nil {
+			return fmt.Errorf("[DeleteByFilter] DeleteData failed: %w", err)
+		}
+
+		deleted = append(deleted, ids...)
+
+		if len(data) < defaultDeleteBatchSize {
+			return nil
+		}
+	}
+}
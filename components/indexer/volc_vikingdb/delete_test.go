@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package volc_vikingdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/volcengine/volc-sdk-golang/service/vikingdb"
+)
+
+func TestDelete(t *testing.T) {
+	PatchConvey("test Delete", t, func() {
+		ctx := context.Background()
+		collection := &vikingdb.Collection{}
+		i := &Indexer{config: &IndexerConfig{}, collection: collection}
+
+		PatchConvey("test DeleteData failed", func() {
+			Mock(GetMethod(collection, "DeleteData")).Return(fmt.Errorf("mock err")).Build()
+			err := i.Delete(ctx, []string{"1"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "mock err")
+		})
+
+		PatchConvey("test success", func() {
+			var batches []interface{}
+			Mock(GetMethod(collection, "DeleteData")).To(func(id interface{}) error {
+				batches = append(batches, id)
+				return nil
+			}).Build()
+
+			ids := make([]string, defaultDeleteBatchSize+1)
+			for j := range ids {
+				ids[j] = fmt.Sprintf("%d", j)
+			}
+
+			convey.So(i.Delete(ctx, ids), convey.ShouldBeNil)
+			convey.So(len(batches), convey.ShouldEqual, 2)
+			convey.So(batches[0], convey.ShouldResemble, ids[:defaultDeleteBatchSize])
+			convey.So(batches[1], convey.ShouldResemble, ids[defaultDeleteBatchSize:])
+		})
+	})
+}
+
+func TestDeleteByFilter(t *testing.T) {
+	PatchConvey("test DeleteByFilter", t, func() {
+		ctx := context.Background()
+		svc := &vikingdb.VikingDBService{}
+		collection := &vikingdb.Collection{}
+		index := &vikingdb.Index{}
+		i := &Indexer{config: &IndexerConfig{Collection: "test_collection", Index: "test_index"}, service: svc, collection: collection}
+		filter := map[string]interface{}{"op": "must", "field": "source", "conds": []string{"a.md"}}
+
+		PatchConvey("test index not provided", func() {
+			i.config.Index = ""
+			convey.So(i.DeleteByFilter(ctx, filter), convey.ShouldNotBeNil)
+		})
+
+		PatchConvey("test GetIndex failed", func() {
+			Mock(GetMethod(svc, "GetIndex")).Return(nil, fmt.Errorf("mock err")).Build()
+			err := i.DeleteByFilter(ctx, filter)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "mock err")
+		})
+
+		PatchConvey("test success", func() {
+			var (
+				searched int
+				deleted  []interface{}
+			)
+			Mock(GetMethod(svc, "GetIndex")).Return(index, nil).Build()
+			Mock(GetMethod(index, "Search")).To(func(order interface{}, searchOptions *vikingdb.SearchOptions) ([]*vikingdb.Data, error) {
+				searched++
+				if searched > 2 {
+					return nil, nil
+				}
+
+				data := make([]*vikingdb.Data, defaultDeleteBatchSize)
+				for j := range data {
+					data[j] = &vikingdb.Data{Id: fmt.Sprintf("%d_%d", searched, j)}
+				}
+				if searched == 2 {
+					data = data[:1]
+				}
+				return data, nil
+			}).Build()
+			Mock(GetMethod(collection, "DeleteData")).To(func(id interface{}) error {
+				deleted = append(deleted, id.([]interface{})...)
+				return nil
+			}).Build()
+
+			convey.So(i.DeleteByFilter(ctx, filter), convey.ShouldBeNil)
+			convey.So(searched, convey.ShouldEqual, 2)
+			convey.So(len(deleted), convey.ShouldEqual, defaultDeleteBatchSize+1)
+		})
+	})
+}
+
+func TestDeleteStaleChunks(t *testing.T) {
+	PatchConvey("test deleteStaleChunks", t, func() {
+		ctx := context.Background()
+		svc := &vikingdb.VikingDBService{}
+		collection := &vikingdb.Collection{}
+		index := &vikingdb.Index{}
+		i := &Indexer{config: &IndexerConfig{Collection: "test_collection", Index: "test_index", SourceIDField: "source_id"}, service: svc, collection: collection}
+		docs := []*schema.Document{
+			{ID: "a_1", MetaData: map[string]any{"source_id": "a.md"}},
+			{ID: "b_1", MetaData: map[string]any{"source_id": "b.md"}},
+			{ID: "a_2", MetaData: map[string]any{"source_id": "a.md"}},
+			{ID: "c_1"},
+		}
+
+		PatchConvey("test no source id", func() {
+			convey.So(i.deleteStaleChunks(ctx, docs[3:]), convey.ShouldBeNil)
+		})
+
+		PatchConvey("test source id not string", func() {
+			d := &schema.Document{ID: "d_1", MetaData: map[string]any{"source_id": 1}}
+			convey.So(i.deleteStaleChunks(ctx, []*schema.Document{d}), convey.ShouldBeError,
+				fmt.Errorf("[deleteStaleChunks] source id should be string, got int, doc id=d_1"))
+		})
+
+		PatchConvey("test success", func() {
+			var (
+				filter   map[string]interface{}
+				excluded []interface{}
+				deleted  []interface{}
+			)
+			Mock((*vikingdb.SearchOptions).SetFilter).To(func(s *vikingdb.SearchOptions, f map[string]interface{}) *vikingdb.SearchOptions {
+				filter = f
+				return s
+			}).Build()
+			Mock((*vikingdb.SearchOptions).SetPrimaryKeyNotIn).To(func(s *vikingdb.SearchOptions, ids []interface{}) *vikingdb.SearchOptions {
+				excluded = ids
+				return s
+			}).Build()
+			Mock(GetMethod(svc, "GetIndex")).Return(index, nil).Build()
+			Mock(GetMethod(index, "Search")).Return([]*vikingdb.Data{{Id: "a_3"}}, nil).Build()
+			Mock(GetMethod(collection, "DeleteData")).To(func(id interface{}) error {
+				deleted = append(deleted, id.([]interface{})...)
+				return nil
+			}).Build()
+
+			convey.So(i.deleteStaleChunks(ctx, docs), convey.ShouldBeNil)
+			convey.So(filter, convey.ShouldResemble, map[string]interface{}{"op": "must", "field": "source_id", "conds": []string{"a.md", "b.md"}})
+			convey.So(excluded, convey.ShouldResemble, []interface{}{"a_1", "b_1", "a_2"})
+			convey.So(deleted, convey.ShouldResemble, []interface{}{"a_3"})
+		})
+	})
+}
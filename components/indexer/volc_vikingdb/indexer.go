@@ -30,7 +30,8 @@ import (
 )
 
 const (
-	defaultAddBatchSize = 5
+	defaultAddBatchSize    = 5
+	defaultDeleteBatchSize = 100
 )
 
 type IndexerConfig struct {
@@ -42,6 +43,12 @@ type IndexerConfig struct {
 	ConnectionTimeout int64  `json:"connection_timeout"` // second
 
 	Collection string `json:"collection"`
+	// Index 数据集上的索引名称，DeleteByFilter 和 SourceIDField 需要
+	Index string `json:"index"`
+	// SourceIDField 源文档 id 的字段名，用于替换源文档（如一个文件）切分出的全部分块
+	// 既是分块 MetaData 中源文档 id 的 key，也是数据集上的 string 标量字段，未在 Fields 中配置时自动写入
+	// 配置后 Store 会删除相同源文档 id 下本次未写入的旧分块，避免源文档变短后残留过期分块
+	SourceIDField string `json:"source_id_field"`
 
 	// WithMultiModal 如果数据集在平台向量化，需要配置此字段为true，无需再配置EmbeddingConfig
 	WithMultiModal  bool            `json:"with_multi_modal"`
@@ -84,6 +91,10 @@ func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
 		}
 	}
 
+	if config.SourceIDField != "" && config.Index == "" {
+		return nil, fmt.Errorf("[VikingDBIndexer] need provide Index when SourceIDField is set")
+	}
+
 	if config.AddBatchSize == 0 {
 		config.AddBatchSize = defaultAddBatchSize
 	}
@@ -118,6 +129,7 @@ func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
 	return i, nil
 }
 
+// Store upserts docs with doc.ID as primary key, existing data with the same primary key will be replaced.
 func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
 
 	options := indexer.GetCommonOptions(&indexer.Options{
@@ -146,6 +158,12 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 		ids = append(ids, iter(sub, func(t *schema.Document) string { return t.ID })...)
 	}
 
+	if i.config.SourceIDField != "" {
+		if err = i.deleteStaleChunks(ctx, docs); err != nil {
+			return nil, fmt.Errorf("deleteStaleChunks failed: %w", err)
+		}
+	}
+
 	ctx = callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
 
 	return ids, nil
@@ -191,6 +209,14 @@ func (i *Indexer) convertDocuments(ctx context.Context, docs []*schema.Document,
 			d.Fields = make(map[string]interface{})
 		}
 
+		if field := i.config.SourceIDField; field != "" {
+			if _, found := d.Fields[field]; !found {
+				if sourceID, ok := doc.MetaData[field]; ok {
+					d.Fields[field] = sourceID
+				}
+			}
+		}
+
 		d.Fields[defaultFieldID] = doc.ID
 		d.Fields[defaultFieldContent] = doc.Content
 		if !i.config.WithMultiModal {
@@ -325,6 +325,13 @@ func TestConvertDocuments(t *testing.T) {
 			"extra_field_1":     "asd",
 		})
 		convey.So(data[1].TTL, convey.ShouldEqual, int64(123))
+
+		idx.config.SourceIDField = "source_id"
+		d1.MetaData = map[string]any{"source_id": "a.md"}
+		data, err = idx.convertDocuments(ctx, docs, options)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(data[0].Fields["source_id"], convey.ShouldEqual, "a.md")
+		convey.So(data[1].Fields, convey.ShouldNotContainKey, "source_id")
 	})
 }
 
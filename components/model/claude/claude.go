@@ -18,6 +18,7 @@ package claude
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -100,6 +101,8 @@ func NewChatModel(ctx context.Context, config *Config) (*ChatModel, error) {
 		stopSequences: config.StopSequences,
 		temperature:   config.Temperature,
 		thinking:      config.Thinking,
+		cacheControl:  config.CacheControl,
+		citations:     config.EnableCitations,
 		topK:          config.TopK,
 		topP:          config.TopP,
 	}, nil
@@ -177,6 +180,17 @@ type Config struct {
 
 	Thinking *Thinking
 
+	// CacheControl marks the bound tools and system prompt as prompt cache breakpoints
+	// It can be overridden by WithCacheControl
+	// Optional. Use SetMessageBreakpoint to mark breakpoints on messages
+	CacheControl *CacheControl
+
+	// EnableCitations enables citations of the documents given by ChatMessagePartTypeFileURL parts
+	// The citations of the response can be obtained by GetCitations
+	// It can be overridden by WithCitations
+	// Optional. Default: false
+	EnableCitations bool
+
 	// HTTPClient specifies the client to send HTTP requests.
 	HTTPClient *http.Client `json:"http_client"`
 }
@@ -186,6 +200,15 @@ type Thinking struct {
 	BudgetTokens int  `json:"budget_tokens"`
 }
 
+// CacheControl specifies which parts of the request prefix are marked as prompt cache breakpoints.
+// Claude caches the prefix in the order of tools, system and messages, and allows at most 4 breakpoints per request.
+type CacheControl struct {
+	// Tools marks the last tool definition as a breakpoint, caching all the tool definitions.
+	Tools bool `json:"tools"`
+	// System marks the last system prompt block as a breakpoint, caching the tools and the system prompt.
+	System bool `json:"system"`
+}
+
 type ChatModel struct {
 	cli anthropic.Client
 
@@ -196,6 +219,8 @@ type ChatModel struct {
 	topK          *int32
 	topP          *float32
 	thinking      *Thinking
+	cacheControl  *CacheControl
+	citations     bool
 	tools         []anthropic.ToolUnionParam
 	origTools     []*schema.ToolInfo
 	toolChoice    *schema.ToolChoice
@@ -406,8 +431,10 @@ func (cm *ChatModel) genMessageNewParams(input []*schema.Message, opts ...model.
 		ToolChoice:  cm.toolChoice,
 	}, opts...)
 	claudeOptions := model.GetImplSpecificOptions(&options{
-		TopK:     cm.topK,
-		Thinking: cm.thinking}, opts...)
+		TopK:            cm.topK,
+		Thinking:        cm.thinking,
+		CacheControl:    cm.cacheControl,
+		EnableCitations: &cm.citations}, opts...)
 
 	params := anthropic.MessageNewParams{}
 	if commonOptions.Model != nil {
@@ -446,7 +473,15 @@ func (cm *ChatModel) genMessageNewParams(input []*schema.Message, opts ...model.
 		}
 	}
 
+	cacheControl := claudeOptions.CacheControl
+	if cacheControl == nil {
+		cacheControl = &CacheControl{}
+	}
+
 	if len(tools) > 0 {
+		if cacheControl.Tools {
+			tools = setToolsBreakpoint(tools)
+		}
 		params.Tools = tools
 	}
 
@@ -476,28 +511,88 @@ func (cm *ChatModel) genMessageNewParams(input []*schema.Message, opts ...model.
 	// Convert messages
 	var systemTextBlocks []anthropic.TextBlockParam
 	for len(input) > 1 && input[0].Role == schema.System {
-		systemTextBlocks = append(systemTextBlocks, anthropic.TextBlockParam{
+		block := anthropic.TextBlockParam{
 			Text: input[0].Content,
-		})
+		}
+		if isBreakpoint(input[0]) {
+			block.CacheControl = anthropic.NewCacheControlEphemeralParam()
+		}
+		systemTextBlocks = append(systemTextBlocks, block)
 		input = input[1:]
 	}
 	if len(systemTextBlocks) > 0 {
+		if cacheControl.System {
+			systemTextBlocks[len(systemTextBlocks)-1].CacheControl = anthropic.NewCacheControlEphemeralParam()
+		}
 		params.System = systemTextBlocks
 	}
 
 	messages := make([]anthropic.MessageParam, 0, len(input))
 	for _, msg := range input {
-		message, err := convSchemaMessage(msg)
+		message, err := convSchemaMessage(msg, fromOrDefault(claudeOptions.EnableCitations, false))
 		if err != nil {
 			return anthropic.MessageNewParams{}, fmt.Errorf("convert schema message fail: %w", err)
 		}
+		if isBreakpoint(msg) && len(message.Content) > 0 {
+			if cc := message.Content[len(message.Content)-1].GetCacheControl(); cc != nil {
+				*cc = anthropic.NewCacheControlEphemeralParam()
+			}
+		}
 		messages = append(messages, message)
 	}
 	params.Messages = messages
 
+	if n := countBreakpoints(params); n > maxCacheBreakpoints {
+		return anthropic.MessageNewParams{}, fmt.Errorf("too many cache breakpoints: %d, at most %d are allowed", n, maxCacheBreakpoints)
+	}
+
 	return params, nil
 }
 
+const maxCacheBreakpoints = 4
+
+// setToolsBreakpoint marks the last tool as a cache breakpoint.
+// The tools are copied because the bound tools are shared between requests.
+func setToolsBreakpoint(tools []anthropic.ToolUnionParam) []anthropic.ToolUnionParam {
+	result := make([]anthropic.ToolUnionParam, len(tools))
+	copy(result, tools)
+
+	last := result[len(result)-1]
+	if last.OfTool == nil {
+		return result
+	}
+	t := *last.OfTool
+	t.CacheControl = anthropic.NewCacheControlEphemeralParam()
+	result[len(result)-1] = anthropic.ToolUnionParam{OfTool: &t}
+	return result
+}
+
+func countBreakpoints(params anthropic.MessageNewParams) int {
+	isSet := func(cc *anthropic.CacheControlEphemeralParam) bool {
+		return cc != nil && cc.Type != ""
+	}
+
+	count := 0
+	for i := range params.Tools {
+		if isSet(params.Tools[i].GetCacheControl()) {
+			count++
+		}
+	}
+	for i := range params.System {
+		if isSet(&params.System[i].CacheControl) {
+			count++
+		}
+	}
+	for _, msg := range params.Messages {
+		for i := range msg.Content {
+			if isSet(msg.Content[i].GetCacheControl()) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 func (cm *ChatModel) getCallbackInput(input []*schema.Message, opts ...model.Option) *model.CallbackInput {
 	result := &model.CallbackInput{
 		Messages: input,
@@ -521,6 +616,11 @@ func (cm *ChatModel) getCallbackOutput(output *schema.Message) *model.CallbackOu
 			TotalTokens:      output.ResponseMeta.Usage.TotalTokens,
 		}
 	}
+	if cacheUsage, ok := GetCacheUsage(output); ok {
+		result.Extra = map[string]any{
+			keyOfCacheUsage: cacheUsage,
+		}
+	}
 	return result
 }
 
@@ -547,7 +647,7 @@ func (cm *ChatModel) IsCallbacksEnabled() bool {
 	return true
 }
 
-func convSchemaMessage(message *schema.Message) (mp anthropic.MessageParam, err error) {
+func convSchemaMessage(message *schema.Message, enableCitations bool) (mp anthropic.MessageParam, err error) {
 
 	var messageParams []anthropic.ContentBlockParamUnion
 	if len(message.Content) > 0 {
@@ -570,6 +670,15 @@ func convSchemaMessage(message *schema.Message) (mp anthropic.MessageParam, err
 					return mp, fmt.Errorf("extract base64 image fail: %w", err_)
 				}
 				messageParams = append(messageParams, anthropic.NewImageBlockBase64(mediaType, data))
+			case schema.ChatMessagePartTypeFileURL:
+				if message.MultiContent[i].FileURL == nil {
+					continue
+				}
+				document, err_ := convDocumentBlock(message.MultiContent[i].FileURL, enableCitations)
+				if err_ != nil {
+					return mp, fmt.Errorf("convert document fail: %w", err_)
+				}
+				messageParams = append(messageParams, document)
 			default:
 				return mp, fmt.Errorf("anthropic message type not supported: %s", message.MultiContent[i].Type)
 			}
@@ -594,18 +703,68 @@ func convSchemaMessage(message *schema.Message) (mp anthropic.MessageParam, err
 	return mp, nil
 }
 
+// convDocumentBlock converts a file part to an anthropic document block.
+// PDF can be given by base64 data url or http url, plain text can only be given by base64 data url.
+func convDocumentBlock(file *schema.ChatMessageFileURL, enableCitations bool) (anthropic.ContentBlockParamUnion, error) {
+	document := anthropic.DocumentBlockParam{}
+
+	switch {
+	case strings.HasPrefix(file.URL, "data:"):
+		mediaType, data, err := convImageBase64(file.URL)
+		if err != nil {
+			return anthropic.ContentBlockParamUnion{}, err
+		}
+		switch mediaType {
+		case "application/pdf":
+			document.Source.OfBase64 = &anthropic.Base64PDFSourceParam{Data: data}
+		case "text/plain":
+			text, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return anthropic.ContentBlockParamUnion{}, fmt.Errorf("decode base64 text document fail: %w", err)
+			}
+			document.Source.OfText = &anthropic.PlainTextSourceParam{Data: string(text)}
+		default:
+			return anthropic.ContentBlockParamUnion{}, fmt.Errorf("document media type not supported: %s", mediaType)
+		}
+	case strings.HasPrefix(file.URL, "http://") || strings.HasPrefix(file.URL, "https://"):
+		if file.MIMEType != "" && file.MIMEType != "application/pdf" {
+			return anthropic.ContentBlockParamUnion{}, fmt.Errorf("only pdf document is supported by url, but got: %s", file.MIMEType)
+		}
+		document.Source.OfURL = &anthropic.URLPDFSourceParam{URL: file.URL}
+	default:
+		return anthropic.ContentBlockParamUnion{}, fmt.Errorf("invalid document url: %s", file.URL)
+	}
+
+	if file.Name != "" {
+		document.Title = param.NewOpt(file.Name)
+	}
+	if enableCitations {
+		document.Citations = anthropic.CitationsConfigParam{Enabled: param.NewOpt(true)}
+	}
+
+	return anthropic.ContentBlockParamUnion{OfDocument: &document}, nil
+}
+
 func convOutputMessage(resp *anthropic.Message) (*schema.Message, error) {
+	// input_tokens of claude excludes the tokens written to or read from the cache
+	promptTokens := resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens
 	message := &schema.Message{
 		Role: schema.Assistant,
 		ResponseMeta: &schema.ResponseMeta{
 			FinishReason: string(resp.StopReason),
 			Usage: &schema.TokenUsage{
-				PromptTokens:     int(resp.Usage.InputTokens),
+				PromptTokens:     int(promptTokens),
 				CompletionTokens: int(resp.Usage.OutputTokens),
-				TotalTokens:      int(resp.Usage.InputTokens + resp.Usage.OutputTokens),
+				TotalTokens:      int(promptTokens + resp.Usage.OutputTokens),
 			},
 		},
 	}
+	if resp.Usage.CacheCreationInputTokens > 0 || resp.Usage.CacheReadInputTokens > 0 {
+		setCacheUsage(message, &CacheUsage{
+			CacheCreationInputTokens: int(resp.Usage.CacheCreationInputTokens),
+			CacheReadInputTokens:     int(resp.Usage.CacheReadInputTokens),
+		})
+	}
 
 	streamCtx := &streamContext{}
 	for _, item := range resp.Content {
@@ -633,6 +792,9 @@ func convContentBlockToEinoMsg(
 	switch block := contentBlock.(type) {
 	case anthropic.TextBlock:
 		dstMsg.Content += block.Text
+		for _, citation := range block.Citations {
+			appendCitations(dstMsg, convTextCitation(citation))
+		}
 	case anthropic.ToolUseBlock:
 		dstMsg.ToolCalls = append(dstMsg.ToolCalls,
 			toolEvent(true, block.ID, block.Name, block.Input, streamCtx))
@@ -673,6 +835,12 @@ func convStreamEvent(event anthropic.MessageStreamEventUnion, streamCtx *streamC
 				CompletionTokens: int(e.Usage.OutputTokens),
 			},
 		}
+		if e.Usage.CacheCreationInputTokens > 0 || e.Usage.CacheReadInputTokens > 0 {
+			setCacheUsage(result, &CacheUsage{
+				CacheCreationInputTokens: int(e.Usage.CacheCreationInputTokens),
+				CacheReadInputTokens:     int(e.Usage.CacheReadInputTokens),
+			})
+		}
 		return result, nil
 
 	case anthropic.MessageStopEvent, anthropic.ContentBlockStopEvent:
@@ -705,6 +873,8 @@ func convStreamEvent(event anthropic.MessageStreamEventUnion, streamCtx *streamC
 		case anthropic.InputJSONDelta:
 			result.ToolCalls = append(result.ToolCalls,
 				toolEvent(false, "", "", delta.PartialJSON, streamCtx))
+		case anthropic.CitationsDelta:
+			appendCitations(result, convDeltaCitation(delta.Citation))
 		case anthropic.SignatureDelta:
 		}
 
@@ -715,6 +885,36 @@ func convStreamEvent(event anthropic.MessageStreamEventUnion, streamCtx *streamC
 	}
 }
 
+func convTextCitation(c anthropic.TextCitationUnion) *Citation {
+	return &Citation{
+		Type:            c.Type,
+		CitedText:       c.CitedText,
+		DocumentIndex:   int(c.DocumentIndex),
+		DocumentTitle:   c.DocumentTitle,
+		StartCharIndex:  int(c.StartCharIndex),
+		EndCharIndex:    int(c.EndCharIndex),
+		StartPageNumber: int(c.StartPageNumber),
+		EndPageNumber:   int(c.EndPageNumber),
+		StartBlockIndex: int(c.StartBlockIndex),
+		EndBlockIndex:   int(c.EndBlockIndex),
+	}
+}
+
+func convDeltaCitation(c anthropic.CitationsDeltaCitationUnion) *Citation {
+	return &Citation{
+		Type:            c.Type,
+		CitedText:       c.CitedText,
+		DocumentIndex:   int(c.DocumentIndex),
+		DocumentTitle:   c.DocumentTitle,
+		StartCharIndex:  int(c.StartCharIndex),
+		EndCharIndex:    int(c.EndCharIndex),
+		StartPageNumber: int(c.StartPageNumber),
+		EndPageNumber:   int(c.EndPageNumber),
+		StartBlockIndex: int(c.StartBlockIndex),
+		EndBlockIndex:   int(c.EndBlockIndex),
+	}
+}
+
 func convImageBase64(data string) (string, string, error) {
 	if !strings.HasPrefix(data, "data:") {
 		return "", "", fmt.Errorf("invalid base64 image: %s", data)
@@ -738,7 +938,8 @@ func convImageBase64(data string) (string, string, error) {
 
 func isMessageEmpty(message *schema.Message) bool {
 	_, ok := GetThinking(message)
-	if len(message.Content) == 0 && len(message.ToolCalls) == 0 && len(message.MultiContent) == 0 && !ok {
+	_, hasCitations := GetCitations(message)
+	if len(message.Content) == 0 && len(message.ToolCalls) == 0 && len(message.MultiContent) == 0 && !ok && !hasCitations {
 		return true
 	}
 	return false
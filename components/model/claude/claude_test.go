@@ -288,3 +288,166 @@ func TestWithTools(t *testing.T) {
 	assert.Equal(t, "test model", ncm.(*ChatModel).model)
 	assert.Equal(t, "test tool name", ncm.(*ChatModel).origTools[0].Name)
 }
+
+func TestCacheControl(t *testing.T) {
+	cm := &ChatModel{model: "test model", maxTokens: 100}
+	err := cm.BindTools([]*schema.ToolInfo{{Name: "tool1"}, {Name: "tool2"}})
+	assert.NoError(t, err)
+
+	input := []*schema.Message{
+		schema.SystemMessage("you are a helpful assistant"),
+		SetMessageBreakpoint(schema.UserMessage("long document")),
+		schema.AssistantMessage("ok", nil),
+		schema.UserMessage("question"),
+	}
+
+	t.Run("no cache control", func(t *testing.T) {
+		params, err := cm.genMessageNewParams(input)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, countBreakpoints(params))
+		assert.Equal(t, "ephemeral", string(params.Messages[0].Content[0].GetCacheControl().Type))
+		assert.Equal(t, "", string(params.Tools[1].GetCacheControl().Type))
+		assert.Equal(t, "", string(params.System[0].CacheControl.Type))
+	})
+
+	t.Run("with cache control", func(t *testing.T) {
+		params, err := cm.genMessageNewParams(input, WithCacheControl(&CacheControl{Tools: true, System: true}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, countBreakpoints(params))
+		assert.Equal(t, "", string(params.Tools[0].GetCacheControl().Type))
+		assert.Equal(t, "ephemeral", string(params.Tools[1].GetCacheControl().Type))
+		assert.Equal(t, "ephemeral", string(params.System[0].CacheControl.Type))
+		// bound tools should not be modified
+		assert.Equal(t, "", string(cm.tools[1].GetCacheControl().Type))
+	})
+
+	t.Run("too many breakpoints", func(t *testing.T) {
+		msgs := []*schema.Message{
+			SetMessageBreakpoint(schema.SystemMessage("system")),
+			SetMessageBreakpoint(schema.UserMessage("1")),
+			SetMessageBreakpoint(schema.AssistantMessage("2", nil)),
+			SetMessageBreakpoint(schema.UserMessage("3")),
+		}
+		_, err := cm.genMessageNewParams(msgs, WithCacheControl(&CacheControl{Tools: true}))
+		assert.Error(t, err)
+	})
+}
+
+func TestCacheUsage(t *testing.T) {
+	msg, err := convOutputMessage(&anthropic.Message{
+		Usage: anthropic.Usage{
+			InputTokens:              10,
+			OutputTokens:             5,
+			CacheCreationInputTokens: 100,
+			CacheReadInputTokens:     200,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 310, msg.ResponseMeta.Usage.PromptTokens)
+	assert.Equal(t, 315, msg.ResponseMeta.Usage.TotalTokens)
+
+	usage, ok := GetCacheUsage(msg)
+	assert.True(t, ok)
+	assert.Equal(t, &CacheUsage{CacheCreationInputTokens: 100, CacheReadInputTokens: 200}, usage)
+
+	cbOutput := (&ChatModel{}).getCallbackOutput(msg)
+	assert.Equal(t, usage, cbOutput.Extra[keyOfCacheUsage])
+}
+
+func TestConvDocumentBlock(t *testing.T) {
+	t.Run("base64 pdf", func(t *testing.T) {
+		block, err := convDocumentBlock(&schema.ChatMessageFileURL{
+			URL:  "data:application/pdf;base64,JVBERi0xLjQK",
+			Name: "report.pdf",
+		}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "JVBERi0xLjQK", block.OfDocument.Source.OfBase64.Data)
+		assert.Equal(t, "report.pdf", block.OfDocument.Title.Value)
+		assert.True(t, block.OfDocument.Citations.Enabled.Value)
+	})
+
+	t.Run("base64 plain text", func(t *testing.T) {
+		block, err := convDocumentBlock(&schema.ChatMessageFileURL{
+			URL: "data:text/plain;base64,aGVsbG8gd29ybGQ=",
+		}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", block.OfDocument.Source.OfText.Data)
+		assert.False(t, block.OfDocument.Citations.Enabled.Valid())
+	})
+
+	t.Run("pdf url", func(t *testing.T) {
+		block, err := convDocumentBlock(&schema.ChatMessageFileURL{
+			URL: "https://example.com/report.pdf",
+		}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/report.pdf", block.OfDocument.Source.OfURL.URL)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := convDocumentBlock(&schema.ChatMessageFileURL{
+			URL: "data:application/msword;base64,aGVsbG8=",
+		}, false)
+		assert.Error(t, err)
+
+		_, err = convDocumentBlock(&schema.ChatMessageFileURL{
+			URL:      "https://example.com/report.docx",
+			MIMEType: "application/msword",
+		}, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestCitations(t *testing.T) {
+	msg := &schema.Message{}
+	err := convContentBlockToEinoMsg(anthropic.TextBlock{
+		Text: "the grass is green",
+		Citations: []anthropic.TextCitationUnion{
+			{
+				Type:           "char_location",
+				CitedText:      "The grass is green.",
+				DocumentIndex:  0,
+				DocumentTitle:  "doc",
+				StartCharIndex: 0,
+				EndCharIndex:   20,
+			},
+		},
+	}, msg, &streamContext{})
+	assert.NoError(t, err)
+
+	citations, ok := GetCitations(msg)
+	assert.True(t, ok)
+	assert.Equal(t, []*Citation{{
+		Type:          "char_location",
+		CitedText:     "The grass is green.",
+		DocumentTitle: "doc",
+		EndCharIndex:  20,
+	}}, citations)
+
+	mockey.PatchConvey("citations delta", t, func() {
+		event := anthropic.MessageStreamEventUnion{}
+		defer mockey.Mock(anthropic.RawContentBlockDeltaUnion.AsAny).Return(anthropic.CitationsDelta{
+			Citation: anthropic.CitationsDeltaCitationUnion{
+				Type:            "page_location",
+				CitedText:       "The sky is blue.",
+				DocumentIndex:   1,
+				StartPageNumber: 1,
+				EndPageNumber:   2,
+			},
+		}).Build().UnPatch()
+		defer mockey.Mock(anthropic.MessageStreamEventUnion.AsAny).Return(anthropic.ContentBlockDeltaEvent{}).Build().UnPatch()
+
+		message, err := convStreamEvent(event, &streamContext{})
+		assert.NoError(t, err)
+		citations, ok := GetCitations(message)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(citations))
+		assert.Equal(t, "page_location", citations[0].Type)
+		assert.Equal(t, 2, citations[0].EndPageNumber)
+
+		concated, err := schema.ConcatMessages([]*schema.Message{msg, message})
+		assert.NoError(t, err)
+		citations, ok = GetCitations(concated)
+		assert.True(t, ok)
+		assert.Equal(t, 2, len(citations))
+	})
+}
@@ -17,13 +17,78 @@
 package claude
 
 import (
+	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 )
 
 const (
-	keyOfThinking = "_eino_claude_thinking"
+	keyOfThinking   = "_eino_claude_thinking"
+	keyOfBreakpoint = "_eino_claude_breakpoint"
+	keyOfCacheUsage = "_eino_claude_cache_usage"
+	keyOfCitations  = "_eino_claude_citations"
 )
 
+// CacheUsage describes the prompt caching token usage of a response.
+type CacheUsage struct {
+	// CacheCreationInputTokens is the number of input tokens written to the cache.
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	// CacheReadInputTokens is the number of input tokens read from the cache.
+	CacheReadInputTokens int `json:"cache_read_input_tokens"`
+}
+
+// Citation is a reference to the part of a document that supports the response text.
+// Citing a PDF results in page location, plain text results in char location.
+type Citation struct {
+	// Type is one of "char_location", "page_location" and "content_block_location".
+	Type string `json:"type"`
+	// CitedText is the text cited from the document.
+	CitedText string `json:"cited_text"`
+	// DocumentIndex is the index of the cited document in the request, starting from 0.
+	DocumentIndex int `json:"document_index"`
+	// DocumentTitle is the title of the cited document.
+	DocumentTitle string `json:"document_title,omitempty"`
+
+	// StartCharIndex and EndCharIndex are available for char location, EndCharIndex is exclusive.
+	StartCharIndex int `json:"start_char_index,omitempty"`
+	EndCharIndex   int `json:"end_char_index,omitempty"`
+
+	// StartPageNumber and EndPageNumber are available for page location, EndPageNumber is exclusive.
+	StartPageNumber int `json:"start_page_number,omitempty"`
+	EndPageNumber   int `json:"end_page_number,omitempty"`
+
+	// StartBlockIndex and EndBlockIndex are available for content block location, EndBlockIndex is exclusive.
+	StartBlockIndex int `json:"start_block_index,omitempty"`
+	EndBlockIndex   int `json:"end_block_index,omitempty"`
+}
+
+func init() {
+	compose.RegisterStreamChunkConcatFunc(func(chunks [][]*Citation) (final []*Citation, err error) {
+		for _, chunk := range chunks {
+			final = append(final, chunk...)
+		}
+		return final, nil
+	})
+	_ = compose.RegisterSerializableType[Citation]("_eino_ext_claude_citation")
+
+	compose.RegisterStreamChunkConcatFunc(func(chunks []*CacheUsage) (final *CacheUsage, err error) {
+		final = &CacheUsage{}
+		for _, chunk := range chunks {
+			if chunk == nil {
+				continue
+			}
+			// usage in stream chunks is cumulative, keep the largest value
+			if chunk.CacheCreationInputTokens > final.CacheCreationInputTokens {
+				final.CacheCreationInputTokens = chunk.CacheCreationInputTokens
+			}
+			if chunk.CacheReadInputTokens > final.CacheReadInputTokens {
+				final.CacheReadInputTokens = chunk.CacheReadInputTokens
+			}
+		}
+		return final, nil
+	})
+	_ = compose.RegisterSerializableType[CacheUsage]("_eino_ext_claude_cache_usage")
+}
+
 func GetThinking(msg *schema.Message) (string, bool) {
 	if msg == nil {
 		return "", false
@@ -45,3 +110,76 @@ func setThinking(msg *schema.Message, reasoningContent string) {
 	}
 	msg.Extra[keyOfThinking] = reasoningContent
 }
+
+// SetMessageBreakpoint returns a copy of msg marked as a prompt cache breakpoint.
+// The last content block of the message will carry cache_control, so that the whole
+// request prefix up to and including this message can be cached by Claude.
+// See https://docs.anthropic.com/en/docs/build-with-claude/prompt-caching
+func SetMessageBreakpoint(msg *schema.Message) *schema.Message {
+	if msg == nil {
+		return nil
+	}
+	msg_ := *msg
+	extra := make(map[string]any, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		extra[k] = v
+	}
+	extra[keyOfBreakpoint] = true
+	msg_.Extra = extra
+	return &msg_
+}
+
+func isBreakpoint(msg *schema.Message) bool {
+	if msg == nil {
+		return false
+	}
+	ok, _ := msg.Extra[keyOfBreakpoint].(bool)
+	return ok
+}
+
+// GetCacheUsage returns the prompt caching token usage of the response message.
+// The returned tokens are also included in ResponseMeta.Usage.PromptTokens.
+func GetCacheUsage(msg *schema.Message) (*CacheUsage, bool) {
+	if msg == nil {
+		return nil, false
+	}
+	usage, ok := msg.Extra[keyOfCacheUsage].(*CacheUsage)
+	if !ok || usage == nil {
+		return nil, false
+	}
+	return usage, true
+}
+
+func setCacheUsage(msg *schema.Message, usage *CacheUsage) {
+	if msg == nil {
+		return
+	}
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]interface{})
+	}
+	msg.Extra[keyOfCacheUsage] = usage
+}
+
+// GetCitations returns the citations of the response message.
+// Citations are only returned when citations are enabled for the documents in the request, see WithCitations.
+func GetCitations(msg *schema.Message) ([]*Citation, bool) {
+	if msg == nil {
+		return nil, false
+	}
+	citations, ok := msg.Extra[keyOfCitations].([]*Citation)
+	if !ok {
+		return nil, false
+	}
+	return citations, true
+}
+
+func appendCitations(msg *schema.Message, citations ...*Citation) {
+	if msg == nil || len(citations) == 0 {
+		return
+	}
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]interface{})
+	}
+	existing, _ := msg.Extra[keyOfCitations].([]*Citation)
+	msg.Extra[keyOfCitations] = append(existing, citations...)
+}
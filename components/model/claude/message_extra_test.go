@@ -50,3 +50,22 @@ func TestConcatMessages(t *testing.T) {
 	assert.Equal(t, true, ok)
 	assert.Equal(t, "how are you", reasoningContent)
 }
+
+func TestSetMessageBreakpoint(t *testing.T) {
+	msg := schema.UserMessage("hello")
+	msg.Extra = map[string]any{"key": "value"}
+
+	bp := SetMessageBreakpoint(msg)
+	assert.True(t, isBreakpoint(bp))
+	assert.Equal(t, "value", bp.Extra["key"])
+	assert.False(t, isBreakpoint(msg))
+
+	concated, err := schema.ConcatMessages([]*schema.Message{
+		{Role: schema.Assistant, Extra: map[string]any{keyOfCacheUsage: &CacheUsage{CacheReadInputTokens: 10}}},
+		{Role: schema.Assistant, Extra: map[string]any{keyOfCacheUsage: &CacheUsage{CacheReadInputTokens: 10, CacheCreationInputTokens: 5}}},
+	})
+	assert.NoError(t, err)
+	usage, ok := GetCacheUsage(concated)
+	assert.True(t, ok)
+	assert.Equal(t, &CacheUsage{CacheReadInputTokens: 10, CacheCreationInputTokens: 5}, usage)
+}
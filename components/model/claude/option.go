@@ -24,6 +24,10 @@ type options struct {
 	TopK *int32
 
 	Thinking *Thinking
+
+	CacheControl *CacheControl
+
+	EnableCitations *bool
 }
 
 func WithTopK(k int32) model.Option {
@@ -37,3 +41,19 @@ func WithThinking(t *Thinking) model.Option {
 		o.Thinking = t
 	})
 }
+
+// WithCacheControl sets the prompt cache breakpoints of the bound tools and system prompt for a single request,
+// it overrides Config.CacheControl.
+func WithCacheControl(cc *CacheControl) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.CacheControl = cc
+	})
+}
+
+// WithCitations enables or disables citations of the documents in MultiContent for a single request,
+// it overrides Config.EnableCitations.
+func WithCitations(enable bool) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.EnableCitations = &enable
+	})
+}
@@ -17,10 +17,17 @@
 package gemini
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -51,6 +58,10 @@ var _ model.ToolCallingChatModel = (*ChatModel)(nil)
 //	    Model: "gemini-pro",
 //	})
 func NewChatModel(_ context.Context, cfg *Config) (*ChatModel, error) {
+	maxInlineDataSize := cfg.MaxInlineDataSize
+	if maxInlineDataSize == 0 {
+		maxInlineDataSize = defaultMaxInlineDataSize
+	}
 	return &ChatModel{
 		cli: cfg.Client,
 
@@ -63,6 +74,7 @@ func NewChatModel(_ context.Context, cfg *Config) (*ChatModel, error) {
 		enableCodeExecution: cfg.EnableCodeExecution,
 		safetySettings:      cfg.SafetySettings,
 		thinkingConfig:      cfg.ThinkingConfig,
+		maxInlineDataSize:   maxInlineDataSize,
 	}, nil
 }
 
@@ -109,6 +121,13 @@ type Config struct {
 	SafetySettings []*genai.SafetySetting
 
 	ThinkingConfig *genai.ThinkingConfig
+
+	// MaxInlineDataSize is the max size in bytes of the media sent inline,
+	// which is given by base64 data url or local file url(file://) in MultiContent
+	// Larger media is uploaded by the Files API(only available for the Gemini Developer API) and referenced by its uri
+	// Optional. Default: 20MB, and a negative value disables the uploading,
+	// in which case media larger than the 20MB inline data limit of the API is rejected with an error
+	MaxInlineDataSize int
 }
 
 type ChatModel struct {
@@ -126,13 +145,17 @@ type ChatModel struct {
 	enableCodeExecution bool
 	safetySettings      []*genai.SafetySetting
 	thinkingConfig      *genai.ThinkingConfig
+	maxInlineDataSize   int
 }
 
 func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (message *schema.Message, err error) {
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
-	modelName, nInput, genaiConf, cbConf, err := cm.genInputAndConf(input, opts...)
+	modelName, nInput, genaiConf, cbConf, err := cm.genInputAndConf(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
 		Messages: input,
@@ -148,7 +171,7 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 	if len(input) == 0 {
 		return nil, fmt.Errorf("gemini input is empty")
 	}
-	contents, err := cm.convSchemaMessages(nInput)
+	contents, err := cm.convSchemaMessages(ctx, nInput)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +194,7 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
-	modelName, nInput, genaiConf, cbConf, err := cm.genInputAndConf(input, opts...)
+	modelName, nInput, genaiConf, cbConf, err := cm.genInputAndConf(ctx, input, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +213,7 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 		return nil, fmt.Errorf("gemini input is empty")
 	}
 
-	contents, err := cm.convSchemaMessages(nInput)
+	contents, err := cm.convSchemaMessages(ctx, nInput)
 	if err != nil {
 		return nil, fmt.Errorf("convert schema message fail: %w", err)
 	}
@@ -278,7 +301,76 @@ func (cm *ChatModel) BindForcedTools(tools []*schema.ToolInfo) error {
 	return nil
 }
 
-func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Option) (string, []*schema.Message, *genai.GenerateContentConfig, *model.Config, error) {
+// CacheInfo is the information of the cached content created by CreateCachedContent.
+type CacheInfo struct {
+	// Name is the resource name of the cached content, which can be used with WithCachedContent option
+	Name string
+	// ExpireTime is the time when the cached content is deleted by the server
+	ExpireTime time.Time
+	// Usage specifies the token usage of the cached content
+	Usage schema.TokenUsage
+}
+
+// CreateCachedContent creates a cached content on the server side from the prefix messages and the bound tools,
+// which can be referenced by WithCachedContent in subsequent calls instead of resending them.
+//
+// Parameters:
+//   - ctx: The context for the request
+//   - prefix: Messages to be cached, the leading system message is cached as the system instruction
+//   - ttl: Time-to-live of the cached content, default: 1 hour
+//
+// Returns:
+//   - info: Information about the created cached content, including the name and token usage
+//   - err: Any error encountered during the operation
+//
+// ref: https://ai.google.dev/gemini-api/docs/caching
+//
+// Note that the system instruction and tools of the cached content can not be changed by the requests referencing it,
+// so the tools are not sent with WithCachedContent, and the requests with a leading system message fail.
+func (cm *ChatModel) CreateCachedContent(ctx context.Context, prefix []*schema.Message, ttl time.Duration) (info *CacheInfo, err error) {
+	if len(prefix) == 0 {
+		return nil, fmt.Errorf("create cached content fail: prefix is empty")
+	}
+
+	conf := &genai.CreateCachedContentConfig{
+		TTL:   ttl,
+		Tools: cm.genTools(cm.tools),
+	}
+	conf.ToolConfig, err = genToolConfig(cm.toolChoice, len(conf.Tools) > 0)
+	if err != nil {
+		return nil, fmt.Errorf("create cached content fail: %w", err)
+	}
+	if prefix[0].Role == schema.System {
+		conf.SystemInstruction, err = cm.convSchemaMessage(ctx, prefix[0])
+		if err != nil {
+			return nil, fmt.Errorf("create cached content fail, convert system instruction fail: %w", err)
+		}
+		prefix = prefix[1:]
+	}
+	conf.Contents, err = cm.convSchemaMessages(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("create cached content fail: %w", err)
+	}
+
+	cached, err := cm.cli.Caches.Create(ctx, cm.model, conf)
+	if err != nil {
+		return nil, fmt.Errorf("create cached content fail: %w", err)
+	}
+
+	info = &CacheInfo{
+		Name:       cached.Name,
+		ExpireTime: cached.ExpireTime,
+	}
+	if cached.UsageMetadata != nil {
+		info.Usage = schema.TokenUsage{
+			PromptTokens: int(cached.UsageMetadata.TotalTokenCount),
+			TotalTokens:  int(cached.UsageMetadata.TotalTokenCount),
+		}
+	}
+	return info, nil
+}
+
+func (cm *ChatModel) genInputAndConf(ctx context.Context, input []*schema.Message, opts ...model.Option) (string, []*schema.Message, *genai.GenerateContentConfig, *model.Config, error) {
 	commonOptions := model.GetCommonOptions(&model.Options{
 		Temperature: cm.temperature,
 		MaxTokens:   cm.maxTokens,
@@ -300,28 +392,27 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 	}
 	m.SafetySettings = cm.safetySettings
 
-	tools := cm.tools
-	if commonOptions.Tools != nil {
+	if geminiOptions.CachedContent != "" {
+		// tools and tool config are part of the cached content, and can not be set in the request
+		m.CachedContent = geminiOptions.CachedContent
+	} else {
+		tools := cm.tools
+		if commonOptions.Tools != nil {
+			var err error
+			tools, err = cm.toGeminiTools(commonOptions.Tools)
+			if err != nil {
+				return "", nil, nil, nil, err
+			}
+		}
+
+		m.Tools = cm.genTools(tools)
 		var err error
-		tools, err = cm.toGeminiTools(commonOptions.Tools)
+		m.ToolConfig, err = genToolConfig(commonOptions.ToolChoice, len(m.Tools) > 0)
 		if err != nil {
 			return "", nil, nil, nil, err
 		}
 	}
 
-	if len(tools) > 0 {
-		t := &genai.Tool{
-			FunctionDeclarations: make([]*genai.FunctionDeclaration, len(tools)),
-		}
-		copy(t.FunctionDeclarations, tools)
-		m.Tools = append(m.Tools, t)
-	}
-	if cm.enableCodeExecution {
-		m.Tools = append(m.Tools, &genai.Tool{
-			CodeExecution: &genai.ToolCodeExecution{},
-		})
-	}
-
 	if commonOptions.MaxTokens != nil {
 		conf.MaxTokens = *commonOptions.MaxTokens
 		m.MaxOutputTokens = int32(*commonOptions.MaxTokens)
@@ -334,29 +425,6 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 		conf.Temperature = *commonOptions.Temperature
 		m.Temperature = commonOptions.Temperature
 	}
-	if commonOptions.ToolChoice != nil {
-		switch *commonOptions.ToolChoice {
-		case schema.ToolChoiceForbidden:
-			m.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
-				Mode: genai.FunctionCallingConfigModeNone,
-			}}
-		case schema.ToolChoiceAllowed:
-			m.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
-				Mode: genai.FunctionCallingConfigModeAuto,
-			}}
-		case schema.ToolChoiceForced:
-			// The predicted function call will be any one of the provided "functionDeclarations".
-			if len(m.Tools) == 0 {
-				return "", nil, nil, nil, fmt.Errorf("tool choice is forced but tool is not provided")
-			} else {
-				m.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
-					Mode: genai.FunctionCallingConfigModeAny,
-				}}
-			}
-		default:
-			return "", nil, nil, nil, fmt.Errorf("tool choice=%s not support", *commonOptions.ToolChoice)
-		}
-	}
 	if geminiOptions.TopK != nil {
 		topK := float32(*geminiOptions.TopK)
 		m.TopK = &topK
@@ -372,9 +440,14 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 
 	nInput := make([]*schema.Message, len(input))
 	copy(nInput, input)
+	if len(input) > 0 && input[0].Role == schema.System && geminiOptions.CachedContent != "" {
+		// the system instruction is part of the cached content, and gemini rejects the request setting it again
+		return "", nil, nil, nil, fmt.Errorf("system message is not allowed with cached content, " +
+			"put it in the prefix of CreateCachedContent instead")
+	}
 	if len(input) > 1 && input[0].Role == schema.System {
 		var err error
-		m.SystemInstruction, err = cm.convSchemaMessage(input[0])
+		m.SystemInstruction, err = cm.convSchemaMessage(ctx, input[0])
 		if err != nil {
 			return "", nil, nil, nil, fmt.Errorf("failed to convert system instruction: %w", err)
 		}
@@ -385,6 +458,49 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 	return conf.Model, nInput, m, conf, nil
 }
 
+func (cm *ChatModel) genTools(tools []*genai.FunctionDeclaration) []*genai.Tool {
+	var result []*genai.Tool
+	if len(tools) > 0 {
+		t := &genai.Tool{
+			FunctionDeclarations: make([]*genai.FunctionDeclaration, len(tools)),
+		}
+		copy(t.FunctionDeclarations, tools)
+		result = append(result, t)
+	}
+	if cm.enableCodeExecution {
+		result = append(result, &genai.Tool{
+			CodeExecution: &genai.ToolCodeExecution{},
+		})
+	}
+	return result
+}
+
+func genToolConfig(toolChoice *schema.ToolChoice, hasTools bool) (*genai.ToolConfig, error) {
+	if toolChoice == nil {
+		return nil, nil
+	}
+	switch *toolChoice {
+	case schema.ToolChoiceForbidden:
+		return &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+			Mode: genai.FunctionCallingConfigModeNone,
+		}}, nil
+	case schema.ToolChoiceAllowed:
+		return &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+			Mode: genai.FunctionCallingConfigModeAuto,
+		}}, nil
+	case schema.ToolChoiceForced:
+		// The predicted function call will be any one of the provided "functionDeclarations".
+		if !hasTools {
+			return nil, fmt.Errorf("tool choice is forced but tool is not provided")
+		}
+		return &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+			Mode: genai.FunctionCallingConfigModeAny,
+		}}, nil
+	default:
+		return nil, fmt.Errorf("tool choice=%s not support", *toolChoice)
+	}
+}
+
 func (cm *ChatModel) toGeminiTools(tools []*schema.ToolInfo) ([]*genai.FunctionDeclaration, error) {
 	gTools := make([]*genai.FunctionDeclaration, len(tools))
 	for i, tool := range tools {
@@ -478,10 +594,10 @@ func (cm *ChatModel) convOpenSchema(schema *openapi3.Schema) (*genai.Schema, err
 	return result, nil
 }
 
-func (cm *ChatModel) convSchemaMessages(messages []*schema.Message) ([]*genai.Content, error) {
+func (cm *ChatModel) convSchemaMessages(ctx context.Context, messages []*schema.Message) ([]*genai.Content, error) {
 	result := make([]*genai.Content, len(messages))
 	for i, message := range messages {
-		content, err := cm.convSchemaMessage(message)
+		content, err := cm.convSchemaMessage(ctx, message)
 		if err != nil {
 			return nil, fmt.Errorf("convert schema message fail: %w", err)
 		}
@@ -490,7 +606,7 @@ func (cm *ChatModel) convSchemaMessages(messages []*schema.Message) ([]*genai.Co
 	return result, nil
 }
 
-func (cm *ChatModel) convSchemaMessage(message *schema.Message) (*genai.Content, error) {
+func (cm *ChatModel) convSchemaMessage(ctx context.Context, message *schema.Message) (*genai.Content, error) {
 	if message == nil {
 		return nil, nil
 	}
@@ -521,36 +637,154 @@ func (cm *ChatModel) convSchemaMessage(message *schema.Message) (*genai.Content,
 		if message.Content != "" {
 			content.Parts = append(content.Parts, genai.NewPartFromText(message.Content))
 		}
-		content.Parts = append(content.Parts, cm.convMedia(message.MultiContent)...)
+		parts, err := cm.convMedia(ctx, message.MultiContent)
+		if err != nil {
+			return nil, err
+		}
+		content.Parts = append(content.Parts, parts...)
 	}
 	return content, nil
 }
 
-func (cm *ChatModel) convMedia(contents []schema.ChatMessagePart) []*genai.Part {
+func (cm *ChatModel) convMedia(ctx context.Context, contents []schema.ChatMessagePart) ([]*genai.Part, error) {
 	result := make([]*genai.Part, 0, len(contents))
 	for _, content := range contents {
+		var uri, mimeType string
 		switch content.Type {
 		case schema.ChatMessagePartTypeText:
 			result = append(result, genai.NewPartFromText(content.Text))
+			continue
 		case schema.ChatMessagePartTypeImageURL:
-			if content.ImageURL != nil {
-				result = append(result, genai.NewPartFromURI(content.ImageURL.URI, content.ImageURL.MIMEType))
+			if content.ImageURL == nil {
+				continue
 			}
+			uri, mimeType = firstNonEmpty(content.ImageURL.URI, content.ImageURL.URL), content.ImageURL.MIMEType
 		case schema.ChatMessagePartTypeAudioURL:
-			if content.AudioURL != nil {
-				result = append(result, genai.NewPartFromURI(content.AudioURL.URI, content.AudioURL.MIMEType))
+			if content.AudioURL == nil {
+				continue
 			}
+			uri, mimeType = firstNonEmpty(content.AudioURL.URI, content.AudioURL.URL), content.AudioURL.MIMEType
 		case schema.ChatMessagePartTypeVideoURL:
-			if content.VideoURL != nil {
-				result = append(result, genai.NewPartFromURI(content.VideoURL.URI, content.VideoURL.MIMEType))
+			if content.VideoURL == nil {
+				continue
 			}
+			uri, mimeType = firstNonEmpty(content.VideoURL.URI, content.VideoURL.URL), content.VideoURL.MIMEType
 		case schema.ChatMessagePartTypeFileURL:
-			if content.FileURL != nil {
-				result = append(result, genai.NewPartFromURI(content.FileURL.URI, content.FileURL.MIMEType))
+			if content.FileURL == nil {
+				continue
 			}
+			uri, mimeType = firstNonEmpty(content.FileURL.URI, content.FileURL.URL), content.FileURL.MIMEType
+		default:
+			continue
 		}
+		part, err := cm.convMediaPart(ctx, uri, mimeType)
+		if err != nil {
+			return nil, fmt.Errorf("convert %s fail: %w", content.Type, err)
+		}
+		result = append(result, part)
 	}
-	return result
+	return result, nil
+}
+
+// convMediaPart converts the media given by uri to genai.Part.
+// The base64 data url and local file url(file://) are sent inline, or uploaded by the Files API if larger than
+// maxInlineDataSize, while the others such as http url, gcs uri and the uri of uploaded file are referenced directly.
+func (cm *ChatModel) convMediaPart(ctx context.Context, uri, mimeType string) (*genai.Part, error) {
+	var data []byte
+	switch {
+	case strings.HasPrefix(uri, "data:"):
+		dataMIMEType, b64, err := parseDataURL(uri)
+		if err != nil {
+			return nil, err
+		}
+		data, err = base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return nil, fmt.Errorf("decode base64 data fail: %w", err)
+		}
+		if mimeType == "" {
+			mimeType = dataMIMEType
+		}
+	case strings.HasPrefix(uri, "file://"):
+		path := strings.TrimPrefix(uri, "file://")
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read file fail: %w", err)
+		}
+		if mimeType == "" {
+			mimeType = mime.TypeByExtension(filepath.Ext(path))
+		}
+	default:
+		return genai.NewPartFromURI(uri, mimeType), nil
+	}
+
+	if cm.maxInlineDataSize < 0 {
+		if len(data) > defaultMaxInlineDataSize {
+			return nil, fmt.Errorf("media size %d exceeds the inline data limit %d, and uploading is disabled by negative MaxInlineDataSize",
+				len(data), defaultMaxInlineDataSize)
+		}
+		return genai.NewPartFromBytes(data, mimeType), nil
+	}
+	if len(data) <= cm.maxInlineDataSize {
+		return genai.NewPartFromBytes(data, mimeType), nil
+	}
+	file, err := cm.uploadFile(ctx, data, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	return genai.NewPartFromURI(file.URI, file.MIMEType), nil
+}
+
+// uploadFile uploads the data by the Files API, and waits until the file is ready to be referenced.
+func (cm *ChatModel) uploadFile(ctx context.Context, data []byte, mimeType string) (*genai.File, error) {
+	file, err := cm.cli.Files.Upload(ctx, bytes.NewReader(data), &genai.UploadFileConfig{MIMEType: mimeType})
+	if err != nil {
+		return nil, fmt.Errorf("upload file fail: %w", err)
+	}
+	// video files are processed asynchronously after uploading
+	for file.State == genai.FileStateProcessing {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(filePollInterval):
+		}
+		file, err = cm.cli.Files.Get(ctx, file.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("get file fail: %w", err)
+		}
+	}
+	if file.State == genai.FileStateFailed {
+		var msg string
+		if file.Error != nil {
+			msg = file.Error.Message
+		}
+		return nil, fmt.Errorf("process file %s fail: %s", file.Name, msg)
+	}
+	return file, nil
+}
+
+// parseDataURL parses the mime type and base64 data from a data url, such as "data:image/png;base64,iVBORw0...".
+func parseDataURL(dataURL string) (mimeType string, data string, err error) {
+	header, data, found := strings.Cut(strings.TrimPrefix(dataURL, "data:"), ",")
+	if !found {
+		return "", "", fmt.Errorf("invalid data url: %.32s", dataURL)
+	}
+	params := strings.Split(header, ";")
+	for _, param := range params[1:] {
+		if param == "base64" {
+			return params[0], data, nil
+		}
+	}
+	return "", "", fmt.Errorf("data url is not base64 encoded: %.32s", dataURL)
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, str := range s {
+		if str != "" {
+			return str
+		}
+	}
+	return ""
 }
 
 func (cm *ChatModel) convResponse(resp *genai.GenerateContentResponse) (*schema.Message, error) {
@@ -572,6 +806,9 @@ func (cm *ChatModel) convResponse(resp *genai.GenerateContentResponse) (*schema.
 			CompletionTokens: int(resp.UsageMetadata.CandidatesTokenCount),
 			TotalTokens:      int(resp.UsageMetadata.TotalTokenCount),
 		}
+		if resp.UsageMetadata.CachedContentTokenCount > 0 {
+			setCachedTokens(message, int(resp.UsageMetadata.CachedContentTokenCount))
+		}
 	}
 	return message, nil
 }
@@ -668,7 +905,13 @@ func toGeminiRole(role schema.RoleType) string {
 	return roleUser
 }
 
-const typ = "Gemini"
+const (
+	typ = "Gemini"
+
+	// defaultMaxInlineDataSize is the request size limit of the inline data
+	defaultMaxInlineDataSize = 20 * 1024 * 1024
+	filePollInterval         = time.Second
+)
 
 func (cm *ChatModel) GetType() string {
 	return typ
@@ -18,8 +18,12 @@ package gemini
 
 import (
 	"context"
+	"encoding/base64"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/bytedance/mockey"
 	"github.com/bytedance/sonic"
@@ -299,7 +303,8 @@ func TestChatModelConvMedia(t *testing.T) {
 		},
 	}
 
-	parts := cm.convMedia(contents)
+	parts, err := cm.convMedia(context.Background(), contents)
+	assert.NoError(t, err)
 	assert.Equal(t, 5, len(parts))
 	assert.Equal(t, "test text", parts[0].Text)
 
@@ -308,3 +313,211 @@ func TestChatModelConvMedia(t *testing.T) {
 		assert.Equal(t, "test mime type", parts[i].FileData.MIMEType)
 	}
 }
+
+func TestChatModelConvInlineMedia(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("fake image bytes")
+	dataURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString(data)
+
+	t.Run("inline data url", func(t *testing.T) {
+		cm := &ChatModel{maxInlineDataSize: defaultMaxInlineDataSize}
+		parts, err := cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: dataURL}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(parts))
+		assert.Equal(t, data, parts[0].InlineData.Data)
+		assert.Equal(t, "image/png", parts[0].InlineData.MIMEType)
+	})
+
+	t.Run("inline local file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audio.wav")
+		assert.NoError(t, os.WriteFile(path, data, 0o644))
+		cm := &ChatModel{maxInlineDataSize: defaultMaxInlineDataSize}
+		parts, err := cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeAudioURL, AudioURL: &schema.ChatMessageAudioURL{URI: "file://" + path, MIMEType: "audio/wav"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, data, parts[0].InlineData.Data)
+		assert.Equal(t, "audio/wav", parts[0].InlineData.MIMEType)
+	})
+
+	t.Run("invalid data url", func(t *testing.T) {
+		cm := &ChatModel{maxInlineDataSize: defaultMaxInlineDataSize}
+		_, err := cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URI: "data:image/png,raw"}},
+		})
+		assert.ErrorContains(t, err, "data url is not base64 encoded")
+	})
+
+	t.Run("reject large media when uploading is disabled", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.mp4")
+		f, err := os.Create(path)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Truncate(defaultMaxInlineDataSize+1))
+		assert.NoError(t, f.Close())
+
+		cm := &ChatModel{maxInlineDataSize: -1}
+		_, err = cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "file://" + path}},
+		})
+		assert.ErrorContains(t, err, "exceeds the inline data limit")
+
+		parts, err := cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: dataURL}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, data, parts[0].InlineData.Data)
+	})
+
+	mockey.PatchConvey("upload large media", t, func() {
+		cm := &ChatModel{cli: &genai.Client{Files: &genai.Files{}}, maxInlineDataSize: 4}
+		var uploaded []byte
+		mockey.Mock(genai.Files.Upload).To(func(m genai.Files, ctx context.Context, r io.Reader, config *genai.UploadFileConfig) (*genai.File, error) {
+			uploaded, _ = io.ReadAll(r)
+			assert.Equal(t, "video/mp4", config.MIMEType)
+			return &genai.File{Name: "files/abc", State: genai.FileStateProcessing}, nil
+		}).Build()
+		mockey.Mock(time.After).Return(closedTimeChan()).Build()
+		getMocker := mockey.Mock(genai.Files.Get).Return(&genai.File{
+			Name:     "files/abc",
+			URI:      "https://generativelanguage.googleapis.com/v1beta/files/abc",
+			MIMEType: "video/mp4",
+			State:    genai.FileStateActive,
+		}, nil).Build()
+
+		parts, err := cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(data)}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, data, uploaded)
+		assert.Nil(t, parts[0].InlineData)
+		assert.Equal(t, "https://generativelanguage.googleapis.com/v1beta/files/abc", parts[0].FileData.FileURI)
+		assert.Equal(t, "video/mp4", parts[0].FileData.MIMEType)
+
+		getMocker.UnPatch()
+		mockey.Mock(genai.Files.Get).Return(&genai.File{
+			Name:  "files/abc",
+			State: genai.FileStateFailed,
+			Error: &genai.FileStatus{Message: "unsupported"},
+		}, nil).Build()
+		_, err = cm.convMedia(ctx, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(data)}},
+		})
+		assert.ErrorContains(t, err, "process file files/abc fail: unsupported")
+	})
+}
+
+func closedTimeChan() <-chan time.Time {
+	ch := make(chan time.Time)
+	close(ch)
+	return ch
+}
+
+func TestCachedContent(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewChatModel(ctx, &Config{
+		Client: &genai.Client{Models: &genai.Models{}, Caches: &genai.Caches{}},
+		Model:  "gemini-2.0-flash",
+	})
+	assert.Nil(t, err)
+	assert.NoError(t, cm.BindTools([]*schema.ToolInfo{{Name: "get_weather", Desc: "get weather"}}))
+
+	mockey.PatchConvey("create cached content", t, func() {
+		expire := time.Now().Add(time.Hour)
+		mockey.Mock(genai.Caches.Create).To(func(m genai.Caches, ctx context.Context, model string, config *genai.CreateCachedContentConfig) (*genai.CachedContent, error) {
+			assert.Equal(t, "gemini-2.0-flash", model)
+			assert.Equal(t, time.Hour, config.TTL)
+			assert.Equal(t, "you are a helpful assistant", config.SystemInstruction.Parts[0].Text)
+			assert.Equal(t, 1, len(config.Contents))
+			assert.Equal(t, "get_weather", config.Tools[0].FunctionDeclarations[0].Name)
+			assert.Equal(t, genai.FunctionCallingConfigModeAuto, config.ToolConfig.FunctionCallingConfig.Mode)
+			return &genai.CachedContent{
+				Name:          "cachedContents/123",
+				ExpireTime:    expire,
+				UsageMetadata: &genai.CachedContentUsageMetadata{TotalTokenCount: 4096},
+			}, nil
+		}).Build()
+
+		info, err := cm.CreateCachedContent(ctx, []*schema.Message{
+			schema.SystemMessage("you are a helpful assistant"),
+			schema.UserMessage("a long document"),
+		}, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, "cachedContents/123", info.Name)
+		assert.Equal(t, expire, info.ExpireTime)
+		assert.Equal(t, 4096, info.Usage.PromptTokens)
+
+		_, err = cm.CreateCachedContent(ctx, nil, time.Hour)
+		assert.ErrorContains(t, err, "prefix is empty")
+	})
+
+	mockey.PatchConvey("generate with cached content", t, func() {
+		mockey.Mock(genai.Models.GenerateContent).To(func(m genai.Models, ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+			assert.Equal(t, "cachedContents/123", config.CachedContent)
+			assert.Nil(t, config.Tools)
+			assert.Nil(t, config.ToolConfig)
+			return &genai.GenerateContentResponse{
+				Candidates: []*genai.Candidate{{Content: &genai.Content{Role: "model", Parts: []*genai.Part{genai.NewPartFromText("ok")}}}},
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:        4100,
+					CandidatesTokenCount:    1,
+					TotalTokenCount:         4101,
+					CachedContentTokenCount: 4096,
+				},
+			}, nil
+		}).Build()
+
+		resp, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("summarize it")}, WithCachedContent("cachedContents/123"))
+		assert.NoError(t, err)
+		assert.Equal(t, 4100, resp.ResponseMeta.Usage.PromptTokens)
+		cached, ok := GetCachedTokens(resp)
+		assert.True(t, ok)
+		assert.Equal(t, 4096, cached)
+
+		_, err = cm.Generate(ctx, []*schema.Message{
+			schema.SystemMessage("you are a helpful assistant"),
+			schema.UserMessage("summarize it"),
+		}, WithCachedContent("cachedContents/123"))
+		assert.ErrorContains(t, err, "system message is not allowed with cached content")
+	})
+
+	mockey.PatchConvey("stream with cached content", t, func() {
+		mockey.Mock(genai.Models.GenerateContentStream).Return(func(yield func(*genai.GenerateContentResponse, error) bool) {
+			for i, text := range []string{"o", "k", "!"} {
+				resp := &genai.GenerateContentResponse{
+					Candidates: []*genai.Candidate{{Content: &genai.Content{Role: "model", Parts: []*genai.Part{genai.NewPartFromText(text)}}}},
+					UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+						PromptTokenCount:        4100,
+						CandidatesTokenCount:    int32(i + 1),
+						TotalTokenCount:         int32(4101 + i),
+						CachedContentTokenCount: 4096,
+					},
+				}
+				if !yield(resp, nil) {
+					return
+				}
+			}
+		}).Build()
+
+		sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("summarize it")}, WithCachedContent("cachedContents/123"))
+		assert.NoError(t, err)
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		assert.Equal(t, 3, len(chunks))
+
+		msg, err := schema.ConcatMessages(chunks)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok!", msg.Content)
+		cached, ok := GetCachedTokens(msg)
+		assert.True(t, ok)
+		assert.Equal(t, 4096, cached)
+	})
+}
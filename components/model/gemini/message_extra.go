@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	keyOfCachedTokens = "_eino_gemini_cached_tokens"
+)
+
+// CachedTokens is the type of the cached tokens in Message.Extra, read it by GetCachedTokens.
+// It's exported for registering the type to the serializers of Message.Extra.
+type CachedTokens int
+
+func init() {
+	compose.RegisterStreamChunkConcatFunc(func(chunks []CachedTokens) (final CachedTokens, err error) {
+		// usage metadata is sent with every stream chunk, keep the largest value
+		for _, chunk := range chunks {
+			if chunk > final {
+				final = chunk
+			}
+		}
+		return final, nil
+	})
+	_ = compose.RegisterSerializableType[CachedTokens]("_eino_ext_gemini_cached_tokens")
+}
+
+// GetCachedTokens returns the number of prompt tokens read from the cached content, which are included in the PromptTokens of usage.
+func GetCachedTokens(msg *schema.Message) (int, bool) {
+	if msg == nil {
+		return 0, false
+	}
+	tokens, ok := msg.Extra[keyOfCachedTokens].(CachedTokens)
+	return int(tokens), ok
+}
+
+func setCachedTokens(msg *schema.Message, tokens int) {
+	if msg == nil {
+		return
+	}
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]any)
+	}
+	msg.Extra[keyOfCachedTokens] = CachedTokens(tokens)
+}
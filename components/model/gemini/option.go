@@ -24,6 +24,7 @@ import (
 type options struct {
 	TopK           *int32
 	ResponseSchema *openapi3.Schema
+	CachedContent  string
 }
 
 func WithTopK(k int32) model.Option {
@@ -37,3 +38,12 @@ func WithResponseSchema(s *openapi3.Schema) model.Option {
 		o.ResponseSchema = s
 	})
 }
+
+// WithCachedContent specifies the name of the cached content used as the prefix of the request,
+// which is typically obtained from a previous call to CreateCachedContent.
+// The input should not start with a system message, as the system instruction is part of the cached content.
+func WithCachedContent(name string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.CachedContent = name
+	})
+}
@@ -22,6 +22,8 @@ import (
 
 type options struct {
 	Seed *int
+
+	Thinking *bool
 }
 
 func WithSeed(seed int) model.Option {
@@ -29,3 +31,10 @@ func WithSeed(seed int) model.Option {
 		o.Seed = &seed
 	})
 }
+
+// WithThinking controls whether thinking models think before responding, it overrides ChatModelConfig.Thinking.
+func WithThinking(enable bool) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.Thinking = &enable
+	})
+}
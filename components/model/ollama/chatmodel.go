@@ -18,12 +18,17 @@ package ollama
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/cloudwego/eino/components"
@@ -51,6 +56,12 @@ type ChatModelConfig struct {
 	Format    json.RawMessage `json:"format"`
 	KeepAlive *time.Duration  `json:"keep_alive"`
 
+	// Thinking controls whether thinking models think before responding,
+	// the thinking process is returned in schema.Message.ReasoningContent.
+	// It can be overridden by WithThinking.
+	// Optional. Default: nil, which follows the default behavior of the model
+	Thinking *bool `json:"thinking"`
+
 	Options *api.Options `json:"options"`
 }
 
@@ -115,8 +126,9 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 	err = cm.cli.Chat(ctx, req, func(resp api.ChatResponse) error {
 		outMsg = toEinoMessage(resp)
 		cbOutput = &model.CallbackOutput{
-			Message: outMsg,
-			Config:  cbInput.Config,
+			Message:    outMsg,
+			Config:     cbInput.Config,
+			TokenUsage: toModelTokenUsage(outMsg.ResponseMeta),
 			Extra: map[string]any{
 				CallbackMetricsExtraKey: resp.Metrics,
 			},
@@ -167,12 +179,12 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 			outMsg := toEinoMessage(resp)
 
 			cbOutput := &model.CallbackOutput{
-				// Notice: no token usage
 				Message: outMsg,
 				Config:  conf,
 			}
 
 			if resp.Done {
+				cbOutput.TokenUsage = toModelTokenUsage(outMsg.ResponseMeta)
 				cbOutput.Extra = map[string]any{
 					CallbackMetricsExtraKey: resp.Metrics,
 				}
@@ -230,7 +242,7 @@ func (cm *ChatModel) genRequest(ctx context.Context, stream bool, in []*schema.M
 	req *api.ChatRequest, cbInput *model.CallbackInput, err error) {
 
 	var (
-		o  = &options{}
+		o  = &options{Thinking: cm.config.Thinking}
 		mo = &model.Options{
 			Model: &cm.config.Model,
 			Tools: cm.tools,
@@ -292,6 +304,7 @@ func (cm *ChatModel) genRequest(ctx context.Context, stream bool, in []*schema.M
 		Format:   cm.config.Format,
 
 		Tools: tools,
+		Think: specificOptions.Thinking,
 
 		Options: reqOptions,
 	}
@@ -316,11 +329,22 @@ func (cm *ChatModel) genRequest(ctx context.Context, stream bool, in []*schema.M
 
 func toOllamaMessages(messages []*schema.Message) ([]api.Message, error) {
 	var ollamaMessages []api.Message
+	// ollama identifies the tool call of a tool message by tool name instead of tool call id
+	toolNames := make(map[string]string)
 	for _, msg := range messages {
+		for _, toolCall := range msg.ToolCalls {
+			if toolCall.ID != "" {
+				toolNames[toolCall.ID] = toolCall.Function.Name
+			}
+		}
+
 		ollamaMsg, err := toOllamaMessage(msg)
 		if err != nil {
 			return nil, err
 		}
+		if msg.Role == schema.Tool && ollamaMsg.ToolName == "" {
+			ollamaMsg.ToolName = toolNames[msg.ToolCallID]
+		}
 
 		ollamaMessages = append(ollamaMessages, ollamaMsg)
 	}
@@ -343,19 +367,70 @@ func toOllamaMessage(einoMsg *schema.Message) (api.Message, error) {
 		})
 	}
 
-	// Notice: not support ToolCallID, MultiContent
+	content := einoMsg.Content
+	var images []api.ImageData
+	for _, part := range einoMsg.MultiContent {
+		switch part.Type {
+		case schema.ChatMessagePartTypeText:
+			if len(content) > 0 {
+				content += "\n"
+			}
+			content += part.Text
+		case schema.ChatMessagePartTypeImageURL:
+			if part.ImageURL == nil {
+				continue
+			}
+			image, err := loadImage(part.ImageURL.URL)
+			if err != nil {
+				return api.Message{}, fmt.Errorf("error loading image: %w", err)
+			}
+			images = append(images, image)
+		default:
+			return api.Message{}, fmt.Errorf("unsupported chat message part type: %s", part.Type)
+		}
+	}
+
 	return api.Message{
 		Role:      string(einoMsg.Role),
-		Content:   einoMsg.Content,
+		Content:   content,
+		Thinking:  einoMsg.ReasoningContent,
+		Images:    images,
 		ToolCalls: toolCalls,
+		ToolName:  einoMsg.ToolName,
 	}, nil
 }
 
+// loadImage reads the raw bytes of an image given by base64 data url or local file path.
+func loadImage(imageURL string) (api.ImageData, error) {
+	switch {
+	case strings.HasPrefix(imageURL, "data:"):
+		_, data, found := strings.Cut(imageURL, ",")
+		if !found || !strings.Contains(imageURL[:len(imageURL)-len(data)], ";base64") {
+			return nil, fmt.Errorf("invalid base64 data url: %.32s", imageURL)
+		}
+		image, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding base64 image: %w", err)
+		}
+		return image, nil
+	case strings.HasPrefix(imageURL, "http://") || strings.HasPrefix(imageURL, "https://"):
+		return nil, fmt.Errorf("remote image url is not supported by ollama: %s", imageURL)
+	default:
+		image, err := os.ReadFile(strings.TrimPrefix(imageURL, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading image file: %w", err)
+		}
+		return image, nil
+	}
+}
+
 func toEinoMessage(resp api.ChatResponse) *schema.Message {
 	var toolCalls []schema.ToolCall
 	for _, toolCall := range resp.Message.ToolCalls {
 		arguments := toolCall.Function.Arguments.String()
 		toolCalls = append(toolCalls, schema.ToolCall{
+			// ollama does not return tool call id, generate one so that tool messages can refer to the tool call
+			ID:   genToolCallID(),
 			Type: "function",
 			Function: schema.FunctionCall{
 				Name:      toolCall.Function.Name,
@@ -364,18 +439,58 @@ func toEinoMessage(resp api.ChatResponse) *schema.Message {
 		})
 	}
 
-	// Notice: not support Images
+	var multiContent []schema.ChatMessagePart
+	for _, image := range resp.Message.Images {
+		mimeType := http.DetectContentType(image)
+		multiContent = append(multiContent, schema.ChatMessagePart{
+			Type: schema.ChatMessagePartTypeImageURL,
+			ImageURL: &schema.ChatMessageImageURL{
+				URL:      fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(image)),
+				MIMEType: mimeType,
+			},
+		})
+	}
+
+	var usage *schema.TokenUsage
+	if resp.Done {
+		usage = &schema.TokenUsage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+		}
+	}
+
 	return &schema.Message{
-		Role:      schema.RoleType(resp.Message.Role),
-		Content:   resp.Message.Content,
-		ToolCalls: toolCalls,
+		Role:             schema.RoleType(resp.Message.Role),
+		Content:          resp.Message.Content,
+		MultiContent:     multiContent,
+		ReasoningContent: resp.Message.Thinking,
+		ToolCalls:        toolCalls,
+		ToolName:         resp.Message.ToolName,
 		ResponseMeta: &schema.ResponseMeta{
 			FinishReason: resp.DoneReason,
-			Usage:        nil,
+			Usage:        usage,
 		},
 	}
 }
 
+func toModelTokenUsage(meta *schema.ResponseMeta) *model.TokenUsage {
+	if meta == nil || meta.Usage == nil {
+		return nil
+	}
+	return &model.TokenUsage{
+		PromptTokens:     meta.Usage.PromptTokens,
+		CompletionTokens: meta.Usage.CompletionTokens,
+		TotalTokens:      meta.Usage.TotalTokens,
+	}
+}
+
+func genToolCallID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "call_" + hex.EncodeToString(b)
+}
+
 func parseJSONToObject(jsonStr string) (map[string]any, error) {
 	result := make(map[string]interface{})
 
@@ -387,9 +502,10 @@ func toOllamaTools(einoTools []*schema.ToolInfo) ([]api.Tool, error) {
 	var ollamaTools []api.Tool
 	for _, einoTool := range einoTools {
 		properties := make(map[string]struct {
-			Type        string   `json:"type"`
-			Description string   `json:"description"`
-			Enum        []string `json:"enum,omitempty"`
+			Type        api.PropertyType `json:"type"`
+			Items       any              `json:"items,omitempty"`
+			Description string           `json:"description"`
+			Enum        []any            `json:"enum,omitempty"`
 		})
 		var required []string
 
@@ -402,7 +518,7 @@ func toOllamaTools(einoTools []*schema.ToolInfo) ([]api.Tool, error) {
 			required = openTool.Required
 
 			for name, param := range openTool.Properties {
-				enums := make([]string, 0, len(param.Value.Enum))
+				enums := make([]any, 0, len(param.Value.Enum))
 				for _, e := range param.Value.Enum {
 					str, ok := e.(string)
 					if !ok {
@@ -412,11 +528,12 @@ func toOllamaTools(einoTools []*schema.ToolInfo) ([]api.Tool, error) {
 				}
 
 				properties[name] = struct {
-					Type        string   `json:"type"`
-					Description string   `json:"description"`
-					Enum        []string `json:"enum,omitempty"`
+					Type        api.PropertyType `json:"type"`
+					Items       any              `json:"items,omitempty"`
+					Description string           `json:"description"`
+					Enum        []any            `json:"enum,omitempty"`
 				}{
-					Type:        param.Value.Type,
+					Type:        api.PropertyType{param.Value.Type},
 					Description: param.Value.Description,
 					Enum:        enums,
 				}
@@ -428,21 +545,11 @@ func toOllamaTools(einoTools []*schema.ToolInfo) ([]api.Tool, error) {
 			Function: api.ToolFunction{
 				Name:        einoTool.Name,
 				Description: einoTool.Desc,
-				Parameters: struct {
-					Type       string   `json:"type"`
-					Required   []string `json:"required"`
-					Properties map[string]struct {
-						Type        string   `json:"type"`
-						Description string   `json:"description"`
-						Enum        []string `json:"enum,omitempty"`
-					} `json:"properties"`
-				}{
-					Type:       "object",
-					Required:   required,
-					Properties: properties,
-				},
 			},
 		}
+		ollamaTool.Function.Parameters.Type = "object"
+		ollamaTool.Function.Parameters.Required = required
+		ollamaTool.Function.Parameters.Properties = properties
 		ollamaTools = append(ollamaTools, ollamaTool)
 	}
 	return ollamaTools, nil
@@ -18,8 +18,12 @@ package ollama
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -302,3 +306,79 @@ func TestWithTools(t *testing.T) {
 	assert.Equal(t, "test model", ncm.(*ChatModel).config.Model)
 	assert.Equal(t, "test tool name", ncm.(*ChatModel).tools[0].Name)
 }
+
+func TestToOllamaMessages(t *testing.T) {
+	imageFile := filepath.Join(t.TempDir(), "image.png")
+	assert.NoError(t, os.WriteFile(imageFile, []byte("file image"), 0644))
+
+	msgs, err := toOllamaMessages([]*schema.Message{
+		{
+			Role: schema.User,
+			MultiContent: []schema.ChatMessagePart{
+				{Type: schema.ChatMessagePartTypeText, Text: "what's in the images?"},
+				{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{
+					URL: "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("base64 image")),
+				}},
+				{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: imageFile}},
+			},
+		},
+		{
+			Role:             schema.Assistant,
+			ReasoningContent: "let me check the weather",
+			ToolCalls: []schema.ToolCall{
+				{ID: "call_1", Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+			},
+		},
+		schema.ToolMessage("sunny", "call_1"),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, "what's in the images?", msgs[0].Content)
+	assert.Equal(t, []api.ImageData{api.ImageData("base64 image"), api.ImageData("file image")}, msgs[0].Images)
+	assert.Equal(t, "let me check the weather", msgs[1].Thinking)
+	assert.Equal(t, "Paris", msgs[1].ToolCalls[0].Function.Arguments["city"])
+	assert.Equal(t, "get_weather", msgs[2].ToolName)
+
+	_, err = toOllamaMessages([]*schema.Message{
+		{
+			Role: schema.User,
+			MultiContent: []schema.ChatMessagePart{
+				{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "https://example.com/image.png"}},
+			},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestToEinoMessage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	msg := toEinoMessage(api.ChatResponse{
+		Message: api.Message{
+			Role:     "assistant",
+			Content:  "it is sunny",
+			Thinking: "the user asks about weather",
+			Images:   []api.ImageData{png},
+			ToolCalls: []api.ToolCall{
+				{Function: api.ToolCallFunction{Name: "get_weather", Arguments: map[string]any{"city": "Paris"}}},
+			},
+		},
+		Done:       true,
+		DoneReason: "stop",
+		Metrics: api.Metrics{
+			PromptEvalCount: 10,
+			EvalCount:       5,
+		},
+	})
+	assert.Equal(t, "it is sunny", msg.Content)
+	assert.Equal(t, "the user asks about weather", msg.ReasoningContent)
+	assert.Equal(t, &schema.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, msg.ResponseMeta.Usage)
+	assert.Len(t, msg.MultiContent, 1)
+	assert.Equal(t, "image/png", msg.MultiContent[0].ImageURL.MIMEType)
+	assert.Equal(t, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png), msg.MultiContent[0].ImageURL.URL)
+	assert.Len(t, msg.ToolCalls, 1)
+	assert.True(t, strings.HasPrefix(msg.ToolCalls[0].ID, "call_"))
+	assert.Equal(t, `{"city":"Paris"}`, msg.ToolCalls[0].Function.Arguments)
+
+	chunk := toEinoMessage(api.ChatResponse{Message: api.Message{Role: "assistant", Content: "it"}})
+	assert.Nil(t, chunk.ResponseMeta.Usage)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openai
+
+import (
+	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/responses"
+)
+
+// NewWebSearchTool returns the built-in web search tool of ResponsesAPI.
+// contextSize is one of low, medium and high, the server default is used when it is empty.
+func NewWebSearchTool(contextSize responses.WebSearchToolSearchContextSize) responses.ToolUnionParam {
+	return responses.ToolUnionParam{
+		OfWebSearchPreview: &responses.WebSearchToolParam{
+			Type:              responses.WebSearchToolTypeWebSearchPreview,
+			SearchContextSize: contextSize,
+		},
+	}
+}
+
+// NewFileSearchTool returns the built-in file search tool of ResponsesAPI, which searches the given vector stores.
+// maxNumResults takes effect when it is positive.
+func NewFileSearchTool(vectorStoreIDs []string, maxNumResults int) responses.ToolUnionParam {
+	tool := &responses.FileSearchToolParam{VectorStoreIDs: vectorStoreIDs}
+	if maxNumResults > 0 {
+		tool.MaxNumResults = param.NewOpt(int64(maxNumResults))
+	}
+	return responses.ToolUnionParam{OfFileSearch: tool}
+}
+
+// NewCodeInterpreterTool returns the built-in code interpreter tool of ResponsesAPI,
+// which runs in an auto created container with the given files.
+func NewCodeInterpreterTool(fileIDs []string) responses.ToolUnionParam {
+	return responses.ToolUnionParam{
+		OfCodeInterpreter: &responses.ToolCodeInterpreterParam{
+			Container: responses.ToolCodeInterpreterContainerUnionParam{
+				OfCodeInterpreterContainerAuto: &responses.ToolCodeInterpreterContainerCodeInterpreterContainerAutoParam{
+					FileIDs: fileIDs,
+				},
+			},
+		},
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/openai/openai-go/responses"
+
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/model"
@@ -32,6 +34,24 @@ import (
 var _ model.ToolCallingChatModel = (*ChatModel)(nil)
 var _ model.ChatModel = (*ChatModel)(nil)
 
+type APIType string
+
+const (
+	// ChatCompletionAPI uses the chat completion api, ref: https://platform.openai.com/docs/api-reference/chat/create
+	ChatCompletionAPI APIType = "chat_completion_api"
+	// ResponsesAPI uses the responses api, ref: https://platform.openai.com/docs/api-reference/responses/create
+	ResponsesAPI APIType = "responses_api"
+)
+
+// ReasoningSummary specifies the detail level of the reasoning summary returned by ResponsesAPI.
+type ReasoningSummary string
+
+const (
+	ReasoningSummaryAuto     ReasoningSummary = "auto"
+	ReasoningSummaryConcise  ReasoningSummary = "concise"
+	ReasoningSummaryDetailed ReasoningSummary = "detailed"
+)
+
 type ChatModelConfig struct {
 	// APIKey is your authentication key
 	// Use OpenAI API key or Azure API key depending on the service
@@ -125,10 +145,39 @@ type ChatModelConfig struct {
 	// ExtraFields will override any existing fields with the same key.
 	// Optional. Useful for experimental features not yet officially supported.
 	ExtraFields map[string]any `json:"extra_fields,omitempty"`
+
+	// ReasoningEffort will override the default reasoning level of "medium"
+	// Optional. Useful for fine tuning response latency vs. accuracy
+	ReasoningEffort openai.ReasoningEffortLevel `json:"reasoning_effort,omitempty"`
+
+	// APIType specifies which api is used to call the model
+	// Stop, PresencePenalty, FrequencyPenalty, LogitBias and Seed are not supported by ResponsesAPI.
+	// Optional. Default: ChatCompletionAPI
+	APIType APIType `json:"api_type,omitempty"`
+
+	// The following fields only take effect when APIType is ResponsesAPI
+	// Ref: https://platform.openai.com/docs/api-reference/responses/create
+
+	// ReasoningSummary specifies the detail level of the reasoning summary of reasoning models
+	// The summary can be got by GetReasoningContent.
+	// Optional. Default: no summary
+	ReasoningSummary ReasoningSummary `json:"reasoning_summary,omitempty"`
+
+	// Store specifies whether to store the generated response, which is required by WithPreviousResponseID
+	// Optional. Default: true
+	Store *bool `json:"store,omitempty"`
+
+	// BuiltinTools are the tools hosted by OpenAI, which are sent along with the bound function tools
+	// Use NewWebSearchTool, NewFileSearchTool and NewCodeInterpreterTool to build them.
+	// Optional.
+	BuiltinTools []responses.ToolUnionParam `json:"builtin_tools,omitempty"`
 }
 
 type ChatModel struct {
 	cli *openai.Client
+
+	respChatModel *responsesAPIChatModel
+	apiType       APIType
 }
 
 func NewChatModel(ctx context.Context, config *ChatModelConfig) (*ChatModel, error) {
@@ -161,6 +210,7 @@ func NewChatModel(ctx context.Context, config *ChatModelConfig) (*ChatModel, err
 			User:                 config.User,
 			AzureModelMapperFunc: config.AzureModelMapperFunc,
 			ExtraFields:          config.ExtraFields,
+			ReasoningEffort:      config.ReasoningEffort,
 		}
 	}
 	cli, err := openai.NewClient(ctx, nConf)
@@ -168,19 +218,36 @@ func NewChatModel(ctx context.Context, config *ChatModelConfig) (*ChatModel, err
 		return nil, err
 	}
 
-	return &ChatModel{
-		cli: cli,
-	}, nil
+	cm := &ChatModel{
+		cli:     cli,
+		apiType: ChatCompletionAPI,
+	}
+	if config != nil && config.APIType == ResponsesAPI {
+		respChatModel, err := buildResponsesAPIChatModel(config)
+		if err != nil {
+			return nil, err
+		}
+		cm.respChatModel = respChatModel
+		cm.apiType = ResponsesAPI
+	}
+
+	return cm, nil
 }
 
 func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ...model.Option) (
 	outMsg *schema.Message, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
+	if cm.apiType == ResponsesAPI {
+		return cm.respChatModel.Generate(ctx, in, opts...)
+	}
 	return cm.cli.Generate(ctx, in, opts...)
 }
 
 func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (outStream *schema.StreamReader[*schema.Message], err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
+	if cm.apiType == ResponsesAPI {
+		return cm.respChatModel.Stream(ctx, in, opts...)
+	}
 	return cm.cli.Stream(ctx, in, opts...)
 }
 
@@ -189,15 +256,41 @@ func (cm *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatM
 	if err != nil {
 		return nil, err
 	}
-	return &ChatModel{cli: cli}, nil
+
+	var respChatModel *responsesAPIChatModel
+	if cm.respChatModel != nil {
+		respTools, err := toTools(tools)
+		if err != nil {
+			return nil, err
+		}
+		nrcm := *cm.respChatModel
+		nrcm.tools = respTools
+		nrcm.rawTools = tools
+		nrcm.toolChoice = ptrOf(schema.ToolChoiceAllowed)
+		respChatModel = &nrcm
+	}
+
+	return &ChatModel{cli: cli, respChatModel: respChatModel, apiType: cm.apiType}, nil
 }
 
 func (cm *ChatModel) BindTools(tools []*schema.ToolInfo) error {
-	return cm.cli.BindTools(tools)
+	if err := cm.cli.BindTools(tools); err != nil {
+		return err
+	}
+	if cm.respChatModel != nil {
+		return cm.respChatModel.bindTools(tools, schema.ToolChoiceAllowed)
+	}
+	return nil
 }
 
 func (cm *ChatModel) BindForcedTools(tools []*schema.ToolInfo) error {
-	return cm.cli.BindForcedTools(tools)
+	if err := cm.cli.BindForcedTools(tools); err != nil {
+		return err
+	}
+	if cm.respChatModel != nil {
+		return cm.respChatModel.bindTools(tools, schema.ToolChoiceForced)
+	}
+	return nil
 }
 
 const typ = "OpenAI"
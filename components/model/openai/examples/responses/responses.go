@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/openai/openai-go/responses"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+)
+
+func main() {
+	accessKey := os.Getenv("OPENAI_API_KEY")
+
+	ctx := context.Background()
+	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
+		APIKey:           accessKey,
+		Model:            "o4-mini",
+		APIType:          openai.ResponsesAPI,
+		ReasoningSummary: openai.ReasoningSummaryAuto,
+		BuiltinTools:     []responses.ToolUnionParam{openai.NewWebSearchTool("")},
+	})
+	if err != nil {
+		log.Fatalf("NewChatModel of openai failed, err=%v", err)
+	}
+
+	resp, err := chatModel.Generate(ctx, []*schema.Message{
+		schema.UserMessage("what is the latest release of golang?"),
+	})
+	if err != nil {
+		log.Fatalf("Generate of openai failed, err=%v", err)
+	}
+	reasoning, _ := openai.GetReasoningContent(resp)
+	fmt.Printf("reasoning summary: %s\noutput: %s\n", reasoning, resp.Content)
+
+	// continue the conversation on the server side, only the new message is sent
+	respID, _ := openai.GetResponseID(resp)
+	resp, err = chatModel.Generate(ctx, []*schema.Message{
+		schema.UserMessage("what are the main changes of it?"),
+	}, openai.WithPreviousResponseID(respID))
+	if err != nil {
+		log.Fatalf("Generate of openai failed, err=%v", err)
+	}
+	fmt.Printf("output: %s\n", resp.Content)
+}
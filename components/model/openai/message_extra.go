@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openai
+
+import (
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+)
+
+const (
+	keyOfResponseID = "openai-response-id"
+	// keep the same key as the chat completion api, so that GetReasoningContent works for both
+	keyOfReasoningContent = "reasoning-content"
+)
+
+// ResponseID is the type of the response id in Message.Extra, read it by GetResponseID.
+// It's exported for registering the type to the serializers of Message.Extra.
+type ResponseID string
+
+func init() {
+	compose.RegisterStreamChunkConcatFunc(func(chunks []ResponseID) (final ResponseID, err error) {
+		if len(chunks) == 0 {
+			return "", nil
+		}
+
+		return chunks[len(chunks)-1], nil
+	})
+	_ = compose.RegisterSerializableType[ResponseID]("_eino_ext_openai_response_id")
+}
+
+// GetResponseID returns the id of the response generated by ResponsesAPI,
+// which can be passed to WithPreviousResponseID to continue the conversation.
+func GetResponseID(msg *schema.Message) (string, bool) {
+	if msg == nil || msg.Extra == nil {
+		return "", false
+	}
+	id, ok := msg.Extra[keyOfResponseID].(ResponseID)
+	return string(id), ok
+}
+
+func setResponseID(msg *schema.Message, id string) {
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]any)
+	}
+	msg.Extra[keyOfResponseID] = ResponseID(id)
+}
+
+// GetReasoningContent returns the reasoning content of the message,
+// which is the reasoning summary when using ResponsesAPI.
+func GetReasoningContent(msg *schema.Message) (string, bool) {
+	return openai.GetReasoningContent(msg)
+}
+
+func setReasoningContent(msg *schema.Message, reasoningContent string) {
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]any)
+	}
+	msg.Extra[keyOfReasoningContent] = reasoningContent
+	msg.ReasoningContent = reasoningContent
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openai
+
+import (
+	"github.com/cloudwego/eino/components/model"
+)
+
+type openaiOptions struct {
+	previousResponseID *string
+}
+
+// WithPreviousResponseID chains the request to a previous response, only available for ResponsesAPI.
+// The messages of the previous response are not required in the input when it is set.
+func WithPreviousResponseID(id string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *openaiOptions) {
+		o.previousResponseID = &id
+	})
+}
@@ -0,0 +1,684 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/packages/ssestream"
+	"github.com/openai/openai-go/responses"
+	"github.com/openai/openai-go/shared"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+)
+
+const toolCallTypeFunction = "function"
+
+type responsesAPIChatModel struct {
+	client responses.ResponseService
+
+	tools        []responses.ToolUnionParam
+	rawTools     []*schema.ToolInfo
+	toolChoice   *schema.ToolChoice
+	builtinTools []responses.ToolUnionParam
+
+	model            string
+	maxTokens        *int
+	temperature      *float32
+	topP             *float32
+	responseFormat   *openai.ChatCompletionResponseFormat
+	reasoningEffort  openai.ReasoningEffortLevel
+	reasoningSummary ReasoningSummary
+	store            *bool
+	user             *string
+	extraFields      map[string]any
+}
+
+func buildResponsesAPIChatModel(config *ChatModelConfig) (*responsesAPIChatModel, error) {
+	if config.APIType == ResponsesAPI {
+		if err := checkResponsesAPIConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+	opts := []option.RequestOption{option.WithHTTPClient(httpClient)}
+
+	modelName := config.Model
+	if config.ByAzure {
+		// the deployment is given by the model field in the request body of the responses api
+		if config.AzureModelMapperFunc != nil {
+			modelName = config.AzureModelMapperFunc(modelName)
+		}
+		opts = append(opts,
+			option.WithBaseURL(strings.TrimSuffix(config.BaseURL, "/")+"/openai/"),
+			option.WithQueryAdd("api-version", config.APIVersion),
+			option.WithHeader("api-key", config.APIKey),
+		)
+	} else {
+		if config.BaseURL != "" {
+			opts = append(opts, option.WithBaseURL(config.BaseURL))
+		}
+		opts = append(opts, option.WithAPIKey(config.APIKey))
+	}
+
+	return &responsesAPIChatModel{
+		client:           responses.NewResponseService(opts...),
+		builtinTools:     config.BuiltinTools,
+		model:            modelName,
+		maxTokens:        config.MaxTokens,
+		temperature:      config.Temperature,
+		topP:             config.TopP,
+		responseFormat:   config.ResponseFormat,
+		reasoningEffort:  config.ReasoningEffort,
+		reasoningSummary: config.ReasoningSummary,
+		store:            config.Store,
+		user:             config.User,
+		extraFields:      config.ExtraFields,
+	}, nil
+}
+
+func (cm *responsesAPIChatModel) bindTools(tools []*schema.ToolInfo, toolChoice schema.ToolChoice) error {
+	if len(tools) == 0 {
+		return errors.New("no tools to bind")
+	}
+	respTools, err := toTools(tools)
+	if err != nil {
+		return err
+	}
+	cm.tools = respTools
+	cm.rawTools = tools
+	cm.toolChoice = &toolChoice
+	return nil
+}
+
+func checkResponsesAPIConfig(config *ChatModelConfig) error {
+	if len(config.Stop) > 0 {
+		return fmt.Errorf("'Stop' is not supported by ResponsesAPI")
+	}
+	if config.PresencePenalty != nil {
+		return fmt.Errorf("'PresencePenalty' is not supported by ResponsesAPI")
+	}
+	if config.FrequencyPenalty != nil {
+		return fmt.Errorf("'FrequencyPenalty' is not supported by ResponsesAPI")
+	}
+	if len(config.LogitBias) > 0 {
+		return fmt.Errorf("'LogitBias' is not supported by ResponsesAPI")
+	}
+	if config.Seed != nil {
+		return fmt.Errorf("'Seed' is not supported by ResponsesAPI")
+	}
+	return nil
+}
+
+func (cm *responsesAPIChatModel) Generate(ctx context.Context, input []*schema.Message,
+	opts ...model.Option) (outMsg *schema.Message, err error) {
+
+	req, reqOpts, err := cm.genRequestAndOptions(input, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create generate request: %w", err)
+	}
+
+	config := cm.toCallbackConfig(req)
+	cbOptions := model.GetCommonOptions(&model.Options{Tools: cm.rawTools, ToolChoice: cm.toolChoice}, opts...)
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+		Messages:   input,
+		Tools:      cbOptions.Tools,
+		ToolChoice: cbOptions.ToolChoice,
+		Config:     config,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	resp, err := cm.client.New(ctx, req, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response: %w", err)
+	}
+
+	outMsg, err = cm.toOutputMessage(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert output to schema.Message: %w", err)
+	}
+
+	callbacks.OnEnd(ctx, &model.CallbackOutput{
+		Message:    outMsg,
+		Config:     config,
+		TokenUsage: toModelTokenUsage(outMsg.ResponseMeta.Usage),
+	})
+	return outMsg, nil
+}
+
+func (cm *responsesAPIChatModel) Stream(ctx context.Context, input []*schema.Message,
+	opts ...model.Option) (outStream *schema.StreamReader[*schema.Message], err error) {
+
+	req, reqOpts, err := cm.genRequestAndOptions(input, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream request: %w", err)
+	}
+
+	config := cm.toCallbackConfig(req)
+	cbOptions := model.GetCommonOptions(&model.Options{Tools: cm.rawTools, ToolChoice: cm.toolChoice}, opts...)
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+		Messages:   input,
+		Tools:      cbOptions.Tools,
+		ToolChoice: cbOptions.ToolChoice,
+		Config:     config,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	streamResp := cm.client.NewStreaming(ctx, req, reqOpts...)
+	if streamResp.Err() != nil {
+		return nil, fmt.Errorf("failed to create stream response: %w", streamResp.Err())
+	}
+
+	sr, sw := schema.Pipe[*model.CallbackOutput](1)
+	go func() {
+		defer func() {
+			pe := recover()
+			if pe != nil {
+				_ = sw.Send(nil, newPanicErr(pe, debug.Stack()))
+			}
+			_ = streamResp.Close()
+			sw.Close()
+		}()
+
+		cm.receiveStreamResponse(streamResp, config, sw)
+	}()
+
+	ctx, nsr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(sr,
+		func(src *model.CallbackOutput) (callbacks.CallbackOutput, error) {
+			return src, nil
+		}))
+
+	outStream = schema.StreamReaderWithConvert(nsr,
+		func(src callbacks.CallbackOutput) (*schema.Message, error) {
+			s := src.(*model.CallbackOutput)
+			if s.Message == nil {
+				return nil, schema.ErrNoValue
+			}
+			return s.Message, nil
+		},
+	)
+	return outStream, nil
+}
+
+// receiveStreamResponse maps the stream events to message chunks, the tool call chunks of
+// the same function call share the index of its output item, so that they can be concatenated.
+func (cm *responsesAPIChatModel) receiveStreamResponse(streamResp *ssestream.Stream[responses.ResponseStreamEventUnion],
+	config *model.Config, sw *schema.StreamWriter[*model.CallbackOutput]) {
+
+	// summaryStarted reports whether a reasoning summary part has been sent, the following parts are
+	// separated by a blank line, the same as the reasoning content joined by toOutputMessage
+	summaryStarted := false
+	for streamResp.Next() {
+		var msg *schema.Message
+
+		switch event := streamResp.Current().AsAny().(type) {
+		case responses.ResponseCreatedEvent:
+			msg = &schema.Message{Role: schema.Assistant}
+			setResponseID(msg, event.Response.ID)
+
+		case responses.ResponseTextDeltaEvent:
+			msg = &schema.Message{Role: schema.Assistant, Content: event.Delta}
+
+		case responses.ResponseReasoningSummaryPartAddedEvent:
+			if !summaryStarted {
+				summaryStarted = true
+				continue
+			}
+			msg = &schema.Message{Role: schema.Assistant}
+			setReasoningContent(msg, "\n\n")
+
+		case responses.ResponseReasoningSummaryTextDeltaEvent:
+			msg = &schema.Message{Role: schema.Assistant}
+			setReasoningContent(msg, event.Delta)
+
+		case responses.ResponseOutputItemAddedEvent:
+			fc, ok := event.Item.AsAny().(responses.ResponseFunctionToolCall)
+			if !ok {
+				continue
+			}
+			msg = &schema.Message{
+				Role: schema.Assistant,
+				ToolCalls: []schema.ToolCall{{
+					Index: ptrOf(int(event.OutputIndex)),
+					ID:    fc.CallID,
+					Type:  toolCallTypeFunction,
+					Function: schema.FunctionCall{
+						Name: fc.Name,
+					},
+				}},
+			}
+
+		case responses.ResponseFunctionCallArgumentsDeltaEvent:
+			msg = &schema.Message{
+				Role: schema.Assistant,
+				ToolCalls: []schema.ToolCall{{
+					Index: ptrOf(int(event.OutputIndex)),
+					Function: schema.FunctionCall{
+						Arguments: event.Delta,
+					},
+				}},
+			}
+
+		case responses.ResponseCompletedEvent:
+			cm.sendCallbackOutput(sw, config, cm.toFinalChunk(event.Response))
+			return
+
+		case responses.ResponseIncompleteEvent:
+			cm.sendCallbackOutput(sw, config, cm.toFinalChunk(event.Response))
+			return
+
+		case responses.ResponseFailedEvent:
+			_ = sw.Send(nil, fmt.Errorf("response failed: %s", event.Response.Error.Message))
+			return
+
+		case responses.ResponseErrorEvent:
+			_ = sw.Send(nil, fmt.Errorf("received error: %s", event.Message))
+			return
+
+		default:
+			continue
+		}
+
+		if closed := cm.sendCallbackOutput(sw, config, msg); closed {
+			return
+		}
+	}
+
+	if streamResp.Err() != nil {
+		_ = sw.Send(nil, fmt.Errorf("failed to read stream: %w", streamResp.Err()))
+	}
+}
+
+func (cm *responsesAPIChatModel) toFinalChunk(resp responses.Response) *schema.Message {
+	return &schema.Message{
+		Role: schema.Assistant,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: toFinishReason(&resp),
+			Usage:        toEinoTokenUsage(resp.Usage),
+		},
+	}
+}
+
+func (cm *responsesAPIChatModel) sendCallbackOutput(sw *schema.StreamWriter[*model.CallbackOutput], config *model.Config,
+	msg *schema.Message) bool {
+
+	var usage *schema.TokenUsage
+	if msg.ResponseMeta != nil {
+		usage = msg.ResponseMeta.Usage
+	}
+	return sw.Send(&model.CallbackOutput{
+		Message:    msg,
+		Config:     config,
+		TokenUsage: toModelTokenUsage(usage),
+	}, nil)
+}
+
+func (cm *responsesAPIChatModel) genRequestAndOptions(in []*schema.Message, opts ...model.Option) (
+	req responses.ResponseNewParams, reqOpts []option.RequestOption, err error) {
+
+	options := model.GetCommonOptions(&model.Options{
+		Temperature: cm.temperature,
+		MaxTokens:   cm.maxTokens,
+		Model:       &cm.model,
+		TopP:        cm.topP,
+		ToolChoice:  cm.toolChoice,
+	}, opts...)
+	specOptions := model.GetImplSpecificOptions(&openaiOptions{}, opts...)
+
+	if len(options.Stop) > 0 {
+		return req, nil, fmt.Errorf("'Stop' is not supported by ResponsesAPI")
+	}
+
+	req = responses.ResponseNewParams{
+		Model:              ptrFromOrZero(options.Model),
+		MaxOutputTokens:    newOpenaiIntOpt(options.MaxTokens),
+		Temperature:        newOpenaiFloatOpt(options.Temperature),
+		TopP:               newOpenaiFloatOpt(options.TopP),
+		PreviousResponseID: newOpenaiStringOpt(specOptions.previousResponseID),
+		User:               newOpenaiStringOpt(cm.user),
+		Store:              newOpenaiBoolOpt(cm.store),
+	}
+	if cm.reasoningEffort != "" || cm.reasoningSummary != "" {
+		req.Reasoning = shared.ReasoningParam{
+			Effort:  shared.ReasoningEffort(cm.reasoningEffort),
+			Summary: shared.ReasoningSummary(cm.reasoningSummary),
+		}
+	}
+	if req.Text, err = toTextConfig(cm.responseFormat); err != nil {
+		return req, nil, err
+	}
+	if req.Input, err = toInput(in); err != nil {
+		return req, nil, err
+	}
+
+	tools := cm.tools
+	if options.Tools != nil {
+		if tools, err = toTools(options.Tools); err != nil {
+			return req, nil, err
+		}
+	}
+	req.Tools = append(append(req.Tools, tools...), cm.builtinTools...)
+	if options.ToolChoice != nil {
+		var choice responses.ToolChoiceOptions
+		switch *options.ToolChoice {
+		case schema.ToolChoiceForbidden:
+			choice = responses.ToolChoiceOptionsNone
+		case schema.ToolChoiceAllowed:
+			choice = responses.ToolChoiceOptionsAuto
+		case schema.ToolChoiceForced:
+			if len(req.Tools) == 0 {
+				return req, nil, fmt.Errorf("tool choice is forced but tool is not provided")
+			}
+			choice = responses.ToolChoiceOptionsRequired
+		default:
+			return req, nil, fmt.Errorf("tool choice=%s not support", *options.ToolChoice)
+		}
+		req.ToolChoice = responses.ResponseNewParamsToolChoiceUnion{OfToolChoiceMode: param.NewOpt(choice)}
+	}
+
+	for k, v := range cm.extraFields {
+		reqOpts = append(reqOpts, option.WithJSONSet(k, v))
+	}
+	return req, reqOpts, nil
+}
+
+func toTextConfig(format *openai.ChatCompletionResponseFormat) (responses.ResponseTextConfigParam, error) {
+	text := responses.ResponseTextConfigParam{}
+	if format == nil {
+		return text, nil
+	}
+
+	switch format.Type {
+	case openai.ChatCompletionResponseFormatTypeText:
+		text.Format.OfText = ptrOf(shared.NewResponseFormatTextParam())
+	case openai.ChatCompletionResponseFormatTypeJSONObject:
+		text.Format.OfJSONObject = ptrOf(shared.NewResponseFormatJSONObjectParam())
+	case openai.ChatCompletionResponseFormatTypeJSONSchema:
+		if format.JSONSchema == nil {
+			return text, fmt.Errorf("json schema is required by response format %s", format.Type)
+		}
+		schemaMap, err := toJSONMap(format.JSONSchema.Schema)
+		if err != nil {
+			return text, fmt.Errorf("convert json schema fail: %w", err)
+		}
+		text.Format.OfJSONSchema = &responses.ResponseFormatTextJSONSchemaConfigParam{
+			Name:        format.JSONSchema.Name,
+			Description: newOpenaiStringOpt(&format.JSONSchema.Description),
+			Schema:      schemaMap,
+			Strict:      param.NewOpt(format.JSONSchema.Strict),
+		}
+	default:
+		return text, fmt.Errorf("response format type=%s not support", format.Type)
+	}
+	return text, nil
+}
+
+func toInput(in []*schema.Message) (responses.ResponseNewParamsInputUnion, error) {
+	items := make([]responses.ResponseInputItemUnionParam, 0, len(in))
+	for _, msg := range in {
+		switch msg.Role {
+		case schema.User, schema.System:
+			content, err := toInputContent(msg)
+			if err != nil {
+				return responses.ResponseNewParamsInputUnion{}, err
+			}
+			role := responses.EasyInputMessageRoleUser
+			if msg.Role == schema.System {
+				role = responses.EasyInputMessageRoleDeveloper
+			}
+			items = append(items, responses.ResponseInputItemUnionParam{
+				OfMessage: &responses.EasyInputMessageParam{Role: role, Content: content},
+			})
+
+		case schema.Assistant:
+			if msg.Content != "" || len(msg.MultiContent) > 0 {
+				content, err := toInputContent(msg)
+				if err != nil {
+					return responses.ResponseNewParamsInputUnion{}, err
+				}
+				items = append(items, responses.ResponseInputItemUnionParam{
+					OfMessage: &responses.EasyInputMessageParam{
+						Role:    responses.EasyInputMessageRoleAssistant,
+						Content: content,
+					},
+				})
+			}
+			for _, tc := range msg.ToolCalls {
+				items = append(items, responses.ResponseInputItemUnionParam{
+					OfFunctionCall: &responses.ResponseFunctionToolCallParam{
+						CallID:    tc.ID,
+						Name:      tc.Function.Name,
+						Arguments: tc.Function.Arguments,
+					},
+				})
+			}
+
+		case schema.Tool:
+			items = append(items, responses.ResponseInputItemUnionParam{
+				OfFunctionCallOutput: &responses.ResponseInputItemFunctionCallOutputParam{
+					CallID: msg.ToolCallID,
+					Output: msg.Content,
+				},
+			})
+
+		default:
+			return responses.ResponseNewParamsInputUnion{}, fmt.Errorf("unknown role: %s", msg.Role)
+		}
+	}
+	return responses.ResponseNewParamsInputUnion{OfInputItemList: items}, nil
+}
+
+func toInputContent(msg *schema.Message) (responses.EasyInputMessageContentUnionParam, error) {
+	content := responses.EasyInputMessageContentUnionParam{}
+	if len(msg.MultiContent) == 0 {
+		content.OfString = param.NewOpt(msg.Content)
+		return content, nil
+	}
+
+	if msg.Content != "" {
+		content.OfInputItemContentList = append(content.OfInputItemContentList, responses.ResponseInputContentUnionParam{
+			OfInputText: &responses.ResponseInputTextParam{Text: msg.Content},
+		})
+	}
+	for _, c := range msg.MultiContent {
+		switch c.Type {
+		case schema.ChatMessagePartTypeText:
+			content.OfInputItemContentList = append(content.OfInputItemContentList, responses.ResponseInputContentUnionParam{
+				OfInputText: &responses.ResponseInputTextParam{Text: c.Text},
+			})
+		case schema.ChatMessagePartTypeImageURL:
+			if c.ImageURL == nil {
+				continue
+			}
+			content.OfInputItemContentList = append(content.OfInputItemContentList, responses.ResponseInputContentUnionParam{
+				OfInputImage: &responses.ResponseInputImageParam{
+					ImageURL: param.NewOpt(c.ImageURL.URL),
+					Detail:   toImageDetail(c.ImageURL.Detail),
+				},
+			})
+		case schema.ChatMessagePartTypeFileURL:
+			if c.FileURL == nil {
+				continue
+			}
+			file := &responses.ResponseInputFileParam{}
+			if strings.HasPrefix(c.FileURL.URL, "data:") {
+				file.FileData = param.NewOpt(c.FileURL.URL)
+				file.Filename = newOpenaiStringOpt(&c.FileURL.Name)
+			} else {
+				file.FileURL = param.NewOpt(c.FileURL.URL)
+			}
+			content.OfInputItemContentList = append(content.OfInputItemContentList, responses.ResponseInputContentUnionParam{
+				OfInputFile: file,
+			})
+		default:
+			return content, fmt.Errorf("unsupported content type by ResponsesAPI: %s", c.Type)
+		}
+	}
+	return content, nil
+}
+
+func toImageDetail(detail schema.ImageURLDetail) responses.ResponseInputImageDetail {
+	switch detail {
+	case schema.ImageURLDetailLow:
+		return responses.ResponseInputImageDetailLow
+	case schema.ImageURLDetailHigh:
+		return responses.ResponseInputImageDetailHigh
+	default:
+		return responses.ResponseInputImageDetailAuto
+	}
+}
+
+func toTools(tis []*schema.ToolInfo) ([]responses.ToolUnionParam, error) {
+	tools := make([]responses.ToolUnionParam, len(tis))
+	for i, ti := range tis {
+		if ti == nil {
+			return nil, fmt.Errorf("tool info cannot be nil")
+		}
+		paramsJSONSchema, err := ti.ParamsOneOf.ToOpenAPIV3()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool parameters to JSONSchema: %w", err)
+		}
+		params, err := toJSONMap(paramsJSONSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool parameters to JSONSchema: %w", err)
+		}
+		tools[i] = responses.ToolUnionParam{
+			OfFunction: &responses.FunctionToolParam{
+				Name:        ti.Name,
+				Description: newOpenaiStringOpt(&ti.Desc),
+				Parameters:  params,
+			},
+		}
+	}
+	return tools, nil
+}
+
+func (cm *responsesAPIChatModel) toCallbackConfig(req responses.ResponseNewParams) *model.Config {
+	return &model.Config{
+		Model:       req.Model,
+		MaxTokens:   int(req.MaxOutputTokens.Value),
+		Temperature: float32(req.Temperature.Value),
+		TopP:        float32(req.TopP.Value),
+	}
+}
+
+func (cm *responsesAPIChatModel) toOutputMessage(resp *responses.Response) (*schema.Message, error) {
+	if resp.Status == responses.ResponseStatusFailed {
+		return nil, fmt.Errorf("response failed: %s", resp.Error.Message)
+	}
+
+	msg := &schema.Message{
+		Role: schema.Assistant,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: toFinishReason(resp),
+			Usage:        toEinoTokenUsage(resp.Usage),
+		},
+	}
+	setResponseID(msg, resp.ID)
+
+	var texts, summaries []string
+	for _, item := range resp.Output {
+		switch asItem := item.AsAny().(type) {
+		case responses.ResponseOutputMessage:
+			for _, c := range asItem.Content {
+				if c.Type == "output_text" {
+					texts = append(texts, c.Text)
+				}
+			}
+		case responses.ResponseReasoningItem:
+			for _, s := range asItem.Summary {
+				summaries = append(summaries, s.Text)
+			}
+		case responses.ResponseFunctionToolCall:
+			msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+				ID:   asItem.CallID,
+				Type: toolCallTypeFunction,
+				Function: schema.FunctionCall{
+					Name:      asItem.Name,
+					Arguments: asItem.Arguments,
+				},
+			})
+		}
+	}
+	msg.Content = strings.Join(texts, "")
+	if len(summaries) > 0 {
+		setReasoningContent(msg, strings.Join(summaries, "\n\n"))
+	}
+	return msg, nil
+}
+
+func toFinishReason(resp *responses.Response) string {
+	if resp.Status == responses.ResponseStatusIncomplete && resp.IncompleteDetails.Reason != "" {
+		return resp.IncompleteDetails.Reason
+	}
+	return string(resp.Status)
+}
+
+func toEinoTokenUsage(usage responses.ResponseUsage) *schema.TokenUsage {
+	return &schema.TokenUsage{
+		PromptTokens:     int(usage.InputTokens),
+		CompletionTokens: int(usage.OutputTokens),
+		TotalTokens:      int(usage.TotalTokens),
+	}
+}
+
+func toModelTokenUsage(usage *schema.TokenUsage) *model.TokenUsage {
+	if usage == nil {
+		return nil
+	}
+	return &model.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
+func toJSONMap(v any) (map[string]any, error) {
+	b, err := sonic.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err = sonic.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go/responses"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func newResponsesTestServer(t *testing.T, handle func(body map[string]any, w http.ResponseWriter)) (*httptest.Server, *http.Request) {
+	lastReq := &http.Request{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*lastReq = *r
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		body := map[string]any{}
+		if err = json.Unmarshal(raw, &body); err != nil {
+			t.Error(err)
+		}
+		handle(body, w)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, lastReq
+}
+
+func TestResponsesAPIConfig(t *testing.T) {
+	ctx := context.Background()
+	seed := 1
+	_, err := NewChatModel(ctx, &ChatModelConfig{Model: "gpt-4o", APIType: ResponsesAPI, Seed: &seed})
+	if err == nil || !strings.Contains(err.Error(), "'Seed' is not supported") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cm, err := NewChatModel(ctx, &ChatModelConfig{Model: "gpt-4o", Seed: &seed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.respChatModel != nil {
+		t.Fatal("responses api chat model should not be built for chat completion api")
+	}
+}
+
+func TestResponsesAPIGenerate(t *testing.T) {
+	ctx := context.Background()
+	var reqBody map[string]any
+	srv, lastReq := newResponsesTestServer(t, func(body map[string]any, w http.ResponseWriter) {
+		reqBody = body
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp_2", "object": "response", "status": "completed", "model": "deployment",
+			"output": [
+				{"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "think"}]},
+				{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed",
+				 "content": [{"type": "output_text", "text": "hello", "annotations": []}]},
+				{"type": "function_call", "id": "fc_1", "call_id": "call_1", "name": "get_weather", "arguments": "{\"city\":\"bj\"}", "status": "completed"}
+			],
+			"usage": {"input_tokens": 10, "output_tokens": 5, "total_tokens": 15,
+			          "input_tokens_details": {"cached_tokens": 0}, "output_tokens_details": {"reasoning_tokens": 2}}
+		}`))
+	})
+
+	store := true
+	cm, err := NewChatModel(ctx, &ChatModelConfig{
+		APIKey:               "key",
+		ByAzure:              true,
+		BaseURL:              srv.URL,
+		APIVersion:           "2025-03-01-preview",
+		AzureModelMapperFunc: func(model string) string { return "deployment" },
+		Model:                "o4-mini",
+		APIType:              ResponsesAPI,
+		ReasoningEffort:      "low",
+		ReasoningSummary:     ReasoningSummaryAuto,
+		Store:                &store,
+		BuiltinTools:         []responses.ToolUnionParam{NewWebSearchTool("low"), NewFileSearchTool([]string{"vs_1"}, 3), NewCodeInterpreterTool(nil)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cm.BindTools([]*schema.ToolInfo{{
+		Name: "get_weather",
+		Desc: "get weather of a city",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"city": {Type: schema.String},
+		}),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cbToolChoice *schema.ToolChoice
+	cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			cbToolChoice, _ = model.ConvCallbackInput(input).ToolChoice.(*schema.ToolChoice)
+			return ctx
+		}).Build())
+
+	msg, err := cm.Generate(cbCtx, []*schema.Message{
+		schema.SystemMessage("system"),
+		schema.UserMessage("weather?"),
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{ID: "call_0", Function: schema.FunctionCall{Name: "get_weather", Arguments: "{}"}}}},
+		schema.ToolMessage("sunny", "call_0"),
+	}, WithPreviousResponseID("resp_1"), model.WithToolChoice(schema.ToolChoiceForced))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cbToolChoice == nil || *cbToolChoice != schema.ToolChoiceForced {
+		t.Fatalf("unexpected callback tool choice: %v", cbToolChoice)
+	}
+
+	if lastReq.URL.Path != "/openai/responses" || lastReq.URL.Query().Get("api-version") != "2025-03-01-preview" ||
+		lastReq.Header.Get("api-key") != "key" {
+		t.Fatalf("unexpected azure request: %s %v", lastReq.URL.String(), lastReq.Header)
+	}
+	if reqBody["model"] != "deployment" || reqBody["previous_response_id"] != "resp_1" || reqBody["store"] != true ||
+		reqBody["tool_choice"] != "required" {
+		t.Fatalf("unexpected request body: %v", reqBody)
+	}
+	reasoning, _ := reqBody["reasoning"].(map[string]any)
+	if reasoning["effort"] != "low" || reasoning["summary"] != "auto" {
+		t.Fatalf("unexpected reasoning: %v", reqBody["reasoning"])
+	}
+	var toolTypes []string
+	for _, tool := range reqBody["tools"].([]any) {
+		toolTypes = append(toolTypes, tool.(map[string]any)["type"].(string))
+	}
+	if strings.Join(toolTypes, ",") != "function,web_search_preview,file_search,code_interpreter" {
+		t.Fatalf("unexpected tools: %v", toolTypes)
+	}
+	var inputTypes []string
+	for _, item := range reqBody["input"].([]any) {
+		m := item.(map[string]any)
+		if typ, ok := m["type"].(string); ok {
+			inputTypes = append(inputTypes, typ)
+		} else {
+			inputTypes = append(inputTypes, m["role"].(string))
+		}
+	}
+	if strings.Join(inputTypes, ",") != "developer,user,function_call,function_call_output" {
+		t.Fatalf("unexpected input: %v", inputTypes)
+	}
+
+	if msg.Content != "hello" || msg.ReasoningContent != "think" {
+		t.Fatalf("unexpected message: %v", msg)
+	}
+	if rc, _ := GetReasoningContent(msg); rc != "think" {
+		t.Fatalf("unexpected reasoning content: %s", rc)
+	}
+	if id, _ := GetResponseID(msg); id != "resp_2" {
+		t.Fatalf("unexpected response id: %s", id)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].ID != "call_1" || msg.ToolCalls[0].Function.Arguments != `{"city":"bj"}` {
+		t.Fatalf("unexpected tool calls: %v", msg.ToolCalls)
+	}
+	if msg.ResponseMeta.FinishReason != "completed" || msg.ResponseMeta.Usage.TotalTokens != 15 {
+		t.Fatalf("unexpected response meta: %v", msg.ResponseMeta)
+	}
+}
+
+func TestResponsesAPIStream(t *testing.T) {
+	ctx := context.Background()
+	events := []string{
+		`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1","object":"response","status":"in_progress","output":[]}}`,
+		`{"type":"response.reasoning_summary_part.added","sequence_number":1,"item_id":"rs_1","output_index":0,"summary_index":0,"part":{"type":"summary_text","text":""}}`,
+		`{"type":"response.reasoning_summary_text.delta","sequence_number":2,"item_id":"rs_1","output_index":0,"summary_index":0,"delta":"thi"}`,
+		`{"type":"response.reasoning_summary_text.delta","sequence_number":3,"item_id":"rs_1","output_index":0,"summary_index":0,"delta":"nk"}`,
+		`{"type":"response.reasoning_summary_part.added","sequence_number":4,"item_id":"rs_1","output_index":0,"summary_index":1,"part":{"type":"summary_text","text":""}}`,
+		`{"type":"response.reasoning_summary_text.delta","sequence_number":5,"item_id":"rs_1","output_index":0,"summary_index":1,"delta":"again"}`,
+		`{"type":"response.output_text.delta","sequence_number":6,"item_id":"msg_1","output_index":1,"content_index":0,"delta":"hel"}`,
+		`{"type":"response.output_text.delta","sequence_number":7,"item_id":"msg_1","output_index":1,"content_index":0,"delta":"lo"}`,
+		`{"type":"response.output_item.added","sequence_number":8,"output_index":2,"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_weather","arguments":"","status":"in_progress"}}`,
+		`{"type":"response.function_call_arguments.delta","sequence_number":9,"item_id":"fc_1","output_index":2,"delta":"{\"city\":"}`,
+		`{"type":"response.function_call_arguments.delta","sequence_number":10,"item_id":"fc_1","output_index":2,"delta":"\"bj\"}"}`,
+		`{"type":"response.completed","sequence_number":11,"response":{"id":"resp_1","object":"response","status":"completed","output":[],"usage":{"input_tokens":3,"output_tokens":4,"total_tokens":7}}}`,
+	}
+	var reqBody map[string]any
+	srv, lastReq := newResponsesTestServer(t, func(body map[string]any, w http.ResponseWriter) {
+		reqBody = body
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			_, _ = w.Write([]byte("data: " + e + "\n\n"))
+		}
+	})
+
+	cm, err := NewChatModel(ctx, &ChatModelConfig{
+		APIKey:  "key",
+		BaseURL: srv.URL,
+		Model:   "o4-mini",
+		APIType: ResponsesAPI,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("weather?")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	if lastReq.URL.Path != "/responses" || lastReq.Header.Get("Authorization") != "Bearer key" || reqBody["stream"] != true {
+		t.Fatalf("unexpected request: %s %v %v", lastReq.URL.String(), lastReq.Header, reqBody)
+	}
+
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Content != "hello" || msg.ReasoningContent != "think\n\nagain" {
+		t.Fatalf("unexpected message: %v", msg)
+	}
+	if rc, _ := GetReasoningContent(msg); rc != "think\n\nagain" {
+		t.Fatalf("unexpected reasoning content: %s", rc)
+	}
+	if id, _ := GetResponseID(msg); id != "resp_1" {
+		t.Fatalf("unexpected response id: %s", id)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].ID != "call_1" || msg.ToolCalls[0].Function.Name != "get_weather" ||
+		msg.ToolCalls[0].Function.Arguments != `{"city":"bj"}` {
+		t.Fatalf("unexpected tool calls: %v", msg.ToolCalls)
+	}
+	if msg.ResponseMeta.FinishReason != "completed" || msg.ResponseMeta.Usage.TotalTokens != 7 {
+		t.Fatalf("unexpected response meta: %v", msg.ResponseMeta)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrInteractionNotFound is returned in ModeReplay when no recorded interaction matches the request,
+// or the ones matching it are all replayed.
+var ErrInteractionNotFound = errors.New("replay: no recorded interaction matches the request")
+
+type Mode string
+
+const (
+	// ModeRecord calls the wrapped component and records every interaction, the existing cassette file is overwritten.
+	ModeRecord Mode = "record"
+	// ModeReplay serves the recorded interactions without calling the wrapped component,
+	// and fails with ErrInteractionNotFound on unmatched requests and on the requests made more times than recorded.
+	ModeReplay Mode = "replay"
+	// ModeReplayOrRecord serves the recorded interactions, and records the unmatched requests by calling the wrapped component.
+	ModeReplayOrRecord Mode = "replay_or_record"
+)
+
+const cassetteVersion = 1
+
+type interactionKind string
+
+const (
+	kindGenerate interactionKind = "generate"
+	kindStream   interactionKind = "stream"
+	kindEmbed    interactionKind = "embed"
+)
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	// Key is the hash of the normalized request
+	Key string `json:"key"`
+	// Request is the normalized request, kept for reviewing the cassette
+	Request json.RawMessage `json:"request"`
+	// Message is the output of Generate
+	Message *schema.Message `json:"message,omitempty"`
+	// Chunks are the chunks of Stream
+	Chunks []*schema.Message `json:"chunks,omitempty"`
+	// Embeddings are the output of EmbedStrings
+	Embeddings [][]float64 `json:"embeddings,omitempty"`
+	// Error is the error returned by the call, or by the stream after the chunks
+	Error string `json:"error,omitempty"`
+}
+
+type cassetteFile struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Cassette stores the interactions in a JSON file, it can be shared by multiple chat models and embedders.
+// The identical requests are replayed in the order they were recorded, each recorded interaction is replayed once.
+type Cassette struct {
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	byKey        map[string][]*Interaction
+	cursors      map[string]int
+}
+
+// NewCassette opens the cassette file at path in the given mode.
+// The file must exist in ModeReplay, and is created on the first recorded interaction in the other modes.
+func NewCassette(path string, mode Mode) (*Cassette, error) {
+	if path == "" {
+		return nil, errors.New("replay: cassette path is required")
+	}
+	if mode == "" {
+		mode = ModeReplay
+	}
+	if mode != ModeRecord && mode != ModeReplay && mode != ModeReplayOrRecord {
+		return nil, fmt.Errorf("replay: unknown mode: %s", mode)
+	}
+
+	c := &Cassette{
+		path:    path,
+		mode:    mode,
+		byKey:   make(map[string][]*Interaction),
+		cursors: make(map[string]int),
+	}
+	if mode == ModeRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if mode == ModeReplayOrRecord && errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("replay: read cassette fail: %w", err)
+	}
+	file := &cassetteFile{}
+	if err = json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("replay: unmarshal cassette %s fail: %w", path, err)
+	}
+	if file.Version != cassetteVersion {
+		return nil, fmt.Errorf("replay: unsupported cassette version %d of %s", file.Version, path)
+	}
+	for _, it := range file.Interactions {
+		c.interactions = append(c.interactions, it)
+		c.byKey[it.Key] = append(c.byKey[it.Key], it)
+	}
+	return c, nil
+}
+
+// Mode returns the mode of the cassette.
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// Interactions returns the interactions loaded and recorded so far.
+func (c *Cassette) Interactions() []*Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Interaction(nil), c.interactions...)
+}
+
+// find returns the next recorded interaction of the request, or nil if there's none and the request should be recorded.
+func (c *Cassette) find(key string, request json.RawMessage) (*Interaction, error) {
+	if c.mode == ModeRecord {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recorded := c.byKey[key]
+	cursor := c.cursors[key]
+	if cursor < len(recorded) {
+		c.cursors[key] = cursor + 1
+		return recorded[cursor], nil
+	}
+	if c.mode == ModeReplayOrRecord {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%w, cassette=%s, key=%s, recorded=%d, request=%s",
+		ErrInteractionNotFound, c.path, key, len(recorded), string(request))
+}
+
+// record appends the interaction and writes the whole cassette to the file.
+// The interaction is rejected if it can not be persisted, e.g. with an extra value of unregistered type.
+func (c *Cassette) record(it *Interaction) error {
+	if _, err := json.Marshal(it); err != nil {
+		return fmt.Errorf("replay: marshal interaction fail: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, it)
+	c.byKey[it.Key] = append(c.byKey[it.Key], it)
+	// the recorded interaction must not be replayed to the following identical requests in ModeReplayOrRecord
+	c.cursors[it.Key] = len(c.byKey[it.Key])
+
+	data, err := json.MarshalIndent(&cassetteFile{Version: cassetteVersion, Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("replay: marshal cassette fail: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("replay: create cassette dir fail: %w", err)
+	}
+	// write to a temp file first, so that an interrupted test does not leave a broken cassette
+	tmp := c.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("replay: write cassette fail: %w", err)
+	}
+	if err = os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("replay: write cassette fail: %w", err)
+	}
+	return nil
+}
+
+// requestKey returns the normalized request in JSON and its hash.
+func requestKey(kind interactionKind, request any) (json.RawMessage, string, error) {
+	data, err := json.Marshal(struct {
+		Kind    interactionKind `json:"kind"`
+		Request any             `json:"request"`
+	}{kind, request})
+	if err != nil {
+		return nil, "", fmt.Errorf("replay: marshal request fail: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+func errorOf(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	return errors.New(msg)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+var _ model.ToolCallingChatModel = (*ChatModel)(nil)
+
+type ChatModelConfig struct {
+	// ChatModel is the chat model to record
+	// Required in ModeRecord and ModeReplayOrRecord, ignored in ModeReplay
+	ChatModel model.ToolCallingChatModel
+	// Cassette stores the recorded interactions
+	// Required
+	Cassette *Cassette
+}
+
+// ChatModel records the requests and responses of the wrapped chat model to a cassette,
+// and serves them back with no network when replaying.
+type ChatModel struct {
+	cm       model.ToolCallingChatModel
+	cassette *Cassette
+	tools    []*schema.ToolInfo
+}
+
+func NewChatModel(_ context.Context, config *ChatModelConfig) (*ChatModel, error) {
+	if config == nil || config.Cassette == nil {
+		return nil, errors.New("replay: cassette is required")
+	}
+	if config.ChatModel == nil && config.Cassette.Mode() != ModeReplay {
+		return nil, fmt.Errorf("replay: chat model is required in mode %s", config.Cassette.Mode())
+	}
+	return &ChatModel{
+		cm:       config.ChatModel,
+		cassette: config.Cassette,
+	}, nil
+}
+
+func (r *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (outMsg *schema.Message, err error) {
+	request, key, err := r.requestKey(kindGenerate, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	it, err := r.cassette.find(key, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if it != nil || !components.IsCallbacksEnabled(r.cm) {
+		ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfChatModel)
+		ctx = callbacks.OnStart(ctx, r.callbackInput(input, opts...))
+		defer func() {
+			if err != nil {
+				callbacks.OnError(ctx, err)
+				return
+			}
+			callbacks.OnEnd(ctx, toCallbackOutput(outMsg))
+		}()
+	}
+
+	if it != nil {
+		if it.Error != "" {
+			return nil, errorOf(it.Error)
+		}
+		return it.Message, nil
+	}
+
+	outMsg, err = r.cm.Generate(ctx, input, opts...)
+	if rErr := r.cassette.record(&Interaction{
+		Key:     key,
+		Request: request,
+		Message: outMsg,
+		Error:   errorString(err),
+	}); rErr != nil && err == nil {
+		err = rErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return outMsg, nil
+}
+
+func (r *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	outStream *schema.StreamReader[*schema.Message], err error) {
+
+	request, key, err := r.requestKey(kindStream, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	it, err := r.cassette.find(key, request)
+	if err != nil {
+		return nil, err
+	}
+
+	needCallbacks := it != nil || !components.IsCallbacksEnabled(r.cm)
+	if needCallbacks {
+		ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfChatModel)
+		ctx = callbacks.OnStart(ctx, r.callbackInput(input, opts...))
+		defer func() {
+			if err != nil {
+				callbacks.OnError(ctx, err)
+			}
+		}()
+	}
+
+	if it != nil {
+		outStream = replayStream(it)
+	} else {
+		sr, err := r.cm.Stream(ctx, input, opts...)
+		if err != nil {
+			if rErr := r.cassette.record(&Interaction{Key: key, Request: request, Error: errorString(err)}); rErr != nil {
+				return nil, errors.Join(err, rErr)
+			}
+			return nil, err
+		}
+		outStream = r.recordStream(key, request, sr)
+	}
+
+	if needCallbacks {
+		_, cbStream := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(outStream,
+			func(msg *schema.Message) (*model.CallbackOutput, error) {
+				return toCallbackOutput(msg), nil
+			}))
+		outStream = schema.StreamReaderWithConvert(cbStream, func(out *model.CallbackOutput) (*schema.Message, error) {
+			return out.Message, nil
+		})
+	}
+	return outStream, nil
+}
+
+// recordStream forwards the chunks of sr, and records them once the stream ends.
+// Nothing is recorded if the stream is closed by the receiver before it ends.
+func (r *ChatModel) recordStream(key string, request []byte, sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	nsr, nsw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			if pe := recover(); pe != nil {
+				_ = nsw.Send(nil, fmt.Errorf("panic error: %v, \nstack: %s", pe, string(debug.Stack())))
+			}
+			sr.Close()
+			nsw.Close()
+		}()
+
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			if err != nil || chunk == nil {
+				if rErr := r.cassette.record(&Interaction{
+					Key:     key,
+					Request: request,
+					Chunks:  chunks,
+					Error:   errorString(err),
+				}); rErr != nil && err == nil {
+					err = rErr
+				}
+				if err != nil {
+					_ = nsw.Send(nil, err)
+				}
+				return
+			}
+
+			chunks = append(chunks, chunk)
+			if closed := nsw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}()
+	return nsr
+}
+
+func replayStream(it *Interaction) *schema.StreamReader[*schema.Message] {
+	sr, sw := schema.Pipe[*schema.Message](len(it.Chunks) + 1)
+	for _, chunk := range it.Chunks {
+		_ = sw.Send(chunk, nil)
+	}
+	if it.Error != "" {
+		_ = sw.Send(nil, errorOf(it.Error))
+	}
+	sw.Close()
+	return sr
+}
+
+func (r *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	nr := &ChatModel{
+		cassette: r.cassette,
+		tools:    tools,
+	}
+	if r.cm != nil {
+		cm, err := r.cm.WithTools(tools)
+		if err != nil {
+			return nil, err
+		}
+		nr.cm = cm
+	}
+	return nr, nil
+}
+
+const typ = "Replay"
+
+// GetType returns the type of the wrapped chat model, or Replay if there is none.
+func (r *ChatModel) GetType() string {
+	if r.cm != nil {
+		if t, ok := components.GetType(r.cm); ok {
+			return t
+		}
+	}
+	return typ
+}
+
+func (r *ChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// chatRequest is the normalized request of a chat model, the fields which vary between runs
+// such as the response meta and the extra of messages are dropped.
+type chatRequest struct {
+	Messages    []*schema.Message  `json:"messages"`
+	Tools       []*toolSchema      `json:"tools,omitempty"`
+	Model       *string            `json:"model,omitempty"`
+	Temperature *float32           `json:"temperature,omitempty"`
+	MaxTokens   *int               `json:"max_tokens,omitempty"`
+	TopP        *float32           `json:"top_p,omitempty"`
+	Stop        []string           `json:"stop,omitempty"`
+	ToolChoice  *schema.ToolChoice `json:"tool_choice,omitempty"`
+}
+
+type toolSchema struct {
+	Name       string `json:"name"`
+	Desc       string `json:"desc,omitempty"`
+	Parameters any    `json:"parameters,omitempty"`
+}
+
+func (r *ChatModel) requestKey(kind interactionKind, input []*schema.Message, opts ...model.Option) ([]byte, string, error) {
+	options := model.GetCommonOptions(&model.Options{Tools: r.tools}, opts...)
+
+	req := &chatRequest{
+		Messages:    make([]*schema.Message, 0, len(input)),
+		Model:       options.Model,
+		Temperature: options.Temperature,
+		MaxTokens:   options.MaxTokens,
+		TopP:        options.TopP,
+		Stop:        options.Stop,
+		ToolChoice:  options.ToolChoice,
+	}
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		m := *msg
+		m.ResponseMeta = nil
+		m.Extra = nil
+		req.Messages = append(req.Messages, &m)
+	}
+	for _, ti := range options.Tools {
+		if ti == nil {
+			continue
+		}
+		ts := &toolSchema{Name: ti.Name, Desc: ti.Desc}
+		if ti.ParamsOneOf != nil {
+			params, err := ti.ParamsOneOf.ToOpenAPIV3()
+			if err != nil {
+				return nil, "", fmt.Errorf("replay: convert parameters of tool %s fail: %w", ti.Name, err)
+			}
+			ts.Parameters = params
+		}
+		req.Tools = append(req.Tools, ts)
+	}
+	return requestKey(kind, req)
+}
+
+func (r *ChatModel) callbackInput(input []*schema.Message, opts ...model.Option) *model.CallbackInput {
+	options := model.GetCommonOptions(&model.Options{Tools: r.tools}, opts...)
+	return &model.CallbackInput{
+		Messages:   input,
+		Tools:      options.Tools,
+		ToolChoice: options.ToolChoice,
+	}
+}
+
+func toCallbackOutput(msg *schema.Message) *model.CallbackOutput {
+	out := &model.CallbackOutput{Message: msg}
+	if msg != nil && msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		out.TokenUsage = &model.TokenUsage{
+			PromptTokens:     msg.ResponseMeta.Usage.PromptTokens,
+			CompletionTokens: msg.ResponseMeta.Usage.CompletionTokens,
+			TotalTokens:      msg.ResponseMeta.Usage.TotalTokens,
+		}
+	}
+	return out
+}
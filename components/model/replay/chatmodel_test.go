@@ -0,0 +1,278 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeChatModel answers with the content of the last input message and a counter,
+// so that a replayed answer can be told from a live one.
+type fakeChatModel struct {
+	calls     int
+	tools     []*schema.ToolInfo
+	streamErr error
+	extra     map[string]any
+}
+
+func (f *fakeChatModel) answer(input []*schema.Message) *schema.Message {
+	f.calls++
+	msg := schema.AssistantMessage(input[len(input)-1].Content+"-"+string(rune('0'+f.calls)), nil)
+	if len(f.tools) > 0 {
+		msg.ToolCalls = []schema.ToolCall{{ID: "call_1", Function: schema.FunctionCall{Name: f.tools[0].Name, Arguments: "{}"}}}
+	}
+	msg.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{TotalTokens: 3}}
+	msg.Extra = f.extra
+	return msg
+}
+
+func (f *fakeChatModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	if input[len(input)-1].Content == "fail" {
+		f.calls++
+		return nil, errors.New("rate limited")
+	}
+	return f.answer(input), nil
+}
+
+func (f *fakeChatModel) Stream(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg := f.answer(input)
+	sr, sw := schema.Pipe[*schema.Message](3)
+	_ = sw.Send(schema.AssistantMessage(msg.Content[:1], nil), nil)
+	_ = sw.Send(schema.AssistantMessage(msg.Content[1:], nil), nil)
+	if f.streamErr != nil {
+		_ = sw.Send(nil, f.streamErr)
+	}
+	sw.Close()
+	return sr, nil
+}
+
+func (f *fakeChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &fakeChatModel{tools: tools}, nil
+}
+
+func (f *fakeChatModel) GetType() string {
+	return "Fake"
+}
+
+func newTestChatModel(t *testing.T, path string, mode Mode, cm model.ToolCallingChatModel) *ChatModel {
+	cassette, err := NewCassette(path, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewChatModel(context.Background(), &ChatModelConfig{ChatModel: cm, Cassette: cassette})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func recvAll(t *testing.T, sr *schema.StreamReader[*schema.Message]) (string, error) {
+	t.Helper()
+	defer sr.Close()
+	var sb strings.Builder
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return sb.String(), err
+		}
+		sb.WriteString(chunk.Content)
+	}
+}
+
+func TestNewChatModel(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewChatModel(ctx, &ChatModelConfig{}); err == nil {
+		t.Fatal("cassette should be required")
+	}
+	cassette, err := NewCassette(filepath.Join(t.TempDir(), "c.json"), ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewChatModel(ctx, &ChatModelConfig{Cassette: cassette}); err == nil {
+		t.Fatal("chat model should be required in record mode")
+	}
+}
+
+func TestChatModelGenerate(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "testdata", "generate.json")
+	tools := []*schema.ToolInfo{{
+		Name: "search",
+		Desc: "search the web",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"query": {Type: schema.String},
+		}),
+	}}
+
+	recorder := newTestChatModel(t, path, ModeRecord, &fakeChatModel{})
+	withTools, err := recorder.WithTools(tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := withTools.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}, model.WithTemperature(0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Content != "hi-1" || len(recorded.ToolCalls) != 1 {
+		t.Fatalf("unexpected message: %v", recorded)
+	}
+	// identical requests are replayed in order
+	if msg, _ := withTools.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}, model.WithTemperature(0.5)); msg.Content != "hi-2" {
+		t.Fatalf("unexpected message: %v", msg)
+	}
+	if _, err = recorder.Generate(ctx, []*schema.Message{schema.UserMessage("fail")}); err == nil {
+		t.Fatal("error should be returned")
+	}
+
+	var started, ended int
+	handler := callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if info.Type == typ && len(model.ConvCallbackInput(input).Tools) == 1 {
+				started++
+			}
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if model.ConvCallbackOutput(output).TokenUsage.TotalTokens == 3 {
+				ended++
+			}
+			return ctx
+		}).Build()
+	cbCtx := callbacks.InitCallbacks(ctx, nil, handler)
+
+	// the chat model is not required for replaying
+	player := newTestChatModel(t, path, ModeReplay, nil)
+	playerWithTools, err := player.WithTools(tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for i := 0; i < 2; i++ {
+		msg, err := playerWithTools.Generate(cbCtx, []*schema.Message{schema.UserMessage("hi")}, model.WithTemperature(0.5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, msg.Content)
+	}
+	if strings.Join(contents, ",") != "hi-1,hi-2" {
+		t.Fatalf("unexpected contents: %v", contents)
+	}
+	// the recorded interactions are not repeated once they are used up
+	if _, err = playerWithTools.Generate(cbCtx, []*schema.Message{schema.UserMessage("hi")}, model.WithTemperature(0.5)); !errors.Is(err, ErrInteractionNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if started != 2 || ended != 2 {
+		t.Fatalf("unexpected callbacks: started=%d, ended=%d", started, ended)
+	}
+	if _, err = player.Generate(ctx, []*schema.Message{schema.UserMessage("fail")}); err == nil || err.Error() != "rate limited" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the messages are matched with the options and tools
+	_, err = playerWithTools.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if !errors.Is(err, ErrInteractionNotFound) || !strings.Contains(err.Error(), `"content":"hi"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = player.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}, model.WithTemperature(0.5)); !errors.Is(err, ErrInteractionNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the response meta and the extra of the input messages are ignored
+	history := []*schema.Message{schema.UserMessage("hi"), recorded, schema.UserMessage("again")}
+	fake := &fakeChatModel{}
+	if _, err = newTestChatModel(t, path, ModeReplayOrRecord, fake).Generate(ctx, history); err != nil {
+		t.Fatal(err)
+	}
+	history[1] = &schema.Message{Role: recorded.Role, Content: recorded.Content, ToolCalls: recorded.ToolCalls,
+		Extra: map[string]any{"request_id": "x"}}
+	mixed := newTestChatModel(t, path, ModeReplayOrRecord, fake)
+	if msg, err := mixed.Generate(ctx, history); err != nil || msg.Content != "again-1" || fake.calls != 1 {
+		t.Fatalf("unexpected result: %v, %v, calls=%d", msg, err, fake.calls)
+	}
+	// recorded by ModeReplayOrRecord when the recorded ones are used up
+	if msg, err := mixed.Generate(ctx, history); err != nil || msg.Content != "again-2" || fake.calls != 2 {
+		t.Fatalf("unexpected result: %v, %v, calls=%d", msg, err, fake.calls)
+	}
+}
+
+func TestChatModelStream(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "stream.json")
+
+	recorder := newTestChatModel(t, path, ModeRecord, &fakeChatModel{})
+	sr, err := recorder.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := recvAll(t, sr); err != nil || content != "hi-1" {
+		t.Fatalf("unexpected result: %s, %v", content, err)
+	}
+
+	broken := newTestChatModel(t, path, ModeReplayOrRecord, &fakeChatModel{streamErr: errors.New("connection reset")})
+	sr, err = broken.Stream(ctx, []*schema.Message{schema.UserMessage("bye")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := recvAll(t, sr); err == nil || content != "bye-1" {
+		t.Fatalf("unexpected result: %s, %v", content, err)
+	}
+
+	player := newTestChatModel(t, path, ModeReplay, nil)
+	sr, err = player.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) != 2 || chunks[0].Content+chunks[1].Content != "hi-1" {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+
+	sr, err = player.Stream(ctx, []*schema.Message{schema.UserMessage("bye")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := recvAll(t, sr); err == nil || err.Error() != "connection reset" || content != "bye-1" {
+		t.Fatalf("unexpected result: %s, %v", content, err)
+	}
+
+	// generate and stream are recorded separately
+	if _, err = player.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}); !errors.Is(err, ErrInteractionNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
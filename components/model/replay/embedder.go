@@ -0,0 +1,123 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+var _ embedding.Embedder = (*Embedder)(nil)
+
+type EmbedderConfig struct {
+	// Embedder is the embedder to record
+	// Required in ModeRecord and ModeReplayOrRecord, ignored in ModeReplay
+	Embedder embedding.Embedder
+	// Cassette stores the recorded interactions
+	// Required
+	Cassette *Cassette
+}
+
+// Embedder records the requests and responses of the wrapped embedder to a cassette,
+// and serves them back with no network when replaying.
+type Embedder struct {
+	embedder embedding.Embedder
+	cassette *Cassette
+}
+
+func NewEmbedder(_ context.Context, config *EmbedderConfig) (*Embedder, error) {
+	if config == nil || config.Cassette == nil {
+		return nil, errors.New("replay: cassette is required")
+	}
+	if config.Embedder == nil && config.Cassette.Mode() != ModeReplay {
+		return nil, fmt.Errorf("replay: embedder is required in mode %s", config.Cassette.Mode())
+	}
+	return &Embedder{
+		embedder: config.Embedder,
+		cassette: config.Cassette,
+	}, nil
+}
+
+// embedRequest is the normalized request of an embedder.
+type embedRequest struct {
+	Texts []string `json:"texts"`
+	Model *string  `json:"model,omitempty"`
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) (embeddings [][]float64, err error) {
+	options := embedding.GetCommonOptions(nil, opts...)
+	request, key, err := requestKey(kindEmbed, &embedRequest{Texts: texts, Model: options.Model})
+	if err != nil {
+		return nil, err
+	}
+	it, err := e.cassette.find(key, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if it != nil || !components.IsCallbacksEnabled(e.embedder) {
+		ctx = callbacks.EnsureRunInfo(ctx, e.GetType(), components.ComponentOfEmbedding)
+		ctx = callbacks.OnStart(ctx, &embedding.CallbackInput{Texts: texts})
+		defer func() {
+			if err != nil {
+				callbacks.OnError(ctx, err)
+				return
+			}
+			callbacks.OnEnd(ctx, &embedding.CallbackOutput{Embeddings: embeddings})
+		}()
+	}
+
+	if it != nil {
+		if it.Error != "" {
+			return nil, errorOf(it.Error)
+		}
+		return it.Embeddings, nil
+	}
+
+	embeddings, err = e.embedder.EmbedStrings(ctx, texts, opts...)
+	if rErr := e.cassette.record(&Interaction{
+		Key:        key,
+		Request:    request,
+		Embeddings: embeddings,
+		Error:      errorString(err),
+	}); rErr != nil && err == nil {
+		err = rErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+// GetType returns the type of the wrapped embedder, or Replay if there is none.
+func (e *Embedder) GetType() string {
+	if e.embedder != nil {
+		if t, ok := components.GetType(e.embedder); ok {
+			return t
+		}
+	}
+	return typ
+}
+
+func (e *Embedder) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+type fakeEmbedder struct {
+	calls int
+}
+
+func (f *fakeEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	f.calls++
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embeddings[i] = []float64{float64(len(text)), float64(f.calls)}
+	}
+	return embeddings, nil
+}
+
+func TestEmbedder(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "embed.json")
+
+	cassette, err := NewCassette(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder, err := NewEmbedder(ctx, &EmbedderConfig{Embedder: &fakeEmbedder{}, Cassette: cassette})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = recorder.EmbedStrings(ctx, []string{"a", "bb"}, embedding.WithModel("m1")); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette, err = NewCassette(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player, err := NewEmbedder(ctx, &EmbedderConfig{Cassette: cassette})
+	if err != nil {
+		t.Fatal(err)
+	}
+	embeddings, err := player.EmbedStrings(ctx, []string{"a", "bb"}, embedding.WithModel("m1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embeddings) != 2 || embeddings[1][0] != 2 || embeddings[1][1] != 1 {
+		t.Fatalf("unexpected embeddings: %v", embeddings)
+	}
+	if _, err = player.EmbedStrings(ctx, []string{"a", "bb"}, embedding.WithModel("m2")); !errors.Is(err, ErrInteractionNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewCassette(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewCassette("", ModeReplay); err == nil {
+		t.Fatal("path should be required")
+	}
+	if _, err := NewCassette(filepath.Join(dir, "c.json"), "unknown"); err == nil {
+		t.Fatal("mode should be checked")
+	}
+	if _, err := NewCassette(filepath.Join(dir, "missing.json"), ModeReplay); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c, err := NewCassette(filepath.Join(dir, "missing.json"), ModeReplayOrRecord); err != nil || len(c.Interactions()) != 0 {
+		t.Fatalf("unexpected result: %v", err)
+	}
+
+	path := filepath.Join(dir, "v0.json")
+	if err := os.WriteFile(path, []byte(`{"version":0,"interactions":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCassette(path, ModeReplay); err == nil {
+		t.Fatal("version should be checked")
+	}
+}
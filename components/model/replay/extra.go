@@ -0,0 +1,186 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+var (
+	extraTypesMu sync.RWMutex
+	extraTypes   = map[string]reflect.Type{}
+	extraNames   = map[reflect.Type]string{}
+)
+
+func init() {
+	_ = RegisterExtraType[string]("string")
+	_ = RegisterExtraType[bool]("bool")
+	_ = RegisterExtraType[int]("int")
+	_ = RegisterExtraType[int32]("int32")
+	_ = RegisterExtraType[int64]("int64")
+	_ = RegisterExtraType[float32]("float32")
+	_ = RegisterExtraType[float64]("float64")
+	_ = RegisterExtraType[[]string]("[]string")
+	_ = RegisterExtraType[[]any]("[]any")
+	_ = RegisterExtraType[map[string]any]("map[string]any")
+}
+
+// RegisterExtraType registers the type of the values in Message.Extra under name, which is the type tag in the cassette.
+// The values are persisted as JSON with the tag, and restored as T on replay, e.g.
+//
+//	_ = replay.RegisterExtraType[*claude.CacheUsage]("claude_cache_usage")
+//
+// The primitive types, []string, []any and map[string]any are registered by default.
+// Recording a message with an extra value of an unregistered type fails, since it could not be restored.
+func RegisterExtraType[T any](name string) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	extraTypesMu.Lock()
+	defer extraTypesMu.Unlock()
+
+	if nt, ok := extraTypes[name]; ok {
+		return fmt.Errorf("replay: extra type name %s already registered to %s", name, nt)
+	}
+	if nn, ok := extraNames[t]; ok {
+		return fmt.Errorf("replay: extra type %s already registered as %s", t, nn)
+	}
+	extraTypes[name] = t
+	extraNames[t] = name
+	return nil
+}
+
+// taggedValue is an extra value persisted with the name of its registered type.
+type taggedValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// taggedMessage is a message persisted with the tagged extra values, the Extra field shadows the one of schema.Message.
+type taggedMessage struct {
+	*schema.Message
+	Extra map[string]*taggedValue `json:"extra,omitempty"`
+}
+
+func tagMessage(msg *schema.Message) (*taggedMessage, error) {
+	if msg == nil {
+		return nil, nil
+	}
+	tm := &taggedMessage{Message: msg}
+	if len(msg.Extra) == 0 {
+		return tm, nil
+	}
+
+	extraTypesMu.RLock()
+	defer extraTypesMu.RUnlock()
+
+	tm.Extra = make(map[string]*taggedValue, len(msg.Extra))
+	for k, v := range msg.Extra {
+		if v == nil {
+			continue
+		}
+		name, ok := extraNames[reflect.TypeOf(v)]
+		if !ok {
+			return nil, fmt.Errorf("replay: type %T of extra %s is not registered, register it with RegisterExtraType", v, k)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("replay: marshal extra %s fail: %w", k, err)
+		}
+		tm.Extra[k] = &taggedValue{Type: name, Value: data}
+	}
+	return tm, nil
+}
+
+func untagMessage(tm *taggedMessage) (*schema.Message, error) {
+	if tm == nil || tm.Message == nil {
+		return nil, nil
+	}
+	msg := tm.Message
+	msg.Extra = nil
+	if len(tm.Extra) == 0 {
+		return msg, nil
+	}
+
+	extraTypesMu.RLock()
+	defer extraTypesMu.RUnlock()
+
+	msg.Extra = make(map[string]any, len(tm.Extra))
+	for k, tv := range tm.Extra {
+		t, ok := extraTypes[tv.Type]
+		if !ok {
+			return nil, fmt.Errorf("replay: type %s of extra %s is not registered, register it with RegisterExtraType", tv.Type, k)
+		}
+		v := reflect.New(t)
+		if err := json.Unmarshal(tv.Value, v.Interface()); err != nil {
+			return nil, fmt.Errorf("replay: unmarshal extra %s fail: %w", k, err)
+		}
+		msg.Extra[k] = v.Elem().Interface()
+	}
+	return msg, nil
+}
+
+// interactionJSON is the persisted form of Interaction.
+type interactionJSON struct {
+	*interactionAlias
+	Message *taggedMessage   `json:"message,omitempty"`
+	Chunks  []*taggedMessage `json:"chunks,omitempty"`
+}
+
+type interactionAlias Interaction
+
+// MarshalJSON persists the extra values of the messages with their type tags.
+func (it *Interaction) MarshalJSON() ([]byte, error) {
+	out := &interactionJSON{interactionAlias: (*interactionAlias)(it)}
+	var err error
+	if out.Message, err = tagMessage(it.Message); err != nil {
+		return nil, err
+	}
+	for _, chunk := range it.Chunks {
+		tm, err := tagMessage(chunk)
+		if err != nil {
+			return nil, err
+		}
+		out.Chunks = append(out.Chunks, tm)
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON restores the extra values of the messages as their registered types.
+func (it *Interaction) UnmarshalJSON(data []byte) error {
+	in := &interactionJSON{interactionAlias: (*interactionAlias)(it)}
+	if err := json.Unmarshal(data, in); err != nil {
+		return err
+	}
+	var err error
+	if it.Message, err = untagMessage(in.Message); err != nil {
+		return err
+	}
+	it.Chunks = nil
+	for _, tm := range in.Chunks {
+		msg, err := untagMessage(tm)
+		if err != nil {
+			return err
+		}
+		it.Chunks = append(it.Chunks, msg)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type testUsage struct {
+	Cached int `json:"cached"`
+}
+
+type testResponseID string
+
+type testUnregistered struct{}
+
+func init() {
+	_ = RegisterExtraType[*testUsage]("test_usage")
+	_ = RegisterExtraType[testResponseID]("test_response_id")
+}
+
+func TestRegisterExtraType(t *testing.T) {
+	if err := RegisterExtraType[int]("my_int"); err == nil {
+		t.Fatal("registering a type twice should fail")
+	}
+	if err := RegisterExtraType[uint8]("string"); err == nil {
+		t.Fatal("registering a name twice should fail")
+	}
+}
+
+func TestExtraRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "extra.json")
+	extra := map[string]any{
+		"usage":     &testUsage{Cached: 3},
+		"id":        testResponseID("resp_1"),
+		"reasoning": "think",
+		"count":     2,
+		"nil":       nil,
+	}
+
+	recorder := newTestChatModel(t, path, ModeRecord, &fakeChatModel{extra: extra})
+	if _, err := recorder.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	sr, err := recorder.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = recvAll(t, sr); err != nil {
+		t.Fatal(err)
+	}
+
+	player := newTestChatModel(t, path, ModeReplay, nil)
+	msg, err := player.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(extra, "nil")
+	if !reflect.DeepEqual(msg.Extra, extra) {
+		t.Fatalf("unexpected extra: %#v", msg.Extra)
+	}
+
+	// values of unregistered types are rejected when recording
+	unregistered := newTestChatModel(t, filepath.Join(t.TempDir(), "unregistered.json"), ModeRecord,
+		&fakeChatModel{extra: map[string]any{"x": testUnregistered{}}})
+	_, err = unregistered.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if err == nil || !strings.Contains(err.Error(), "replay.testUnregistered of extra x is not registered") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseBackoff = 200 * time.Millisecond
+	defaultMaxBackoff  = 10 * time.Second
+)
+
+// DefaultBackoff doubles the wait from 200ms for every retry up to 10s, with a random jitter of up to 20%.
+func DefaultBackoff(n int) time.Duration {
+	if n < 1 {
+		n = 1
+	}
+	d := defaultMaxBackoff
+	if n <= 16 {
+		d = min(defaultBaseBackoff<<(n-1), defaultMaxBackoff)
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+var (
+	statusCodeFieldNames = []string{"HTTPStatusCode", "StatusCode", "Status", "Code"}
+	statusCodePattern    = regexp.MustCompile(`(?i)(?:status(?:[ _]?code)?|http)[^0-9]{0,3}(\d{3})\b`)
+	retryableMessages    = []string{
+		"rate limit", "too many requests", "overloaded", "server error", "service unavailable",
+		"bad gateway", "gateway timeout", "timeout", "timed out", "connection reset", "connection refused",
+		"unexpected eof",
+	}
+)
+
+// IsRetryableError reports whether err is likely transient, that is rate limiting (429), request timeout (408),
+// server errors (5xx), network timeouts and connection failures.
+// The status code is extracted from the HTTPStatusCode or StatusCode field of the errors in the chain,
+// which covers the error types of the common model SDKs, or from the error message as a fallback.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	if code, ok := statusCodeOf(err); ok {
+		return isRetryableStatusCode(code)
+	}
+
+	msg := strings.ToLower(err.Error())
+	if matches := statusCodePattern.FindStringSubmatch(msg); len(matches) == 2 {
+		if code, _ := strconv.Atoi(matches[1]); code >= 400 {
+			return isRetryableStatusCode(code)
+		}
+	}
+	for _, m := range retryableMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRetryableStatusCode(code int) bool {
+	return code == 408 || code == 429 || code >= 500
+}
+
+// statusCodeOf looks for an integer field holding an HTTP status code in the error chain.
+func statusCodeOf(err error) (int, bool) {
+	queue := []error{err}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		if e == nil {
+			continue
+		}
+
+		if code, ok := statusCodeField(e); ok {
+			return code, true
+		}
+
+		switch u := e.(type) {
+		case interface{ Unwrap() error }:
+			queue = append(queue, u.Unwrap())
+		case interface{ Unwrap() []error }:
+			queue = append(queue, u.Unwrap()...)
+		}
+	}
+	return 0, false
+}
+
+func statusCodeField(err error) (int, bool) {
+	v := reflect.ValueOf(err)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return 0, false
+	}
+	for _, name := range statusCodeFieldNames {
+		f := v.FieldByName(name)
+		if !f.IsValid() || !f.CanInt() {
+			continue
+		}
+		if code := int(f.Int()); code >= 100 && code < 600 {
+			return code, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,452 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+var _ model.ToolCallingChatModel = (*ChatModel)(nil)
+
+// ErrNoAvailableModel is returned when the circuit breakers of all models are open.
+var ErrNoAvailableModel = errors.New("no available model, circuit breakers of all models are open")
+
+type Strategy string
+
+const (
+	// StrategyFailover tries the models in the order of Config.Models.
+	StrategyFailover Strategy = "failover"
+	// StrategyWeightedRoundRobin starts from a model chosen by smooth weighted round-robin,
+	// and fails over to the others in the order of Config.Models.
+	StrategyWeightedRoundRobin Strategy = "weighted_round_robin"
+)
+
+// Model is a member chat model of the router.
+type Model struct {
+	// Name identifies the model in errors and callbacks
+	// Optional. Default: the type of the chat model
+	Name string
+	// Model is the chat model to call
+	// Required
+	Model model.ToolCallingChatModel
+	// Weight is the weight of the model in StrategyWeightedRoundRobin
+	// Optional. Default: 1
+	Weight int
+}
+
+type Config struct {
+	// Models are the chat models to route requests to
+	// Required
+	Models []*Model
+
+	// Strategy decides the order in which the models are tried
+	// Optional. Default: StrategyFailover
+	Strategy Strategy
+
+	// MaxRetries is the max number of retries on the same model when a retryable error occurs,
+	// the request fails over to the next model once the retries are exhausted or the error is not retryable.
+	// Optional. Default: 0
+	MaxRetries int
+
+	// Backoff returns the duration to wait before the n-th retry on the same model, n starts from 1
+	// Optional. Default: DefaultBackoff
+	Backoff func(n int) time.Duration
+
+	// IsRetryable classifies the errors returned by models.
+	// Only retryable errors are retried and counted by the circuit breaker.
+	// Optional. Default: IsRetryableError
+	IsRetryable func(err error) bool
+
+	// FailureThreshold is the number of consecutive retryable failures which opens the circuit breaker of a model,
+	// the model is skipped while its circuit breaker is open. Set a negative value to disable circuit breaking.
+	// Optional. Default: 5
+	FailureThreshold int
+
+	// OpenTimeout is the duration that a circuit breaker stays open,
+	// after which a single probe request is let through to decide whether to close it.
+	// Optional. Default: 30s
+	OpenTimeout time.Duration
+}
+
+type member struct {
+	name    string
+	cm      model.ToolCallingChatModel
+	weight  int
+	breaker *circuitBreaker
+}
+
+// ChatModel routes requests to a group of chat models with failover, load balancing,
+// retry and circuit breaking.
+type ChatModel struct {
+	members     []*member
+	strategy    Strategy
+	maxRetries  int
+	backoff     func(n int) time.Duration
+	isRetryable func(err error) bool
+
+	// shared by the chat models derived by WithTools, since they share the same underlying models
+	balancer *weightedRoundRobin
+}
+
+func NewChatModel(_ context.Context, config *Config) (*ChatModel, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	if len(config.Models) == 0 {
+		return nil, errors.New("at least one model is required")
+	}
+
+	strategy := config.Strategy
+	if strategy == "" {
+		strategy = StrategyFailover
+	}
+	if strategy != StrategyFailover && strategy != StrategyWeightedRoundRobin {
+		return nil, fmt.Errorf("unknown strategy: %s", strategy)
+	}
+	if config.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative, given=%d", config.MaxRetries)
+	}
+	backoff := config.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+	isRetryable := config.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsRetryableError
+	}
+	threshold := config.FailureThreshold
+	if threshold == 0 {
+		threshold = defaultFailureThreshold
+	}
+	openTimeout := config.OpenTimeout
+	if openTimeout <= 0 {
+		openTimeout = defaultOpenTimeout
+	}
+
+	members := make([]*member, 0, len(config.Models))
+	weights := make([]int, 0, len(config.Models))
+	for i, m := range config.Models {
+		if m == nil || m.Model == nil {
+			return nil, fmt.Errorf("model at index %d is nil", i)
+		}
+		if m.Weight < 0 {
+			return nil, fmt.Errorf("weight of model at index %d must not be negative, given=%d", i, m.Weight)
+		}
+		name := m.Name
+		if name == "" {
+			name, _ = components.GetType(m.Model)
+		}
+		if name == "" {
+			name = fmt.Sprintf("model_%d", i)
+		}
+		weight := m.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		members = append(members, &member{
+			name:    name,
+			cm:      m.Model,
+			weight:  weight,
+			breaker: newCircuitBreaker(threshold, openTimeout),
+		})
+		weights = append(weights, weight)
+	}
+
+	return &ChatModel{
+		members:     members,
+		strategy:    strategy,
+		maxRetries:  config.MaxRetries,
+		backoff:     backoff,
+		isRetryable: isRetryable,
+		balancer:    newWeightedRoundRobin(weights),
+	}, nil
+}
+
+func (r *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	var outMsg *schema.Message
+	err := r.do(ctx, func(ctx context.Context, m *member) (err error) {
+		outMsg, err = generate(ctx, m, input, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outMsg, nil
+}
+
+// Stream fails over only before the first chunk is received, the errors after that are returned by the stream,
+// since the chunks that have been received cannot be withdrawn.
+func (r *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	var outStream *schema.StreamReader[*schema.Message]
+	err := r.do(ctx, func(ctx context.Context, m *member) error {
+		sr, err := stream(ctx, m, input, opts...)
+		if err != nil {
+			return err
+		}
+		first, err := sr.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			sr.Close()
+			return err
+		}
+		if errors.Is(err, io.EOF) {
+			sr.Close()
+			outStream = schema.StreamReaderFromArray([]*schema.Message{})
+			return nil
+		}
+		outStream = prependChunk(first, sr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outStream, nil
+}
+
+// WithTools binds the tools to every model of the router, the returned chat model shares
+// the circuit breakers and the load balancing state with the current one.
+func (r *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	members := make([]*member, 0, len(r.members))
+	for _, m := range r.members {
+		cm, err := m.cm.WithTools(tools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind tools to model %s: %w", m.name, err)
+		}
+		nm := *m
+		nm.cm = cm
+		members = append(members, &nm)
+	}
+
+	nr := *r
+	nr.members = members
+	return &nr, nil
+}
+
+const typ = "Router"
+
+func (r *ChatModel) GetType() string {
+	return typ
+}
+
+// IsCallbacksEnabled returns true, the callbacks are triggered by every attempt on the member models
+// with their own names and types, rather than once for the router.
+func (r *ChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (r *ChatModel) do(ctx context.Context, call func(ctx context.Context, m *member) error) error {
+	var errs []error
+	tried := false
+	for _, m := range r.order() {
+		if !m.breaker.allow() {
+			continue
+		}
+		tried = true
+
+		err := r.callWithRetry(ctx, m, call)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("model %s: %w", m.name, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if !tried {
+		return ErrNoAvailableModel
+	}
+	return fmt.Errorf("all models failed: %w", errors.Join(errs...))
+}
+
+func (r *ChatModel) callWithRetry(ctx context.Context, m *member, call func(ctx context.Context, m *member) error) error {
+	for n := 0; ; n++ {
+		if n > 0 {
+			if err := sleep(ctx, r.backoff(n)); err != nil {
+				return err
+			}
+			// another request may have opened the circuit breaker during the backoff
+			if !m.breaker.allow() {
+				return errors.New("circuit breaker is open")
+			}
+		}
+
+		err := call(ctx, m)
+		if err == nil {
+			m.breaker.onSuccess()
+			return nil
+		}
+		if ctx.Err() != nil || !r.isRetryable(err) {
+			// not the fault of the model, keep the breaker state unchanged
+			m.breaker.onIgnored()
+			return err
+		}
+		m.breaker.onFailure()
+		if n >= r.maxRetries {
+			return err
+		}
+	}
+}
+
+// order returns the models in the order to be tried for a request.
+func (r *ChatModel) order() []*member {
+	if r.strategy != StrategyWeightedRoundRobin || len(r.members) == 1 {
+		return r.members
+	}
+	start := r.balancer.next()
+	ordered := make([]*member, 0, len(r.members))
+	ordered = append(ordered, r.members[start])
+	for i, m := range r.members {
+		if i != start {
+			ordered = append(ordered, m)
+		}
+	}
+	return ordered
+}
+
+func (m *member) runInfo() *callbacks.RunInfo {
+	t, _ := components.GetType(m.cm)
+	return &callbacks.RunInfo{
+		Name:      m.name,
+		Type:      t,
+		Component: components.ComponentOfChatModel,
+	}
+}
+
+func generate(ctx context.Context, m *member, input []*schema.Message, opts ...model.Option) (outMsg *schema.Message, err error) {
+	ctx = callbacks.ReuseHandlers(ctx, m.runInfo())
+	if components.IsCallbacksEnabled(m.cm) {
+		return m.cm.Generate(ctx, input, opts...)
+	}
+
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input})
+	outMsg, err = m.cm.Generate(ctx, input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, &model.CallbackOutput{Message: outMsg})
+	return outMsg, nil
+}
+
+func stream(ctx context.Context, m *member, input []*schema.Message, opts ...model.Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	ctx = callbacks.ReuseHandlers(ctx, m.runInfo())
+	if components.IsCallbacksEnabled(m.cm) {
+		return m.cm.Stream(ctx, input, opts...)
+	}
+
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input})
+	sr, err := m.cm.Stream(ctx, input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	_, sr = callbacks.OnEndWithStreamOutput(ctx, sr)
+	return sr, nil
+}
+
+// prependChunk returns a stream which yields the first chunk and then the rest of sr.
+func prependChunk(first *schema.Message, sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	nsr, nsw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			if pe := recover(); pe != nil {
+				_ = nsw.Send(nil, fmt.Errorf("panic error: %v, \nstack: %s", pe, string(debug.Stack())))
+			}
+			sr.Close()
+			nsw.Close()
+		}()
+
+		if closed := nsw.Send(first, nil); closed {
+			return
+		}
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if closed := nsw.Send(chunk, err); closed || err != nil {
+				return
+			}
+		}
+	}()
+	return nsr
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// weightedRoundRobin is the smooth weighted round-robin used by nginx,
+// which spreads the picks of a heavy model instead of picking it consecutively.
+type weightedRoundRobin struct {
+	mu      sync.Mutex
+	weights []int
+	current []int
+	total   int
+}
+
+func newWeightedRoundRobin(weights []int) *weightedRoundRobin {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	return &weightedRoundRobin{
+		weights: weights,
+		current: make([]int, len(weights)),
+		total:   total,
+	}
+}
+
+func (w *weightedRoundRobin) next() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	best := 0
+	for i := range w.weights {
+		w.current[i] += w.weights[i]
+		if w.current[i] > w.current[best] {
+			best = i
+		}
+	}
+	w.current[best] -= w.total
+	return best
+}
@@ -0,0 +1,438 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type statusError struct {
+	HTTPStatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("error, status code: %d", e.HTTPStatusCode)
+}
+
+// fakeModel returns the errors in order, and succeeds with its name once they are used up.
+type fakeModel struct {
+	name string
+
+	mu     sync.Mutex
+	errs   []error
+	calls  int
+	tools  []*schema.ToolInfo
+	chunks []string
+	// streamErr is returned by the stream after the chunks
+	streamErr error
+}
+
+func (f *fakeModel) nextErr() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeModel) Generate(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	if err := f.nextErr(); err != nil {
+		return nil, err
+	}
+	return schema.AssistantMessage(f.name, nil), nil
+}
+
+func (f *fakeModel) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := f.nextErr(); err != nil {
+		// fail on the first chunk like the real models
+		sr, sw := schema.Pipe[*schema.Message](1)
+		_ = sw.Send(nil, err)
+		sw.Close()
+		return sr, nil
+	}
+	sr, sw := schema.Pipe[*schema.Message](len(f.chunks) + 1)
+	for _, c := range f.chunks {
+		_ = sw.Send(schema.AssistantMessage(c, nil), nil)
+	}
+	if f.streamErr != nil {
+		_ = sw.Send(nil, f.streamErr)
+	}
+	sw.Close()
+	return sr, nil
+}
+
+func (f *fakeModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &fakeModel{name: f.name, tools: tools, chunks: f.chunks}, nil
+}
+
+func (f *fakeModel) GetType() string {
+	return "Fake"
+}
+
+func noBackoff(int) time.Duration {
+	return 0
+}
+
+func TestNewChatModel(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		config *Config
+		errMsg string
+	}{
+		{nil, "config is required"},
+		{&Config{}, "at least one model is required"},
+		{&Config{Models: []*Model{{}}}, "model at index 0 is nil"},
+		{&Config{Models: []*Model{{Model: &fakeModel{}}}, Strategy: "random"}, "unknown strategy"},
+		{&Config{Models: []*Model{{Model: &fakeModel{}, Weight: -1}}}, "weight of model at index 0 must not be negative"},
+	}
+	for _, c := range cases {
+		_, err := NewChatModel(ctx, c.config)
+		if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+			t.Fatalf("unexpected error, given=%v, expected=%s", err, c.errMsg)
+		}
+	}
+
+	r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: &fakeModel{}}, {Name: "b", Model: &fakeModel{}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.members[0].name != "Fake" || r.members[1].name != "b" || r.members[0].weight != 1 {
+		t.Fatalf("unexpected members: %v, %v", r.members[0], r.members[1])
+	}
+}
+
+func TestFailoverAndRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retry then failover", func(t *testing.T) {
+		a := &fakeModel{name: "a", errs: []error{&statusError{429}, &statusError{503}, &statusError{500}}}
+		b := &fakeModel{name: "b"}
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: a}, {Model: b}}, MaxRetries: 2, Backoff: noBackoff})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Content != "b" || a.calls != 3 || b.calls != 1 {
+			t.Fatalf("unexpected result: %s, calls a=%d b=%d", msg.Content, a.calls, b.calls)
+		}
+	})
+
+	t.Run("non retryable error fails over without retry", func(t *testing.T) {
+		a := &fakeModel{name: "a", errs: []error{&statusError{400}}}
+		b := &fakeModel{name: "b"}
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: a}, {Model: b}}, MaxRetries: 2, Backoff: noBackoff})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Content != "b" || a.calls != 1 {
+			t.Fatalf("unexpected result: %s, calls a=%d", msg.Content, a.calls)
+		}
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		errA, errB := &statusError{500}, errors.New("invalid api key")
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{
+			{Name: "a", Model: &fakeModel{errs: []error{errA}}},
+			{Name: "b", Model: &fakeModel{errs: []error{errB}}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err == nil || !errors.Is(err, errA) || !errors.Is(err, errB) || !strings.Contains(err.Error(), "model b") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("context canceled during backoff", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		a := &fakeModel{name: "a", errs: []error{&statusError{503}}}
+		b := &fakeModel{name: "b"}
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: a}, {Model: b}}, MaxRetries: 1,
+			Backoff: func(int) time.Duration {
+				cancel()
+				return time.Minute
+			}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.Generate(cctx, []*schema.Message{schema.UserMessage("hi")})
+		if !errors.Is(err, context.Canceled) || b.calls != 0 {
+			t.Fatalf("unexpected result: %v, calls b=%d", err, b.calls)
+		}
+	})
+}
+
+func TestWeightedRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	a, b := &fakeModel{name: "a"}, &fakeModel{name: "b"}
+	r, err := NewChatModel(ctx, &Config{
+		Models:   []*Model{{Model: a, Weight: 2}, {Model: b}},
+		Strategy: StrategyWeightedRoundRobin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var picks []string
+	for i := 0; i < 6; i++ {
+		msg, err := r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		picks = append(picks, msg.Content)
+	}
+	if strings.Join(picks, "") != "abaaba" {
+		t.Fatalf("unexpected picks: %v", picks)
+	}
+
+	// fails over to the other model when the picked one fails
+	b.errs = []error{&statusError{502}}
+	var contents []string
+	for i := 0; i < 3; i++ {
+		msg, err := r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, msg.Content)
+	}
+	if strings.Join(contents, "") != "aaa" {
+		t.Fatalf("unexpected contents: %v", contents)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	a := &fakeModel{name: "a", errs: []error{&statusError{503}, &statusError{503}, &statusError{503}}}
+	b := &fakeModel{name: "b"}
+	r, err := NewChatModel(ctx, &Config{
+		Models:           []*Model{{Model: a}, {Model: b}},
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generate := func() string {
+		msg, err := r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg.Content
+	}
+
+	generate()
+	generate()
+	// the breaker of a is open
+	if c := generate(); c != "b" || a.calls != 2 {
+		t.Fatalf("unexpected result: %s, calls a=%d", c, a.calls)
+	}
+
+	// the probe fails and opens the breaker again
+	now = now.Add(time.Minute)
+	generate()
+	if c := generate(); c != "b" || a.calls != 3 {
+		t.Fatalf("unexpected result: %s, calls a=%d", c, a.calls)
+	}
+
+	// the probe succeeds and closes the breaker
+	now = now.Add(time.Minute)
+	if c := generate(); c != "a" || a.calls != 4 {
+		t.Fatalf("unexpected result: %s, calls a=%d", c, a.calls)
+	}
+
+	// non retryable errors do not open the breaker
+	a.errs = []error{&statusError{400}, &statusError{400}, &statusError{400}}
+	generate()
+	generate()
+	generate()
+	if c := generate(); c != "a" {
+		t.Fatalf("unexpected result: %s", c)
+	}
+
+	single, err := NewChatModel(ctx, &Config{
+		Models:           []*Model{{Model: &fakeModel{errs: []error{&statusError{503}}}}},
+		FailureThreshold: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = single.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	if _, err = single.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}); !errors.Is(err, ErrNoAvailableModel) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	recvAll := func(sr *schema.StreamReader[*schema.Message]) (string, error) {
+		defer sr.Close()
+		var sb strings.Builder
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return sb.String(), nil
+			}
+			if err != nil {
+				return sb.String(), err
+			}
+			sb.WriteString(chunk.Content)
+		}
+	}
+
+	t.Run("failover before first chunk", func(t *testing.T) {
+		a := &fakeModel{name: "a", errs: []error{&statusError{429}}, chunks: []string{"a1", "a2"}}
+		b := &fakeModel{name: "b", chunks: []string{"b1", "b2"}}
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: a}, {Model: b}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr, err := r.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := recvAll(sr)
+		if err != nil || content != "b1b2" {
+			t.Fatalf("unexpected result: %s, %v", content, err)
+		}
+	})
+
+	t.Run("no failover after first chunk", func(t *testing.T) {
+		streamErr := &statusError{500}
+		a := &fakeModel{name: "a", chunks: []string{"a1"}, streamErr: streamErr}
+		b := &fakeModel{name: "b", chunks: []string{"b1"}}
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: a}, {Model: b}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr, err := r.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := recvAll(sr)
+		if !errors.Is(err, streamErr) || content != "a1" || b.calls != 0 {
+			t.Fatalf("unexpected result: %s, %v, calls b=%d", content, err, b.calls)
+		}
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: &fakeModel{}}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr, err := r.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content, err := recvAll(sr); err != nil || content != "" {
+			t.Fatalf("unexpected result: %s, %v", content, err)
+		}
+	})
+}
+
+func TestWithTools(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewChatModel(ctx, &Config{Models: []*Model{{Model: &fakeModel{name: "a"}}, {Model: &fakeModel{name: "b"}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools := []*schema.ToolInfo{{Name: "search"}}
+	ncm, err := r.WithTools(tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nr := ncm.(*ChatModel)
+	for i, m := range nr.members {
+		if got := m.cm.(*fakeModel).tools; len(got) != 1 || got[0].Name != "search" {
+			t.Fatalf("tools are not bound to model %d", i)
+		}
+		if len(r.members[i].cm.(*fakeModel).tools) != 0 {
+			t.Fatalf("tools of the original model %d are changed", i)
+		}
+		if m.breaker != r.members[i].breaker {
+			t.Fatalf("circuit breaker of model %d is not shared", i)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryableError(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), true},
+		{timeoutError{}, true},
+		{&statusError{429}, true},
+		{&statusError{408}, true},
+		{&statusError{503}, true},
+		{&statusError{400}, false},
+		{fmt.Errorf("create chat completion fail: %w", &statusError{401}), false},
+		{errors.Join(errors.New("x"), &statusError{502}), true},
+		{errors.New("POST \"https://api.openai.com/v1/responses\": 429 Too Many Requests"), true},
+		{errors.New("error, status code: 500, message: internal error"), true},
+		{errors.New("error, status code: 404, message: model not found"), false},
+		{errors.New("the server is overloaded"), true},
+		{errors.New("invalid argument"), false},
+	}
+	for _, c := range cases {
+		if got := IsRetryableError(c.err); got != c.retryable {
+			t.Fatalf("unexpected result of %v, given=%v, expected=%v", c.err, got, c.retryable)
+		}
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	if d := DefaultBackoff(1); d < defaultBaseBackoff || d > defaultBaseBackoff*6/5 {
+		t.Fatalf("unexpected backoff: %v", d)
+	}
+	if d := DefaultBackoff(100); d < defaultMaxBackoff || d > defaultMaxBackoff*6/5 {
+		t.Fatalf("unexpected backoff: %v", d)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+const typ = "Ensemble"
+
+type FusionMode string
+
+const (
+	// FusionRRF scores a document by reciprocal rank fusion, sum(weight / (k + rank)) over the members returning it,
+	// which only depends on the ranks, so that the members with incomparable scores (e.g. BM25 and cosine) can be mixed.
+	FusionRRF FusionMode = "rrf"
+	// FusionScore scores a document by sum(weight * normalized score) over the members returning it,
+	// the scores of each member are min-max normalized to [0, 1]. Every member should return the scores
+	// where higher is better, e.g. similarity rather than distance.
+	FusionScore FusionMode = "score"
+)
+
+const defaultRRFK = 60
+
+// Member is a retriever queried by the ensemble.
+type Member struct {
+	// Name identifies the member in errors, callbacks and the sources of the documents
+	// Optional. Default: the type of the retriever
+	Name string
+	// Retriever is the retriever to query
+	// Required
+	Retriever retriever.Retriever
+	// Weight is the weight of the member in fusion
+	// Optional. Default: 1
+	Weight float64
+	// Timeout limits the time of a single Retrieve of the member, the member exceeding it is treated as failed
+	// Optional. Default: no timeout
+	Timeout time.Duration
+	// Options are passed to the member after the options of Retrieve, e.g. retriever.WithTopK, es8.WithFilters
+	// Optional.
+	Options []retriever.Option
+}
+
+type Config struct {
+	// Retrievers are queried concurrently
+	// Required
+	Retrievers []*Member
+	// FusionMode is the way to merge the results of the members
+	// Optional. Default: FusionRRF
+	FusionMode FusionMode
+	// RRFK is the constant k of reciprocal rank fusion, the larger k, the less the top ranks dominate
+	// Optional. Default: 60
+	RRFK int
+	// TopK limits the number of the fused documents, which can be overridden by retriever.WithTopK,
+	// 0 returns all the documents
+	// Optional. Default: 0
+	TopK int
+	// DedupKey returns the key to identify the same document returned by different members
+	// Optional. Default: DedupByID
+	DedupKey func(doc *schema.Document) string
+	// RequireAll makes Retrieve fail if any member fails,
+	// otherwise the failed members are skipped, and Retrieve fails only if all members fail
+	// Optional. Default: false
+	RequireAll bool
+}
+
+// Retriever fans a query out to multiple retrievers and fuses the results.
+type Retriever struct {
+	members    []*member
+	fusionMode FusionMode
+	rrfK       int
+	topK       int
+	dedupKey   func(doc *schema.Document) string
+	requireAll bool
+}
+
+type member struct {
+	name    string
+	r       retriever.Retriever
+	weight  float64
+	timeout time.Duration
+	opts    []retriever.Option
+}
+
+func NewRetriever(ctx context.Context, config *Config) (*Retriever, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	if len(config.Retrievers) == 0 {
+		return nil, errors.New("at least one retriever is required")
+	}
+	fusionMode := config.FusionMode
+	if fusionMode == "" {
+		fusionMode = FusionRRF
+	}
+	if fusionMode != FusionRRF && fusionMode != FusionScore {
+		return nil, fmt.Errorf("unknown fusion mode: %s", fusionMode)
+	}
+	rrfK := config.RRFK
+	if rrfK < 0 {
+		return nil, fmt.Errorf("rrf k must not be negative, given=%d", rrfK)
+	}
+	if rrfK == 0 {
+		rrfK = defaultRRFK
+	}
+	if config.TopK < 0 {
+		return nil, fmt.Errorf("top k must not be negative, given=%d", config.TopK)
+	}
+	dedupKey := config.DedupKey
+	if dedupKey == nil {
+		dedupKey = DedupByID
+	}
+
+	members := make([]*member, 0, len(config.Retrievers))
+	names := make(map[string]bool, len(config.Retrievers))
+	for i, m := range config.Retrievers {
+		if m == nil || m.Retriever == nil {
+			return nil, fmt.Errorf("retriever at index %d is nil", i)
+		}
+		if m.Weight < 0 {
+			return nil, fmt.Errorf("weight of retriever at index %d must not be negative, given=%v", i, m.Weight)
+		}
+		name := m.Name
+		if name == "" {
+			name, _ = components.GetType(m.Retriever)
+		}
+		if name == "" || names[name] {
+			name = fmt.Sprintf("retriever_%d", i)
+		}
+		names[name] = true
+		weight := m.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		members = append(members, &member{
+			name:    name,
+			r:       m.Retriever,
+			weight:  weight,
+			timeout: m.Timeout,
+			opts:    m.Options,
+		})
+	}
+
+	return &Retriever{
+		members:    members,
+		fusionMode: fusionMode,
+		rrfK:       rrfK,
+		topK:       config.TopK,
+		dedupKey:   dedupKey,
+		requireAll: config.RequireAll,
+	}, nil
+}
+
+// Retrieve queries the members concurrently and returns the fused documents sorted by the fused score.
+// The options are passed to every member, the implementation specific options of other retrievers are ignored by each member.
+// The returned documents are copies, whose Score is the fused score, and whose sources are available by GetSources.
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{TopK: &r.topK}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query: query,
+		TopK:  *co.TopK,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	results := make([][]*schema.Document, len(r.members))
+	errs := make([]error, len(r.members))
+	var wg sync.WaitGroup
+	for i, m := range r.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if pe := recover(); pe != nil {
+					errs[i] = fmt.Errorf("panic error: %v, \nstack: %s", pe, string(debug.Stack()))
+				}
+			}()
+			results[i], errs[i] = m.retrieve(ctx, query, opts...)
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for i, e := range errs {
+		if e != nil {
+			failed = append(failed, fmt.Errorf("retriever %s failed: %w", r.members[i].name, e))
+		}
+	}
+	if len(failed) > 0 && (r.requireAll || len(failed) == len(r.members)) {
+		return nil, errors.Join(failed...)
+	}
+
+	docs = r.fuse(results, errs)
+	if *co.TopK > 0 && len(docs) > *co.TopK {
+		docs = docs[:*co.TopK]
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+func (m *member) retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+	if len(m.opts) > 0 {
+		opts = append(append(make([]retriever.Option, 0, len(opts)+len(m.opts)), opts...), m.opts...)
+	}
+
+	t, _ := components.GetType(m.r)
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+		Name:      m.name,
+		Type:      t,
+		Component: components.ComponentOfRetriever,
+	})
+	if components.IsCallbacksEnabled(m.r) {
+		return m.r.Retrieve(ctx, query, opts...)
+	}
+
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{Query: query})
+	docs, err = m.r.Retrieve(ctx, query, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+	return docs, nil
+}
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,358 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fakeRetriever struct {
+	docs  []*schema.Document
+	err   error
+	delay time.Duration
+	// topK records the top k given to the last Retrieve
+	topK int
+}
+
+func (f *fakeRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	f.topK = *retriever.GetCommonOptions(&retriever.Options{TopK: new(int)}, opts...).TopK
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.docs, nil
+}
+
+func doc(id string, score float64) *schema.Document {
+	return (&schema.Document{ID: id, Content: "content of " + id}).WithScore(score)
+}
+
+func ids(docs []*schema.Document) []string {
+	ret := make([]string, len(docs))
+	for i, d := range docs {
+		ret[i] = d.ID
+	}
+	return ret
+}
+
+func TestRetrieveRRF(t *testing.T) {
+	ctx := context.Background()
+	dense := &fakeRetriever{docs: []*schema.Document{doc("d1", 0.9), doc("d2", 0.5), doc("d3", 0.1)}}
+	sparse := &fakeRetriever{docs: []*schema.Document{doc("d3", 12), doc("d4", 6), doc("d1", 3)}}
+
+	r, err := NewRetriever(ctx, &Config{Retrievers: []*Member{
+		{Name: "dense", Retriever: dense},
+		{Name: "sparse", Retriever: sparse},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := r.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// d1 and d3 tie, as well as d2 and d4, and keep the order of first appearance
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"d1", "d3", "d2", "d4"}) {
+		t.Fatalf("unexpected order: %v", got)
+	}
+	if want := 1.0/61 + 1.0/63; docs[0].Score() != want {
+		t.Fatalf("unexpected score: %v, want: %v", docs[0].Score(), want)
+	}
+	sources := GetSources(docs[0])
+	if !reflect.DeepEqual(sources, []*Source{{Name: "dense", Rank: 1, Score: 0.9}, {Name: "sparse", Rank: 3, Score: 3}}) {
+		t.Fatalf("unexpected sources: %+v", sources)
+	}
+	// the documents of the members are not modified
+	if dense.docs[0].Score() != 0.9 || GetSources(dense.docs[0]) != nil {
+		t.Fatal("member document is modified")
+	}
+
+	r, err = NewRetriever(ctx, &Config{Retrievers: []*Member{
+		{Name: "dense", Retriever: dense},
+		{Name: "sparse", Retriever: sparse, Weight: 2},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err = r.Retrieve(ctx, "query", retriever.WithTopK(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"d3", "d1", "d4"}) {
+		t.Fatalf("unexpected order: %v", got)
+	}
+	if dense.topK != 3 || sparse.topK != 3 {
+		t.Fatalf("top k is not passed to members: %d, %d", dense.topK, sparse.topK)
+	}
+}
+
+func TestRetrieveScoreFusion(t *testing.T) {
+	ctx := context.Background()
+	dense := &fakeRetriever{docs: []*schema.Document{doc("d1", 0.9), doc("d2", 0.5), doc("d3", 0.1)}}
+	sparse := &fakeRetriever{docs: []*schema.Document{doc("d3", 12), doc("d4", 6), doc("d1", 3)}}
+	single := &fakeRetriever{docs: []*schema.Document{doc("d5", 0.3)}}
+
+	r, err := NewRetriever(ctx, &Config{
+		Retrievers: []*Member{
+			{Name: "dense", Retriever: dense, Weight: 2},
+			{Name: "sparse", Retriever: sparse, Options: []retriever.Option{retriever.WithTopK(10)}},
+			{Name: "single", Retriever: single, Weight: 0.5},
+		},
+		FusionMode: FusionScore,
+		TopK:       4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := r.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// d1: 2*1+0, d2: 2*0.5, d3: 2*0+1, d5: 0.5*1, d4: 1/3
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"d1", "d2", "d3", "d5"}) {
+		t.Fatalf("unexpected order: %v", got)
+	}
+	want := []float64{2, 1, 1, 0.5}
+	for i, d := range docs {
+		if d.Score() != want[i] {
+			t.Fatalf("unexpected score of %s: %v, want: %v", d.ID, d.Score(), want[i])
+		}
+	}
+	if sparse.topK != 10 || dense.topK != 0 {
+		t.Fatalf("unexpected member top k: %d, %d", sparse.topK, dense.topK)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	ctx := context.Background()
+	es := &fakeRetriever{docs: []*schema.Document{
+		{ID: "es-1", Content: "same"},
+		{ID: "es-2", Content: "other"},
+	}}
+	milvus := &fakeRetriever{docs: []*schema.Document{
+		{ID: "milvus-1", Content: "same"},
+		{ID: "milvus-1", Content: "same"},
+	}}
+	members := []*Member{{Name: "es", Retriever: es}, {Name: "milvus", Retriever: milvus}}
+
+	r, err := NewRetriever(ctx, &Config{Retrievers: members})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := r.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"es-1", "milvus-1", "es-2"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+	if len(GetSources(docs[1])) != 1 {
+		t.Fatalf("repeated document is counted twice: %+v", GetSources(docs[1]))
+	}
+
+	r, err = NewRetriever(ctx, &Config{Retrievers: members, DedupKey: DedupByContent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err = r.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"es-1", "es-2"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+	if len(GetSources(docs[0])) != 2 {
+		t.Fatalf("unexpected sources: %+v", GetSources(docs[0]))
+	}
+
+	if DedupByID(&schema.Document{Content: "same"}) != DedupByContent(&schema.Document{ID: "x", Content: "same"}) {
+		t.Fatal("document without id should be identified by content")
+	}
+}
+
+func TestFuseRRF(t *testing.T) {
+	results := [][]*schema.Document{
+		{doc("d1", 0.9), doc("d2", 0.8), doc("d1", 0.7)},
+		nil,
+		{doc("d2", 0.5), doc("d3", 0.4)},
+	}
+	docs := FuseRRF(results, 0, nil)
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"d2", "d1", "d3"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+	if want := 1.0/62 + 1.0/61; math.Abs(docs[0].Score()-want) > 1e-9 {
+		t.Fatalf("unexpected score: %v, want: %v", docs[0].Score(), want)
+	}
+	// the repeated document is counted once per list
+	if want := 1.0 / 61; math.Abs(docs[1].Score()-want) > 1e-9 {
+		t.Fatalf("unexpected score: %v, want: %v", docs[1].Score(), want)
+	}
+	if GetSources(docs[0]) != nil || docs[0] == results[0][1] {
+		t.Fatalf("unexpected doc: %+v", docs[0])
+	}
+
+	docs = FuseRRF(results, 1, func(doc *schema.Document) string { return "same" })
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"d1"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+	if want := 1.0/2 + 1.0/2; math.Abs(docs[0].Score()-want) > 1e-9 {
+		t.Fatalf("unexpected score: %v, want: %v", docs[0].Score(), want)
+	}
+}
+
+func TestMemberFailure(t *testing.T) {
+	ctx := context.Background()
+	ok := &fakeRetriever{docs: []*schema.Document{doc("d1", 1)}}
+	slow := &fakeRetriever{docs: []*schema.Document{doc("d2", 1)}, delay: time.Second}
+	broken := &fakeRetriever{err: errors.New("connection refused")}
+
+	members := []*Member{
+		{Name: "ok", Retriever: ok},
+		{Name: "slow", Retriever: slow, Timeout: 10 * time.Millisecond},
+		{Name: "broken", Retriever: broken},
+	}
+	r, err := NewRetriever(ctx, &Config{Retrievers: members})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	docs, err := r.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("member timeout is not applied")
+	}
+	if got := ids(docs); !reflect.DeepEqual(got, []string{"d1"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+
+	r, err = NewRetriever(ctx, &Config{Retrievers: members, RequireAll: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Retrieve(ctx, "query")
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "retriever broken failed: connection refused") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r, err = NewRetriever(ctx, &Config{Retrievers: members[1:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Retrieve(ctx, "query"); err == nil {
+		t.Fatal("expect error when all members fail")
+	}
+}
+
+type panicRetriever struct{}
+
+func (p *panicRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	panic("boom")
+}
+
+func TestMemberPanic(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRetriever(ctx, &Config{Retrievers: []*Member{
+		{Retriever: &panicRetriever{}},
+		{Retriever: &fakeRetriever{docs: []*schema.Document{doc("d1", 1)}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := r.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("unexpected docs: %v", ids(docs))
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRetriever(ctx, &Config{Retrievers: []*Member{
+		{Name: "a", Retriever: &fakeRetriever{docs: []*schema.Document{doc("d1", 1)}}},
+		{Name: "b", Retriever: &fakeRetriever{docs: []*schema.Document{doc("d2", 1)}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var ended []string
+	handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+		mu.Lock()
+		ended = append(ended, info.Name+"/"+info.Type)
+		mu.Unlock()
+		return ctx
+	}).Build()
+	ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "ensemble"}, handler)
+
+	if _, err = r.Retrieve(ctx, "query"); err != nil {
+		t.Fatal(err)
+	}
+	if len(ended) != 3 || ended[2] != "ensemble/" {
+		t.Fatalf("unexpected callbacks: %v", ended)
+	}
+	if !(ended[0] == "a/" && ended[1] == "b/") && !(ended[0] == "b/" && ended[1] == "a/") {
+		t.Fatalf("unexpected member callbacks: %v", ended)
+	}
+}
+
+func TestNewRetriever(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeRetriever{}
+	for _, config := range []*Config{
+		nil,
+		{},
+		{Retrievers: []*Member{nil}},
+		{Retrievers: []*Member{{Retriever: f, Weight: -1}}},
+		{Retrievers: []*Member{{Retriever: f}}, FusionMode: "unknown"},
+		{Retrievers: []*Member{{Retriever: f}}, RRFK: -1},
+		{Retrievers: []*Member{{Retriever: f}}, TopK: -1},
+	} {
+		if _, err := NewRetriever(ctx, config); err == nil {
+			t.Fatalf("expect error for config: %+v", config)
+		}
+	}
+
+	r, err := NewRetriever(ctx, &Config{Retrievers: []*Member{{Retriever: f}, {Retriever: f}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.members[0].name != "retriever_0" || r.members[1].name != "retriever_1" {
+		t.Fatalf("unexpected names: %s, %s", r.members[0].name, r.members[1].name)
+	}
+}
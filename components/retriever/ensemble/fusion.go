@@ -0,0 +1,210 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+const keyOfSources = "_ensemble_sources"
+
+// Source records that a member returned the document.
+type Source struct {
+	// Name is the name of the member
+	Name string `json:"name"`
+	// Rank is the 1-based rank of the document in the results of the member
+	Rank int `json:"rank"`
+	// Score is the score of the document given by the member
+	Score float64 `json:"score"`
+}
+
+// GetSources returns the members which returned the document, in the order of Config.Retrievers.
+func GetSources(doc *schema.Document) []*Source {
+	if doc == nil || doc.MetaData == nil {
+		return nil
+	}
+	sources, _ := doc.MetaData[keyOfSources].([]*Source)
+	return sources
+}
+
+// DedupByID identifies a document by its ID, and by the hash of its content if the ID is empty.
+func DedupByID(doc *schema.Document) string {
+	if doc.ID != "" {
+		return "id:" + doc.ID
+	}
+	return DedupByContent(doc)
+}
+
+// DedupByContent identifies a document by the hash of its content,
+// which suits the members indexing the same corpus with different IDs.
+func DedupByContent(doc *schema.Document) string {
+	sum := sha256.Sum256([]byte(doc.Content))
+	return "content:" + hex.EncodeToString(sum[:])
+}
+
+type fused struct {
+	doc     *schema.Document
+	score   float64
+	sources []*Source
+}
+
+// FuseRRF merges the ranked lists of documents by reciprocal rank fusion, sum(1 / (k + rank)) over the lists
+// returning a document, e.g. the results of several queries to the same retriever. k is 60 if not positive,
+// and dedupKey is DedupByID if nil. The returned documents are copies whose Score is the fused score.
+func FuseRRF(results [][]*schema.Document, k int, dedupKey func(doc *schema.Document) string) []*schema.Document {
+	if k <= 0 {
+		k = defaultRRFK
+	}
+	if dedupKey == nil {
+		dedupKey = DedupByID
+	}
+	merged := merge(results, nil, dedupKey, func(_ int, docs []*schema.Document) []float64 {
+		return rrfScores(docs, k)
+	})
+	return toDocuments(merged)
+}
+
+// fuse merges the results of the members, and records the sources of the documents.
+func (r *Retriever) fuse(results [][]*schema.Document, errs []error) []*schema.Document {
+	lists := make([][]*schema.Document, len(results))
+	names := make([]string, len(r.members))
+	for i, m := range r.members {
+		names[i] = m.name
+		if errs[i] == nil {
+			lists[i] = results[i]
+		}
+	}
+	merged := merge(lists, names, r.dedupKey, func(i int, docs []*schema.Document) []float64 {
+		scores := r.memberScores(docs)
+		for rank := range scores {
+			scores[rank] *= r.members[i].weight
+		}
+		return scores
+	})
+	return toDocuments(merged)
+}
+
+// merge sums the scores of the documents over the lists, scores returns the contribution of each document of the i-th list.
+// The document first returned in the order of lists and ranks is kept for the duplicates, and the documents of the same
+// fused score keep that order. The sources are recorded if names of the lists are given.
+func merge(results [][]*schema.Document, names []string, dedupKey func(doc *schema.Document) string,
+	scores func(i int, docs []*schema.Document) []float64) []*fused {
+
+	var merged []*fused
+	index := make(map[string]*fused)
+	for i, docs := range results {
+		if len(docs) == 0 {
+			continue
+		}
+		contributions := scores(i, docs)
+		counted := make(map[string]bool, len(docs))
+		for rank, doc := range docs {
+			if doc == nil {
+				continue
+			}
+			key := dedupKey(doc)
+			if counted[key] {
+				// counts the document only once for a list returning it repeatedly, e.g. chunks of the same file
+				continue
+			}
+			counted[key] = true
+			f, ok := index[key]
+			if !ok {
+				f = &fused{doc: doc}
+				index[key] = f
+				merged = append(merged, f)
+			}
+			f.score += contributions[rank]
+			if names != nil {
+				f.sources = append(f.sources, &Source{Name: names[i], Rank: rank + 1, Score: doc.Score()})
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].score > merged[j].score
+	})
+	return merged
+}
+
+// toDocuments copies the merged documents with the fused score and the sources.
+func toDocuments(merged []*fused) []*schema.Document {
+	docs := make([]*schema.Document, 0, len(merged))
+	for _, f := range merged {
+		doc := &schema.Document{
+			ID:       f.doc.ID,
+			Content:  f.doc.Content,
+			MetaData: make(map[string]any, len(f.doc.MetaData)+2),
+		}
+		for k, v := range f.doc.MetaData {
+			doc.MetaData[k] = v
+		}
+		doc.WithScore(f.score)
+		if f.sources != nil {
+			doc.MetaData[keyOfSources] = f.sources
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// memberScores returns the unweighted contribution of each document of a member to the fused score.
+func (r *Retriever) memberScores(docs []*schema.Document) []float64 {
+	if r.fusionMode == FusionRRF {
+		return rrfScores(docs, r.rrfK)
+	}
+
+	scores := make([]float64, len(docs))
+	first := true
+	var lo, hi float64
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		s := doc.Score()
+		if first || s < lo {
+			lo = s
+		}
+		if first || s > hi {
+			hi = s
+		}
+		first = false
+	}
+	for i, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if hi == lo {
+			scores[i] = 1
+			continue
+		}
+		scores[i] = (doc.Score() - lo) / (hi - lo)
+	}
+	return scores
+}
+
+func rrfScores(docs []*schema.Document, k int) []float64 {
+	scores := make([]float64, len(docs))
+	for rank := range docs {
+		scores[rank] = 1 / float64(k+rank+1)
+	}
+	return scores
+}
@@ -19,6 +19,8 @@ package es8
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
+	"sync"
 
 	"github.com/cloudwego/eino/components"
 	"github.com/elastic/go-elasticsearch/v8"
@@ -46,6 +48,7 @@ type RetrieverConfig struct {
 	// use search_mode.SearchModeDenseVectorSimilarity with search_mode.DenseVectorSimilarityQuery
 	// use search_mode.SearchModeSparseVectorTextExpansion with search_mode.SparseVectorTextExpansionQuery
 	// use search_mode.SearchModeRawStringRequest with json search request
+	// use search_mode.SearchModeHybrid with string query
 	SearchMode SearchMode `json:"search_mode"`
 	// ResultParser parse document from es search hits.
 	// If ResultParser not provided, defaultResultParser will be used as default
@@ -61,6 +64,17 @@ type SearchMode interface {
 	BuildRequest(ctx context.Context, conf *RetrieverConfig, query string, opts ...retriever.Option) (*search.Request, error)
 }
 
+// MultiSearchMode is an optional interface of SearchMode, for search modes which send several search requests
+// and fuse their hits on the client side, e.g. hybrid search without the rrf retriever of elasticsearch.
+type MultiSearchMode interface {
+	SearchMode
+	// BuildRequests generate search requests from config, query and options, which will be sent concurrently.
+	// If no request returned, the request generated by BuildRequest will be used instead.
+	BuildRequests(ctx context.Context, conf *RetrieverConfig, query string, opts ...retriever.Option) ([]*search.Request, error)
+	// FuseHits fuse hits of each search request into the final ranked hits, hits[i] is the result of i-th request.
+	FuseHits(ctx context.Context, conf *RetrieverConfig, hits [][]types.Hit, opts ...retriever.Option) ([]types.Hit, error)
+}
+
 type Retriever struct {
 	client *elasticsearch.Client
 	config *RetrieverConfig
@@ -108,33 +122,98 @@ func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retrieve
 		}
 	}()
 
-	req, err := r.config.SearchMode.BuildRequest(ctx, r.config, query, opts...)
+	var hits []types.Hit
+	if msm, ok := r.config.SearchMode.(MultiSearchMode); ok {
+		reqs, err := msm.BuildRequests(ctx, r.config, query, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reqs) > 0 {
+			hits, err = r.multiSearch(ctx, msm, reqs, opts...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if hits == nil {
+		req, err := r.config.SearchMode.BuildRequest(ctx, r.config, query, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := r.search(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		hits = resp.Hits.Hits
+	}
+
+	docs, err = r.parseSearchResult(ctx, hits)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := search.NewSearchFunc(r.client)().
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+
+	return docs, nil
+}
+
+func (r *Retriever) search(ctx context.Context, req *search.Request) (*search.Response, error) {
+	return search.NewSearchFunc(r.client)().
 		Index(r.config.Index).
 		Request(req).
 		Do(ctx)
-	if err != nil {
-		return nil, err
+}
+
+func (r *Retriever) multiSearch(ctx context.Context, msm MultiSearchMode, reqs []*search.Request, opts ...retriever.Option) ([]types.Hit, error) {
+	var (
+		wg   sync.WaitGroup
+		hits = make([][]types.Hit, len(reqs))
+		errs = make([]error, len(reqs))
+	)
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req *search.Request) {
+			defer func() {
+				if e := recover(); e != nil {
+					errs[i] = fmt.Errorf("[multiSearch] panic: %v, stack: %s", e, debug.Stack())
+				}
+				wg.Done()
+			}()
+
+			resp, err := r.search(ctx, req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			hits[i] = resp.Hits.Hits
+		}(i, req)
 	}
+	wg.Wait()
 
-	docs, err = r.parseSearchResult(ctx, resp)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fused, err := msm.FuseHits(ctx, r.config, hits, opts...)
 	if err != nil {
 		return nil, err
 	}
-
-	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
-
-	return docs, nil
+	if fused == nil {
+		fused = []types.Hit{}
+	}
+	return fused, nil
 }
 
-func (r *Retriever) parseSearchResult(ctx context.Context, resp *search.Response) (docs []*schema.Document, err error) {
-	docs = make([]*schema.Document, 0, len(resp.Hits.Hits))
+func (r *Retriever) parseSearchResult(ctx context.Context, hits []types.Hit) (docs []*schema.Document, err error) {
+	docs = make([]*schema.Document, 0, len(hits))
 
-	for _, hit := range resp.Hits.Hits {
+	for _, hit := range hits {
 		doc, err := r.config.ResultParser(ctx, hit)
 		if err != nil {
 			return nil, err
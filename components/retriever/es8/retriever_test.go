@@ -92,6 +92,51 @@ func TestNewRetriever(t *testing.T) {
 		assert.Equal(t, "i'm fine, thank you", docs[0].Content)
 	})
 
+	t.Run("retrieve_documents_multi_search", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &RetrieverConfig{
+			Client: &elasticsearch.Client{},
+			Index:  "eino_ut",
+			TopK:   10,
+			ResultParser: func(ctx context.Context, hit types.Hit) (doc *schema.Document, err error) {
+				var mp map[string]any
+				if err := json.Unmarshal(hit.Source_, &mp); err != nil {
+					return nil, err
+				}
+
+				content, _ := mp["eino_doc_content"].(string)
+				return &schema.Document{ID: *hit.Id_, Content: content}, nil
+			},
+			SearchMode: &mockMultiSearchMode{},
+		})
+		assert.NoError(t, err)
+
+		mockSearch := search.NewSearchFunc(r.client)()
+
+		defer mockey.Mock(mockey.GetMethod(mockSearch, "Index")).
+			Return(mockSearch).Build().Patch().UnPatch()
+
+		defer mockey.Mock(mockey.GetMethod(mockSearch, "Request")).
+			Return(mockSearch).Build().Patch().UnPatch()
+
+		defer mockey.Mock(mockey.GetMethod(mockSearch, "Do")).Return(&search.Response{
+			Hits: types.HitsMetadata{
+				Hits: []types.Hit{
+					{
+						Id_:     ptrOf("1"),
+						Source_: json.RawMessage([]byte(`{"eino_doc_content": "i'm fine, thank you"}`)),
+					},
+				},
+			},
+		}, nil).Build().Patch().UnPatch()
+
+		docs, err := r.Retrieve(ctx, "how are you")
+		assert.NoError(t, err)
+
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "1", docs[0].ID)
+		assert.Equal(t, "i'm fine, thank you", docs[1].Content)
+	})
+
 }
 
 type mockSearchMode struct{}
@@ -99,3 +144,23 @@ type mockSearchMode struct{}
 func (m *mockSearchMode) BuildRequest(ctx context.Context, conf *RetrieverConfig, query string, opts ...retriever.Option) (*search.Request, error) {
 	return &search.Request{}, nil
 }
+
+type mockMultiSearchMode struct {
+	mockSearchMode
+}
+
+func (m *mockMultiSearchMode) BuildRequests(ctx context.Context, conf *RetrieverConfig, query string, opts ...retriever.Option) ([]*search.Request, error) {
+	return []*search.Request{{}, {}}, nil
+}
+
+func (m *mockMultiSearchMode) FuseHits(ctx context.Context, conf *RetrieverConfig, hits [][]types.Hit, opts ...retriever.Option) ([]types.Hit, error) {
+	var fused []types.Hit
+	for _, h := range hits {
+		fused = append(fused, h...)
+	}
+	return fused, nil
+}
+
+func ptrOf[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,438 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+
+	"github.com/cloudwego/eino-ext/components/retriever/es8"
+)
+
+// SearchModeHybrid retrieve with a text query (match / multi_match) and a knn query together, and fuse their results.
+// Filters from es8.WithFilters are applied to both queries.
+// ScoreThreshold from es8.RetrieverConfig or retriever.WithScoreThreshold is the min score of each query before fusion,
+// use HybridConfig.FusedScoreThreshold to filter the fused results.
+// rrf retriever: https://www.elastic.co/guide/en/elasticsearch/reference/current/retriever.html#rrf-retriever
+func SearchModeHybrid(config *HybridConfig) es8.SearchMode {
+	return &hybrid{config}
+}
+
+// HybridFusion the method to fuse results of text query and knn query
+type HybridFusion string
+
+const (
+	// HybridFusionClientRRF send text query and knn query separately, and fuse them by reciprocal rank fusion on client side,
+	// score = sum(boost / (rank_constant + rank)), works with all licenses
+	HybridFusionClientRRF HybridFusion = "client_rrf"
+	// HybridFusionClientWeighted send text query and knn query separately, normalize scores of each query with min-max,
+	// and sum them weighted by TextBoost and VectorBoost on client side
+	HybridFusionClientWeighted HybridFusion = "client_weighted"
+	// HybridFusionRRF fuse results with the rrf retriever in a single request,
+	// only available with specific licenses, see: https://www.elastic.co/subscriptions
+	HybridFusionRRF HybridFusion = "rrf"
+)
+
+const (
+	defaultHybridRRFRankConstant = 60
+	defaultHybridWindowSize      = 10
+	maxHybridNumCandidates       = 10000
+)
+
+type HybridConfig struct {
+	// QueryFieldNames the names of text fields to match query against, required
+	// match query is used for a single field, multi_match query is used for multiple fields
+	QueryFieldNames []string
+	// MultiMatchType the type of multi_match query, e.g. best_fields, most_fields, cross_fields
+	MultiMatchType *textquerytype.TextQueryType
+	// VectorFieldName the name of the vector field to search against, required
+	VectorFieldName string
+	// Fusion the method to fuse results of text query and knn query, default HybridFusionClientRRF
+	Fusion HybridFusion
+	// RRFRankConstant determines how much influence documents in
+	// individual result sets per query have over the final ranked result set, default 60
+	RRFRankConstant *int
+	// WindowSize the size of the individual result sets per query before fusion, default TopK, or 10 if TopK not set
+	WindowSize *int
+	// TextBoost the weight of text query in client side fusion, default 1.0
+	// ignored by HybridFusionRRF, the rrf retriever does not support weights
+	TextBoost *float32
+	// VectorBoost the weight of knn query in client side fusion, default 1.0
+	// ignored by HybridFusionRRF, the rrf retriever does not support weights
+	VectorBoost *float32
+	// QueryVectorBuilderModelID the query vector builder model id
+	// see: https://www.elastic.co/guide/en/machine-learning/8.16/ml-nlp-text-emb-vector-search-example.html
+	QueryVectorBuilderModelID *string
+	// K The number of nearest neighbors to return from knn query, default WindowSize
+	K *int
+	// NumCandidates The number of nearest neighbor candidates to consider per shard, default min(1.5 * K, 10000)
+	NumCandidates *int
+	// Similarity The minimum similarity for a vector to be considered a match
+	Similarity *float32
+	// FusedScoreThreshold the minimum score of fused results, no threshold by default
+	// Notice: rrf scores are at most sum(boost) / (rank_constant + 1), e.g. about 0.033 with default rank constant and boosts,
+	// while client weighted scores range from 0 to TextBoost + VectorBoost
+	FusedScoreThreshold *float64
+}
+
+type hybrid struct {
+	config *HybridConfig
+}
+
+// BuildRequest build a single request with rrf retriever, which is used by HybridFusionRRF.
+func (h *hybrid) BuildRequest(ctx context.Context, conf *es8.RetrieverConfig, query string, opts ...retriever.Option) (*search.Request, error) {
+	co, io := h.getOptions(conf, opts...)
+
+	textQuery, err := h.textQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	knn, err := h.knnRetriever(ctx, co, io, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rankConstant := h.rankConstant()
+	windowSize := h.windowSize(co)
+	standard := &types.StandardRetriever{Query: textQuery, Filter: io.Filters}
+	if co.ScoreThreshold != nil {
+		standard.MinScore = ptrWithoutZero(float32(*co.ScoreThreshold))
+		knn.MinScore = ptrWithoutZero(float32(*co.ScoreThreshold))
+	}
+
+	rrf := &types.RRFRetriever{
+		RankConstant:   &rankConstant,
+		RankWindowSize: &windowSize,
+		Retrievers: []types.RetrieverContainer{
+			{Standard: standard},
+			{Knn: knn},
+		},
+	}
+
+	if h.config.FusedScoreThreshold != nil {
+		rrf.MinScore = ptrWithoutZero(float32(*h.config.FusedScoreThreshold))
+	}
+
+	return &search.Request{Retriever: &types.RetrieverContainer{Rrf: rrf}, Size: co.TopK}, nil
+}
+
+// BuildRequests build a text request and a knn request for client side fusion,
+// returns nil when using HybridFusionRRF.
+func (h *hybrid) BuildRequests(ctx context.Context, conf *es8.RetrieverConfig, query string, opts ...retriever.Option) ([]*search.Request, error) {
+	fusion, err := h.fusion()
+	if err != nil {
+		return nil, err
+	}
+
+	if fusion == HybridFusionRRF {
+		return nil, nil
+	}
+
+	co, io := h.getOptions(conf, opts...)
+
+	textQuery, err := h.textQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	knn, err := h.knnRetriever(ctx, co, io, query)
+	if err != nil {
+		return nil, err
+	}
+
+	windowSize := h.windowSize(co)
+	textReq := &search.Request{
+		Query: &types.Query{
+			Bool: &types.BoolQuery{
+				Filter: io.Filters,
+				Must:   []types.Query{*textQuery},
+			},
+		},
+		Size: &windowSize,
+	}
+
+	knnReq := &search.Request{
+		Knn: []types.KnnSearch{{
+			Field:              knn.Field,
+			Filter:             knn.Filter,
+			K:                  &knn.K,
+			NumCandidates:      &knn.NumCandidates,
+			QueryVector:        knn.QueryVector,
+			QueryVectorBuilder: knn.QueryVectorBuilder,
+			Similarity:         knn.Similarity,
+		}},
+		Size: &windowSize,
+	}
+
+	if co.ScoreThreshold != nil {
+		textReq.MinScore = (*types.Float64)(ptrWithoutZero(*co.ScoreThreshold))
+		knnReq.MinScore = (*types.Float64)(ptrWithoutZero(*co.ScoreThreshold))
+	}
+
+	return []*search.Request{textReq, knnReq}, nil
+}
+
+// FuseHits fuse hits of text request and knn request, hits are identified by index and id.
+func (h *hybrid) FuseHits(ctx context.Context, conf *es8.RetrieverConfig, hits [][]types.Hit, opts ...retriever.Option) ([]types.Hit, error) {
+	fusion, err := h.fusion()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hits) != 2 {
+		return nil, fmt.Errorf("[FuseHits][SearchModeHybrid] hits len error, expected=2, got=%d", len(hits))
+	}
+
+	co, _ := h.getOptions(conf, opts...)
+	boosts := []float64{derefOr(h.config.TextBoost, 1), derefOr(h.config.VectorBoost, 1)}
+
+	var (
+		keys   []string
+		fused  = make(map[string]*types.Hit)
+		scores = make(map[string]float64)
+	)
+
+	for i, legHits := range hits {
+		// ScoreThreshold applies to the scores of each query, which is also the min_score of requests
+		if co.ScoreThreshold != nil {
+			kept := make([]types.Hit, 0, len(legHits))
+			for _, hit := range legHits {
+				if hitScore(hit) >= *co.ScoreThreshold {
+					kept = append(kept, hit)
+				}
+			}
+			legHits = kept
+		}
+
+		var legScores []float64
+		if fusion == HybridFusionClientWeighted {
+			legScores = minMaxNormalize(legHits)
+		}
+
+		for rank, hit := range legHits {
+			key := hitKey(hit, i, rank)
+			if _, ok := fused[key]; !ok {
+				hit := hit
+				fused[key] = &hit
+				keys = append(keys, key)
+			}
+
+			switch fusion {
+			case HybridFusionClientRRF:
+				scores[key] += boosts[i] / float64(h.rankConstant()+rank+1)
+			case HybridFusionClientWeighted:
+				scores[key] += boosts[i] * legScores[rank]
+			}
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return scores[keys[i]] > scores[keys[j]]
+	})
+
+	result := make([]types.Hit, 0, len(keys))
+	for _, key := range keys {
+		score := scores[key]
+		if h.config.FusedScoreThreshold != nil && score < *h.config.FusedScoreThreshold {
+			continue
+		}
+
+		hit := fused[key]
+		hit.Score_ = (*types.Float64)(&score)
+		result = append(result, *hit)
+
+		if co.TopK != nil && len(result) >= *co.TopK {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (h *hybrid) getOptions(conf *es8.RetrieverConfig, opts ...retriever.Option) (*retriever.Options, *es8.ImplOptions) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		Index:          ptrWithoutZero(conf.Index),
+		TopK:           ptrWithoutZero(conf.TopK),
+		ScoreThreshold: conf.ScoreThreshold,
+		Embedding:      conf.Embedding,
+	}, opts...)
+
+	io := retriever.GetImplSpecificOptions[es8.ImplOptions](nil, opts...)
+
+	return co, io
+}
+
+func (h *hybrid) fusion() (HybridFusion, error) {
+	switch h.config.Fusion {
+	case "":
+		return HybridFusionClientRRF, nil
+	case HybridFusionClientRRF, HybridFusionClientWeighted, HybridFusionRRF:
+		return h.config.Fusion, nil
+	default:
+		return "", fmt.Errorf("[SearchModeHybrid] unknown fusion: %s", h.config.Fusion)
+	}
+}
+
+func (h *hybrid) textQuery(query string) (*types.Query, error) {
+	switch len(h.config.QueryFieldNames) {
+	case 0:
+		return nil, fmt.Errorf("[BuildRequest][SearchModeHybrid] query field names not provided")
+	case 1:
+		return &types.Query{
+			Match: map[string]types.MatchQuery{
+				h.config.QueryFieldNames[0]: {Query: query},
+			},
+		}, nil
+	default:
+		return &types.Query{
+			MultiMatch: &types.MultiMatchQuery{
+				Fields: h.config.QueryFieldNames,
+				Query:  query,
+				Type:   h.config.MultiMatchType,
+			},
+		}, nil
+	}
+}
+
+func (h *hybrid) knnRetriever(ctx context.Context, co *retriever.Options, io *es8.ImplOptions, query string) (*types.KnnRetriever, error) {
+	if h.config.VectorFieldName == "" {
+		return nil, fmt.Errorf("[BuildRequest][SearchModeHybrid] vector field name not provided")
+	}
+
+	k := h.windowSize(co)
+	if h.config.K != nil {
+		k = *h.config.K
+	}
+
+	numCandidates := k + k/2
+	if numCandidates > maxHybridNumCandidates {
+		numCandidates = maxHybridNumCandidates
+	}
+	if numCandidates < k {
+		numCandidates = k
+	}
+	if h.config.NumCandidates != nil {
+		numCandidates = *h.config.NumCandidates
+	}
+
+	knn := &types.KnnRetriever{
+		Field:         h.config.VectorFieldName,
+		Filter:        io.Filters,
+		K:             k,
+		NumCandidates: numCandidates,
+		Similarity:    h.config.Similarity,
+	}
+
+	if h.config.QueryVectorBuilderModelID != nil {
+		knn.QueryVectorBuilder = &types.QueryVectorBuilder{TextEmbedding: &types.TextEmbedding{
+			ModelId:   *h.config.QueryVectorBuilderModelID,
+			ModelText: query,
+		}}
+	} else {
+		emb := co.Embedding
+		if emb == nil {
+			return nil, fmt.Errorf("[BuildRequest][SearchModeHybrid] embedding not provided")
+		}
+
+		vector, err := emb.EmbedStrings(makeEmbeddingCtx(ctx, emb), []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("[BuildRequest][SearchModeHybrid] embedding failed, %w", err)
+		}
+
+		if len(vector) != 1 {
+			return nil, fmt.Errorf("[BuildRequest][SearchModeHybrid] vector len error, expected=1, got=%d", len(vector))
+		}
+
+		knn.QueryVector = f64To32(vector[0])
+	}
+
+	return knn, nil
+}
+
+func (h *hybrid) rankConstant() int {
+	if h.config.RRFRankConstant != nil {
+		return *h.config.RRFRankConstant
+	}
+	return defaultHybridRRFRankConstant
+}
+
+func (h *hybrid) windowSize(co *retriever.Options) int {
+	if h.config.WindowSize != nil {
+		return *h.config.WindowSize
+	}
+	if co.TopK != nil {
+		return *co.TopK
+	}
+	return defaultHybridWindowSize
+}
+
+// hitKey identifies a hit across result sets, hits without id are never merged.
+func hitKey(hit types.Hit, leg, rank int) string {
+	if hit.Id_ == nil {
+		return fmt.Sprintf("\x00%d\x00%d", leg, rank)
+	}
+	return hit.Index_ + "\x00" + *hit.Id_
+}
+
+func minMaxNormalize(hits []types.Hit) []float64 {
+	scores := make([]float64, len(hits))
+	if len(hits) == 0 {
+		return scores
+	}
+
+	lo, hi := hitScore(hits[0]), hitScore(hits[0])
+	for i, hit := range hits {
+		scores[i] = hitScore(hit)
+		if scores[i] < lo {
+			lo = scores[i]
+		}
+		if scores[i] > hi {
+			hi = scores[i]
+		}
+	}
+
+	for i := range scores {
+		if hi == lo {
+			scores[i] = 1
+		} else {
+			scores[i] = (scores[i] - lo) / (hi - lo)
+		}
+	}
+
+	return scores
+}
+
+func hitScore(hit types.Hit) float64 {
+	if hit.Score_ == nil {
+		return 0
+	}
+	return float64(*hit.Score_)
+}
+
+func derefOr(v *float32, def float64) float64 {
+	if v == nil {
+		return def
+	}
+	return float64(*v)
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino-ext/components/retriever/es8"
+)
+
+func TestSearchModeHybrid(t *testing.T) {
+	PatchConvey("test SearchModeHybrid", t, func() {
+		ctx := context.Background()
+		query := "content"
+		conf := &es8.RetrieverConfig{}
+		filters := es8.WithFilters([]types.Query{
+			{Match: map[string]types.MatchQuery{"label": {Query: "good"}}},
+		})
+
+		PatchConvey("test BuildRequest with rrf retriever", func() {
+			h := &hybrid{config: &HybridConfig{
+				QueryFieldNames:           []string{"eino_doc_content"},
+				VectorFieldName:           "vector_eino_doc_content",
+				Fusion:                    HybridFusionRRF,
+				RRFRankConstant:           ptrWithoutZero(10),
+				QueryVectorBuilderModelID: ptrWithoutZero("mock_model"),
+				FusedScoreThreshold:       ptrWithoutZero(0.01),
+			}}
+
+			reqs, err := h.BuildRequests(ctx, conf, query, filters)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(reqs, convey.ShouldBeNil)
+
+			req, err := h.BuildRequest(ctx, conf, query,
+				retriever.WithTopK(5),
+				retriever.WithScoreThreshold(1.5),
+				filters)
+			convey.So(err, convey.ShouldBeNil)
+			b, err := json.Marshal(req)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(string(b), convey.ShouldEqual, `{"retriever":{"rrf":{"min_score":0.01,"rank_constant":10,"rank_window_size":5,"retrievers":[{"standard":{"filter":[{"match":{"label":{"query":"good"}}}],"min_score":1.5,"query":{"match":{"eino_doc_content":{"query":"content"}}}}},{"knn":{"field":"vector_eino_doc_content","filter":[{"match":{"label":{"query":"good"}}}],"k":5,"min_score":1.5,"num_candidates":7,"query_vector_builder":{"text_embedding":{"model_id":"mock_model","model_text":"content"}}}}]}},"size":5}`)
+		})
+
+		PatchConvey("test BuildRequests for client fusion", func() {
+			h := &hybrid{config: &HybridConfig{
+				QueryFieldNames: []string{"title", "eino_doc_content"},
+				MultiMatchType:  &textquerytype.Mostfields,
+				VectorFieldName: "vector_eino_doc_content",
+				WindowSize:      ptrWithoutZero(20),
+				Similarity:      ptrWithoutZero(float32(0.5)),
+			}}
+
+			reqs, err := h.BuildRequests(ctx, conf, query,
+				retriever.WithEmbedding(&mockEmbedding{size: 1, mockVector: []float64{1.1, 1.2}}),
+				retriever.WithScoreThreshold(1.5),
+				filters)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(reqs), convey.ShouldEqual, 2)
+			b, err := json.Marshal(reqs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(string(b), convey.ShouldEqual, `[{"min_score":1.5,"query":{"bool":{"filter":[{"match":{"label":{"query":"good"}}}],"must":[{"multi_match":{"fields":["title","eino_doc_content"],"query":"content","type":"most_fields"}}]}},"size":20},{"knn":[{"field":"vector_eino_doc_content","filter":[{"match":{"label":{"query":"good"}}}],"k":20,"num_candidates":30,"query_vector":[1.1,1.2],"similarity":0.5}],"min_score":1.5,"size":20}]`)
+		})
+
+		PatchConvey("test BuildRequests error", func() {
+			h := &hybrid{config: &HybridConfig{VectorFieldName: "vector_eino_doc_content"}}
+			_, err := h.BuildRequests(ctx, conf, query)
+			convey.So(err, convey.ShouldNotBeNil)
+
+			h = &hybrid{config: &HybridConfig{QueryFieldNames: []string{"eino_doc_content"}, VectorFieldName: "vector_eino_doc_content"}}
+			_, err = h.BuildRequests(ctx, conf, query)
+			convey.So(err, convey.ShouldNotBeNil)
+
+			h = &hybrid{config: &HybridConfig{Fusion: "unknown"}}
+			_, err = h.BuildRequests(ctx, conf, query)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		mockHit := func(id string, score float64) types.Hit {
+			return types.Hit{Index_: "eino_ut", Id_: &id, Score_: (*types.Float64)(&score)}
+		}
+		hits := [][]types.Hit{
+			{mockHit("1", 10), mockHit("2", 8), mockHit("3", 2)},
+			{mockHit("3", 0.9), mockHit("4", 0.8), mockHit("1", 0.7)},
+		}
+		ids := func(hits []types.Hit) []string {
+			var ids []string
+			for _, hit := range hits {
+				ids = append(ids, *hit.Id_)
+			}
+			return ids
+		}
+
+		PatchConvey("test FuseHits with client rrf", func() {
+			h := &hybrid{config: &HybridConfig{RRFRankConstant: ptrWithoutZero(1)}}
+			fused, err := h.FuseHits(ctx, conf, hits)
+			convey.So(err, convey.ShouldBeNil)
+			// 1: 1/2+1/4, 3: 1/4+1/2, 2: 1/3, 4: 1/3
+			convey.So(ids(fused), convey.ShouldResemble, []string{"1", "3", "2", "4"})
+			convey.So(float64(*fused[0].Score_), convey.ShouldAlmostEqual, 0.75)
+
+			fused, err = h.FuseHits(ctx, conf, hits, retriever.WithTopK(2))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(fused), convey.ShouldResemble, []string{"1", "3"})
+
+			h = &hybrid{config: &HybridConfig{RRFRankConstant: ptrWithoutZero(1), VectorBoost: ptrWithoutZero(float32(2))}}
+			fused, err = h.FuseHits(ctx, conf, hits)
+			convey.So(err, convey.ShouldBeNil)
+			// 3: 1/4+2/2, 1: 1/2+2/4, 4: 2/3, 2: 1/3
+			convey.So(ids(fused), convey.ShouldResemble, []string{"3", "1", "4", "2"})
+		})
+
+		PatchConvey("test FuseHits with client weighted", func() {
+			h := &hybrid{config: &HybridConfig{Fusion: HybridFusionClientWeighted, FusedScoreThreshold: ptrWithoutZero(0.5)}}
+			fused, err := h.FuseHits(ctx, conf, hits)
+			convey.So(err, convey.ShouldBeNil)
+			// 1: 1+0, 2: 0.75, 3: 0+1, 4: 0.5
+			convey.So(ids(fused), convey.ShouldResemble, []string{"1", "3", "2", "4"})
+			convey.So(float64(*fused[2].Score_), convey.ShouldAlmostEqual, 0.75)
+
+			h = &hybrid{config: &HybridConfig{Fusion: HybridFusionClientWeighted, FusedScoreThreshold: ptrWithoutZero(0.6)}}
+			fused, err = h.FuseHits(ctx, conf, hits)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(fused), convey.ShouldResemble, []string{"1", "3", "2"})
+		})
+
+		PatchConvey("test FuseHits with score threshold per query", func() {
+			// a bm25 / similarity threshold keeps rrf results instead of dropping all of them
+			h := &hybrid{config: &HybridConfig{}}
+			fused, err := h.FuseHits(ctx, conf, hits, retriever.WithScoreThreshold(0.75))
+			convey.So(err, convey.ShouldBeNil)
+			// text: 1, 2, 3; knn: 3, 4
+			// 3: 1/63+1/61, 1: 1/61, 4: 1/62, 2: 1/62
+			convey.So(ids(fused), convey.ShouldResemble, []string{"3", "1", "2", "4"})
+
+			h = &hybrid{config: &HybridConfig{FusedScoreThreshold: ptrWithoutZero(0.02)}}
+			fused, err = h.FuseHits(ctx, conf, hits, retriever.WithScoreThreshold(0.75))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids(fused), convey.ShouldResemble, []string{"3"})
+		})
+
+		PatchConvey("test FuseHits error", func() {
+			h := &hybrid{config: &HybridConfig{}}
+			_, err := h.FuseHits(ctx, conf, hits[:1])
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
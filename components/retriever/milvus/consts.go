@@ -26,7 +26,10 @@ const (
 	defaultAutoIndexLevel = 1
 	defaultLoadedProgress = 100
 
-	defaultMetricType = entity.HAMMING
+	defaultMetricType      = entity.HAMMING
+	defaultFloatMetricType = entity.COSINE
+
+	defaultSparseDropRatio = 0
 
 	typeParamDim = "dim"
 )
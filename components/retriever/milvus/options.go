@@ -31,6 +31,10 @@ type ImplOptions struct {
 	// Optional, and the default value is nil
 	// It's means the milvus search extra search options, and refer to client.SearchQueryOptionFunc
 	SearchQueryOptFn func(option *client.SearchQueryOption)
+
+	// SparseVector is the sparse vector of the query, key indices -> value
+	// Optional, and it's only used when RetrieverConfig.SparseVectorField is set
+	SparseVector map[int]float64
 }
 
 func WithFilter(filter string) retriever.Option {
@@ -44,3 +48,10 @@ func WithSearchQueryOptFn(f func(option *client.SearchQueryOption)) retriever.Op
 		o.SearchQueryOptFn = f
 	})
 }
+
+// WithSparseVector sets the sparse vector of the query to run a hybrid search.
+func WithSparseVector(sparse map[int]float64) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.SparseVector = sparse
+	})
+}
@@ -53,9 +53,11 @@ type RetrieverConfig struct {
 	// Optional, and the default value is defaultDocumentConverter
 	DocumentConverter func(ctx context.Context, doc client.SearchResult) ([]*schema.Document, error)
 	// VectorConverter is the function to convert the vectors to entity.Vector
+	// Optional, and the default value converts the vectors by the data type of VectorField in the collection,
+	// which could be binary, float, float16 or bfloat16 vector
 	VectorConverter func(ctx context.Context, vectors [][]float64) ([]entity.Vector, error)
 	// MetricType is the metric type for vector
-	// Optional, and the default value is "HAMMING"
+	// Optional, and the default value is "HAMMING" for binary vector, "COSINE" for the float vectors
 	MetricType entity.MetricType
 	// TopK is the top k results to be returned
 	// Optional, and the default value is 5
@@ -65,8 +67,20 @@ type RetrieverConfig struct {
 	ScoreThreshold float64
 	// SearchParams
 	// Optional, and the default value is entity.IndexAUTOINDEXSearchParam, and the level is 1
+	// For the float vectors, ScoreThreshold is used as the search radius if it is larger than 0
 	Sp entity.SearchParam
 	
+	// Hybrid search config
+	// SparseVectorField is the sparse vector field name in the collection
+	// Optional, and when it is set, Retrieve runs a dense + sparse HybridSearch if WithSparseVector is provided
+	SparseVectorField string
+	// SparseSp is the search params for the sparse vector field
+	// Optional, and the default value is entity.IndexSparseInvertedSearchParam with drop ratio 0
+	SparseSp entity.SearchParam
+	// Ranker fuses the dense and sparse results of a hybrid search, such as client.NewWeightedReranker
+	// Optional, and the default value is client.NewRRFReranker()
+	Ranker client.Reranker
+	
 	// Embedding is the embedding vectorization method for values needs to be embedded from schema.Document's content.
 	// Required
 	Embedding embedding.Embedder
@@ -106,6 +120,25 @@ func NewRetriever(ctx context.Context, config *RetrieverConfig) (*Retriever, err
 	if err := checkCollectionSchema(config.VectorField, collection.Schema); err != nil {
 		return nil, fmt.Errorf("[NewRetriever] collection schema not match: %w", err)
 	}
+	if config.SparseVectorField != "" {
+		if err := checkCollectionSchema(config.SparseVectorField, collection.Schema); err != nil {
+			return nil, fmt.Errorf("[NewRetriever] collection sparse vector field not match: %w", err)
+		}
+	}
+	vectorType, err := getVectorFieldType(config.VectorField, collection.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("[NewRetriever] collection schema not match: %w", err)
+	}
+	floatVector := isFloatVector(vectorType)
+	if config.VectorConverter == nil {
+		config.VectorConverter = defaultVectorConverterOf(vectorType)
+	}
+	if config.MetricType == "" {
+		config.MetricType = defaultMetricType
+		if floatVector {
+			config.MetricType = defaultFloatMetricType
+		}
+	}
 	
 	// check the collection load state
 	if !collection.Loaded {
@@ -115,6 +148,9 @@ func NewRetriever(ctx context.Context, config *RetrieverConfig) (*Retriever, err
 		}
 	}
 	
+	if config.Sp == nil && floatVector {
+		config.Sp = defaultFloatSearchParam(config.ScoreThreshold)
+	}
 	if config.Sp == nil {
 		dim, err := getCollectionDim(config.VectorField, collection.Schema)
 		if err != nil {
@@ -125,6 +161,9 @@ func NewRetriever(ctx context.Context, config *RetrieverConfig) (*Retriever, err
 	
 	// get the score threshold
 	scoreThreshold, ok := config.Sp.Params()["range_filter"]
+	if !ok && floatVector {
+		scoreThreshold, ok = config.Sp.Params()["radius"]
+	}
 	if !ok {
 		config.ScoreThreshold = 0
 	} else {
@@ -145,6 +184,9 @@ func NewRetriever(ctx context.Context, config *RetrieverConfig) (*Retriever, err
 			TopK:              config.TopK,
 			ScoreThreshold:    config.ScoreThreshold,
 			Sp:                config.Sp,
+			SparseVectorField: config.SparseVectorField,
+			SparseSp:          config.SparseSp,
+			Ranker:            config.Ranker,
 			Embedding:         config.Embedding,
 		},
 	}, nil
@@ -207,19 +249,23 @@ func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retrieve
 		searchParams = append(searchParams, io.SearchQueryOptFn)
 	}
 	
-	results, err = r.config.Client.Search(
-		ctx,
-		r.config.Collection,
-		r.config.Partition,
-		io.Filter,
-		r.config.OutputFields,
-		vec,
-		r.config.VectorField,
-		r.config.MetricType,
-		*co.TopK,
-		r.config.Sp,
-		searchParams...,
-	)
+	if r.config.SparseVectorField != "" && len(io.SparseVector) > 0 {
+		results, err = r.hybridSearch(ctx, vec, io, *co.TopK)
+	} else {
+		results, err = r.config.Client.Search(
+			ctx,
+			r.config.Collection,
+			r.config.Partition,
+			io.Filter,
+			r.config.OutputFields,
+			vec,
+			r.config.VectorField,
+			r.config.MetricType,
+			*co.TopK,
+			r.config.Sp,
+			searchParams...,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("[milvus retriever] search has error: %w", err)
 	}
@@ -250,6 +296,33 @@ func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retrieve
 	return documents, nil
 }
 
+// hybridSearch searches the dense and sparse vector fields and fuses the results with the configured ranker
+func (r *Retriever) hybridSearch(ctx context.Context, vec []entity.Vector, io *ImplOptions, topK int) ([]client.SearchResult, error) {
+	sparse, err := sparse2Embedding(io.SparseVector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert sparse vector: %w", err)
+	}
+	
+	var searchParams []client.SearchQueryOptionFunc
+	if io.SearchQueryOptFn != nil {
+		searchParams = append(searchParams, io.SearchQueryOptFn)
+	}
+	subRequests := []*client.ANNSearchRequest{
+		client.NewANNSearchRequest(r.config.VectorField, r.config.MetricType, io.Filter, vec, r.config.Sp, topK, searchParams...),
+		client.NewANNSearchRequest(r.config.SparseVectorField, entity.IP, io.Filter, []entity.Vector{sparse}, r.config.SparseSp, topK, searchParams...),
+	}
+	
+	return r.config.Client.HybridSearch(
+		ctx,
+		r.config.Collection,
+		r.config.Partition,
+		topK,
+		r.config.OutputFields,
+		r.config.Ranker,
+		subRequests,
+	)
+}
+
 func (r *Retriever) GetType() string {
 	return typ
 }
@@ -284,14 +357,16 @@ func (r *RetrieverConfig) check() error {
 	if r.DocumentConverter == nil {
 		r.DocumentConverter = defaultDocumentConverter()
 	}
-	if r.VectorConverter == nil {
-		r.VectorConverter = defaultVectorConverter()
-	}
 	if r.TopK == 0 {
 		r.TopK = defaultTopK
 	}
-	if r.MetricType == "" {
-		r.MetricType = defaultMetricType
+	if r.SparseVectorField != "" {
+		if r.SparseSp == nil {
+			r.SparseSp, _ = entity.NewIndexSparseInvertedSearchParam(defaultSparseDropRatio)
+		}
+		if r.Ranker == nil {
+			r.Ranker = client.NewRRFReranker()
+		}
 	}
 	return nil
 }
@@ -414,6 +414,211 @@ func TestRetriever_Retrieve(t *testing.T) {
 	})
 }
 
+func TestRetriever_HybridSearch(t *testing.T) {
+	PatchConvey("test Retriever.Retrieve with hybrid search", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+
+		Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+		Mock(GetMethod(mockClient, "DescribeCollection")).Return(&entity.Collection{
+			Loaded: true,
+			Schema: &entity.Schema{
+				Fields: []*entity.Field{
+					{
+						Name:       defaultVectorField,
+						DataType:   entity.FieldTypeBinaryVector,
+						TypeParams: map[string]string{"dim": "128"},
+					},
+					{
+						Name:     "sparse_vector",
+						DataType: entity.FieldTypeSparseVector,
+					},
+				},
+			},
+		}, nil).Build()
+
+		var (
+			searchCalled bool
+			ranker       client.Reranker
+			subRequests  []*client.ANNSearchRequest
+		)
+		Mock(GetMethod(mockClient, "Search")).To(func(ctx context.Context, collName string, partitions []string, expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...client.SearchQueryOptionFunc) ([]client.SearchResult, error) {
+			searchCalled = true
+			return []client.SearchResult{
+				{
+					IDs:    entity.NewColumnVarChar("id", []string{"1"}),
+					Fields: []entity.Column{entity.NewColumnVarChar("content", []string{"dense"})},
+				},
+			}, nil
+		}).Build()
+		Mock(GetMethod(mockClient, "HybridSearch")).To(func(ctx context.Context, collName string, partitions []string, limit int, outputFields []string, reranker client.Reranker, requests []*client.ANNSearchRequest, opts ...client.SearchQueryOptionFunc) ([]client.SearchResult, error) {
+			ranker = reranker
+			subRequests = requests
+			return []client.SearchResult{
+				{
+					IDs: entity.NewColumnVarChar("id", []string{"1", "2"}),
+					Fields: []entity.Column{
+						entity.NewColumnVarChar("id", []string{"1", "2"}),
+						entity.NewColumnVarChar("content", []string{"hybrid", "hybrid"}),
+					},
+					Scores: []float32{0.9, 0.8},
+				},
+			}, nil
+		}).Build()
+
+		PatchConvey("test sparse vector field not found", func() {
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:            mockClient,
+				SparseVectorField: "not_exist",
+				Embedding:         &mockEmbedding{sizeForCall: []int{1}},
+			})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(r, convey.ShouldBeNil)
+		})
+
+		PatchConvey("test fallback to dense search without sparse vector", func() {
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:            mockClient,
+				SparseVectorField: "sparse_vector",
+				Embedding:         &mockEmbedding{sizeForCall: []int{1}},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			documents, err := r.Retrieve(ctx, "test")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(searchCalled, convey.ShouldBeTrue)
+			convey.So(len(documents), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test hybrid search with default ranker", func() {
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:            mockClient,
+				SparseVectorField: "sparse_vector",
+				Embedding:         &mockEmbedding{sizeForCall: []int{1}},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			documents, err := r.Retrieve(ctx, "test", WithSparseVector(map[int]float64{3: 0.5, 1: 0.2}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(searchCalled, convey.ShouldBeFalse)
+			convey.So(len(subRequests), convey.ShouldEqual, 2)
+			convey.So(ranker.GetParams()[0].GetKey(), convey.ShouldEqual, "strategy")
+			convey.So(ranker.GetParams()[0].GetValue(), convey.ShouldEqual, "rrf")
+			convey.So(len(documents), convey.ShouldEqual, 2)
+			convey.So(documents[0].Content, convey.ShouldEqual, "hybrid")
+		})
+
+		PatchConvey("test hybrid search with weighted ranker", func() {
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:            mockClient,
+				SparseVectorField: "sparse_vector",
+				Ranker:            client.NewWeightedReranker([]float64{0.7, 0.3}),
+				Embedding:         &mockEmbedding{sizeForCall: []int{1}},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "test", WithSparseVector(map[int]float64{1: 0.2}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ranker.GetParams()[0].GetValue(), convey.ShouldEqual, "weighted")
+		})
+	})
+}
+
+func TestRetriever_FloatVector(t *testing.T) {
+	PatchConvey("test Retriever.Retrieve with float vectors", t, func() {
+		ctx := context.Background()
+		Mock(client.NewClient).Return(&client.GrpcClient{}, nil).Build()
+		mockClient, _ := client.NewClient(ctx, client.Config{})
+
+		var fieldType entity.FieldType
+		Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+		Mock(GetMethod(mockClient, "DescribeCollection")).To(func(ctx context.Context, collName string) (*entity.Collection, error) {
+			return &entity.Collection{
+				Loaded: true,
+				Schema: &entity.Schema{
+					Fields: []*entity.Field{
+						{
+							Name:       defaultVectorField,
+							DataType:   fieldType,
+							TypeParams: map[string]string{"dim": "2"},
+						},
+					},
+				},
+			}, nil
+		}).Build()
+
+		var (
+			searchVectors []entity.Vector
+			searchMetric  entity.MetricType
+			searchParam   entity.SearchParam
+		)
+		Mock(GetMethod(mockClient, "Search")).To(func(ctx context.Context, collName string, partitions []string, expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...client.SearchQueryOptionFunc) ([]client.SearchResult, error) {
+			searchVectors, searchMetric, searchParam = vectors, metricType, sp
+			return []client.SearchResult{
+				{
+					IDs:    entity.NewColumnVarChar("id", []string{"1"}),
+					Fields: []entity.Column{entity.NewColumnVarChar("content", []string{"test"})},
+				},
+			}, nil
+		}).Build()
+
+		PatchConvey("test float vector", func() {
+			fieldType = entity.FieldTypeFloatVector
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:         mockClient,
+				ScoreThreshold: 0.5,
+				Embedding:      &mockEmbedding{sizeForCall: []int{1}, dims: 2},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(r.config.ScoreThreshold, convey.ShouldEqual, 0.5)
+			_, err = r.Retrieve(ctx, "test")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(searchMetric, convey.ShouldEqual, entity.COSINE)
+			convey.So(searchVectors[0], convey.ShouldResemble, entity.FloatVector{1.1, 1.1})
+			convey.So(searchParam.Params()["radius"], convey.ShouldEqual, 0.5)
+		})
+
+		PatchConvey("test float16 vector", func() {
+			fieldType = entity.FieldTypeFloat16Vector
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:     mockClient,
+				MetricType: entity.L2,
+				Embedding:  &mockEmbedding{sizeForCall: []int{1}, dims: 2},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "test")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(searchMetric, convey.ShouldEqual, entity.L2)
+			convey.So(searchVectors[0], convey.ShouldResemble, entity.Float16Vector{0x66, 0x3c, 0x66, 0x3c})
+			_, ok := searchParam.Params()["radius"]
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+
+		PatchConvey("test bfloat16 vector", func() {
+			fieldType = entity.FieldTypeBFloat16Vector
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:    mockClient,
+				Embedding: &mockEmbedding{sizeForCall: []int{1}, dims: 2},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "test")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(searchVectors[0], convey.ShouldResemble, entity.BFloat16Vector{0x8d, 0x3f, 0x8d, 0x3f})
+		})
+	})
+}
+
+func TestSparse2Embedding(t *testing.T) {
+	convey.Convey("test sparse2Embedding", t, func() {
+		emb, err := sparse2Embedding(map[int]float64{9: 0.9, 2: 0.2, 5: 0.5})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(emb.Len(), convey.ShouldEqual, 3)
+		for i, expected := range []uint32{2, 5, 9} {
+			pos, _, ok := emb.Get(i)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(pos, convey.ShouldEqual, expected)
+		}
+	})
+}
+
 type mockEmbedding struct {
 	err         error
 	cnt         int
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -100,6 +101,53 @@ func defaultVectorConverter() func(ctx context.Context, vectors [][]float64) ([]
 	}
 }
 
+// defaultVectorConverterOf returns the default vector converter for the vector field type
+func defaultVectorConverterOf(fieldType entity.FieldType) func(ctx context.Context, vectors [][]float64) ([]entity.Vector, error) {
+	if !isFloatVector(fieldType) {
+		return defaultVectorConverter()
+	}
+	return func(ctx context.Context, vectors [][]float64) ([]entity.Vector, error) {
+		vec := make([]entity.Vector, 0, len(vectors))
+		for _, vector := range vectors {
+			switch fieldType {
+			case entity.FieldTypeFloat16Vector:
+				vec = append(vec, entity.Float16Vector(vector2Float16Bytes(vector)))
+			case entity.FieldTypeBFloat16Vector:
+				vec = append(vec, entity.BFloat16Vector(vector2BFloat16Bytes(vector)))
+			default:
+				vec = append(vec, entity.FloatVector(vector2Float32(vector)))
+			}
+		}
+		return vec, nil
+	}
+}
+
+// defaultFloatSearchParam returns the default search param for the float vectors
+func defaultFloatSearchParam(score float64) entity.SearchParam {
+	searchParam, _ := entity.NewIndexAUTOINDEXSearchParam(defaultAutoIndexLevel)
+	if score > 0 {
+		searchParam.AddRadius(score)
+	}
+	return searchParam
+}
+
+// isFloatVector reports whether the field type is one of the float vectors
+func isFloatVector(fieldType entity.FieldType) bool {
+	return fieldType == entity.FieldTypeFloatVector ||
+		fieldType == entity.FieldTypeFloat16Vector ||
+		fieldType == entity.FieldTypeBFloat16Vector
+}
+
+// getVectorFieldType gets the data type of the vector field
+func getVectorFieldType(field string, s *entity.Schema) (entity.FieldType, error) {
+	for _, column := range s.Fields {
+		if column.Name == field {
+			return column.DataType, nil
+		}
+	}
+	return entity.FieldTypeNone, errors.New("vector field not found")
+}
+
 // checkCollectionSchema checks if the vector field exists in the schema
 func checkCollectionSchema(field string, s *entity.Schema) error {
 	for _, column := range s.Fields {
@@ -194,3 +242,94 @@ func vector2Bytes(vector []float64) []byte {
 	}
 	return bytes
 }
+
+// sparse2Embedding converts the sparse vector to entity.SparseEmbedding
+func sparse2Embedding(sparse map[int]float64) (entity.SparseEmbedding, error) {
+	positions := make([]uint32, 0, len(sparse))
+	for pos := range sparse {
+		positions = append(positions, uint32(pos))
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	values := make([]float32, 0, len(positions))
+	for _, pos := range positions {
+		values = append(values, float32(sparse[int(pos)]))
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
+
+// vector2Float32 converts the vector to float32 slice
+func vector2Float32(vector []float64) []float32 {
+	float32Arr := make([]float32, len(vector))
+	for i, v := range vector {
+		float32Arr[i] = float32(v)
+	}
+	return float32Arr
+}
+
+// vector2Float16Bytes converts the vector to IEEE 754 half precision bytes in little endian
+func vector2Float16Bytes(vector []float64) []byte {
+	bytes := make([]byte, len(vector)*2)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint16(bytes[i*2:], float32ToFloat16(float32(v)))
+	}
+	return bytes
+}
+
+// vector2BFloat16Bytes converts the vector to bfloat16 bytes in little endian
+func vector2BFloat16Bytes(vector []float64) []byte {
+	bytes := make([]byte, len(vector)*2)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint16(bytes[i*2:], float32ToBFloat16(float32(v)))
+	}
+	return bytes
+}
+
+// float32ToFloat16 converts float32 to half precision bits, rounding to nearest even
+func float32ToFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+	switch {
+	case bits&0x7fffffff == 0:
+		return sign
+	case bits&0x7f800000 == 0x7f800000 && mant != 0:
+		// NaN
+		return sign | 0x7e00
+	case exp >= 0x1f:
+		// overflow to Inf
+		return sign | 0x7c00
+	case exp <= 0:
+		// subnormal
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem, halfway := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		// the carry could overflow into exponent, which is still the right result
+		half++
+	}
+	return half
+}
+
+// float32ToBFloat16 converts float32 to bfloat16 bits, rounding to nearest even
+func float32ToBFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	if bits&0x7fffffff > 0x7f800000 {
+		// NaN, keep it quiet
+		return uint16(bits>>16) | 0x40
+	}
+	bits += 0x7fff + (bits>>16)&1
+	return uint16(bits >> 16)
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import "github.com/cloudwego/eino/components/retriever"
+
+type ImplOptions struct {
+	// Filter is the boolean expression to filter the search results
+	// Optional, and the default value is empty, refer to https://milvus.io/docs/boolean.md
+	Filter string
+
+	// SparseVector is the sparse vector of the query, key indices -> value
+	// Optional, it's required by SearchModeSparse and SearchModeHybrid unless the sparse field is generated by BM25
+	SparseVector map[int]float64
+}
+
+// WithFilter sets the boolean expression to filter the search results.
+func WithFilter(filter string) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.Filter = filter
+	})
+}
+
+// WithSparseVector sets the sparse vector of the query.
+func WithSparseVector(sparse map[int]float64) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.SparseVector = sparse
+	})
+}
@@ -0,0 +1,375 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// SearchMode decides which vector fields are searched
+type SearchMode string
+
+const (
+	// SearchModeDense searches the dense vector field with the embedding of the query
+	SearchModeDense SearchMode = "dense"
+	// SearchModeSparse searches the sparse vector field only, it's the full-text search if the field is generated by BM25
+	SearchModeSparse SearchMode = "sparse"
+	// SearchModeHybrid searches both of the dense and sparse vector fields, and fuses the results with Ranker
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+type RetrieverConfig struct {
+	// Client is the milvus client to be called
+	// It requires the milvusclient of github.com/milvus-io/milvus/client/v2
+	// Required
+	Client *milvusclient.Client
+
+	// Collection is the collection name in the milvus database
+	// Optional, and the default value is "eino_collection"
+	Collection string
+	// Partitions is the collection partition names
+	// Optional, and the default value is empty
+	Partitions []string
+	// VectorField is the dense vector field name in the collection
+	// Optional, and the default value is "vector"
+	VectorField string
+	// SparseVectorField is the sparse vector field name in the collection
+	// Optional, and it's required by SearchModeSparse and SearchModeHybrid
+	SparseVectorField string
+	// OutputFields is the fields to be returned
+	// Optional, and the default value is id, content and metadata
+	OutputFields []string
+
+	// SearchMode is the search mode of the retriever
+	// Optional, and the default value is SearchModeDense
+	SearchMode SearchMode
+	// MetricType is the metric type for the dense vector, which should be the same as the index
+	// Optional, and the default value is the metric type of the index
+	MetricType entity.MetricType
+	// AnnParam is the search param of the dense vector, such as index.NewHNSWAnnParam
+	// Optional, and the default value is empty, or the radius of ScoreThreshold if it is larger than 0
+	AnnParam index.AnnParam
+	// Ranker fuses the dense and sparse results in SearchModeHybrid, such as milvusclient.NewWeightedReranker
+	// Optional, and the default value is milvusclient.NewRRFReranker()
+	Ranker milvusclient.Reranker
+	// TopK is the top k results to be returned
+	// Optional, and the default value is 5
+	TopK int
+	// ScoreThreshold is the radius of the dense vector search
+	// Optional, and the default value is 0(disable)
+	ScoreThreshold float64
+
+	// DocumentConverter is the function to convert the search result to schema.Document
+	// Optional, and the default value is defaultDocumentConverter
+	DocumentConverter func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error)
+	// VectorConverter is the function to convert the embedding vectors to entity.Vector
+	// Optional, and the default value converts the vectors by the data type of VectorField in the collection
+	VectorConverter func(ctx context.Context, vectors [][]float64) ([]entity.Vector, error)
+
+	// Embedding is the embedding vectorization method for values needs to be embedded from schema.Document's content.
+	// Required by SearchModeDense and SearchModeHybrid
+	Embedding embedding.Embedder
+}
+
+type Retriever struct {
+	config RetrieverConfig
+	// bm25 is true if the sparse vector field is generated by the BM25 function of the collection
+	bm25 bool
+}
+
+func NewRetriever(ctx context.Context, config *RetrieverConfig) (*Retriever, error) {
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+
+	ok, err := config.Client.HasCollection(ctx, milvusclient.NewHasCollectionOption(config.Collection))
+	if err != nil {
+		return nil, fmt.Errorf("[NewRetriever] failed to check collection: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("[NewRetriever] collection not found")
+	}
+
+	collection, err := config.Client.DescribeCollection(ctx, milvusclient.NewDescribeCollectionOption(config.Collection))
+	if err != nil {
+		return nil, fmt.Errorf("[NewRetriever] failed to describe collection: %w", err)
+	}
+	if config.SearchMode != SearchModeSparse {
+		vectorType, err := getFieldType(config.VectorField, collection.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("[NewRetriever] collection schema not match: %w", err)
+		}
+		if config.VectorConverter == nil {
+			config.VectorConverter = defaultVectorConverter(vectorType)
+		}
+	}
+	if config.SearchMode != SearchModeDense {
+		if _, err := getFieldType(config.SparseVectorField, collection.Schema); err != nil {
+			return nil, fmt.Errorf("[NewRetriever] collection schema not match: %w", err)
+		}
+	}
+
+	if !collection.Loaded {
+		task, err := config.Client.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(config.Collection))
+		if err != nil {
+			return nil, fmt.Errorf("[NewRetriever] failed to load collection: %w", err)
+		}
+		if err := task.Await(ctx); err != nil {
+			return nil, fmt.Errorf("[NewRetriever] failed to load collection: %w", err)
+		}
+	}
+
+	return &Retriever{
+		config: *config,
+		bm25:   isBM25Output(config.SparseVectorField, collection.Schema),
+	}, nil
+}
+
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK:           &r.config.TopK,
+		ScoreThreshold: &r.config.ScoreThreshold,
+		Embedding:      r.config.Embedding,
+	}, opts...)
+	io := retriever.GetImplSpecificOptions(&ImplOptions{}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query:          query,
+		TopK:           *co.TopK,
+		Filter:         io.Filter,
+		ScoreThreshold: co.ScoreThreshold,
+		Extra: map[string]any{
+			"search_mode": r.config.SearchMode,
+		},
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var dense, sparse *annRequestBuilder
+	if r.config.SearchMode != SearchModeSparse {
+		dense, err = r.denseRequest(ctx, query, co, io)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.config.SearchMode != SearchModeDense {
+		sparse, err = r.sparseRequest(query, *co.TopK, io)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results []milvusclient.ResultSet
+	switch r.config.SearchMode {
+	case SearchModeHybrid:
+		results, err = r.config.Client.HybridSearch(ctx, r.hybridSearchOption(*co.TopK, dense, sparse))
+	default:
+		req := dense
+		if r.config.SearchMode == SearchModeSparse {
+			req = sparse
+		}
+		results, err = r.config.Client.Search(ctx, r.searchOption(req))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[milvus2 retriever] search has error: %w", err)
+	}
+
+	docs = make([]*schema.Document, 0, *co.TopK)
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("[milvus2 retriever] search result has error: %w", result.Err)
+		}
+		documents, err := r.config.DocumentConverter(ctx, result)
+		if err != nil {
+			return nil, fmt.Errorf("[milvus2 retriever] failed to convert search result to schema.Document: %w", err)
+		}
+		docs = append(docs, documents...)
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+	return docs, nil
+}
+
+// denseRequest embeds the query and builds the search request of the dense vector field
+func (r *Retriever) denseRequest(ctx context.Context, query string, co *retriever.Options, io *ImplOptions) (*annRequestBuilder, error) {
+	emb := co.Embedding
+	if emb == nil {
+		return nil, fmt.Errorf("[milvus2 retriever] embedding not provided")
+	}
+	vectors, err := emb.EmbedStrings(makeEmbeddingCtx(ctx, emb), []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("[milvus2 retriever] embedding has error: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("[milvus2 retriever] invalid return length of vector, got=%d, expected=1", len(vectors))
+	}
+	vec, err := r.config.VectorConverter(ctx, vectors)
+	if err != nil {
+		return nil, fmt.Errorf("[milvus2 retriever] failed to convert vector: %w", err)
+	}
+
+	annParam := r.config.AnnParam
+	if annParam == nil && co.ScoreThreshold != nil && *co.ScoreThreshold > 0 {
+		p := index.NewCustomAnnParam()
+		p.WithRadius(*co.ScoreThreshold)
+		annParam = p
+	}
+	return &annRequestBuilder{
+		field:    r.config.VectorField,
+		vectors:  vec,
+		topK:     *co.TopK,
+		filter:   io.Filter,
+		metric:   r.config.MetricType,
+		annParam: annParam,
+	}, nil
+}
+
+// sparseRequest builds the search request of the sparse vector field,
+// the query text is searched directly if the field is generated by BM25
+func (r *Retriever) sparseRequest(query string, topK int, io *ImplOptions) (*annRequestBuilder, error) {
+	var vec entity.Vector
+	if r.bm25 {
+		vec = entity.Text(query)
+	} else {
+		if len(io.SparseVector) == 0 {
+			return nil, fmt.Errorf("[milvus2 retriever] sparse vector not provided")
+		}
+		sparse, err := sparse2Embedding(io.SparseVector)
+		if err != nil {
+			return nil, fmt.Errorf("[milvus2 retriever] failed to convert sparse vector: %w", err)
+		}
+		vec = sparse
+	}
+	return &annRequestBuilder{
+		field:   r.config.SparseVectorField,
+		vectors: []entity.Vector{vec},
+		topK:    topK,
+		filter:  io.Filter,
+	}, nil
+}
+
+// annRequestBuilder holds the params of an ann search request
+type annRequestBuilder struct {
+	field    string
+	vectors  []entity.Vector
+	topK     int
+	filter   string
+	metric   entity.MetricType
+	annParam index.AnnParam
+}
+
+// searchOption builds the option to search a single vector field
+func (r *Retriever) searchOption(b *annRequestBuilder) milvusclient.SearchOption {
+	option := milvusclient.NewSearchOption(r.config.Collection, b.topK, b.vectors).
+		WithANNSField(b.field).
+		WithFilter(b.filter).
+		WithOutputFields(r.config.OutputFields...)
+	if len(r.config.Partitions) > 0 {
+		option = option.WithPartitions(r.config.Partitions...)
+	}
+	if b.annParam != nil {
+		option = option.WithAnnParam(b.annParam)
+	}
+	if b.metric != "" {
+		option = option.WithSearchParam(index.MetricTypeKey, string(b.metric))
+	}
+	return option
+}
+
+// hybridSearchOption builds the option to search the dense and sparse vector fields, and fuse the results by Ranker
+func (r *Retriever) hybridSearchOption(topK int, dense, sparse *annRequestBuilder) milvusclient.HybridSearchOption {
+	denseReq := milvusclient.NewAnnRequest(dense.field, dense.topK, dense.vectors...).WithFilter(dense.filter)
+	if dense.annParam != nil {
+		denseReq = denseReq.WithAnnParam(dense.annParam)
+	}
+	if dense.metric != "" {
+		denseReq = denseReq.WithSearchParam(index.MetricTypeKey, string(dense.metric))
+	}
+	sparseReq := milvusclient.NewAnnRequest(sparse.field, sparse.topK, sparse.vectors...).WithFilter(sparse.filter)
+
+	option := milvusclient.NewHybridSearchOption(r.config.Collection, topK, denseReq, sparseReq).
+		WithReranker(r.config.Ranker).
+		WithOutputFields(r.config.OutputFields...)
+	if len(r.config.Partitions) > 0 {
+		option = option.WithPartitions(r.config.Partitions...)
+	}
+	return option
+}
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+// check the retriever config and set the default value
+func (r *RetrieverConfig) check() error {
+	if r.Client == nil {
+		return fmt.Errorf("[NewRetriever] milvus client not provided")
+	}
+	if r.SearchMode == "" {
+		r.SearchMode = SearchModeDense
+	}
+	switch r.SearchMode {
+	case SearchModeDense, SearchModeSparse, SearchModeHybrid:
+	default:
+		return fmt.Errorf("[NewRetriever] unknown search mode: %s", r.SearchMode)
+	}
+	if r.SearchMode != SearchModeSparse && r.Embedding == nil {
+		return fmt.Errorf("[NewRetriever] embedding not provided")
+	}
+	if r.SearchMode != SearchModeDense && r.SparseVectorField == "" {
+		return fmt.Errorf("[NewRetriever] sparse vector field not provided")
+	}
+	if r.ScoreThreshold < 0 {
+		return fmt.Errorf("[NewRetriever] invalid score threshold")
+	}
+	if r.Collection == "" {
+		r.Collection = defaultCollection
+	}
+	if r.VectorField == "" {
+		r.VectorField = defaultVectorField
+	}
+	if r.OutputFields == nil {
+		r.OutputFields = []string{defaultIDField, defaultContentField, defaultMetadataField}
+	}
+	if r.Ranker == nil {
+		r.Ranker = milvusclient.NewRRFReranker()
+	}
+	if r.TopK <= 0 {
+		r.TopK = defaultTopK
+	}
+	if r.DocumentConverter == nil {
+		r.DocumentConverter = defaultDocumentConverter()
+	}
+	return nil
+}
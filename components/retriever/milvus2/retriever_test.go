@@ -0,0 +1,235 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+type mockEmbedding struct {
+	err error
+}
+
+func (m *mockEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([][]float64, len(texts))
+	for i := range texts {
+		result[i] = []float64{1, -2}
+	}
+	return result, nil
+}
+
+func mockSchema(vectorType entity.FieldType, bm25 bool) *entity.Schema {
+	s := entity.NewSchema().
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar)).
+		WithField(entity.NewField().WithName("vector").WithDataType(vectorType).WithDim(2)).
+		WithField(entity.NewField().WithName("metadata").WithDataType(entity.FieldTypeJSON)).
+		WithField(entity.NewField().WithName("sparse").WithDataType(entity.FieldTypeSparseVector))
+	if bm25 {
+		s.WithFunction(entity.NewFunction().
+			WithName("bm25_fn").
+			WithType(entity.FunctionTypeBM25).
+			WithInputFields("content").
+			WithOutputFields("sparse"))
+	}
+	return s
+}
+
+func mockResultSet() milvusclient.ResultSet {
+	return milvusclient.ResultSet{
+		ResultCount: 2,
+		IDs:         column.NewColumnVarChar("id", []string{"1", "2"}),
+		Fields: milvusclient.DataSet{
+			column.NewColumnVarChar("id", []string{"1", "2"}),
+			column.NewColumnVarChar("content", []string{"a", "b"}),
+			column.NewColumnJSONBytes("metadata", [][]byte{[]byte(`{"k":"v"}`), []byte(`{}`)}),
+			column.NewColumnInt64("extra", []int64{7, 8}),
+		},
+		Scores: []float32{0.9, 0.5},
+	}
+}
+
+func TestNewRetriever(t *testing.T) {
+	PatchConvey("test NewRetriever", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		mockEmb := &mockEmbedding{}
+
+		PatchConvey("test retriever config check", func() {
+			_, err := NewRetriever(ctx, &RetrieverConfig{Embedding: mockEmb})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewRetriever] milvus client not provided"))
+
+			_, err = NewRetriever(ctx, &RetrieverConfig{Client: mockClient})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewRetriever] embedding not provided"))
+
+			_, err = NewRetriever(ctx, &RetrieverConfig{Client: mockClient, SearchMode: SearchModeHybrid, Embedding: mockEmb})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewRetriever] sparse vector field not provided"))
+
+			_, err = NewRetriever(ctx, &RetrieverConfig{Client: mockClient, SearchMode: "unknown"})
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewRetriever] unknown search mode: unknown"))
+		})
+
+		PatchConvey("test collection", func() {
+			Mock((*milvusclient.Client).HasCollection).Return(true, nil).Build()
+			Mock((*milvusclient.Client).DescribeCollection).Return(&entity.Collection{Schema: mockSchema(entity.FieldTypeFloatVector, true)}, nil).Build()
+
+			PatchConvey("test collection not found", func() {
+				Mock((*milvusclient.Client).HasCollection).Return(false, nil).Build()
+				_, err := NewRetriever(ctx, &RetrieverConfig{Client: mockClient, Embedding: mockEmb})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewRetriever] collection not found"))
+			})
+
+			PatchConvey("test vector field not found", func() {
+				_, err := NewRetriever(ctx, &RetrieverConfig{Client: mockClient, VectorField: "not_exist", Embedding: mockEmb})
+				convey.So(err, convey.ShouldBeError, fmt.Errorf("[NewRetriever] collection schema not match: vector field not found"))
+			})
+
+			PatchConvey("test load collection", func() {
+				var loaded bool
+				Mock((*milvusclient.Client).LoadCollection).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.LoadCollectionOption, callOptions ...grpc.CallOption) (milvusclient.LoadTask, error) {
+					loaded = true
+					return milvusclient.LoadTask{}, nil
+				}).Build()
+				Mock((*milvusclient.LoadTask).Await).Return(nil).Build()
+				r, err := NewRetriever(ctx, &RetrieverConfig{Client: mockClient, SparseVectorField: "sparse", SearchMode: SearchModeSparse})
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(loaded, convey.ShouldBeTrue)
+				convey.So(r.bm25, convey.ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestRetriever_Retrieve(t *testing.T) {
+	PatchConvey("test Retriever.Retrieve", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		var bm25 bool
+		var vectorType entity.FieldType
+
+		Mock((*milvusclient.Client).HasCollection).Return(true, nil).Build()
+		Mock((*milvusclient.Client).DescribeCollection).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.DescribeCollectionOption, callOptions ...grpc.CallOption) (*entity.Collection, error) {
+			return &entity.Collection{Schema: mockSchema(vectorType, bm25), Loaded: true}, nil
+		}).Build()
+
+		var searchOption milvusclient.SearchOption
+		var hybridOption milvusclient.HybridSearchOption
+		Mock((*milvusclient.Client).Search).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.SearchOption, callOptions ...grpc.CallOption) ([]milvusclient.ResultSet, error) {
+			searchOption = option
+			return []milvusclient.ResultSet{mockResultSet()}, nil
+		}).Build()
+		Mock((*milvusclient.Client).HybridSearch).To(func(c *milvusclient.Client, ctx context.Context, option milvusclient.HybridSearchOption, callOptions ...grpc.CallOption) ([]milvusclient.ResultSet, error) {
+			hybridOption = option
+			return []milvusclient.ResultSet{mockResultSet()}, nil
+		}).Build()
+
+		PatchConvey("test dense search", func() {
+			vectorType = entity.FieldTypeFloat16Vector
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:         mockClient,
+				MetricType:     entity.IP,
+				ScoreThreshold: 0.3,
+				Embedding:      &mockEmbedding{},
+			})
+			convey.So(err, convey.ShouldBeNil)
+
+			docs, err := r.Retrieve(ctx, "query", WithFilter("id > 0"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 2)
+			convey.So(docs[0].ID, convey.ShouldEqual, "1")
+			convey.So(docs[0].Content, convey.ShouldEqual, "a")
+			convey.So(docs[0].MetaData["k"], convey.ShouldEqual, "v")
+			convey.So(docs[0].MetaData["extra"], convey.ShouldEqual, int64(7))
+			convey.So(docs[0].Score(), convey.ShouldAlmostEqual, 0.9, 1e-6)
+
+			req, err := searchOption.Request()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.GetDsl(), convey.ShouldEqual, "id > 0")
+			params := entity.KvPairsMap(req.GetSearchParams())
+			convey.So(params["anns_field"], convey.ShouldEqual, "vector")
+			convey.So(params["metric_type"], convey.ShouldEqual, "IP")
+			convey.So(params["params"], convey.ShouldEqual, `{"radius":0.3}`)
+		})
+
+		PatchConvey("test dense search embedding error", func() {
+			vectorType = entity.FieldTypeFloatVector
+			r, err := NewRetriever(ctx, &RetrieverConfig{Client: mockClient, Embedding: &mockEmbedding{err: fmt.Errorf("embedding error")}})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "query")
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[milvus2 retriever] embedding has error: embedding error"))
+		})
+
+		PatchConvey("test full-text search", func() {
+			vectorType, bm25 = entity.FieldTypeFloatVector, true
+			r, err := NewRetriever(ctx, &RetrieverConfig{Client: mockClient, SparseVectorField: "sparse", SearchMode: SearchModeSparse})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "query")
+			convey.So(err, convey.ShouldBeNil)
+			req, err := searchOption.Request()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(entity.KvPairsMap(req.GetSearchParams())["anns_field"], convey.ShouldEqual, "sparse")
+		})
+
+		PatchConvey("test sparse search without sparse vector", func() {
+			vectorType, bm25 = entity.FieldTypeFloatVector, false
+			r, err := NewRetriever(ctx, &RetrieverConfig{Client: mockClient, SparseVectorField: "sparse", SearchMode: SearchModeSparse})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = r.Retrieve(ctx, "query")
+			convey.So(err, convey.ShouldBeError, fmt.Errorf("[milvus2 retriever] sparse vector not provided"))
+
+			_, err = r.Retrieve(ctx, "query", WithSparseVector(map[int]float64{1: 0.5}))
+			convey.So(err, convey.ShouldBeNil)
+		})
+
+		PatchConvey("test hybrid search", func() {
+			vectorType, bm25 = entity.FieldTypeFloatVector, true
+			r, err := NewRetriever(ctx, &RetrieverConfig{
+				Client:            mockClient,
+				SparseVectorField: "sparse",
+				SearchMode:        SearchModeHybrid,
+				Ranker:            milvusclient.NewWeightedReranker([]float64{0.7, 0.3}),
+				Partitions:        []string{"p1"},
+				Embedding:         &mockEmbedding{},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			docs, err := r.Retrieve(ctx, "query", WithFilter("id > 0"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 2)
+
+			req, err := hybridOption.HybridRequest()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(req.GetRequests()), convey.ShouldEqual, 2)
+			convey.So(req.GetPartitionNames(), convey.ShouldResemble, []string{"p1"})
+			convey.So(entity.KvPairsMap(req.GetRankParams())["strategy"], convey.ShouldEqual, "weighted")
+			for _, sub := range req.GetRequests() {
+				convey.So(sub.GetDsl(), convey.ShouldEqual, "id > 0")
+			}
+		})
+	})
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package milvus2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// defaultDocumentConverter returns the default document converter, which reads the fields of the default schema
+// and puts the other output fields into the metadata
+func defaultDocumentConverter() func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+	return func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+		docs := make([]*schema.Document, 0, result.ResultCount)
+		for i := 0; i < result.ResultCount; i++ {
+			doc := &schema.Document{
+				MetaData: make(map[string]any),
+			}
+			if result.IDs != nil {
+				id, err := result.IDs.GetAsString(i)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get id: %w", err)
+				}
+				doc.ID = id
+			}
+			for _, field := range result.Fields {
+				switch field.Name() {
+				case defaultIDField:
+				case defaultContentField:
+					content, err := field.GetAsString(i)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get content: %w", err)
+					}
+					doc.Content = content
+				case defaultMetadataField:
+					v, err := field.Get(i)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get metadata: %w", err)
+					}
+					b, ok := v.([]byte)
+					if !ok {
+						return nil, fmt.Errorf("failed to get metadata: unexpected type %T", v)
+					}
+					if err := sonic.Unmarshal(b, &doc.MetaData); err != nil {
+						return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+					}
+				default:
+					v, err := field.Get(i)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get field %s: %w", field.Name(), err)
+					}
+					doc.MetaData[field.Name()] = v
+				}
+			}
+			if i < len(result.Scores) {
+				doc.WithScore(float64(result.Scores[i]))
+			}
+			docs = append(docs, doc)
+		}
+		return docs, nil
+	}
+}
+
+// defaultVectorConverter returns the vector converter for the data type of the vector field
+func defaultVectorConverter(fieldType entity.FieldType) func(ctx context.Context, vectors [][]float64) ([]entity.Vector, error) {
+	return func(ctx context.Context, vectors [][]float64) ([]entity.Vector, error) {
+		vec := make([]entity.Vector, 0, len(vectors))
+		for _, vector := range vectors {
+			fv := entity.FloatVector(vector2Float32(vector))
+			switch fieldType {
+			case entity.FieldTypeFloatVector:
+				vec = append(vec, fv)
+			case entity.FieldTypeFloat16Vector:
+				vec = append(vec, fv.ToFloat16Vector())
+			case entity.FieldTypeBFloat16Vector:
+				vec = append(vec, fv.ToBFloat16Vector())
+			default:
+				return nil, fmt.Errorf("unsupported vector field type: %s", fieldType.Name())
+			}
+		}
+		return vec, nil
+	}
+}
+
+// getFieldType gets the data type of the field
+func getFieldType(field string, s *entity.Schema) (entity.FieldType, error) {
+	for _, column := range s.Fields {
+		if column.Name == field {
+			return column.DataType, nil
+		}
+	}
+	return entity.FieldTypeNone, errors.New("vector field not found")
+}
+
+// isBM25Output reports whether the field is the output of a BM25 function
+func isBM25Output(field string, s *entity.Schema) bool {
+	if field == "" {
+		return false
+	}
+	for _, fn := range s.Functions {
+		if fn.Type != entity.FunctionTypeBM25 {
+			continue
+		}
+		for _, output := range fn.OutputFieldNames {
+			if output == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// vector2Float32 converts the vector to float32 slice
+func vector2Float32(vector []float64) []float32 {
+	float32Arr := make([]float32, len(vector))
+	for i, v := range vector {
+		float32Arr[i] = float32(v)
+	}
+	return float32Arr
+}
+
+// sparse2Embedding converts the sparse vector to entity.SparseEmbedding
+func sparse2Embedding(sparse map[int]float64) (entity.SparseEmbedding, error) {
+	positions := make([]uint32, 0, len(sparse))
+	for pos := range sparse {
+		positions = append(positions, uint32(pos))
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	values := make([]float32, 0, len(positions))
+	for _, pos := range positions {
+		values = append(values, float32(sparse[int(pos)]))
+	}
+	return entity.NewSliceSparseEmbedding(positions, values)
+}
+
+// makeEmbeddingCtx makes the embedding context
+func makeEmbeddingCtx(ctx context.Context, emb embedding.Embedder) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: components.ComponentOfEmbedding,
+	}
+
+	if embType, ok := components.GetType(emb); ok {
+		runInfo.Type = embType
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
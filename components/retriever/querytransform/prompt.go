@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querytransform
+
+import (
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// the variables of the prompts
+	varQuery = "query"
+	varNum   = "num"
+)
+
+const multiQuerySystemPrompt = `You are an assistant of a search system. The user question may retrieve poorly against the knowledge base,
+rewrite it into {num} different search queries, which look at the question from different perspectives
+and keep its intent, to retrieve more relevant documents.
+Reply with the queries only, one per line, without numbering or any explanation.`
+
+const hydeSystemPrompt = `You are an assistant of a search system. Write a short passage which answers the user question,
+as if it were a document in the knowledge base. It is fine to make up the details if you are not sure.
+Reply with the passage only, without any explanation.`
+
+const stepBackSystemPrompt = `You are an assistant of a search system. Step back from the details of the user question,
+and rewrite it into a more generic question about the underlying concept or principle,
+whose answer helps to answer the original question.
+Reply with the question only, without any explanation.`
+
+const userPrompt = `{query}`
+
+func defaultPrompt(mode Mode) prompt.ChatTemplate {
+	var system string
+	switch mode {
+	case ModeHyDE:
+		system = hydeSystemPrompt
+	case ModeStepBack:
+		system = stepBackSystemPrompt
+	default:
+		system = multiQuerySystemPrompt
+	}
+	return prompt.FromMessages(schema.FString,
+		schema.SystemMessage(system),
+		schema.UserMessage(userPrompt))
+}
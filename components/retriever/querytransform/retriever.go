@@ -0,0 +1,386 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querytransform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/components/retriever/ensemble"
+)
+
+const typ = "QueryTransform"
+
+// ExtraKeyQueries is the key in retriever.CallbackOutput.Extra of the queries retrieved, whose value is []*Query.
+const ExtraKeyQueries = "queries"
+
+type Mode string
+
+const (
+	// ModeOriginal is the mode of the original query, which is retrieved unless Config.ExcludeOriginal is set.
+	ModeOriginal Mode = "original"
+	// ModeMultiQuery rewrites the query into several queries from different perspectives.
+	ModeMultiQuery Mode = "multi_query"
+	// ModeHyDE generates a hypothetical document answering the query, and retrieves the documents similar to it,
+	// which suits the vector retrievers (https://arxiv.org/abs/2212.10496).
+	ModeHyDE Mode = "hyde"
+	// ModeStepBack rewrites the query into a more generic question about the underlying concept (https://arxiv.org/abs/2310.06117).
+	ModeStepBack Mode = "step_back"
+)
+
+const defaultNumQueries = 3
+
+// Query is a query retrieved by the underlying retriever.
+type Query struct {
+	Mode  Mode   `json:"mode"`
+	Query string `json:"query"`
+}
+
+type Config struct {
+	// ChatModel generates the queries
+	// Required
+	ChatModel model.BaseChatModel
+	// Retriever retrieves the documents of every query
+	// Required
+	Retriever retriever.Retriever
+	// Modes are the ways to transform the query, the queries of all modes are retrieved
+	// Optional. Default: []Mode{ModeMultiQuery}
+	Modes []Mode
+	// NumQueries is the number of queries generated in ModeMultiQuery
+	// Optional. Default: 3
+	NumQueries int
+	// Prompts overrides the default prompts of the modes.
+	// The variables are {query} and {num}, which is NumQueries.
+	// The model must reply one query per line in ModeMultiQuery, a passage in ModeHyDE and a question in ModeStepBack.
+	// Optional.
+	Prompts map[Mode]prompt.ChatTemplate
+	// ExcludeOriginal skips retrieving the original query
+	// Optional. Default: false
+	ExcludeOriginal bool
+	// TopK limits the number of the merged documents, which can be overridden by retriever.WithTopK,
+	// 0 returns all the documents
+	// Optional. Default: 0
+	TopK int
+	// DedupKey returns the key to identify the same document retrieved by different queries
+	// Optional. Default: ensemble.DedupByID
+	DedupKey func(doc *schema.Document) string
+}
+
+// Retriever transforms the query by a chat model, retrieves every query by the underlying retriever,
+// and merges the results by reciprocal rank fusion.
+type Retriever struct {
+	cm              model.BaseChatModel
+	r               retriever.Retriever
+	modes           []Mode
+	numQueries      int
+	prompts         map[Mode]prompt.ChatTemplate
+	excludeOriginal bool
+	topK            int
+	dedupKey        func(doc *schema.Document) string
+}
+
+func NewRetriever(ctx context.Context, config *Config) (*Retriever, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	if config.ChatModel == nil {
+		return nil, errors.New("chat model is required")
+	}
+	if config.Retriever == nil {
+		return nil, errors.New("retriever is required")
+	}
+	modes := config.Modes
+	if len(modes) == 0 {
+		modes = []Mode{ModeMultiQuery}
+	}
+	prompts := make(map[Mode]prompt.ChatTemplate, len(modes))
+	for _, mode := range modes {
+		if mode != ModeMultiQuery && mode != ModeHyDE && mode != ModeStepBack {
+			return nil, fmt.Errorf("unknown mode: %s", mode)
+		}
+		if _, ok := prompts[mode]; ok {
+			return nil, fmt.Errorf("duplicate mode: %s", mode)
+		}
+		tpl := config.Prompts[mode]
+		if tpl == nil {
+			tpl = defaultPrompt(mode)
+		}
+		prompts[mode] = tpl
+	}
+	if config.NumQueries < 0 {
+		return nil, fmt.Errorf("num queries must not be negative, given=%d", config.NumQueries)
+	}
+	numQueries := config.NumQueries
+	if numQueries == 0 {
+		numQueries = defaultNumQueries
+	}
+	if config.TopK < 0 {
+		return nil, fmt.Errorf("top k must not be negative, given=%d", config.TopK)
+	}
+	dedupKey := config.DedupKey
+	if dedupKey == nil {
+		dedupKey = ensemble.DedupByID
+	}
+
+	return &Retriever{
+		cm:              config.ChatModel,
+		r:               config.Retriever,
+		modes:           modes,
+		numQueries:      numQueries,
+		prompts:         prompts,
+		excludeOriginal: config.ExcludeOriginal,
+		topK:            config.TopK,
+		dedupKey:        dedupKey,
+	}, nil
+}
+
+// Retrieve generates the queries, retrieves them concurrently and returns the merged documents.
+// The options are passed to the underlying retriever. The returned documents are copies whose Score is the fused score.
+// The chat model and the underlying retriever are called with the callback handlers of this retriever,
+// and RunInfo.Name of the mode, so the generated queries show in the traces,
+// they are also returned in retriever.CallbackOutput.Extra[ExtraKeyQueries].
+// A mode failing to generate is skipped, Retrieve fails only if no query is left to retrieve.
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{TopK: &r.topK}, opts...)
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query: query,
+		TopK:  *co.TopK,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	queries, err := r.generateQueries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]*schema.Document, len(queries))
+	err = runConcurrently(len(queries), func(i int) (err error) {
+		results[i], err = r.retrieve(ctx, queries[i], opts...)
+		if err != nil {
+			return fmt.Errorf("retrieve %s query fail: %w", queries[i].Mode, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs = ensemble.FuseRRF(results, 0, r.dedupKey)
+	if *co.TopK > 0 && len(docs) > *co.TopK {
+		docs = docs[:*co.TopK]
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{
+		Docs:  docs,
+		Extra: map[string]any{ExtraKeyQueries: queries},
+	})
+
+	return docs, nil
+}
+
+// generateQueries returns the original query followed by the generated queries in the order of modes,
+// the duplicates are removed. The modes failing to generate are skipped, and their errors are returned
+// only if no query is left.
+func (r *Retriever) generateQueries(ctx context.Context, query string) ([]*Query, error) {
+	generated := make([][]string, len(r.modes))
+	errs := make([]error, len(r.modes))
+	err := runConcurrently(len(r.modes), func(i int) error {
+		reply, err := r.generate(ctx, r.modes[i], query)
+		if err != nil {
+			// the error is reported to the callbacks of the chat model, degrade to the queries of the other modes
+			errs[i] = fmt.Errorf("generate %s query fail: %w", r.modes[i], err)
+			return nil
+		}
+		generated[i] = parseReply(r.modes[i], reply, r.numQueries)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var queries []*Query
+	add := func(mode Mode, q string) {
+		if q == "" || seen[q] {
+			return
+		}
+		seen[q] = true
+		queries = append(queries, &Query{Mode: mode, Query: q})
+	}
+	if !r.excludeOriginal {
+		add(ModeOriginal, query)
+	} else {
+		// never retrieves the original query, even if it is generated again
+		seen[query] = true
+	}
+	for i, qs := range generated {
+		for _, q := range qs {
+			add(r.modes[i], q)
+		}
+	}
+	if len(queries) == 0 {
+		return nil, errors.Join(append([]error{errors.New("no query is generated")}, errs...)...)
+	}
+	return queries, nil
+}
+
+func (r *Retriever) generate(ctx context.Context, mode Mode, query string) (reply string, err error) {
+	msgs, err := r.prompts[mode].Format(ctx, map[string]any{
+		varQuery: query,
+		varNum:   r.numQueries,
+	})
+	if err != nil {
+		return "", fmt.Errorf("format prompt fail: %w", err)
+	}
+
+	t, _ := components.GetType(r.cm)
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+		Name:      string(mode),
+		Type:      t,
+		Component: components.ComponentOfChatModel,
+	})
+	var msg *schema.Message
+	if components.IsCallbacksEnabled(r.cm) {
+		msg, err = r.cm.Generate(ctx, msgs)
+	} else {
+		ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: msgs})
+		msg, err = r.cm.Generate(ctx, msgs)
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		} else {
+			callbacks.OnEnd(ctx, &model.CallbackOutput{Message: msg})
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return msg.Content, nil
+}
+
+func (r *Retriever) retrieve(ctx context.Context, query *Query, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	t, _ := components.GetType(r.r)
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+		Name:      string(query.Mode),
+		Type:      t,
+		Component: components.ComponentOfRetriever,
+	})
+	if components.IsCallbacksEnabled(r.r) {
+		return r.r.Retrieve(ctx, query.Query, opts...)
+	}
+
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{Query: query.Query})
+	docs, err = r.r.Retrieve(ctx, query.Query, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+	return docs, nil
+}
+
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+// parseReply extracts the queries from the reply of the model.
+func parseReply(mode Mode, reply string, numQueries int) []string {
+	reply = strings.TrimSpace(reply)
+	if mode != ModeMultiQuery {
+		if mode == ModeStepBack {
+			reply = trimQuery(reply)
+		}
+		if reply == "" {
+			return nil
+		}
+		return []string{reply}
+	}
+
+	var queries []string
+	for _, line := range strings.Split(reply, "\n") {
+		if q := trimQuery(line); q != "" {
+			queries = append(queries, q)
+		}
+		if len(queries) == numQueries {
+			break
+		}
+	}
+	return queries
+}
+
+// trimQuery removes the numbering, bullet and surrounding quotes the models often add, e.g. `1. "query"`.
+func trimQuery(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "-*•")
+	// the numbering must be followed by a space, so that "3.5 GPA" is kept
+	if i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); i > 0 && i+1 < len(s) &&
+		(s[i] == '.' || s[i] == ')' || s[i] == ':') && (s[i+1] == ' ' || s[i+1] == '\t') {
+		s = s[i+1:]
+	}
+	s = strings.TrimSpace(s)
+	for _, q := range [][2]string{{`"`, `"`}, {"'", "'"}, {"`", "`"}, {"“", "”"}} {
+		if len(s) >= len(q[0])+len(q[1]) && strings.HasPrefix(s, q[0]) && strings.HasSuffix(s, q[1]) {
+			return strings.TrimSpace(s[len(q[0]) : len(s)-len(q[1])])
+		}
+	}
+	return s
+}
+
+// runConcurrently runs the tasks concurrently, and returns the first error in the order of tasks.
+func runConcurrently(n int, task func(i int) error) error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if pe := recover(); pe != nil {
+					errs[i] = fmt.Errorf("panic error: %v, \nstack: %s", pe, string(debug.Stack()))
+				}
+			}()
+			errs[i] = task(i)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
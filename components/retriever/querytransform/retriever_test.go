@@ -0,0 +1,277 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querytransform
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fakeChatModel struct {
+	// replies maps a keyword of the system prompt to the reply
+	replies map[string]string
+	err     error
+}
+
+func (f *fakeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for keyword, reply := range f.replies {
+		if strings.Contains(input[0].Content, keyword) {
+			return schema.AssistantMessage(reply, nil), nil
+		}
+	}
+	return nil, errors.New("unexpected prompt: " + input[0].Content)
+}
+
+func (f *fakeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+type fakeRetriever struct {
+	mu      sync.Mutex
+	docs    map[string][]*schema.Document
+	queries []string
+	topK    int
+}
+
+func (f *fakeRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, query)
+	f.topK = *retriever.GetCommonOptions(&retriever.Options{TopK: new(int)}, opts...).TopK
+	docs, ok := f.docs[query]
+	if !ok {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	return docs, nil
+}
+
+func docs(ids ...string) []*schema.Document {
+	ret := make([]*schema.Document, len(ids))
+	for i, id := range ids {
+		ret[i] = &schema.Document{ID: id, Content: "content of " + id}
+	}
+	return ret
+}
+
+func ids(docs []*schema.Document) []string {
+	ret := make([]string, len(docs))
+	for i, d := range docs {
+		ret[i] = d.ID
+	}
+	return ret
+}
+
+func TestMultiQuery(t *testing.T) {
+	ctx := context.Background()
+	cm := &fakeChatModel{replies: map[string]string{
+		"3 different search queries": "1. eino agent tutorial\n2) \"build agent with eino\"\n\n- how to build agent with eino\n4. extra query",
+	}}
+	r := &fakeRetriever{docs: map[string][]*schema.Document{
+		"how to build agent with eino": docs("d1", "d2"),
+		"eino agent tutorial":          docs("d3", "d2"),
+		"build agent with eino":        docs("d2", "d4", "d2"),
+	}}
+	rt, err := NewRetriever(ctx, &Config{ChatModel: cm, Retriever: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var ended []string
+	var queries []*Query
+	handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+		mu.Lock()
+		defer mu.Unlock()
+		ended = append(ended, string(info.Component)+"/"+info.Name)
+		if info.Name == "root" {
+			// the retriever keeps the RunInfo given by the caller
+			queries = retriever.ConvCallbackOutput(output).Extra[ExtraKeyQueries].([]*Query)
+		}
+		return ctx
+	}).Build()
+	ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "root"}, handler)
+
+	result, err := rt.Retrieve(ctx, "how to build agent with eino", retriever.WithTopK(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// d2: 1/62+1/62+1/61, d1: 1/61, d3: 1/61, d4: 1/62
+	if got := ids(result); !reflect.DeepEqual(got, []string{"d2", "d1", "d3"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+	if want := 2.0/62 + 1.0/61; result[0].Score() != want {
+		t.Fatalf("unexpected score: %v, want: %v", result[0].Score(), want)
+	}
+	if r.topK != 3 {
+		t.Fatalf("top k is not passed to the retriever: %d", r.topK)
+	}
+
+	want := []*Query{
+		{Mode: ModeOriginal, Query: "how to build agent with eino"},
+		{Mode: ModeMultiQuery, Query: "eino agent tutorial"},
+		{Mode: ModeMultiQuery, Query: "build agent with eino"},
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Fatalf("unexpected queries: %+v", queries)
+	}
+	sort.Strings(ended)
+	if !reflect.DeepEqual(ended, []string{
+		"/root", "ChatModel/multi_query", "ChatTemplate/",
+		"Retriever/multi_query", "Retriever/multi_query", "Retriever/original",
+	}) {
+		t.Fatalf("unexpected callbacks: %v", ended)
+	}
+}
+
+func TestHyDEAndStepBack(t *testing.T) {
+	ctx := context.Background()
+	cm := &fakeChatModel{replies: map[string]string{
+		"passage":   "Eino provides the ChatModelAgent to build agents.",
+		"Step back": "\"What is an agent framework?\"",
+	}}
+	r := &fakeRetriever{docs: map[string][]*schema.Document{
+		"Eino provides the ChatModelAgent to build agents.": docs("d1"),
+		"What is an agent framework?":                       docs("d2", "d1"),
+	}}
+	rt, err := NewRetriever(ctx, &Config{
+		ChatModel:       cm,
+		Retriever:       r,
+		Modes:           []Mode{ModeHyDE, ModeStepBack},
+		ExcludeOriginal: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := rt.Retrieve(ctx, "how to build agent with eino")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(result); !reflect.DeepEqual(got, []string{"d1", "d2"}) {
+		t.Fatalf("unexpected docs: %v", got)
+	}
+	sort.Strings(r.queries)
+	if !reflect.DeepEqual(r.queries, []string{"Eino provides the ChatModelAgent to build agents.", "What is an agent framework?"}) {
+		t.Fatalf("unexpected queries: %q", r.queries)
+	}
+}
+
+func TestRetrieveError(t *testing.T) {
+	ctx := context.Background()
+	r := &fakeRetriever{docs: map[string][]*schema.Document{"query": docs("d1")}}
+
+	// degrades to the original query if the generation fails
+	rt, err := NewRetriever(ctx, &Config{ChatModel: &fakeChatModel{err: errors.New("rate limited")}, Retriever: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := rt.Retrieve(ctx, "query"); err != nil || !reflect.DeepEqual(ids(got), []string{"d1"}) {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+	// degrades to the queries of the other modes if a mode fails
+	hyde := &fakeRetriever{docs: map[string][]*schema.Document{"a passage": docs("d2")}}
+	rt, err = NewRetriever(ctx, &Config{
+		ChatModel:       &fakeChatModel{replies: map[string]string{"passage": "a passage"}},
+		Retriever:       hyde,
+		Modes:           []Mode{ModeHyDE, ModeStepBack},
+		ExcludeOriginal: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := rt.Retrieve(ctx, "query"); err != nil || !reflect.DeepEqual(ids(got), []string{"d2"}) {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+
+	rt, err = NewRetriever(ctx, &Config{ChatModel: &fakeChatModel{err: errors.New("rate limited")}, Retriever: r, ExcludeOriginal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rt.Retrieve(ctx, "query"); err == nil || !strings.Contains(err.Error(), "generate multi_query query fail: rate limited") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cm := &fakeChatModel{replies: map[string]string{"search queries": "unknown"}}
+	rt, err = NewRetriever(ctx, &Config{ChatModel: cm, Retriever: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rt.Retrieve(ctx, "query"); err == nil || !strings.Contains(err.Error(), "retrieve multi_query query fail") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cm = &fakeChatModel{replies: map[string]string{"search queries": "query"}}
+	rt, err = NewRetriever(ctx, &Config{ChatModel: cm, Retriever: r, ExcludeOriginal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rt.Retrieve(ctx, "query"); err == nil {
+		t.Fatal("expect error when no query is generated")
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	for _, c := range []struct {
+		mode  Mode
+		reply string
+		num   int
+		want  []string
+	}{
+		{ModeMultiQuery, "1. a\n2. b\n3. c", 2, []string{"a", "b"}},
+		{ModeMultiQuery, "* 'a'\n\n• “b”\n10) c", 5, []string{"a", "b", "c"}},
+		{ModeMultiQuery, "2024 sales report", 3, []string{"2024 sales report"}},
+		{ModeMultiQuery, "1. 3.5 GPA\n2. 10:30 am\n3)\tc", 3, []string{"3.5 GPA", "10:30 am", "c"}},
+		{ModeStepBack, "  \"what is rag?\"\n", 1, []string{"what is rag?"}},
+		{ModeHyDE, "1. first\n2. second", 1, []string{"1. first\n2. second"}},
+		{ModeHyDE, "  ", 1, nil},
+	} {
+		if got := parseReply(c.mode, c.reply, c.num); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("parse %q in %s, got: %q, want: %q", c.reply, c.mode, got, c.want)
+		}
+	}
+}
+
+func TestNewRetriever(t *testing.T) {
+	ctx := context.Background()
+	cm := &fakeChatModel{}
+	r := &fakeRetriever{}
+	for _, config := range []*Config{
+		nil,
+		{Retriever: r},
+		{ChatModel: cm},
+		{ChatModel: cm, Retriever: r, Modes: []Mode{ModeOriginal}},
+		{ChatModel: cm, Retriever: r, Modes: []Mode{ModeHyDE, ModeHyDE}},
+		{ChatModel: cm, Retriever: r, NumQueries: -1},
+		{ChatModel: cm, Retriever: r, TopK: -1},
+	} {
+		if _, err := NewRetriever(ctx, config); err == nil {
+			t.Fatalf("expect error for config: %+v", config)
+		}
+	}
+}